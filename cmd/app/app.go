@@ -4,6 +4,7 @@ import (
 	"DataTask/internal/config"
 	"DataTask/internal/di"
 	"DataTask/pkg/logger"
+	"context"
 	"fmt"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -18,6 +19,10 @@ func Run(cfg *config.Config) error {
 		return err
 	}
 
+	for _, w := range app.Workers {
+		go w.Start(context.Background())
+	}
+
 	router := gin.New()
 	router.Use(gin.Recovery())
 	router.Use(cors.New(getCORSConfig(app.Config)))
@@ -52,30 +57,125 @@ func Run(cfg *config.Config) error {
 	kanbanHandlerRouterGroup := protectedApiRouter.Group("/kanban")
 	{
 		kanbanHandlerRouterGroup.GET("/project/:project_id", app.KanbanHandler.HandleGetKanbansByProjectID)
+		kanbanHandlerRouterGroup.POST("/project/:project_id/reorder", app.KanbanHandler.HandleReorderKanbans)
 		kanbanHandlerRouterGroup.POST("/", app.KanbanHandler.HandleCreateKanban)
 		kanbanHandlerRouterGroup.GET("/:id", app.KanbanHandler.HandleGetKanbanByID)
 		kanbanHandlerRouterGroup.PUT("/:id", app.KanbanHandler.HandleUpdateKanban)
 		kanbanHandlerRouterGroup.DELETE("/:id", app.KanbanHandler.HandleDeleteKanban)
+		kanbanHandlerRouterGroup.POST("/:id/restore", app.TrashHandler.HandleRestoreKanban)
+		kanbanHandlerRouterGroup.POST("/:id/clone", app.CloneHandler.HandleCloneKanban)
+		kanbanHandlerRouterGroup.PUT("/:id/status", app.WorkflowHandler.HandleSetKanbanStatus)
+		kanbanHandlerRouterGroup.PUT("/:id/wip_limit", app.KanbanHandler.HandleSetWIPLimit)
 	}
 
 	// Task Routes
 	taskHandlerRouterGroup := protectedApiRouter.Group("/task")
+	taskHandlerRouterGroup.Use(app.TaskKeyMiddleware.Middleware())
 	{
 		taskHandlerRouterGroup.POST("/", app.TaskHandler.HandleCreateTask)
+		taskHandlerRouterGroup.POST("/bulk", app.TaskBulkHandler.HandleBulkTasks)
 		taskHandlerRouterGroup.GET("/:id", app.TaskHandler.HandleGetTaskByID)
 		taskHandlerRouterGroup.PUT("/:id", app.TaskHandler.HandleUpdateTask)
 		taskHandlerRouterGroup.DELETE("/:id", app.TaskHandler.HandleDeleteTask)
+		taskHandlerRouterGroup.POST("/:task_id/restore", app.TrashHandler.HandleRestoreTask)
+		taskHandlerRouterGroup.POST("/:task_id/clone", app.CloneHandler.HandleCloneTask)
 		taskHandlerRouterGroup.POST("/:task_id/assign", app.TaskHandler.HandleAssignUserToTask)
+		taskHandlerRouterGroup.GET("/:id/assignees", app.AssigneeHandler.HandleGetAssignees)
+		taskHandlerRouterGroup.POST("/:task_id/assignees", app.AssigneeHandler.HandleAddAssignee)
+		taskHandlerRouterGroup.PUT("/:id/assignees", app.AssigneeHandler.HandleReplaceAssignees)
+		taskHandlerRouterGroup.DELETE("/:id/assignees/:user_id", app.AssigneeHandler.HandleRemoveAssignee)
+		taskHandlerRouterGroup.GET("/:id/watchers", app.AssigneeHandler.HandleGetWatchers)
+		taskHandlerRouterGroup.POST("/:task_id/watchers", app.AssigneeHandler.HandleAddWatcher)
+		taskHandlerRouterGroup.DELETE("/:id/watchers/:user_id", app.AssigneeHandler.HandleRemoveWatcher)
+		taskHandlerRouterGroup.GET("/:id/history", app.HistoryHandler.HandleGetTaskHistory)
+		taskHandlerRouterGroup.GET("/:id/timeline", app.HistoryHandler.HandleGetTaskTimeline)
+		taskHandlerRouterGroup.POST("/:task_id/labels", app.LabelHandler.HandleAttachLabelToTask)
+		taskHandlerRouterGroup.DELETE("/:id/labels/:label_id", app.LabelHandler.HandleDetachLabelFromTask)
+		taskHandlerRouterGroup.GET("/:id/subtasks", app.TaskHandler.HandleGetSubtasks)
+		taskHandlerRouterGroup.GET("/:id/checklist", app.ChecklistHandler.HandleGetChecklist)
+		taskHandlerRouterGroup.POST("/:task_id/checklist", app.ChecklistHandler.HandleCreateChecklistItem)
+		taskHandlerRouterGroup.POST("/:task_id/checklist/reorder", app.ChecklistHandler.HandleReorderChecklist)
+		taskHandlerRouterGroup.PUT("/:id/checklist/:item_id", app.ChecklistHandler.HandleUpdateChecklistItem)
+		taskHandlerRouterGroup.DELETE("/:id/checklist/:item_id", app.ChecklistHandler.HandleDeleteChecklistItem)
+		taskHandlerRouterGroup.GET("/:id/links", app.TaskLinkHandler.HandleGetTaskLinks)
+		taskHandlerRouterGroup.POST("/:task_id/links", app.TaskLinkHandler.HandleCreateTaskLink)
+		taskHandlerRouterGroup.DELETE("/:id/links/:link_id", app.TaskLinkHandler.HandleDeleteTaskLink)
+		taskHandlerRouterGroup.GET("/:id/recurrence", app.RecurrenceHandler.HandleGetRecurrence)
+		taskHandlerRouterGroup.PUT("/:id/recurrence", app.RecurrenceHandler.HandleSetRecurrence)
+		taskHandlerRouterGroup.DELETE("/:id/recurrence", app.RecurrenceHandler.HandleStopRecurrence)
+		taskHandlerRouterGroup.POST("/:task_id/timer/start", app.TimeTrackingHandler.HandleStartTimer)
+		taskHandlerRouterGroup.POST("/:task_id/timer/stop", app.TimeTrackingHandler.HandleStopTimer)
+		taskHandlerRouterGroup.GET("/:id/worklogs", app.TimeTrackingHandler.HandleGetWorklogs)
+		taskHandlerRouterGroup.POST("/:task_id/worklogs", app.TimeTrackingHandler.HandleCreateWorklog)
+		taskHandlerRouterGroup.PUT("/:id/worklogs/:worklog_id", app.TimeTrackingHandler.HandleUpdateWorklog)
+		taskHandlerRouterGroup.DELETE("/:id/worklogs/:worklog_id", app.TimeTrackingHandler.HandleDeleteWorklog)
+		taskHandlerRouterGroup.GET("/:id/custom_fields", app.CustomFieldHandler.HandleGetTaskCustomFields)
+		taskHandlerRouterGroup.PUT("/:id/custom_fields", app.CustomFieldHandler.HandleSetTaskCustomFields)
+		taskHandlerRouterGroup.GET("/:id/attachments", app.AttachmentHandler.HandleGetTaskAttachments)
+		taskHandlerRouterGroup.POST("/:task_id/attachments", app.AttachmentHandler.HandleUploadTaskAttachment)
+	}
+
+	// Time Tracking Routes
+	timeTrackingHandlerRouterGroup := protectedApiRouter.Group("/timetracking")
+	{
+		timeTrackingHandlerRouterGroup.GET("/timer", app.TimeTrackingHandler.HandleGetRunningTimer)
+		timeTrackingHandlerRouterGroup.GET("/totals", app.TimeTrackingHandler.HandleGetTimeTotals)
+	}
+
+	// The task lists are public; signed in users can filter them by assignee:me.
+	optionalAuthApiRouter := apiRouter.Group("/")
+	optionalAuthApiRouter.Use(app.AuthMiddleware.OptionalMiddleware())
+	optionalAuthApiRouter.GET("/kanban_tasks/:kanban_id", app.TaskHandler.HandleGetTasksByKanbanID)
+	optionalAuthApiRouter.GET("/user/:user_id/tasks", app.TaskHandler.HandleGetTasksByUserID)
+	optionalAuthApiRouter.GET("/project_tasks/:project_id", app.TaskHandler.HandleGetTasksByProjectID)
+
+	protectedApiRouter.GET("/search", app.SearchHandler.HandleSearch)
+
+	// Label Routes
+	labelHandlerRouterGroup := protectedApiRouter.Group("/label")
+	{
+		labelHandlerRouterGroup.GET("/project/:project_id", app.LabelHandler.HandleGetLabelsByProjectID)
+		labelHandlerRouterGroup.POST("/", app.LabelHandler.HandleCreateLabel)
+		labelHandlerRouterGroup.GET("/:id", app.LabelHandler.HandleGetLabelByID)
+		labelHandlerRouterGroup.PUT("/:id", app.LabelHandler.HandleUpdateLabel)
+		labelHandlerRouterGroup.DELETE("/:id", app.LabelHandler.HandleDeleteLabel)
+	}
+
+	// Custom Field Routes
+	customFieldHandlerRouterGroup := protectedApiRouter.Group("/custom_field")
+	{
+		customFieldHandlerRouterGroup.GET("/project/:project_id", app.CustomFieldHandler.HandleGetCustomFieldsByProjectID)
+		customFieldHandlerRouterGroup.POST("/", app.CustomFieldHandler.HandleCreateCustomField)
+		customFieldHandlerRouterGroup.GET("/:id", app.CustomFieldHandler.HandleGetCustomFieldByID)
+		customFieldHandlerRouterGroup.PUT("/:id", app.CustomFieldHandler.HandleUpdateCustomField)
+		customFieldHandlerRouterGroup.DELETE("/:id", app.CustomFieldHandler.HandleDeleteCustomField)
+	}
+
+	// Task Template Routes
+	taskTemplateHandlerRouterGroup := protectedApiRouter.Group("/task_template")
+	{
+		taskTemplateHandlerRouterGroup.GET("/project/:project_id", app.TaskTemplateHandler.HandleGetTaskTemplatesByProjectID)
+		taskTemplateHandlerRouterGroup.POST("/", app.TaskTemplateHandler.HandleCreateTaskTemplate)
+		taskTemplateHandlerRouterGroup.GET("/:id", app.TaskTemplateHandler.HandleGetTaskTemplateByID)
+		taskTemplateHandlerRouterGroup.PUT("/:id", app.TaskTemplateHandler.HandleUpdateTaskTemplate)
+		taskTemplateHandlerRouterGroup.DELETE("/:id", app.TaskTemplateHandler.HandleDeleteTaskTemplate)
 	}
-	apiRouter.GET("/kanban_tasks/:kanban_id", app.TaskHandler.HandleGetTasksByKanbanID)
-	apiRouter.GET("/user/:user_id/tasks", app.TaskHandler.HandleGetTasksByUserID)
-	apiRouter.GET("/project_tasks/:project_id", app.TaskHandler.HandleGetTasksByProjectID)
 
 	// Comment Routes
 	commentHandlerRouterGroup := protectedApiRouter.Group("/comment")
 	{
 		commentHandlerRouterGroup.POST("/forTask", app.CommentHandler.HandleCreateCommentForTask)
-		commentHandlerRouterGroup.GET("/forTask/:task_id", app.CommentHandler.HandleGetCommentsByTaskID)
+		commentHandlerRouterGroup.GET("/forTask/:task_id", app.TaskKeyMiddleware.Middleware(), app.CommentHandler.HandleGetCommentsByTaskID)
+		commentHandlerRouterGroup.GET("/:comment_id/attachments", app.AttachmentHandler.HandleGetCommentAttachments)
+		commentHandlerRouterGroup.POST("/:comment_id/attachments", app.AttachmentHandler.HandleUploadCommentAttachment)
+	}
+
+	// Attachment Routes
+	attachmentHandlerRouterGroup := protectedApiRouter.Group("/attachment")
+	{
+		attachmentHandlerRouterGroup.GET("/:id", app.AttachmentHandler.HandleGetAttachmentByID)
+		attachmentHandlerRouterGroup.GET("/:id/download", app.AttachmentHandler.HandleDownloadAttachment)
+		attachmentHandlerRouterGroup.DELETE("/:id", app.AttachmentHandler.HandleDeleteAttachment)
 	}
 
 	// Project Routes
@@ -85,8 +185,31 @@ func Run(cfg *config.Config) error {
 		projectHandlerRouterGroup.GET("/:id", app.ProjectHandler.HandleGetProjectByID)
 		projectHandlerRouterGroup.PUT("/:id", app.ProjectHandler.HandleUpdateProject)
 		projectHandlerRouterGroup.DELETE("/:id", app.ProjectHandler.HandleDeleteProject)
+		projectHandlerRouterGroup.GET("/:id/settings", app.ProjectHandler.HandleGetProjectSettings)
+		projectHandlerRouterGroup.PUT("/:id/settings", app.ProjectHandler.HandleUpdateProjectSettings)
+		projectHandlerRouterGroup.GET("/:id/estimate", app.ProjectHandler.HandleGetProjectEstimate)
+		projectHandlerRouterGroup.GET("/:id/board", app.BoardHandler.HandleGetBoard)
+		projectHandlerRouterGroup.GET("/trash", app.TrashHandler.HandleGetTrashedProjects)
+		projectHandlerRouterGroup.GET("/:id/trash", app.TrashHandler.HandleGetProjectTrash)
+		projectHandlerRouterGroup.POST("/:id/restore", app.TrashHandler.HandleRestoreProject)
+		projectHandlerRouterGroup.POST("/:id/clone", app.CloneHandler.HandleCloneProject)
+		projectHandlerRouterGroup.GET("/:id/views", app.SavedViewHandler.HandleGetSavedViews)
+		projectHandlerRouterGroup.POST("/:id/views", app.SavedViewHandler.HandleCreateSavedView)
+		projectHandlerRouterGroup.POST("/:id/views/reorder", app.SavedViewHandler.HandleReorderSavedViews)
+		projectHandlerRouterGroup.GET("/:id/views/:view_id", app.SavedViewHandler.HandleGetSavedView)
+		projectHandlerRouterGroup.PUT("/:id/views/:view_id", app.SavedViewHandler.HandleUpdateSavedView)
+		projectHandlerRouterGroup.DELETE("/:id/views/:view_id", app.SavedViewHandler.HandleDeleteSavedView)
+		projectHandlerRouterGroup.GET("/:id/views/:view_id/tasks", app.SavedViewHandler.HandleGetSavedViewTasks)
+		projectHandlerRouterGroup.GET("/:id/workflow", app.WorkflowHandler.HandleGetWorkflow)
+		projectHandlerRouterGroup.POST("/:id/workflow/statuses", app.WorkflowHandler.HandleCreateWorkflowStatus)
+		projectHandlerRouterGroup.POST("/:id/workflow/statuses/reorder", app.WorkflowHandler.HandleReorderWorkflowStatuses)
+		projectHandlerRouterGroup.PUT("/:id/workflow/statuses/:status_id", app.WorkflowHandler.HandleUpdateWorkflowStatus)
+		projectHandlerRouterGroup.DELETE("/:id/workflow/statuses/:status_id", app.WorkflowHandler.HandleDeleteWorkflowStatus)
+		projectHandlerRouterGroup.PUT("/:id/workflow/transitions", app.WorkflowHandler.HandleSetWorkflowTransitions)
 	}
 
+	protectedApiRouter.GET("/clone_job/:id", app.CloneHandler.HandleGetCloneJob)
+
 	projectUsersHandlerRouterGroup := protectedApiRouter.Group("/project_users")
 	{
 		projectUsersHandlerRouterGroup.POST("/:project_id/invite", app.ProjectHandler.HandleInviteUserToProject)
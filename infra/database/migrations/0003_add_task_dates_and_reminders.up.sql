@@ -0,0 +1,22 @@
+BEGIN;
+
+ALTER TABLE task
+    ADD COLUMN start_at  TIMESTAMPTZ DEFAULT NULL,
+    ADD COLUMN due_at    TIMESTAMPTZ DEFAULT NULL,
+    ADD COLUMN time_zone VARCHAR(64) NOT NULL DEFAULT 'UTC';
+
+CREATE INDEX idx_task_due_at ON task (due_at) WHERE due_at IS NOT NULL;
+
+-- Отправленные напоминания. Уникальный ключ гарантирует, что одно напоминание
+-- будет отправлено только одним экземпляром приложения.
+CREATE TABLE task_reminders
+(
+    task_id        INTEGER REFERENCES task (id) ON DELETE CASCADE,
+    user_id        INTEGER REFERENCES users (id) ON DELETE CASCADE,
+    offset_minutes INTEGER     NOT NULL,
+    due_at         TIMESTAMPTZ NOT NULL,
+    sent_at        TIMESTAMPTZ DEFAULT NOW(),
+    PRIMARY KEY (task_id, user_id, offset_minutes, due_at)
+);
+
+COMMIT;
@@ -0,0 +1,33 @@
+BEGIN;
+
+ALTER TABLE task
+    ADD COLUMN priority VARCHAR(16) NOT NULL DEFAULT 'none'
+        CHECK (priority IN ('none', 'low', 'medium', 'high', 'urgent'));
+
+CREATE TABLE labels
+(
+    id         SERIAL PRIMARY KEY,
+    project_id INTEGER      NOT NULL REFERENCES projects (id) ON DELETE CASCADE,
+    name       VARCHAR(255) NOT NULL,
+    color      VARCHAR(16)  NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    UNIQUE (project_id, name)
+);
+
+CREATE TABLE task_labels
+(
+    task_id  INTEGER REFERENCES task (id) ON DELETE CASCADE,
+    label_id INTEGER REFERENCES labels (id) ON DELETE CASCADE,
+    PRIMARY KEY (task_id, label_id)
+);
+
+CREATE INDEX idx_task_labels_label_id ON task_labels (label_id);
+
+CREATE TRIGGER set_updated_at_labels
+    BEFORE UPDATE
+    ON labels
+    FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
+
+COMMIT;
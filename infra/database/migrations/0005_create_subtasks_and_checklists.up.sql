@@ -0,0 +1,28 @@
+BEGIN;
+
+ALTER TABLE task
+    ADD COLUMN parent_task_id INTEGER DEFAULT NULL REFERENCES task (id) ON DELETE CASCADE;
+
+CREATE INDEX idx_task_parent_task_id ON task (parent_task_id);
+
+CREATE TABLE checklist_items
+(
+    id          SERIAL PRIMARY KEY,
+    task_id     INTEGER NOT NULL REFERENCES task (id) ON DELETE CASCADE,
+    text        TEXT    NOT NULL,
+    is_done     BOOLEAN   DEFAULT FALSE,
+    position    INTEGER NOT NULL,
+    assignee_id INTEGER   DEFAULT NULL REFERENCES users (id) ON DELETE SET NULL,
+    created_at  TIMESTAMP DEFAULT NOW(),
+    updated_at  TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_checklist_items_task_id ON checklist_items (task_id, position);
+
+CREATE TRIGGER set_updated_at_checklist_items
+    BEFORE UPDATE
+    ON checklist_items
+    FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
+
+COMMIT;
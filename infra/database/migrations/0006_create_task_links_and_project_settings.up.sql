@@ -0,0 +1,32 @@
+BEGIN;
+
+CREATE TABLE project_settings
+(
+    project_id                INTEGER PRIMARY KEY REFERENCES projects (id) ON DELETE CASCADE,
+    blocked_completion_policy VARCHAR(16) NOT NULL DEFAULT 'warn'
+        CHECK (blocked_completion_policy IN ('warn', 'fail')),
+    updated_at                TIMESTAMP            DEFAULT NOW()
+);
+
+CREATE TRIGGER set_updated_at_project_settings
+    BEFORE UPDATE
+    ON project_settings
+    FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
+
+-- "blocked by" is stored as the reversed "blocks" edge.
+CREATE TABLE task_links
+(
+    id             SERIAL PRIMARY KEY,
+    source_task_id INTEGER     NOT NULL REFERENCES task (id) ON DELETE CASCADE,
+    target_task_id INTEGER     NOT NULL REFERENCES task (id) ON DELETE CASCADE,
+    type           VARCHAR(16) NOT NULL CHECK (type IN ('blocks', 'relates_to', 'duplicates')),
+    created_by     INTEGER   DEFAULT NULL REFERENCES users (id) ON DELETE SET NULL,
+    created_at     TIMESTAMP DEFAULT NOW(),
+    CHECK (source_task_id <> target_task_id),
+    UNIQUE (source_task_id, target_task_id, type)
+);
+
+CREATE INDEX idx_task_links_target_task_id ON task_links (target_task_id);
+
+COMMIT;
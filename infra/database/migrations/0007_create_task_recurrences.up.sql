@@ -0,0 +1,29 @@
+BEGIN;
+
+CREATE TABLE task_recurrences
+(
+    id                 SERIAL PRIMARY KEY,
+    rrule              TEXT        NOT NULL,
+    dtstart            TIMESTAMPTZ NOT NULL,
+    time_zone          VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    ends_at            TIMESTAMPTZ          DEFAULT NULL, -- Set when the series was split; exclusive
+    last_occurrence_at TIMESTAMPTZ NOT NULL,              -- Latest occurrence generated so far
+    created_by         INTEGER              DEFAULT NULL REFERENCES users (id) ON DELETE SET NULL,
+    created_at         TIMESTAMP            DEFAULT NOW(),
+    updated_at         TIMESTAMP            DEFAULT NOW()
+);
+
+CREATE TRIGGER set_updated_at_task_recurrences
+    BEFORE UPDATE
+    ON task_recurrences
+    FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
+
+ALTER TABLE task
+    ADD COLUMN recurrence_id INTEGER     DEFAULT NULL REFERENCES task_recurrences (id) ON DELETE SET NULL,
+    ADD COLUMN occurrence_at TIMESTAMPTZ DEFAULT NULL;
+
+-- One task per slot of a series, so concurrent generators cannot duplicate occurrences.
+CREATE UNIQUE INDEX idx_task_recurrence_occurrence ON task (recurrence_id, occurrence_at);
+
+COMMIT;
@@ -0,0 +1,28 @@
+BEGIN;
+
+-- A running timer is a worklog without ended_at.
+CREATE TABLE worklogs
+(
+    id         SERIAL PRIMARY KEY,
+    task_id    INTEGER     NOT NULL REFERENCES task (id) ON DELETE CASCADE,
+    user_id    INTEGER     NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    started_at TIMESTAMPTZ NOT NULL,
+    ended_at   TIMESTAMPTZ          DEFAULT NULL,
+    note       TEXT        NOT NULL DEFAULT '',
+    source     VARCHAR(16) NOT NULL CHECK (source IN ('timer', 'manual')),
+    created_at TIMESTAMP            DEFAULT NOW(),
+    updated_at TIMESTAMP            DEFAULT NOW(),
+    CHECK (ended_at IS NULL OR ended_at >= started_at)
+);
+
+CREATE UNIQUE INDEX idx_worklogs_running_timer ON worklogs (user_id) WHERE ended_at IS NULL;
+CREATE INDEX idx_worklogs_task_id ON worklogs (task_id, started_at);
+CREATE INDEX idx_worklogs_user_id ON worklogs (user_id, started_at);
+
+CREATE TRIGGER set_updated_at_worklogs
+    BEFORE UPDATE
+    ON worklogs
+    FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
+
+COMMIT;
@@ -0,0 +1,11 @@
+BEGIN;
+
+-- The estimate is in the unit chosen in the project settings: hours or story points.
+ALTER TABLE task
+    ADD COLUMN estimate NUMERIC(10, 2) DEFAULT NULL CHECK (estimate >= 0);
+
+ALTER TABLE project_settings
+    ADD COLUMN estimate_unit VARCHAR(16) NOT NULL DEFAULT 'hours'
+        CHECK (estimate_unit IN ('hours', 'story_points'));
+
+COMMIT;
@@ -0,0 +1,40 @@
+BEGIN;
+
+CREATE TABLE custom_fields
+(
+    id         SERIAL PRIMARY KEY,
+    project_id INTEGER      NOT NULL REFERENCES projects (id) ON DELETE CASCADE,
+    name       VARCHAR(255) NOT NULL,
+    type       VARCHAR(16)  NOT NULL
+        CHECK (type IN ('text', 'number', 'date', 'single_select', 'multi_select', 'user', 'url')),
+    options    TEXT[]       NOT NULL DEFAULT '{}', -- Choices of the select types
+    position   INTEGER      NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    UNIQUE (project_id, name)
+);
+
+CREATE TRIGGER set_updated_at_custom_fields
+    BEFORE UPDATE
+    ON custom_fields
+    FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
+
+-- One typed column per kind of value keeps filtering and sorting on plain SQL comparisons.
+-- text, url and single_select use text_value, multi_select uses options_value.
+CREATE TABLE task_custom_field_values
+(
+    task_id       INTEGER NOT NULL REFERENCES task (id) ON DELETE CASCADE,
+    field_id      INTEGER NOT NULL REFERENCES custom_fields (id) ON DELETE CASCADE,
+    text_value    TEXT             DEFAULT NULL,
+    number_value  DOUBLE PRECISION DEFAULT NULL,
+    date_value    DATE             DEFAULT NULL,
+    options_value TEXT[]           DEFAULT NULL,
+    user_id       INTEGER          DEFAULT NULL REFERENCES users (id) ON DELETE CASCADE,
+    updated_at    TIMESTAMP        DEFAULT NOW(),
+    PRIMARY KEY (task_id, field_id)
+);
+
+CREATE INDEX idx_task_custom_field_values_field_id ON task_custom_field_values (field_id);
+
+COMMIT;
@@ -0,0 +1,45 @@
+BEGIN;
+
+-- Attachments of a comment carry the comment's task as well, so access checks and the
+-- cascade from task work the same for both.
+CREATE TABLE attachments
+(
+    id           SERIAL PRIMARY KEY,
+    task_id      INTEGER      NOT NULL REFERENCES task (id) ON DELETE CASCADE,
+    comment_id   INTEGER   DEFAULT NULL REFERENCES comment (id) ON DELETE CASCADE,
+    file_name    VARCHAR(255) NOT NULL,
+    content_type VARCHAR(255) NOT NULL,
+    size         BIGINT       NOT NULL CHECK (size >= 0),
+    storage_key  VARCHAR(512) NOT NULL UNIQUE,
+    uploaded_by  INTEGER   DEFAULT NULL REFERENCES users (id) ON DELETE SET NULL,
+    created_at   TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_attachments_task_id ON attachments (task_id);
+CREATE INDEX idx_attachments_comment_id ON attachments (comment_id);
+
+-- Blobs cannot be removed by the database. Every deleted attachment row, also one removed by
+-- a cascade, queues its blob here for the cleanup worker.
+CREATE TABLE attachment_blob_deletions
+(
+    storage_key VARCHAR(512) PRIMARY KEY,
+    queued_at   TIMESTAMP DEFAULT NOW()
+);
+
+CREATE OR REPLACE FUNCTION queue_attachment_blob_deletion()
+    RETURNS TRIGGER AS
+$$
+BEGIN
+    INSERT INTO attachment_blob_deletions (storage_key) VALUES (OLD.storage_key)
+    ON CONFLICT DO NOTHING;
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER queue_attachment_blob_deletion
+    AFTER DELETE
+    ON attachments
+    FOR EACH ROW
+EXECUTE FUNCTION queue_attachment_blob_deletion();
+
+COMMIT;
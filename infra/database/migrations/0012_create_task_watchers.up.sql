@@ -0,0 +1,15 @@
+BEGIN;
+
+-- Watchers are notified about task changes without being assigned to the task.
+CREATE TABLE task_watchers
+(
+    task_id    INTEGER NOT NULL REFERENCES task (id) ON DELETE CASCADE,
+    user_id    INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT NOW(),
+    PRIMARY KEY (task_id, user_id)
+);
+
+CREATE INDEX idx_task_watchers_user_id ON task_watchers (user_id);
+CREATE INDEX idx_task_users_user_id ON task_users (user_id);
+
+COMMIT;
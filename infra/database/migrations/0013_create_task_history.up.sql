@@ -0,0 +1,31 @@
+BEGIN;
+
+-- Field level change log of tasks. Rows are only ever inserted; they go away with their task.
+CREATE TABLE task_history
+(
+    id         BIGSERIAL PRIMARY KEY,
+    task_id    INTEGER     NOT NULL REFERENCES task (id) ON DELETE CASCADE,
+    actor_id   INTEGER   DEFAULT NULL REFERENCES users (id) ON DELETE SET NULL,
+    field      VARCHAR(64) NOT NULL,
+    old_value  JSONB     DEFAULT NULL,
+    new_value  JSONB     DEFAULT NULL,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_task_history_task_id ON task_history (task_id, created_at);
+
+CREATE OR REPLACE FUNCTION reject_task_history_update()
+    RETURNS TRIGGER AS
+$$
+BEGIN
+    RAISE EXCEPTION 'task_history is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER reject_task_history_update
+    BEFORE UPDATE
+    ON task_history
+    FOR EACH ROW
+EXECUTE FUNCTION reject_task_history_update();
+
+COMMIT;
@@ -0,0 +1,16 @@
+BEGIN;
+
+-- Deleted projects, boards and tasks stay in the trash until the retention job purges them.
+-- Rows trashed together (a board with its tasks, say) share the same deleted_at value.
+ALTER TABLE projects
+    ADD COLUMN deleted_at TIMESTAMP DEFAULT NULL;
+ALTER TABLE kanban
+    ADD COLUMN deleted_at TIMESTAMP DEFAULT NULL;
+ALTER TABLE task
+    ADD COLUMN deleted_at TIMESTAMP DEFAULT NULL;
+
+CREATE INDEX idx_projects_deleted_at ON projects (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_kanban_deleted_at ON kanban (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_task_deleted_at ON task (deleted_at) WHERE deleted_at IS NOT NULL;
+
+COMMIT;
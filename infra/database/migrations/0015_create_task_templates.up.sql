@@ -0,0 +1,29 @@
+BEGIN;
+
+-- Blueprints for new tasks. Templates of a project are available in its subprojects as well.
+-- Label and assignee IDs are checked when the template is saved; ones that no longer apply
+-- are skipped when a task is created from it.
+CREATE TABLE task_templates
+(
+    id            SERIAL PRIMARY KEY,
+    project_id    INTEGER      NOT NULL REFERENCES projects (id) ON DELETE CASCADE,
+    name          VARCHAR(255) NOT NULL,
+    title_pattern VARCHAR(255) NOT NULL,
+    description   TEXT         NOT NULL DEFAULT '',
+    priority      VARCHAR(16)  NOT NULL DEFAULT 'none'
+        CHECK (priority IN ('none', 'low', 'medium', 'high', 'urgent')),
+    label_ids     INTEGER[]    NOT NULL DEFAULT '{}',
+    checklist     TEXT[]       NOT NULL DEFAULT '{}',
+    assignee_ids  INTEGER[]    NOT NULL DEFAULT '{}',
+    created_at    TIMESTAMP DEFAULT NOW(),
+    updated_at    TIMESTAMP DEFAULT NOW(),
+    UNIQUE (project_id, name)
+);
+
+CREATE TRIGGER set_updated_at_task_templates
+    BEFORE UPDATE
+    ON task_templates
+    FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
+
+COMMIT;
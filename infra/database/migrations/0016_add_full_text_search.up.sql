@@ -0,0 +1,24 @@
+BEGIN;
+
+-- Search vectors use the 'simple' configuration: titles and comments mix languages, so words
+-- are lowercased but not stemmed. Titles and names weigh more than descriptions.
+ALTER TABLE task
+    ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple', COALESCE(title, '')), 'A') ||
+        setweight(to_tsvector('simple', COALESCE(description, '')), 'B')
+        ) STORED;
+ALTER TABLE comment
+    ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+        to_tsvector('simple', COALESCE(text, ''))
+        ) STORED;
+ALTER TABLE projects
+    ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple', COALESCE(name, '')), 'A') ||
+        setweight(to_tsvector('simple', COALESCE(description, '')), 'B')
+        ) STORED;
+
+CREATE INDEX idx_task_search_vector ON task USING GIN (search_vector);
+CREATE INDEX idx_comment_search_vector ON comment USING GIN (search_vector);
+CREATE INDEX idx_projects_search_vector ON projects USING GIN (search_vector);
+
+COMMIT;
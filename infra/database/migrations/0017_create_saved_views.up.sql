@@ -0,0 +1,38 @@
+BEGIN;
+
+-- Named task lists of a project. A view is personal to its owner until it is shared with
+-- the project. The filter and sort use the syntax of the task list query parameters.
+CREATE TABLE saved_views
+(
+    id         SERIAL PRIMARY KEY,
+    project_id INTEGER      NOT NULL REFERENCES projects (id) ON DELETE CASCADE,
+    owner_id   INTEGER      NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    name       VARCHAR(255) NOT NULL,
+    filter     TEXT         NOT NULL DEFAULT '',
+    sort       VARCHAR(255) NOT NULL DEFAULT '',
+    group_by   VARCHAR(16)  NOT NULL DEFAULT ''
+        CHECK (group_by IN ('', 'column', 'assignee', 'label', 'priority')),
+    fields     TEXT[]       NOT NULL DEFAULT '{}',
+    shared     BOOLEAN      NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_saved_views_project_id ON saved_views (project_id);
+
+CREATE TRIGGER set_updated_at_saved_views
+    BEFORE UPDATE
+    ON saved_views
+    FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
+
+-- Every user orders the views they see on their own; views without a position come last.
+CREATE TABLE saved_view_positions
+(
+    user_id  INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    view_id  INTEGER NOT NULL REFERENCES saved_views (id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    PRIMARY KEY (user_id, view_id)
+);
+
+COMMIT;
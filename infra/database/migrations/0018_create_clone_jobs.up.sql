@@ -0,0 +1,25 @@
+BEGIN;
+
+-- Clones too large to run within a request. A worker picks up queued jobs and copies everything
+-- in one transaction, reporting progress outside of it.
+CREATE TABLE clone_jobs
+(
+    id          SERIAL PRIMARY KEY,
+    user_id     INTEGER     NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    type        VARCHAR(16) NOT NULL CHECK (type IN ('task', 'kanban', 'project')),
+    source_id   INTEGER     NOT NULL,
+    options     JSONB       NOT NULL DEFAULT '{}',
+    status      VARCHAR(16) NOT NULL DEFAULT 'queued'
+        CHECK (status IN ('queued', 'running', 'completed', 'failed')),
+    total       INTEGER     NOT NULL DEFAULT 0, -- Tasks to copy
+    done        INTEGER     NOT NULL DEFAULT 0, -- Tasks copied so far
+    result_id   INTEGER              DEFAULT NULL,
+    error       TEXT                 DEFAULT NULL,
+    created_at  TIMESTAMPTZ          DEFAULT NOW(),
+    started_at  TIMESTAMPTZ          DEFAULT NULL,
+    finished_at TIMESTAMPTZ          DEFAULT NULL
+);
+
+CREATE INDEX idx_clone_jobs_status ON clone_jobs (status, id) WHERE status IN ('queued', 'running');
+
+COMMIT;
@@ -0,0 +1,87 @@
+BEGIN;
+
+-- The statuses a task of a project goes through. The category tells open from finished work:
+-- a task is completed exactly when its status is in the done category.
+CREATE TABLE workflow_statuses
+(
+    id         SERIAL PRIMARY KEY,
+    project_id INTEGER      NOT NULL REFERENCES projects (id) ON DELETE CASCADE,
+    name       VARCHAR(255) NOT NULL,
+    category   VARCHAR(16)  NOT NULL CHECK (category IN ('todo', 'in_progress', 'done')),
+    position   INTEGER      NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    UNIQUE (project_id, name)
+);
+
+CREATE TRIGGER set_updated_at_workflow_statuses
+    BEFORE UPDATE
+    ON workflow_statuses
+    FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
+
+-- Allowed status changes. A project without transitions allows every change.
+CREATE TABLE workflow_transitions
+(
+    from_status_id INTEGER NOT NULL REFERENCES workflow_statuses (id) ON DELETE CASCADE,
+    to_status_id   INTEGER NOT NULL REFERENCES workflow_statuses (id) ON DELETE CASCADE,
+    CHECK (from_status_id <> to_status_id),
+    PRIMARY KEY (from_status_id, to_status_id)
+);
+
+-- Tasks moved onto a board mapped to a status take that status.
+ALTER TABLE kanban
+    ADD COLUMN status_id INTEGER DEFAULT NULL REFERENCES workflow_statuses (id) ON DELETE SET NULL;
+
+ALTER TABLE task
+    ADD COLUMN status_id INTEGER DEFAULT NULL REFERENCES workflow_statuses (id);
+
+CREATE INDEX idx_task_status_id ON task (status_id);
+
+INSERT INTO workflow_statuses (project_id, name, category, position)
+SELECT p.id, d.name, d.category, d.position
+FROM projects p
+CROSS JOIN (VALUES ('To Do', 'todo', 0), ('In Progress', 'in_progress', 1), ('Done', 'done', 2))
+    AS d (name, category, position);
+
+UPDATE task t
+SET status_id = ws.id
+FROM kanban k, workflow_statuses ws
+WHERE k.id = t.kanban_id
+  AND ws.project_id = k.project_id
+  AND ws.category = CASE WHEN COALESCE(t.is_completed, FALSE) THEN 'done' ELSE 'todo' END;
+
+-- Keeps is_completed derived from the status. Tasks written without a status get the first
+-- status agreeing with their is_completed, preferring the status their board is mapped to.
+CREATE OR REPLACE FUNCTION set_task_status()
+    RETURNS TRIGGER AS
+$$
+BEGIN
+    IF NEW.status_id IS NULL THEN
+        SELECT ws.id
+        INTO NEW.status_id
+        FROM kanban k
+        JOIN workflow_statuses ws ON ws.project_id = k.project_id
+        WHERE k.id = NEW.kanban_id
+        ORDER BY (ws.category = 'done') <> COALESCE(NEW.is_completed, FALSE),
+                 ws.id IS DISTINCT FROM k.status_id,
+                 ws.category <> 'todo',
+                 ws.position,
+                 ws.id
+        LIMIT 1;
+    END IF;
+
+    IF NEW.status_id IS NOT NULL THEN
+        SELECT ws.category = 'done' INTO NEW.is_completed FROM workflow_statuses ws WHERE ws.id = NEW.status_id;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER set_task_status
+    BEFORE INSERT OR UPDATE OF status_id, is_completed, kanban_id
+    ON task
+    FOR EACH ROW
+EXECUTE FUNCTION set_task_status();
+
+COMMIT;
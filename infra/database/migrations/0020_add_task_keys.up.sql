@@ -0,0 +1,129 @@
+BEGIN;
+
+-- Short project keys such as DT, used in task keys such as DT-123.
+ALTER TABLE projects
+    ADD COLUMN key VARCHAR(10) DEFAULT NULL UNIQUE CHECK (key ~ '^[A-Z][A-Z0-9]{1,9}$');
+
+-- project_key_base derives a key from a project name: the initials of up to four words, or the
+-- first three letters of a single word.
+CREATE OR REPLACE FUNCTION project_key_base(project_name TEXT)
+    RETURNS TEXT AS
+$$
+DECLARE
+    words TEXT[] := ARRAY(
+        SELECT w FROM regexp_split_to_table(upper(project_name), '[^A-Z0-9]+') AS w WHERE w ~ '^[A-Z]'
+    );
+    base  TEXT;
+BEGIN
+    IF cardinality(words) >= 2 THEN
+        SELECT string_agg(left(w, 1), '' ORDER BY n) INTO base FROM unnest(words[1:4]) WITH ORDINALITY AS u (w, n);
+    ELSIF cardinality(words) = 1 THEN
+        base := left(words[1], 3);
+    END IF;
+
+    IF base IS NULL OR length(base) < 2 THEN
+        base := 'PRJ';
+    END IF;
+    RETURN base;
+END;
+$$ LANGUAGE plpgsql IMMUTABLE;
+
+-- free_project_key returns base, or base followed by the lowest number making it unused.
+CREATE OR REPLACE FUNCTION free_project_key(base TEXT)
+    RETURNS TEXT AS
+$$
+DECLARE
+    candidate TEXT    := base;
+    n         INTEGER := 1;
+BEGIN
+    WHILE EXISTS (SELECT 1 FROM projects WHERE key = candidate) LOOP
+        n := n + 1;
+        candidate := base || n;
+    END LOOP;
+    RETURN candidate;
+END;
+$$ LANGUAGE plpgsql;
+
+DO
+$$
+DECLARE
+    p RECORD;
+BEGIN
+    FOR p IN SELECT id, name FROM projects ORDER BY id LOOP
+        UPDATE projects SET key = free_project_key(project_key_base(p.name)) WHERE id = p.id;
+    END LOOP;
+END;
+$$;
+
+ALTER TABLE projects
+    ALTER COLUMN key SET NOT NULL;
+
+-- Projects created without a key, including copies, get one derived from their name.
+CREATE OR REPLACE FUNCTION set_project_key()
+    RETURNS TRIGGER AS
+$$
+BEGIN
+    IF NEW.key IS NULL THEN
+        NEW.key := free_project_key(project_key_base(NEW.name));
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER set_project_key
+    BEFORE INSERT
+    ON projects
+    FOR EACH ROW
+EXECUTE FUNCTION set_project_key();
+
+-- The last task number handed out per project. Tasks cannot move between projects, so a number
+-- is never reused within a project.
+CREATE TABLE project_task_counters
+(
+    project_id  INTEGER PRIMARY KEY REFERENCES projects (id) ON DELETE CASCADE,
+    last_number INTEGER NOT NULL
+);
+
+ALTER TABLE task
+    ADD COLUMN number INTEGER DEFAULT NULL;
+
+UPDATE task t
+SET number = numbered.number
+FROM (
+    SELECT t.id, ROW_NUMBER() OVER (PARTITION BY k.project_id ORDER BY t.id) AS number
+    FROM task t
+    JOIN kanban k ON k.id = t.kanban_id
+) AS numbered
+WHERE t.id = numbered.id;
+
+INSERT INTO project_task_counters (project_id, last_number)
+SELECT k.project_id, MAX(t.number)
+FROM task t
+JOIN kanban k ON k.id = t.kanban_id
+GROUP BY k.project_id;
+
+CREATE INDEX idx_task_number ON task (number);
+
+-- The counter row is locked until the inserting transaction ends, so concurrent inserts into
+-- one project get consecutive numbers.
+CREATE OR REPLACE FUNCTION set_task_number()
+    RETURNS TRIGGER AS
+$$
+BEGIN
+    IF NEW.number IS NULL THEN
+        INSERT INTO project_task_counters AS c (project_id, last_number)
+        SELECT k.project_id, 1 FROM kanban k WHERE k.id = NEW.kanban_id
+        ON CONFLICT (project_id) DO UPDATE SET last_number = c.last_number + 1
+        RETURNING c.last_number INTO NEW.number;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER set_task_number
+    BEFORE INSERT
+    ON task
+    FOR EACH ROW
+EXECUTE FUNCTION set_task_number();
+
+COMMIT;
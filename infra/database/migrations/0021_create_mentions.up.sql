@@ -0,0 +1,20 @@
+BEGIN;
+
+-- Users mentioned in a task description (comment_id NULL) or in a comment on the task. The
+-- handle is the text after the @ as written, so the mention still points to the same user after
+-- their email address changes.
+CREATE TABLE mentions
+(
+    id         SERIAL PRIMARY KEY,
+    task_id    INTEGER      NOT NULL REFERENCES task (id) ON DELETE CASCADE,
+    comment_id INTEGER      REFERENCES comment (id) ON DELETE CASCADE,
+    user_id    INTEGER      NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    handle     VARCHAR(320) NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_mentions_task_handle ON mentions (task_id, handle) WHERE comment_id IS NULL;
+CREATE UNIQUE INDEX idx_mentions_comment_handle ON mentions (comment_id, handle) WHERE comment_id IS NOT NULL;
+CREATE INDEX idx_mentions_user_id ON mentions (user_id);
+
+COMMIT;
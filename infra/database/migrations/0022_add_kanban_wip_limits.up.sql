@@ -0,0 +1,12 @@
+BEGIN;
+
+-- The most tasks a column should hold. NULL leaves the column unlimited.
+ALTER TABLE kanban
+    ADD COLUMN wip_limit INTEGER DEFAULT NULL CHECK (wip_limit > 0);
+
+-- What adding a task to a full column does: warn adds it anyway, fail rejects it.
+ALTER TABLE project_settings
+    ADD COLUMN wip_limit_policy VARCHAR(16) NOT NULL DEFAULT 'warn'
+        CHECK (wip_limit_policy IN ('warn', 'fail'));
+
+COMMIT;
@@ -0,0 +1,16 @@
+BEGIN;
+
+-- Kanbans are listed by position within their project. Existing kanbans keep their creation order.
+ALTER TABLE kanban
+    ADD COLUMN position INTEGER NOT NULL DEFAULT 0;
+
+UPDATE kanban k
+SET position = ordered.position
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY project_id ORDER BY id) - 1 AS position FROM kanban
+) ordered
+WHERE k.id = ordered.id;
+
+CREATE INDEX idx_kanban_project_position ON kanban (project_id, position);
+
+COMMIT;
@@ -41,6 +41,9 @@ func NewConfig(envFilePath, ymlFilePath, ymlFileName string) (*Config, error) {
 
 	_ = viper.BindEnv("jwt.JWT_SECRET", "JWT_SECRET")
 
+	_ = viper.BindEnv("attachments.s3.access_key", "S3_ACCESS_KEY")
+	_ = viper.BindEnv("attachments.s3.secret_key", "S3_SECRET_KEY")
+
 	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 	viper.AutomaticEnv()
 
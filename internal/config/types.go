@@ -1,5 +1,7 @@
 package config
 
+import "time"
+
 // Config interface
 type Config struct {
 	HTTP     HTTP
@@ -7,6 +9,15 @@ type Config struct {
 	RabbitMQ RabbitMQ
 	Swagger  Swagger
 	JWT      JWT
+
+	Notifications Notifications
+	Reminders     Reminders
+	Tasks         Tasks
+	Recurrence    Recurrence
+	Attachments   Attachments
+	Trash         Trash
+	Clone         Clone
+	Markdown      Markdown
 }
 
 type HTTP struct {
@@ -42,3 +53,57 @@ type RabbitMQ struct {
 type JWT struct {
 	Secret string `mapstructure:"jwt_secret"`
 }
+
+type Notifications struct {
+	Exchange string `mapstructure:"exchange"`
+}
+
+type Reminders struct {
+	Enabled  bool            `mapstructure:"enabled"`
+	Interval time.Duration   `mapstructure:"interval"`
+	Offsets  []time.Duration `mapstructure:"offsets"` // How long before due_at assignees are reminded
+}
+
+type Tasks struct {
+	// ParentCompletionPolicy decides what completing a task with open subtasks does: block, cascade or allow
+	ParentCompletionPolicy string `mapstructure:"parent_completion_policy"`
+}
+
+type Recurrence struct {
+	Enabled   bool          `mapstructure:"enabled"`
+	Interval  time.Duration `mapstructure:"interval"`
+	Lookahead time.Duration `mapstructure:"lookahead"` // How far ahead the scheduler creates occurrences
+}
+
+type Attachments struct {
+	MaxSize         int64         `mapstructure:"max_size"`      // Upload limit in bytes
+	AllowedTypes    []string      `mapstructure:"allowed_types"` // Media types detected from the content, "image/*" style wildcards allowed
+	Storage         string        `mapstructure:"storage"`       // Blob storage backend: local or s3
+	LocalDir        string        `mapstructure:"local_dir"`
+	S3              S3            `mapstructure:"s3"`
+	CleanupInterval time.Duration `mapstructure:"cleanup_interval"` // How often blobs of deleted attachments are removed
+}
+
+type Trash struct {
+	Retention     time.Duration `mapstructure:"retention"`      // How long deleted items are kept; zero keeps them forever
+	PurgeInterval time.Duration `mapstructure:"purge_interval"` // How often expired items are purged
+}
+
+type Clone struct {
+	AsyncThreshold int           `mapstructure:"async_threshold"` // Clones of more tasks run as background jobs
+	Interval       time.Duration `mapstructure:"interval"`        // How often queued clone jobs are picked up
+}
+
+type Markdown struct {
+	CacheSize  int    `mapstructure:"cache_size"`  // Rendered descriptions and comments kept in memory; zero disables the cache
+	MentionURL string `mapstructure:"mention_url"` // Link of a mentioned user, {id} stands for the user ID
+}
+
+type S3 struct {
+	Endpoint  string `mapstructure:"endpoint"`
+	Region    string `mapstructure:"region"`
+	Bucket    string `mapstructure:"bucket"`
+	AccessKey string `mapstructure:"access_key"`
+	SecretKey string `mapstructure:"secret_key"`
+	PathStyle bool   `mapstructure:"path_style"` // Address the bucket in the path, as MinIO expects
+}
@@ -0,0 +1,266 @@
+package assignee_handler
+
+import (
+	"DataTask/internal/controller/rest/rest_error"
+	"DataTask/internal/usecase/assignee_usecase"
+	"DataTask/pkg/http/response"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+type AssigneeHandler struct {
+	useCase assignee_usecase.AssigneeUseCase
+}
+
+func NewAssigneeHandler(useCase assignee_usecase.AssigneeUseCase) *AssigneeHandler {
+	return &AssigneeHandler{useCase: useCase}
+}
+
+type AddAssigneeRequestParam struct {
+	UserID int `json:"user_id" binding:"required" example:"2"`
+}
+
+type ReplaceAssigneesRequestParam struct {
+	UserIDs []int `json:"user_ids" binding:"required" example:"2,3"` // An empty list unassigns everyone
+}
+
+type AddWatcherRequestParam struct {
+	UserID int `json:"user_id" example:"2"` // Defaults to the current user
+}
+
+// HandleGetAssignees
+// @Summary Get Task Assignees
+// @Description Get the users assigned to the task
+// @Tags Assignee
+// @Produce json
+// @Param id path int true "Task ID"
+// @Success 200 {object} response.JSONResponse{data=[]dto.User}
+// @Failure 400 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 404 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /task/{id}/assignees [get]
+func (h *AssigneeHandler) HandleGetAssignees(ctx *gin.Context) {
+	taskID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Task ID")
+		return
+	}
+
+	assignees, err := h.useCase.GetAssignees(ctx, taskID)
+	if err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	response.JSON(ctx, http.StatusOK, true, assignees, "")
+}
+
+// HandleAddAssignee
+// @Summary Add Task Assignee
+// @Description Assign a project member to the task. Fails with 409 when the user is already assigned
+// @Tags Assignee
+// @Accept json
+// @Produce json
+// @Param task_id path int true "Task ID"
+// @Param request body AddAssigneeRequestParam true "User to assign"
+// @Success 201 {object} response.JSONResponse{data=[]dto.User}
+// @Failure 400 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 404 {object} response.JSONResponse
+// @Failure 409 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /task/{task_id}/assignees [post]
+func (h *AssigneeHandler) HandleAddAssignee(ctx *gin.Context) {
+	taskID, err := strconv.Atoi(ctx.Param("task_id"))
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Task ID")
+		return
+	}
+
+	var param AddAssigneeRequestParam
+	if err := ctx.ShouldBindJSON(&param); err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, err.Error())
+		return
+	}
+
+	assignees, err := h.useCase.AddAssignee(ctx, taskID, param.UserID)
+	if err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	response.JSON(ctx, http.StatusCreated, true, assignees, "")
+}
+
+// HandleReplaceAssignees
+// @Summary Replace Task Assignees
+// @Description Replace the complete assignee list of the task. Every user must be a project member
+// @Tags Assignee
+// @Accept json
+// @Produce json
+// @Param id path int true "Task ID"
+// @Param request body ReplaceAssigneesRequestParam true "New assignees"
+// @Success 200 {object} response.JSONResponse{data=[]dto.User}
+// @Failure 400 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 404 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /task/{id}/assignees [put]
+func (h *AssigneeHandler) HandleReplaceAssignees(ctx *gin.Context) {
+	taskID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Task ID")
+		return
+	}
+
+	var param ReplaceAssigneesRequestParam
+	if err := ctx.ShouldBindJSON(&param); err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, err.Error())
+		return
+	}
+
+	assignees, err := h.useCase.ReplaceAssignees(ctx, taskID, param.UserIDs)
+	if err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	response.JSON(ctx, http.StatusOK, true, assignees, "")
+}
+
+// HandleRemoveAssignee
+// @Summary Remove Task Assignee
+// @Description Unassign a user from the task
+// @Tags Assignee
+// @Param id path int true "Task ID"
+// @Param user_id path int true "User ID"
+// @Success 204
+// @Failure 400 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 404 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /task/{id}/assignees/{user_id} [delete]
+func (h *AssigneeHandler) HandleRemoveAssignee(ctx *gin.Context) {
+	taskID, userID, ok := parseTaskUserPath(ctx)
+	if !ok {
+		return
+	}
+
+	if err := h.useCase.RemoveAssignee(ctx, taskID, userID); err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// HandleGetWatchers
+// @Summary Get Task Watchers
+// @Description Get the users notified about changes of the task without being assigned
+// @Tags Assignee
+// @Produce json
+// @Param id path int true "Task ID"
+// @Success 200 {object} response.JSONResponse{data=[]dto.User}
+// @Failure 400 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 404 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /task/{id}/watchers [get]
+func (h *AssigneeHandler) HandleGetWatchers(ctx *gin.Context) {
+	taskID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Task ID")
+		return
+	}
+
+	watchers, err := h.useCase.GetWatchers(ctx, taskID)
+	if err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	response.JSON(ctx, http.StatusOK, true, watchers, "")
+}
+
+// HandleAddWatcher
+// @Summary Watch Task
+// @Description Subscribe a project member, by default the current user, to changes of the task. Subscribing others requires edit permission
+// @Tags Assignee
+// @Accept json
+// @Produce json
+// @Param task_id path int true "Task ID"
+// @Param request body AddWatcherRequestParam false "User to subscribe"
+// @Success 201 {object} response.JSONResponse{data=[]dto.User}
+// @Failure 400 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 404 {object} response.JSONResponse
+// @Failure 409 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /task/{task_id}/watchers [post]
+func (h *AssigneeHandler) HandleAddWatcher(ctx *gin.Context) {
+	taskID, err := strconv.Atoi(ctx.Param("task_id"))
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Task ID")
+		return
+	}
+
+	var param AddWatcherRequestParam
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&param); err != nil {
+			response.JSON(ctx, http.StatusBadRequest, false, nil, err.Error())
+			return
+		}
+	}
+
+	watchers, err := h.useCase.AddWatcher(ctx, taskID, param.UserID)
+	if err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	response.JSON(ctx, http.StatusCreated, true, watchers, "")
+}
+
+// HandleRemoveWatcher
+// @Summary Unwatch Task
+// @Description Unsubscribe a user from changes of the task. Unsubscribing others requires edit permission
+// @Tags Assignee
+// @Param id path int true "Task ID"
+// @Param user_id path int true "User ID"
+// @Success 204
+// @Failure 400 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 404 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /task/{id}/watchers/{user_id} [delete]
+func (h *AssigneeHandler) HandleRemoveWatcher(ctx *gin.Context) {
+	taskID, userID, ok := parseTaskUserPath(ctx)
+	if !ok {
+		return
+	}
+
+	if err := h.useCase.RemoveWatcher(ctx, taskID, userID); err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// parseTaskUserPath reads the task and user IDs of /task/:id/.../:user_id routes and
+// writes the error response when one is invalid.
+func parseTaskUserPath(ctx *gin.Context) (int, int, bool) {
+	taskID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Task ID")
+		return 0, 0, false
+	}
+	userID, err := strconv.Atoi(ctx.Param("user_id"))
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid User ID")
+		return 0, 0, false
+	}
+	return taskID, userID, true
+}
@@ -0,0 +1,269 @@
+package attachment_handler
+
+import (
+	"DataTask/internal/controller/rest/rest_error"
+	"DataTask/internal/domain/dto"
+	"DataTask/internal/usecase/attachment_usecase"
+	"DataTask/pkg/http/response"
+	"DataTask/pkg/logger"
+	"errors"
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+)
+
+const (
+	fileFormField = "file"
+	// multipartOverhead leaves room for the multipart boundaries and headers around the file.
+	multipartOverhead = 1 << 20
+)
+
+type AttachmentHandler struct {
+	useCase       attachment_usecase.AttachmentUseCase
+	maxUploadSize int64
+}
+
+func NewAttachmentHandler(useCase attachment_usecase.AttachmentUseCase, maxUploadSize int64) *AttachmentHandler {
+	return &AttachmentHandler{useCase: useCase, maxUploadSize: maxUploadSize}
+}
+
+// HandleUploadTaskAttachment
+// @Summary Upload Task Attachment
+// @Description Upload a file to the task. The file type is detected from the content and checked against the allowed types
+// @Tags Attachment
+// @Accept multipart/form-data
+// @Produce json
+// @Param task_id path int true "Task ID"
+// @Param file formData file true "File to attach"
+// @Success 201 {object} response.JSONResponse{data=dto.Attachment}
+// @Failure 400 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 404 {object} response.JSONResponse
+// @Failure 413 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /task/{task_id}/attachments [post]
+func (h *AttachmentHandler) HandleUploadTaskAttachment(ctx *gin.Context) {
+	taskID, err := strconv.Atoi(ctx.Param("task_id"))
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Task ID")
+		return
+	}
+
+	h.upload(ctx, &dto.AttachmentUpload{TaskID: taskID})
+}
+
+// HandleUploadCommentAttachment
+// @Summary Upload Comment Attachment
+// @Description Upload a file to a comment. Only the comment author can attach files
+// @Tags Attachment
+// @Accept multipart/form-data
+// @Produce json
+// @Param comment_id path int true "Comment ID"
+// @Param file formData file true "File to attach"
+// @Success 201 {object} response.JSONResponse{data=dto.Attachment}
+// @Failure 400 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 404 {object} response.JSONResponse
+// @Failure 413 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /comment/{comment_id}/attachments [post]
+func (h *AttachmentHandler) HandleUploadCommentAttachment(ctx *gin.Context) {
+	commentID, err := strconv.Atoi(ctx.Param("comment_id"))
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Comment ID")
+		return
+	}
+
+	h.upload(ctx, &dto.AttachmentUpload{CommentID: &commentID})
+}
+
+func (h *AttachmentHandler) upload(ctx *gin.Context, upload *dto.AttachmentUpload) {
+	if h.maxUploadSize > 0 {
+		ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, h.maxUploadSize+multipartOverhead)
+	}
+
+	fileHeader, err := ctx.FormFile(fileFormField)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			response.JSON(ctx, http.StatusRequestEntityTooLarge, false, nil, "File is too large")
+			return
+		}
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "A file is required in the \"file\" form field")
+		return
+	}
+	if h.maxUploadSize > 0 && fileHeader.Size > h.maxUploadSize {
+		response.JSON(ctx, http.StatusRequestEntityTooLarge, false, nil, "File is too large")
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, err.Error())
+		return
+	}
+	defer file.Close()
+
+	upload.FileName = fileHeader.Filename
+	upload.Size = fileHeader.Size
+	upload.Content = file
+
+	attachment, err := h.useCase.UploadAttachment(ctx, upload)
+	if err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	response.JSON(ctx, http.StatusCreated, true, attachment, "")
+}
+
+// HandleGetTaskAttachments
+// @Summary Get Task Attachments
+// @Description Get the attachments of the task and of its comments
+// @Tags Attachment
+// @Produce json
+// @Param id path int true "Task ID"
+// @Success 200 {object} response.JSONResponse{data=[]dto.Attachment}
+// @Failure 400 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /task/{id}/attachments [get]
+func (h *AttachmentHandler) HandleGetTaskAttachments(ctx *gin.Context) {
+	taskID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Task ID")
+		return
+	}
+
+	attachments, err := h.useCase.GetAttachmentsByTaskID(ctx, taskID)
+	if err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	response.JSON(ctx, http.StatusOK, true, attachments, "")
+}
+
+// HandleGetCommentAttachments
+// @Summary Get Comment Attachments
+// @Description Get the attachments of a comment
+// @Tags Attachment
+// @Produce json
+// @Param comment_id path int true "Comment ID"
+// @Success 200 {object} response.JSONResponse{data=[]dto.Attachment}
+// @Failure 400 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 404 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /comment/{comment_id}/attachments [get]
+func (h *AttachmentHandler) HandleGetCommentAttachments(ctx *gin.Context) {
+	commentID, err := strconv.Atoi(ctx.Param("comment_id"))
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Comment ID")
+		return
+	}
+
+	attachments, err := h.useCase.GetAttachmentsByCommentID(ctx, commentID)
+	if err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	response.JSON(ctx, http.StatusOK, true, attachments, "")
+}
+
+// HandleGetAttachmentByID
+// @Summary Get Attachment
+// @Description Get attachment metadata by ID
+// @Tags Attachment
+// @Produce json
+// @Param id path int true "Attachment ID"
+// @Success 200 {object} response.JSONResponse{data=dto.Attachment}
+// @Failure 400 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 404 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /attachment/{id} [get]
+func (h *AttachmentHandler) HandleGetAttachmentByID(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Attachment ID")
+		return
+	}
+
+	attachment, err := h.useCase.GetAttachmentByID(ctx, id)
+	if err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	response.JSON(ctx, http.StatusOK, true, attachment, "")
+}
+
+// HandleDownloadAttachment
+// @Summary Download Attachment
+// @Description Download the attachment content. Requires read access to the task
+// @Tags Attachment
+// @Produce octet-stream
+// @Param id path int true "Attachment ID"
+// @Success 200 {file} file
+// @Failure 400 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 404 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /attachment/{id}/download [get]
+func (h *AttachmentHandler) HandleDownloadAttachment(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Attachment ID")
+		return
+	}
+
+	attachment, content, err := h.useCase.DownloadAttachment(ctx, id)
+	if err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+	defer content.Close()
+
+	// Always download instead of rendering, so uploaded HTML or SVG never runs in the app's origin.
+	header := ctx.Writer.Header()
+	header.Set("Content-Type", attachment.ContentType)
+	header.Set("Content-Length", strconv.FormatInt(attachment.Size, 10))
+	header.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": attachment.FileName}))
+	header.Set("X-Content-Type-Options", "nosniff")
+	ctx.Status(http.StatusOK)
+
+	if _, err := io.Copy(ctx.Writer, content); err != nil {
+		logger.Log.WithFields(log.Fields{"attachment_id": id}).WithError(err).Error("failed to stream attachment")
+	}
+}
+
+// HandleDeleteAttachment
+// @Summary Delete Attachment
+// @Description Delete an attachment. Uploaders can delete their own files, other files need the project owner
+// @Tags Attachment
+// @Param id path int true "Attachment ID"
+// @Success 204
+// @Failure 400 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 404 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /attachment/{id} [delete]
+func (h *AttachmentHandler) HandleDeleteAttachment(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Attachment ID")
+		return
+	}
+
+	if err := h.useCase.DeleteAttachment(ctx, id); err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
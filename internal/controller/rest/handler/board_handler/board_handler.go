@@ -0,0 +1,67 @@
+package board_handler
+
+import (
+	"DataTask/internal/controller/rest/rest_error"
+	"DataTask/internal/domain/dto"
+	"DataTask/internal/usecase/board_usecase"
+	"DataTask/internal/usecase/task_usecase"
+	"DataTask/pkg/http/response"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+type BoardHandler struct {
+	useCase board_usecase.BoardUseCase
+}
+
+func NewBoardHandler(useCase board_usecase.BoardUseCase) *BoardHandler {
+	return &BoardHandler{useCase: useCase}
+}
+
+// HandleGetBoard
+// @Summary Get Board
+// @Description Get the project's tasks split into swimlanes and, within each lane, into the kanban columns. Tasks with several assignees, labels or multi_select options are in the lane of each; tasks without a value are in the last lane.
+// @Tags Board
+// @Produce json
+// @Param id path int true "Project ID"
+// @Param swimlanes query string false "assignee, priority, label, epic or field.<id>; one lane when empty"
+// @Param empty_lanes query bool false "Also return lanes without tasks, such as unused labels or members without tasks"
+// @Param filter query string false "Filter expression, e.g. assignee:me AND is_completed:false AND updated>2025-05-01; see the README"
+// @Param sort query string false "Order of the tasks in a cell: comma-separated keys title, priority, due_at, created_at, updated_at, estimate or field.<id>; prefix with - to sort descending"
+// @Success 200 {object} response.JSONResponse{data=dto.Board}
+// @Failure 400 {object} response.JSONResponse
+// @Failure 401 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /project/{id}/board [get]
+func (h *BoardHandler) HandleGetBoard(ctx *gin.Context) {
+	projectID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Project ID")
+		return
+	}
+
+	query := dto.BoardQuery{
+		Swimlanes: ctx.Query("swimlanes"),
+		Filter:    &dto.TaskFilter{Query: ctx.Query("filter")},
+	}
+	if emptyLanes := ctx.Query("empty_lanes"); emptyLanes != "" {
+		if query.EmptyLanes, err = strconv.ParseBool(emptyLanes); err != nil {
+			response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid empty_lanes value")
+			return
+		}
+	}
+	if query.Filter.Sort, err = task_usecase.ParseTaskSort(ctx.Query("sort")); err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, err.Error())
+		return
+	}
+
+	board, err := h.useCase.GetBoard(ctx, projectID, &query)
+	if err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	response.JSON(ctx, http.StatusOK, true, board, "")
+}
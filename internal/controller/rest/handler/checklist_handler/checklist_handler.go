@@ -0,0 +1,229 @@
+package checklist_handler
+
+import (
+	"DataTask/internal/controller/rest/rest_error"
+	"DataTask/internal/domain/dto"
+	"DataTask/internal/usecase/checklist_usecase"
+	"DataTask/pkg/http/response"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+type ChecklistHandler struct {
+	useCase checklist_usecase.ChecklistUseCase
+}
+
+func NewChecklistHandler(useCase checklist_usecase.ChecklistUseCase) *ChecklistHandler {
+	return &ChecklistHandler{useCase: useCase}
+}
+
+type CreateChecklistItemRequestParam struct {
+	Text       string `json:"text" binding:"required"`
+	IsDone     bool   `json:"is_done"`
+	AssigneeID *int   `json:"assignee_id"` // Must be a member of the task's project
+}
+
+type UpdateChecklistItemRequestParam struct {
+	Text          *string `json:"text"`
+	IsDone        *bool   `json:"is_done"`
+	AssigneeID    *int    `json:"assignee_id"`
+	ClearAssignee bool    `json:"clear_assignee"`
+}
+
+type ReorderChecklistRequestParam struct {
+	ItemIDs []int `json:"item_ids" binding:"required"` // Every item of the checklist in the new order
+}
+
+// HandleCreateChecklistItem
+// @Summary Create Checklist Item
+// @Description Append an item to the task's checklist
+// @Tags Checklist
+// @Accept json
+// @Produce json
+// @Param task_id path int true "Task ID"
+// @Param request body CreateChecklistItemRequestParam true "Checklist item data"
+// @Success 201 {object} response.JSONResponse{data=dto.ChecklistItem}
+// @Failure 400 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /task/{task_id}/checklist [post]
+func (h *ChecklistHandler) HandleCreateChecklistItem(ctx *gin.Context) {
+	taskIDStr := ctx.Param("task_id")
+	taskID, err := strconv.Atoi(taskIDStr)
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Task ID")
+		return
+	}
+
+	var param CreateChecklistItemRequestParam
+	if err := ctx.ShouldBindJSON(&param); err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, err.Error())
+		return
+	}
+
+	item := dto.ChecklistItem{
+		TaskID:     taskID,
+		Text:       param.Text,
+		IsDone:     param.IsDone,
+		AssigneeID: param.AssigneeID,
+	}
+
+	createdItem, err := h.useCase.CreateItem(ctx, &item)
+	if err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	response.JSON(ctx, http.StatusCreated, true, createdItem, "")
+}
+
+// HandleGetChecklist
+// @Summary Get Checklist
+// @Description Get the checklist items of a task in order
+// @Tags Checklist
+// @Produce json
+// @Param id path int true "Task ID"
+// @Success 200 {object} response.JSONResponse{data=[]dto.ChecklistItem}
+// @Failure 400 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /task/{id}/checklist [get]
+func (h *ChecklistHandler) HandleGetChecklist(ctx *gin.Context) {
+	taskIDStr := ctx.Param("id")
+	taskID, err := strconv.Atoi(taskIDStr)
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Task ID")
+		return
+	}
+
+	items, err := h.useCase.GetItemsByTaskID(ctx, taskID)
+	if err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	response.JSON(ctx, http.StatusOK, true, items, "")
+}
+
+// HandleUpdateChecklistItem
+// @Summary Update Checklist Item
+// @Description Update the text, done flag or assignee of a checklist item
+// @Tags Checklist
+// @Accept json
+// @Produce json
+// @Param id path int true "Task ID"
+// @Param item_id path int true "Checklist Item ID"
+// @Param request body UpdateChecklistItemRequestParam true "Updated checklist item data"
+// @Success 200 {object} response.JSONResponse{data=dto.ChecklistItem}
+// @Failure 400 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 404 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /task/{id}/checklist/{item_id} [put]
+func (h *ChecklistHandler) HandleUpdateChecklistItem(ctx *gin.Context) {
+	taskID, itemID, ok := parseItemPath(ctx)
+	if !ok {
+		return
+	}
+
+	var param UpdateChecklistItemRequestParam
+	if err := ctx.ShouldBindJSON(&param); err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, err.Error())
+		return
+	}
+
+	update := dto.ChecklistItemUpdate{
+		Text:          param.Text,
+		IsDone:        param.IsDone,
+		AssigneeID:    param.AssigneeID,
+		ClearAssignee: param.ClearAssignee,
+	}
+
+	updatedItem, err := h.useCase.UpdateItem(ctx, taskID, itemID, &update)
+	if err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	response.JSON(ctx, http.StatusOK, true, updatedItem, "")
+}
+
+// HandleDeleteChecklistItem
+// @Summary Delete Checklist Item
+// @Description Remove an item from the task's checklist
+// @Tags Checklist
+// @Produce json
+// @Param id path int true "Task ID"
+// @Param item_id path int true "Checklist Item ID"
+// @Success 204 {object} response.JSONResponse
+// @Failure 400 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 404 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /task/{id}/checklist/{item_id} [delete]
+func (h *ChecklistHandler) HandleDeleteChecklistItem(ctx *gin.Context) {
+	taskID, itemID, ok := parseItemPath(ctx)
+	if !ok {
+		return
+	}
+
+	if err := h.useCase.DeleteItem(ctx, taskID, itemID); err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// HandleReorderChecklist
+// @Summary Reorder Checklist
+// @Description Reorder the checklist; item_ids must list every item of the task exactly once
+// @Tags Checklist
+// @Accept json
+// @Produce json
+// @Param task_id path int true "Task ID"
+// @Param request body ReorderChecklistRequestParam true "Checklist item IDs in the new order"
+// @Success 200 {object} response.JSONResponse{data=[]dto.ChecklistItem}
+// @Failure 400 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /task/{task_id}/checklist/reorder [post]
+func (h *ChecklistHandler) HandleReorderChecklist(ctx *gin.Context) {
+	taskIDStr := ctx.Param("task_id")
+	taskID, err := strconv.Atoi(taskIDStr)
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Task ID")
+		return
+	}
+
+	var param ReorderChecklistRequestParam
+	if err := ctx.ShouldBindJSON(&param); err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, err.Error())
+		return
+	}
+
+	items, err := h.useCase.ReorderItems(ctx, taskID, param.ItemIDs)
+	if err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	response.JSON(ctx, http.StatusOK, true, items, "")
+}
+
+// parseItemPath reads the task id and item_id from the URL and answers 400 when either is malformed.
+func parseItemPath(ctx *gin.Context) (int, int, bool) {
+	taskID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Task ID")
+		return 0, 0, false
+	}
+
+	itemID, err := strconv.Atoi(ctx.Param("item_id"))
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Checklist Item ID")
+		return 0, 0, false
+	}
+	return taskID, itemID, true
+}
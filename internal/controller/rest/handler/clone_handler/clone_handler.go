@@ -0,0 +1,214 @@
+package clone_handler
+
+import (
+	"DataTask/internal/controller/rest/rest_error"
+	"DataTask/internal/domain/dto"
+	"DataTask/internal/usecase/clone_usecase"
+	"DataTask/pkg/http/response"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+type CloneHandler struct {
+	useCase clone_usecase.CloneUseCase
+}
+
+func NewCloneHandler(useCase clone_usecase.CloneUseCase) *CloneHandler {
+	return &CloneHandler{useCase: useCase}
+}
+
+type CloneTaskRequestParam struct {
+	Title            string `json:"title" example:"Onboarding checklist"` // "<title> (copy)" when empty
+	TargetKanbanID   *int   `json:"target_kanban_id"`                     // The source task's kanban when empty
+	IncludeComments  bool   `json:"include_comments"`
+	IncludeAssignees bool   `json:"include_assignees"` // Assignees who are not members of the target project are skipped
+}
+
+type CloneKanbanRequestParam struct {
+	Name             string `json:"name" example:"Sprint board"` // "<name> (copy)" when empty
+	TargetProjectID  *int   `json:"target_project_id"`           // The source kanban's project when empty
+	IncludeComments  bool   `json:"include_comments"`
+	IncludeAssignees bool   `json:"include_assignees"`
+}
+
+type CloneProjectRequestParam struct {
+	Name               string `json:"name" example:"Client onboarding"` // "<name> (copy)" when empty
+	ParentProjectID    *int   `json:"parent_project_id"`                // A top-level project when empty
+	IncludeSubprojects bool   `json:"include_subprojects"`              // Subprojects the user cannot read are skipped
+	IncludeMembers     bool   `json:"include_members"`
+	IncludeComments    bool   `json:"include_comments"`
+	IncludeAssignees   bool   `json:"include_assignees"`
+}
+
+// HandleCloneTask
+// @Summary Clone Task
+// @Description Copy a task with its subtasks, checklist, labels, custom field values and links between the copied tasks, optionally with comments and assignees. Large clones run in the background and answer 202 with the job.
+// @Tags Clone
+// @Accept json
+// @Produce json
+// @Param task_id path int true "Task ID"
+// @Param request body CloneTaskRequestParam false "Clone options"
+// @Success 201 {object} response.JSONResponse{data=dto.CloneResult}
+// @Success 202 {object} response.JSONResponse{data=dto.CloneResult}
+// @Failure 400 {object} response.JSONResponse
+// @Failure 401 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 404 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /task/{task_id}/clone [post]
+func (h *CloneHandler) HandleCloneTask(ctx *gin.Context) {
+	taskID, err := strconv.Atoi(ctx.Param("task_id"))
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Task ID")
+		return
+	}
+
+	var param CloneTaskRequestParam
+	if !bindOptionalJSON(ctx, &param) {
+		return
+	}
+
+	options := dto.CloneOptions{
+		Name:             param.Title,
+		TargetKanbanID:   param.TargetKanbanID,
+		IncludeComments:  param.IncludeComments,
+		IncludeAssignees: param.IncludeAssignees,
+	}
+
+	result, err := h.useCase.CloneTask(ctx, taskID, &options)
+	respondClone(ctx, result, err)
+}
+
+// HandleCloneKanban
+// @Summary Clone Kanban
+// @Description Copy a kanban with its tasks into its project or another one. Large clones run in the background and answer 202 with the job.
+// @Tags Clone
+// @Accept json
+// @Produce json
+// @Param id path int true "Kanban ID"
+// @Param request body CloneKanbanRequestParam false "Clone options"
+// @Success 201 {object} response.JSONResponse{data=dto.CloneResult}
+// @Success 202 {object} response.JSONResponse{data=dto.CloneResult}
+// @Failure 400 {object} response.JSONResponse
+// @Failure 401 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 404 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /kanban/{id}/clone [post]
+func (h *CloneHandler) HandleCloneKanban(ctx *gin.Context) {
+	kanbanID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Kanban ID")
+		return
+	}
+
+	var param CloneKanbanRequestParam
+	if !bindOptionalJSON(ctx, &param) {
+		return
+	}
+
+	options := dto.CloneOptions{
+		Name:             param.Name,
+		TargetProjectID:  param.TargetProjectID,
+		IncludeComments:  param.IncludeComments,
+		IncludeAssignees: param.IncludeAssignees,
+	}
+
+	result, err := h.useCase.CloneKanban(ctx, kanbanID, &options)
+	respondClone(ctx, result, err)
+}
+
+// HandleCloneProject
+// @Summary Clone Project
+// @Description Copy a project with its settings, labels, custom fields, task templates, kanbans and tasks, optionally with subprojects and members. The user owns the copy. Large clones run in the background and answer 202 with the job.
+// @Tags Clone
+// @Accept json
+// @Produce json
+// @Param id path int true "Project ID"
+// @Param request body CloneProjectRequestParam false "Clone options"
+// @Success 201 {object} response.JSONResponse{data=dto.CloneResult}
+// @Success 202 {object} response.JSONResponse{data=dto.CloneResult}
+// @Failure 400 {object} response.JSONResponse
+// @Failure 401 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 404 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /project/{id}/clone [post]
+func (h *CloneHandler) HandleCloneProject(ctx *gin.Context) {
+	projectID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Project ID")
+		return
+	}
+
+	var param CloneProjectRequestParam
+	if !bindOptionalJSON(ctx, &param) {
+		return
+	}
+
+	options := dto.CloneOptions{
+		Name:               param.Name,
+		ParentProjectID:    param.ParentProjectID,
+		IncludeSubprojects: param.IncludeSubprojects,
+		IncludeMembers:     param.IncludeMembers,
+		IncludeComments:    param.IncludeComments,
+		IncludeAssignees:   param.IncludeAssignees,
+	}
+
+	result, err := h.useCase.CloneProject(ctx, projectID, &options)
+	respondClone(ctx, result, err)
+}
+
+// HandleGetCloneJob
+// @Summary Get Clone Job
+// @Description Get the status and progress of a background clone started by the user
+// @Tags Clone
+// @Produce json
+// @Param id path int true "Clone Job ID"
+// @Success 200 {object} response.JSONResponse{data=dto.CloneJob}
+// @Failure 400 {object} response.JSONResponse
+// @Failure 401 {object} response.JSONResponse
+// @Failure 404 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /clone_job/{id} [get]
+func (h *CloneHandler) HandleGetCloneJob(ctx *gin.Context) {
+	jobID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Clone Job ID")
+		return
+	}
+
+	job, err := h.useCase.GetJob(ctx, jobID)
+	if err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	response.JSON(ctx, http.StatusOK, true, job, "")
+}
+
+// bindOptionalJSON binds the request body when there is one and answers 400 when it is malformed.
+func bindOptionalJSON(ctx *gin.Context, param any) bool {
+	if err := ctx.ShouldBindJSON(param); err != nil && !errors.Is(err, io.EOF) {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, err.Error())
+		return false
+	}
+	return true
+}
+
+// respondClone answers 201 with the ID of the copy, or 202 with the job when the clone was queued.
+func respondClone(ctx *gin.Context, result *dto.CloneResult, err error) {
+	if err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	status := http.StatusCreated
+	if result.Job != nil {
+		status = http.StatusAccepted
+	}
+	response.JSON(ctx, status, true, result, "")
+}
@@ -1,6 +1,8 @@
 package comment_handler
 
 import (
+	"DataTask/internal/controller/rest/rest_error"
+	"DataTask/internal/controller/rest/rest_query"
 	"DataTask/internal/domain/dto"
 	"DataTask/internal/usecase/comment_usecase"
 	"DataTask/pkg/http/response"
@@ -77,6 +79,7 @@ func (h *CommentHandler) HandleCreateCommentForTask(ctx *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param task_id path int true "Task ID"
+// @Param render query string false "html adds text_html, the text as sanitized HTML" Enums(html)
 // @Success 200 {object} response.JSONResponse{data=[]dto.Comment}
 // @Failure 400 {object} response.JSONResponse
 // @Failure 500 {object} response.JSONResponse
@@ -89,11 +92,23 @@ func (h *CommentHandler) HandleGetCommentsByTaskID(ctx *gin.Context) {
 		return
 	}
 
+	render, err := rest_query.ParseRender(ctx)
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, err.Error())
+		return
+	}
+
 	comments, err := h.useCase.GetCommentsByTaskID(ctx, taskID)
 	if err != nil {
-		response.JSON(ctx, http.StatusInternalServerError, false, nil, err.Error())
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
 		return
 	}
+	if render {
+		if err := h.useCase.RenderComments(ctx, comments); err != nil {
+			response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+			return
+		}
+	}
 
 	response.JSON(ctx, http.StatusOK, true, comments, "")
 }
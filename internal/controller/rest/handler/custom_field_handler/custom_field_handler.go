@@ -0,0 +1,279 @@
+package custom_field_handler
+
+import (
+	"DataTask/internal/controller/rest/rest_error"
+	"DataTask/internal/domain/dto"
+	"DataTask/internal/usecase/custom_field_usecase"
+	"DataTask/pkg/http/response"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+type CustomFieldHandler struct {
+	useCase custom_field_usecase.CustomFieldUseCase
+}
+
+func NewCustomFieldHandler(useCase custom_field_usecase.CustomFieldUseCase) *CustomFieldHandler {
+	return &CustomFieldHandler{useCase: useCase}
+}
+
+type CreateCustomFieldRequestParam struct {
+	ProjectID int      `json:"project_id" binding:"required"`
+	Name      string   `json:"name" binding:"required" example:"Environment"`
+	Type      string   `json:"type" binding:"required" enums:"text,number,date,single_select,multi_select,user,url"`
+	Options   []string `json:"options" example:"dev,staging,prod"` // Required for the select types
+	Position  int      `json:"position"`
+}
+
+type UpdateCustomFieldRequestParam struct {
+	Name     *string  `json:"name"`
+	Options  []string `json:"options"` // Replaces the choices; values with removed choices are dropped
+	Position *int     `json:"position"`
+}
+
+type CustomFieldValueRequestParam struct {
+	FieldID int `json:"field_id" binding:"required"`
+	// A string for text, url, single_select and date (YYYY-MM-DD), a number, a list of options
+	// for multi_select or a user ID. null clears the field.
+	Value any `json:"value" swaggertype:"object"`
+}
+
+type SetCustomFieldValuesRequestParam struct {
+	Values []CustomFieldValueRequestParam `json:"values" binding:"required,dive"`
+}
+
+// HandleCreateCustomField
+// @Summary Create Custom Field
+// @Description Define a custom field for the tasks of a project and its subprojects
+// @Tags CustomField
+// @Accept json
+// @Produce json
+// @Param request body CreateCustomFieldRequestParam true "Custom field data"
+// @Success 201 {object} response.JSONResponse{data=dto.CustomField}
+// @Failure 400 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 409 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /custom_field [post]
+func (h *CustomFieldHandler) HandleCreateCustomField(ctx *gin.Context) {
+	var param CreateCustomFieldRequestParam
+	if err := ctx.ShouldBindJSON(&param); err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, err.Error())
+		return
+	}
+
+	field := dto.CustomField{
+		ProjectID: param.ProjectID,
+		Name:      param.Name,
+		Type:      param.Type,
+		Options:   param.Options,
+		Position:  param.Position,
+	}
+
+	createdField, err := h.useCase.CreateField(ctx, &field)
+	if err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	response.JSON(ctx, http.StatusCreated, true, createdField, "")
+}
+
+// HandleGetCustomFieldByID
+// @Summary Get Custom Field by ID
+// @Description Get a custom field by its ID
+// @Tags CustomField
+// @Produce json
+// @Param id path int true "Custom Field ID"
+// @Success 200 {object} response.JSONResponse{data=dto.CustomField}
+// @Failure 400 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 404 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /custom_field/{id} [get]
+func (h *CustomFieldHandler) HandleGetCustomFieldByID(ctx *gin.Context) {
+	idStr := ctx.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Custom Field ID")
+		return
+	}
+
+	field, err := h.useCase.GetFieldByID(ctx, id)
+	if err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	response.JSON(ctx, http.StatusOK, true, field, "")
+}
+
+// HandleGetCustomFieldsByProjectID
+// @Summary Get Custom Fields by Project ID
+// @Description Get the custom fields usable in a project, including the ones inherited from parent projects
+// @Tags CustomField
+// @Produce json
+// @Param project_id path int true "Project ID"
+// @Success 200 {object} response.JSONResponse{data=[]dto.CustomField}
+// @Failure 400 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /custom_field/project/{project_id} [get]
+func (h *CustomFieldHandler) HandleGetCustomFieldsByProjectID(ctx *gin.Context) {
+	projectIDStr := ctx.Param("project_id")
+	projectID, err := strconv.Atoi(projectIDStr)
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Project ID")
+		return
+	}
+
+	fields, err := h.useCase.GetFieldsByProjectID(ctx, projectID)
+	if err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	response.JSON(ctx, http.StatusOK, true, fields, "")
+}
+
+// HandleUpdateCustomField
+// @Summary Update Custom Field
+// @Description Rename a custom field, move it or change its choices. The type cannot change
+// @Tags CustomField
+// @Accept json
+// @Produce json
+// @Param id path int true "Custom Field ID"
+// @Param request body UpdateCustomFieldRequestParam true "Fields to change"
+// @Success 200 {object} response.JSONResponse{data=dto.CustomField}
+// @Failure 400 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 404 {object} response.JSONResponse
+// @Failure 409 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /custom_field/{id} [put]
+func (h *CustomFieldHandler) HandleUpdateCustomField(ctx *gin.Context) {
+	idStr := ctx.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Custom Field ID")
+		return
+	}
+
+	var param UpdateCustomFieldRequestParam
+	if err := ctx.ShouldBindJSON(&param); err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, err.Error())
+		return
+	}
+
+	update := dto.CustomFieldUpdate{
+		Name:     param.Name,
+		Options:  param.Options,
+		Position: param.Position,
+	}
+
+	field, err := h.useCase.UpdateField(ctx, id, &update)
+	if err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	response.JSON(ctx, http.StatusOK, true, field, "")
+}
+
+// HandleDeleteCustomField
+// @Summary Delete Custom Field
+// @Description Delete a custom field and its values on all tasks
+// @Tags CustomField
+// @Produce json
+// @Param id path int true "Custom Field ID"
+// @Success 204 {object} response.JSONResponse
+// @Failure 400 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 404 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /custom_field/{id} [delete]
+func (h *CustomFieldHandler) HandleDeleteCustomField(ctx *gin.Context) {
+	idStr := ctx.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Custom Field ID")
+		return
+	}
+
+	if err := h.useCase.DeleteField(ctx, id); err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// HandleGetTaskCustomFields
+// @Summary Get Task Custom Field Values
+// @Description Get the custom field values set on a task
+// @Tags CustomField
+// @Produce json
+// @Param id path int true "Task ID"
+// @Success 200 {object} response.JSONResponse{data=[]dto.CustomFieldValue}
+// @Failure 400 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /task/{id}/custom_fields [get]
+func (h *CustomFieldHandler) HandleGetTaskCustomFields(ctx *gin.Context) {
+	idStr := ctx.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Task ID")
+		return
+	}
+
+	values, err := h.useCase.GetTaskValues(ctx, id)
+	if err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	response.JSON(ctx, http.StatusOK, true, values, "")
+}
+
+// HandleSetTaskCustomFields
+// @Summary Set Task Custom Field Values
+// @Description Set or clear custom field values of a task. Fields not listed stay unchanged
+// @Tags CustomField
+// @Accept json
+// @Produce json
+// @Param id path int true "Task ID"
+// @Param request body SetCustomFieldValuesRequestParam true "Values to set"
+// @Success 200 {object} response.JSONResponse{data=[]dto.CustomFieldValue}
+// @Failure 400 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /task/{id}/custom_fields [put]
+func (h *CustomFieldHandler) HandleSetTaskCustomFields(ctx *gin.Context) {
+	idStr := ctx.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Task ID")
+		return
+	}
+
+	var param SetCustomFieldValuesRequestParam
+	if err := ctx.ShouldBindJSON(&param); err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, err.Error())
+		return
+	}
+
+	values := make([]*dto.CustomFieldValueInput, 0, len(param.Values))
+	for _, v := range param.Values {
+		values = append(values, &dto.CustomFieldValueInput{FieldID: v.FieldID, Value: v.Value})
+	}
+
+	stored, err := h.useCase.SetTaskValues(ctx, id, values)
+	if err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	response.JSON(ctx, http.StatusOK, true, stored, "")
+}
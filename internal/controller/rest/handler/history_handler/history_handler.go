@@ -0,0 +1,109 @@
+package history_handler
+
+import (
+	"DataTask/internal/controller/rest/rest_error"
+	"DataTask/internal/domain/dto"
+	"DataTask/internal/usecase/history_usecase"
+	"DataTask/pkg/http/response"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+type HistoryHandler struct {
+	useCase history_usecase.HistoryUseCase
+}
+
+func NewHistoryHandler(useCase history_usecase.HistoryUseCase) *HistoryHandler {
+	return &HistoryHandler{useCase: useCase}
+}
+
+// HandleGetTaskHistory
+// @Summary Get Task History
+// @Description Get the field changes of the task with actor and old/new values, newest first
+// @Tags History
+// @Produce json
+// @Param id path int true "Task ID"
+// @Param limit query int false "Page size, 50 by default and at most 200"
+// @Param offset query int false "Number of entries to skip"
+// @Success 200 {object} response.JSONResponse{data=dto.TaskHistoryPage}
+// @Failure 400 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 404 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /task/{id}/history [get]
+func (h *HistoryHandler) HandleGetTaskHistory(ctx *gin.Context) {
+	taskID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Task ID")
+		return
+	}
+	page, err := parsePage(ctx)
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, err.Error())
+		return
+	}
+
+	history, err := h.useCase.GetTaskHistory(ctx, taskID, page)
+	if err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	response.JSON(ctx, http.StatusOK, true, history, "")
+}
+
+// HandleGetTaskTimeline
+// @Summary Get Task Timeline
+// @Description Get the field changes and the comments of the task as one list, newest first
+// @Tags History
+// @Produce json
+// @Param id path int true "Task ID"
+// @Param limit query int false "Page size, 50 by default and at most 200"
+// @Param offset query int false "Number of items to skip"
+// @Success 200 {object} response.JSONResponse{data=dto.TimelinePage}
+// @Failure 400 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 404 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /task/{id}/timeline [get]
+func (h *HistoryHandler) HandleGetTaskTimeline(ctx *gin.Context) {
+	taskID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Task ID")
+		return
+	}
+	page, err := parsePage(ctx)
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, err.Error())
+		return
+	}
+
+	timeline, err := h.useCase.GetTaskTimeline(ctx, taskID, page)
+	if err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	response.JSON(ctx, http.StatusOK, true, timeline, "")
+}
+
+func parsePage(ctx *gin.Context) (*dto.Page, error) {
+	page := &dto.Page{}
+	if raw := ctx.Query("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid limit %q", raw)
+		}
+		page.Limit = limit
+	}
+	if raw := ctx.Query("offset"); raw != "" {
+		offset, err := strconv.Atoi(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid offset %q", raw)
+		}
+		page.Offset = offset
+	}
+	return page, nil
+}
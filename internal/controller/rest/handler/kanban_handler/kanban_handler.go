@@ -1,6 +1,7 @@
 package kanban_handler
 
 import (
+	"DataTask/internal/controller/rest/rest_error"
 	"DataTask/internal/domain/dto"
 	"DataTask/internal/usecase/kanban_usecase"
 	"DataTask/pkg/http/response"
@@ -26,6 +27,18 @@ type UpdateKanbanRequestParam struct {
 	Name string `json:"name"`
 }
 
+// ReorderKanbansRequestParam takes either kanban_ids, or kanban_id with before_id or after_id.
+type ReorderKanbansRequestParam struct {
+	KanbanIDs []int `json:"kanban_ids"` // Every kanban of the project in the new order
+	KanbanID  *int  `json:"kanban_id"`  // Kanban to move next to before_id or after_id
+	BeforeID  *int  `json:"before_id"`
+	AfterID   *int  `json:"after_id"`
+}
+
+type SetWIPLimitRequestParam struct {
+	WIPLimit *int `json:"wip_limit"` // null removes the limit
+}
+
 // HandleCreateKanban
 // @Summary Create Kanban board
 // @Description Create a new Kanban board
@@ -94,7 +107,7 @@ func (h *KanbanHandler) HandleGetKanbanByID(ctx *gin.Context) {
 
 // HandleGetKanbansByProjectID
 // @Summary Get Kanban board by project ID
-// @Description Get a Kanban board by project ID
+// @Description Get the Kanban boards of a project in their order
 // @Tags Kanban
 // @Produce json
 // @Param project_id path int true "Kanban board project ID"
@@ -172,7 +185,7 @@ func (h *KanbanHandler) HandleUpdateKanban(ctx *gin.Context) {
 
 // HandleDeleteKanban
 // @Summary Delete Kanban board
-// @Description Delete a Kanban board by its ID
+// @Description Move a Kanban board with its tasks to the trash
 // @Tags Kanban
 // @Produce json
 // @Param id path int true "Kanban board ID"
@@ -196,3 +209,82 @@ func (h *KanbanHandler) HandleDeleteKanban(ctx *gin.Context) {
 
 	ctx.Status(http.StatusNoContent) // 204 No Content for successful deletion
 }
+
+// HandleReorderKanbans
+// @Summary Reorder Kanban boards
+// @Description Reorder the Kanban boards of a project, either by listing every board exactly once in kanban_ids or by moving kanban_id right before before_id or right after after_id
+// @Tags Kanban
+// @Accept json
+// @Produce json
+// @Param project_id path int true "Project ID"
+// @Param request body ReorderKanbansRequestParam true "New order of the Kanban boards"
+// @Success 200 {object} response.JSONResponse{data=[]dto.Kanban}
+// @Failure 400 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 404 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /kanban/project/{project_id}/reorder [post]
+func (h *KanbanHandler) HandleReorderKanbans(ctx *gin.Context) {
+	projectID, err := strconv.Atoi(ctx.Param("project_id"))
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Project ID")
+		return
+	}
+
+	var param ReorderKanbansRequestParam
+	if err := ctx.ShouldBindJSON(&param); err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, err.Error())
+		return
+	}
+
+	order := dto.KanbanOrder{
+		KanbanIDs: param.KanbanIDs,
+		KanbanID:  param.KanbanID,
+		BeforeID:  param.BeforeID,
+		AfterID:   param.AfterID,
+	}
+
+	kanbans, err := h.useCase.ReorderKanbans(ctx, projectID, &order)
+	if err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	response.JSON(ctx, http.StatusOK, true, kanbans, "")
+}
+
+// HandleSetWIPLimit
+// @Summary Set Kanban WIP limit
+// @Description Set the most tasks a Kanban board should hold. What adding a task to a full board does depends on the project's WIP limit policy.
+// @Tags Kanban
+// @Accept json
+// @Produce json
+// @Param id path int true "Kanban board ID"
+// @Param request body SetWIPLimitRequestParam true "WIP limit of the Kanban board"
+// @Success 200 {object} response.JSONResponse{data=dto.Kanban}
+// @Failure 400 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 404 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /kanban/{id}/wip_limit [put]
+func (h *KanbanHandler) HandleSetWIPLimit(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Kanban ID")
+		return
+	}
+
+	var param SetWIPLimitRequestParam
+	if err := ctx.ShouldBindJSON(&param); err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, err.Error())
+		return
+	}
+
+	kanban, err := h.useCase.SetWIPLimit(ctx, id, param.WIPLimit)
+	if err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	response.JSON(ctx, http.StatusOK, true, kanban, "")
+}
@@ -0,0 +1,269 @@
+package label_handler
+
+import (
+	"DataTask/internal/controller/rest/rest_error"
+	"DataTask/internal/domain/dto"
+	"DataTask/internal/usecase/label_usecase"
+	"DataTask/pkg/http/response"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+type LabelHandler struct {
+	useCase label_usecase.LabelUseCase
+}
+
+func NewLabelHandler(useCase label_usecase.LabelUseCase) *LabelHandler {
+	return &LabelHandler{useCase: useCase}
+}
+
+type CreateLabelRequestParam struct {
+	ProjectID int    `json:"project_id" binding:"required"`
+	Name      string `json:"name" binding:"required"`
+	Color     string `json:"color" example:"#FF5722"`
+}
+
+type UpdateLabelRequestParam struct {
+	Name  string `json:"name"`
+	Color string `json:"color" example:"#FF5722"`
+}
+
+type AttachLabelRequestParam struct {
+	LabelID int `json:"label_id" binding:"required"`
+}
+
+// HandleCreateLabel
+// @Summary Create Label
+// @Description Create a label in the project's label catalogue
+// @Tags Label
+// @Accept json
+// @Produce json
+// @Param request body CreateLabelRequestParam true "Label data for creation"
+// @Success 201 {object} response.JSONResponse{data=dto.Label}
+// @Failure 400 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 409 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /label [post]
+func (h *LabelHandler) HandleCreateLabel(ctx *gin.Context) {
+	var param CreateLabelRequestParam
+	if err := ctx.ShouldBindJSON(&param); err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, err.Error())
+		return
+	}
+
+	label := dto.Label{
+		ProjectID: param.ProjectID,
+		Name:      param.Name,
+		Color:     param.Color,
+	}
+
+	createdLabel, err := h.useCase.CreateLabel(ctx, &label)
+	if err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	response.JSON(ctx, http.StatusCreated, true, createdLabel, "")
+}
+
+// HandleGetLabelByID
+// @Summary Get Label by ID
+// @Description Get a label by its ID
+// @Tags Label
+// @Produce json
+// @Param id path int true "Label ID"
+// @Success 200 {object} response.JSONResponse{data=dto.Label}
+// @Failure 400 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 404 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /label/{id} [get]
+func (h *LabelHandler) HandleGetLabelByID(ctx *gin.Context) {
+	idStr := ctx.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Label ID")
+		return
+	}
+
+	label, err := h.useCase.GetLabelByID(ctx, id)
+	if err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	response.JSON(ctx, http.StatusOK, true, label, "")
+}
+
+// HandleGetLabelsByProjectID
+// @Summary Get Labels by Project ID
+// @Description Get the labels usable in a project, including the ones inherited from parent projects
+// @Tags Label
+// @Produce json
+// @Param project_id path int true "Project ID"
+// @Success 200 {object} response.JSONResponse{data=[]dto.Label}
+// @Failure 400 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /label/project/{project_id} [get]
+func (h *LabelHandler) HandleGetLabelsByProjectID(ctx *gin.Context) {
+	projectIDStr := ctx.Param("project_id")
+	projectID, err := strconv.Atoi(projectIDStr)
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Project ID")
+		return
+	}
+
+	labels, err := h.useCase.GetLabelsByProjectID(ctx, projectID)
+	if err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	response.JSON(ctx, http.StatusOK, true, labels, "")
+}
+
+// HandleUpdateLabel
+// @Summary Update Label
+// @Description Rename or recolor a label
+// @Tags Label
+// @Accept json
+// @Produce json
+// @Param id path int true "Label ID"
+// @Param request body UpdateLabelRequestParam true "Updated label data"
+// @Success 200 {object} response.JSONResponse{data=dto.Label}
+// @Failure 400 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 409 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /label/{id} [put]
+func (h *LabelHandler) HandleUpdateLabel(ctx *gin.Context) {
+	idStr := ctx.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Label ID")
+		return
+	}
+
+	var param UpdateLabelRequestParam
+	if err := ctx.ShouldBindJSON(&param); err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, err.Error())
+		return
+	}
+
+	label := dto.Label{
+		ID:    id,
+		Name:  param.Name,
+		Color: param.Color,
+	}
+
+	updatedLabel, err := h.useCase.UpdateLabel(ctx, &label)
+	if err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	response.JSON(ctx, http.StatusOK, true, updatedLabel, "")
+}
+
+// HandleDeleteLabel
+// @Summary Delete Label
+// @Description Delete a label and detach it from all tasks
+// @Tags Label
+// @Produce json
+// @Param id path int true "Label ID"
+// @Success 204 {object} response.JSONResponse
+// @Failure 400 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /label/{id} [delete]
+func (h *LabelHandler) HandleDeleteLabel(ctx *gin.Context) {
+	idStr := ctx.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Label ID")
+		return
+	}
+
+	err = h.useCase.DeleteLabel(ctx, id)
+	if err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// HandleAttachLabelToTask
+// @Summary Attach Label to Task
+// @Description Attach a label of the task's project (or of a parent project) to a task
+// @Tags Label
+// @Accept json
+// @Produce json
+// @Param task_id path int true "Task ID"
+// @Param request body AttachLabelRequestParam true "Label to attach"
+// @Success 200 {object} response.JSONResponse
+// @Failure 400 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /task/{task_id}/labels [post]
+func (h *LabelHandler) HandleAttachLabelToTask(ctx *gin.Context) {
+	taskIDStr := ctx.Param("task_id")
+	taskID, err := strconv.Atoi(taskIDStr)
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Task ID")
+		return
+	}
+
+	var param AttachLabelRequestParam
+	if err := ctx.ShouldBindJSON(&param); err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, err.Error())
+		return
+	}
+
+	err = h.useCase.AttachLabelToTask(ctx, taskID, param.LabelID)
+	if err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	response.JSON(ctx, http.StatusOK, true, nil, "Label attached to task")
+}
+
+// HandleDetachLabelFromTask
+// @Summary Detach Label from Task
+// @Description Remove a label from a task
+// @Tags Label
+// @Produce json
+// @Param id path int true "Task ID"
+// @Param label_id path int true "Label ID"
+// @Success 204 {object} response.JSONResponse
+// @Failure 400 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /task/{id}/labels/{label_id} [delete]
+func (h *LabelHandler) HandleDetachLabelFromTask(ctx *gin.Context) {
+	taskIDStr := ctx.Param("id")
+	taskID, err := strconv.Atoi(taskIDStr)
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Task ID")
+		return
+	}
+
+	labelIDStr := ctx.Param("label_id")
+	labelID, err := strconv.Atoi(labelIDStr)
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Label ID")
+		return
+	}
+
+	err = h.useCase.DetachLabelFromTask(ctx, taskID, labelID)
+	if err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
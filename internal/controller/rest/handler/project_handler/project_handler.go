@@ -1,6 +1,7 @@
 package project_handler
 
 import (
+	"DataTask/internal/controller/rest/rest_error"
 	"DataTask/internal/domain/dto"
 	"DataTask/internal/usecase/project_usecase"
 	"DataTask/pkg/http/response"
@@ -22,6 +23,18 @@ type HandleCreateProjectParam struct {
 	Description     string `json:"description" binding:"required"`
 	Color           string `json:"color"`
 	ParentProjectID *int   `json:"parent_project_id,omitempty"`
+	// Prefix of the task keys such as DT-123, fixed once the project is created. Derived from
+	// the name when empty
+	Key string `json:"key" example:"DT"`
+}
+
+type UpdateProjectSettingsRequestParam struct {
+	// What completing a task with open blockers does: warn completes it anyway, fail rejects it
+	BlockedCompletionPolicy *string `json:"blocked_completion_policy" enums:"warn,fail"`
+	// Unit of the task estimates in this project
+	EstimateUnit *string `json:"estimate_unit" enums:"hours,story_points"`
+	// What adding a task to a kanban at its WIP limit does: warn adds it anyway, fail rejects it
+	WIPLimitPolicy *string `json:"wip_limit_policy" enums:"warn,fail"`
 }
 
 // HandleCreateProject
@@ -33,6 +46,7 @@ type HandleCreateProjectParam struct {
 // @Param request body HandleCreateProjectParam true "Project data"
 // @Success 201 {object} response.JSONResponse{data=dto.Project}
 // @Failure 400 {object} response.JSONResponse
+// @Failure 409 {object} response.JSONResponse
 // @Failure 500 {object} response.JSONResponse
 // @Router /project [post]
 func (h *ProjectHandler) HandleCreateProject(ctx *gin.Context) {
@@ -49,12 +63,13 @@ func (h *ProjectHandler) HandleCreateProject(ctx *gin.Context) {
 		Description:     param.Description,
 		Color:           param.Color,
 		ParentProjectID: param.ParentProjectID,
+		Key:             param.Key,
 		OwnerID:         authUserID,
 	}
 
 	createdProject, err := h.useCase.CreateProject(ctx, &project) // UseCase должен вернуть entity
 	if err != nil {
-		response.JSON(ctx, http.StatusInternalServerError, false, nil, err.Error())
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
 		return
 	}
 
@@ -66,6 +81,7 @@ func (h *ProjectHandler) HandleCreateProject(ctx *gin.Context) {
 		Description:     createdProject.Description,
 		Color:           createdProject.Color,
 		ParentProjectID: createdProject.ParentProjectID,
+		Key:             createdProject.Key,
 		CreatedAt:       createdProject.CreatedAt,
 		UpdatedAt:       createdProject.UpdatedAt,
 	}
@@ -143,7 +159,7 @@ func (h *ProjectHandler) HandleUpdateProject(ctx *gin.Context) {
 
 // HandleDeleteProject
 // @Summary Delete Project
-// @Description Delete a project by its ID
+// @Description Move a project with its boards and tasks to the trash
 // @Tags Project
 // @Produce json
 // @Param id path int true "Project ID"
@@ -375,3 +391,102 @@ func (h *ProjectHandler) HandleAcceptProjectInvitation(ctx *gin.Context) {
 
 	response.JSON(ctx, http.StatusOK, true, nil, "Project invitation accepted")
 }
+
+// HandleGetProjectSettings
+// @Summary Get Project Settings
+// @Description Get the behaviour settings of a project
+// @Tags Project
+// @Produce json
+// @Param id path int true "Project ID"
+// @Success 200 {object} response.JSONResponse{data=dto.ProjectSettings}
+// @Failure 400 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /project/{id}/settings [get]
+func (h *ProjectHandler) HandleGetProjectSettings(ctx *gin.Context) {
+	idStr := ctx.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Project ID")
+		return
+	}
+
+	settings, err := h.useCase.GetProjectSettings(ctx, id)
+	if err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	response.JSON(ctx, http.StatusOK, true, settings, "")
+}
+
+// HandleUpdateProjectSettings
+// @Summary Update Project Settings
+// @Description Update the behaviour settings of a project. Only the owner can change them
+// @Tags Project
+// @Accept json
+// @Produce json
+// @Param id path int true "Project ID"
+// @Param request body UpdateProjectSettingsRequestParam true "Settings to change"
+// @Success 200 {object} response.JSONResponse{data=dto.ProjectSettings}
+// @Failure 400 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /project/{id}/settings [put]
+func (h *ProjectHandler) HandleUpdateProjectSettings(ctx *gin.Context) {
+	idStr := ctx.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Project ID")
+		return
+	}
+
+	var param UpdateProjectSettingsRequestParam
+	if err := ctx.ShouldBindJSON(&param); err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, err.Error())
+		return
+	}
+
+	update := dto.ProjectSettingsUpdate{
+		BlockedCompletionPolicy: param.BlockedCompletionPolicy,
+		EstimateUnit:            param.EstimateUnit,
+		WIPLimitPolicy:          param.WIPLimitPolicy,
+	}
+
+	settings, err := h.useCase.UpdateProjectSettings(ctx, id, &update)
+	if err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	response.JSON(ctx, http.StatusOK, true, settings, "")
+}
+
+// HandleGetProjectEstimate
+// @Summary Get Project Estimate
+// @Description Get the total, completed and remaining task estimate of a project including all its subprojects
+// @Tags Project
+// @Produce json
+// @Param id path int true "Project ID"
+// @Success 200 {object} response.JSONResponse{data=dto.ProjectEstimate}
+// @Failure 400 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 404 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /project/{id}/estimate [get]
+func (h *ProjectHandler) HandleGetProjectEstimate(ctx *gin.Context) {
+	idStr := ctx.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Project ID")
+		return
+	}
+
+	estimate, err := h.useCase.GetProjectEstimate(ctx, id)
+	if err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	response.JSON(ctx, http.StatusOK, true, estimate, "")
+}
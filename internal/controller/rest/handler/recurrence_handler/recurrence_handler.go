@@ -0,0 +1,127 @@
+package recurrence_handler
+
+import (
+	"DataTask/internal/controller/rest/rest_error"
+	"DataTask/internal/domain/dto"
+	"DataTask/internal/usecase/recurrence_usecase"
+	"DataTask/pkg/http/response"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type RecurrenceHandler struct {
+	useCase recurrence_usecase.RecurrenceUseCase
+}
+
+func NewRecurrenceHandler(useCase recurrence_usecase.RecurrenceUseCase) *RecurrenceHandler {
+	return &RecurrenceHandler{useCase: useCase}
+}
+
+type SetRecurrenceRequestParam struct {
+	RRule string `json:"rrule" binding:"required" example:"FREQ=WEEKLY;BYDAY=MO"` // RFC 5545 RRULE
+	// First occurrence for a task that does not recur yet; defaults to its due_at, start_at or now
+	DTStart *time.Time `json:"dtstart"`
+	// For a recurring task: change the rule from this occurrence on (default) or for the whole series
+	Scope string `json:"scope" enums:"this_and_future,all"`
+}
+
+// HandleSetRecurrence
+// @Summary Set Task Recurrence
+// @Description Make the task recurring or change the rule of its series. The next occurrence is created in the same kanban with the same assignees and labels
+// @Tags Recurrence
+// @Accept json
+// @Produce json
+// @Param id path int true "Task ID"
+// @Param request body SetRecurrenceRequestParam true "Recurrence rule"
+// @Success 200 {object} response.JSONResponse{data=dto.TaskRecurrence}
+// @Failure 400 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /task/{id}/recurrence [put]
+func (h *RecurrenceHandler) HandleSetRecurrence(ctx *gin.Context) {
+	idStr := ctx.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Task ID")
+		return
+	}
+
+	var param SetRecurrenceRequestParam
+	if err := ctx.ShouldBindJSON(&param); err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, err.Error())
+		return
+	}
+
+	update := dto.RecurrenceUpdate{
+		RRule:   param.RRule,
+		DTStart: param.DTStart,
+		Scope:   param.Scope,
+	}
+
+	recurrence, err := h.useCase.SetRecurrence(ctx, id, &update)
+	if err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	response.JSON(ctx, http.StatusOK, true, recurrence, "")
+}
+
+// HandleGetRecurrence
+// @Summary Get Task Recurrence
+// @Description Get the recurrence series of a task and its next occurrence
+// @Tags Recurrence
+// @Produce json
+// @Param id path int true "Task ID"
+// @Success 200 {object} response.JSONResponse{data=dto.TaskRecurrence}
+// @Failure 400 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 404 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /task/{id}/recurrence [get]
+func (h *RecurrenceHandler) HandleGetRecurrence(ctx *gin.Context) {
+	idStr := ctx.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Task ID")
+		return
+	}
+
+	recurrence, err := h.useCase.GetRecurrence(ctx, id)
+	if err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	response.JSON(ctx, http.StatusOK, true, recurrence, "")
+}
+
+// HandleStopRecurrence
+// @Summary Stop Task Recurrence
+// @Description Stop the series at this task and remove its open future occurrences
+// @Tags Recurrence
+// @Produce json
+// @Param id path int true "Task ID"
+// @Success 204 {object} response.JSONResponse
+// @Failure 400 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 404 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /task/{id}/recurrence [delete]
+func (h *RecurrenceHandler) HandleStopRecurrence(ctx *gin.Context) {
+	idStr := ctx.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Task ID")
+		return
+	}
+
+	if err := h.useCase.StopRecurrence(ctx, id); err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,300 @@
+package saved_view_handler
+
+import (
+	"DataTask/internal/controller/rest/rest_error"
+	"DataTask/internal/domain/dto"
+	"DataTask/internal/usecase/saved_view_usecase"
+	"DataTask/pkg/http/response"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+type SavedViewHandler struct {
+	useCase saved_view_usecase.SavedViewUseCase
+}
+
+func NewSavedViewHandler(useCase saved_view_usecase.SavedViewUseCase) *SavedViewHandler {
+	return &SavedViewHandler{useCase: useCase}
+}
+
+type CreateSavedViewRequestParam struct {
+	Name string `json:"name" binding:"required" example:"My open tasks"`
+	// Same syntax as the filter query parameter of the task lists
+	Filter string `json:"filter" example:"assignee:me AND is_completed:false"`
+	// Same syntax as the sort query parameter of the task lists
+	Sort    string   `json:"sort" example:"-priority,due_at"`
+	GroupBy string   `json:"group_by" enums:",column,assignee,label,priority"`  // Empty for no grouping
+	Fields  []string `json:"fields" example:"title,priority,assignees,field.3"` // Visible task fields, in order
+	Shared  bool     `json:"shared"`                                            // Sharing with the project takes edit permission
+}
+
+type UpdateSavedViewRequestParam struct {
+	Name    *string  `json:"name"`
+	Filter  *string  `json:"filter"`
+	Sort    *string  `json:"sort"`
+	GroupBy *string  `json:"group_by" enums:",column,assignee,label,priority"`
+	Fields  []string `json:"fields"` // Replaces the fields when given
+	Shared  *bool    `json:"shared"`
+}
+
+type ReorderSavedViewsRequestParam struct {
+	ViewIDs []int `json:"view_ids" binding:"required"` // Every view the user sees in the project, in the new order
+}
+
+// HandleCreateSavedView
+// @Summary Create Saved View
+// @Description Save a named task view in a project, personal unless shared with the project
+// @Tags SavedView
+// @Accept json
+// @Produce json
+// @Param id path int true "Project ID"
+// @Param request body CreateSavedViewRequestParam true "Saved view data"
+// @Success 201 {object} response.JSONResponse{data=dto.SavedView}
+// @Failure 400 {object} response.JSONResponse
+// @Failure 401 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /project/{id}/views [post]
+func (h *SavedViewHandler) HandleCreateSavedView(ctx *gin.Context) {
+	projectID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Project ID")
+		return
+	}
+
+	var param CreateSavedViewRequestParam
+	if err := ctx.ShouldBindJSON(&param); err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, err.Error())
+		return
+	}
+
+	view := dto.SavedView{
+		ProjectID: projectID,
+		Name:      param.Name,
+		Filter:    param.Filter,
+		Sort:      param.Sort,
+		GroupBy:   param.GroupBy,
+		Fields:    param.Fields,
+		Shared:    param.Shared,
+	}
+
+	createdView, err := h.useCase.CreateView(ctx, &view)
+	if err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	response.JSON(ctx, http.StatusCreated, true, createdView, "")
+}
+
+// HandleGetSavedViews
+// @Summary Get Saved Views
+// @Description Get the project's shared views and the user's personal views, in the user's order
+// @Tags SavedView
+// @Produce json
+// @Param id path int true "Project ID"
+// @Success 200 {object} response.JSONResponse{data=[]dto.SavedView}
+// @Failure 400 {object} response.JSONResponse
+// @Failure 401 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /project/{id}/views [get]
+func (h *SavedViewHandler) HandleGetSavedViews(ctx *gin.Context) {
+	projectID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Project ID")
+		return
+	}
+
+	views, err := h.useCase.GetViews(ctx, projectID)
+	if err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	response.JSON(ctx, http.StatusOK, true, views, "")
+}
+
+// HandleReorderSavedViews
+// @Summary Reorder Saved Views
+// @Description Reorder the user's list of views; view_ids must list every view the user sees in the project exactly once. Other users' order is not affected.
+// @Tags SavedView
+// @Accept json
+// @Produce json
+// @Param id path int true "Project ID"
+// @Param request body ReorderSavedViewsRequestParam true "Saved view IDs in the new order"
+// @Success 200 {object} response.JSONResponse{data=[]dto.SavedView}
+// @Failure 400 {object} response.JSONResponse
+// @Failure 401 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /project/{id}/views/reorder [post]
+func (h *SavedViewHandler) HandleReorderSavedViews(ctx *gin.Context) {
+	projectID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Project ID")
+		return
+	}
+
+	var param ReorderSavedViewsRequestParam
+	if err := ctx.ShouldBindJSON(&param); err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, err.Error())
+		return
+	}
+
+	views, err := h.useCase.ReorderViews(ctx, projectID, param.ViewIDs)
+	if err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	response.JSON(ctx, http.StatusOK, true, views, "")
+}
+
+// HandleGetSavedView
+// @Summary Get Saved View
+// @Description Get a saved view of the project
+// @Tags SavedView
+// @Produce json
+// @Param id path int true "Project ID"
+// @Param view_id path int true "Saved View ID"
+// @Success 200 {object} response.JSONResponse{data=dto.SavedView}
+// @Failure 400 {object} response.JSONResponse
+// @Failure 401 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 404 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /project/{id}/views/{view_id} [get]
+func (h *SavedViewHandler) HandleGetSavedView(ctx *gin.Context) {
+	projectID, viewID, ok := parseViewPath(ctx)
+	if !ok {
+		return
+	}
+
+	view, err := h.useCase.GetView(ctx, projectID, viewID)
+	if err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	response.JSON(ctx, http.StatusOK, true, view, "")
+}
+
+// HandleUpdateSavedView
+// @Summary Update Saved View
+// @Description Update a saved view; omitted fields are left unchanged. Shared views of other users and sharing take edit permission, only the owner can stop sharing.
+// @Tags SavedView
+// @Accept json
+// @Produce json
+// @Param id path int true "Project ID"
+// @Param view_id path int true "Saved View ID"
+// @Param request body UpdateSavedViewRequestParam true "Saved view changes"
+// @Success 200 {object} response.JSONResponse{data=dto.SavedView}
+// @Failure 400 {object} response.JSONResponse
+// @Failure 401 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 404 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /project/{id}/views/{view_id} [put]
+func (h *SavedViewHandler) HandleUpdateSavedView(ctx *gin.Context) {
+	projectID, viewID, ok := parseViewPath(ctx)
+	if !ok {
+		return
+	}
+
+	var param UpdateSavedViewRequestParam
+	if err := ctx.ShouldBindJSON(&param); err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, err.Error())
+		return
+	}
+
+	update := dto.SavedViewUpdate{
+		Name:    param.Name,
+		Filter:  param.Filter,
+		Sort:    param.Sort,
+		GroupBy: param.GroupBy,
+		Fields:  param.Fields,
+		Shared:  param.Shared,
+	}
+
+	view, err := h.useCase.UpdateView(ctx, projectID, viewID, &update)
+	if err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	response.JSON(ctx, http.StatusOK, true, view, "")
+}
+
+// HandleDeleteSavedView
+// @Summary Delete Saved View
+// @Description Delete a saved view; views of other users take edit permission
+// @Tags SavedView
+// @Param id path int true "Project ID"
+// @Param view_id path int true "Saved View ID"
+// @Success 204 {object} response.JSONResponse
+// @Failure 400 {object} response.JSONResponse
+// @Failure 401 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 404 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /project/{id}/views/{view_id} [delete]
+func (h *SavedViewHandler) HandleDeleteSavedView(ctx *gin.Context) {
+	projectID, viewID, ok := parseViewPath(ctx)
+	if !ok {
+		return
+	}
+
+	if err := h.useCase.DeleteView(ctx, projectID, viewID); err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// HandleGetSavedViewTasks
+// @Summary Get Saved View Tasks
+// @Description Get the project's tasks matching the view's filter, in the view's order. Grouped views also return the tasks by group; me in the filter stands for the current user.
+// @Tags SavedView
+// @Produce json
+// @Param id path int true "Project ID"
+// @Param view_id path int true "Saved View ID"
+// @Success 200 {object} response.JSONResponse{data=dto.SavedViewTasks}
+// @Failure 400 {object} response.JSONResponse
+// @Failure 401 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 404 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /project/{id}/views/{view_id}/tasks [get]
+func (h *SavedViewHandler) HandleGetSavedViewTasks(ctx *gin.Context) {
+	projectID, viewID, ok := parseViewPath(ctx)
+	if !ok {
+		return
+	}
+
+	tasks, err := h.useCase.GetViewTasks(ctx, projectID, viewID)
+	if err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	response.JSON(ctx, http.StatusOK, true, tasks, "")
+}
+
+// parseViewPath reads the project id and view_id from the URL and answers 400 when either is malformed.
+func parseViewPath(ctx *gin.Context) (int, int, bool) {
+	projectID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Project ID")
+		return 0, 0, false
+	}
+
+	viewID, err := strconv.Atoi(ctx.Param("view_id"))
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Saved View ID")
+		return 0, 0, false
+	}
+	return projectID, viewID, true
+}
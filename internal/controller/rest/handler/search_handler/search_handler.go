@@ -0,0 +1,107 @@
+package search_handler
+
+import (
+	"DataTask/internal/controller/rest/rest_error"
+	"DataTask/internal/domain/dto"
+	"DataTask/internal/usecase/search_usecase"
+	"DataTask/pkg/http/response"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type SearchHandler struct {
+	useCase search_usecase.SearchUseCase
+}
+
+func NewSearchHandler(useCase search_usecase.SearchUseCase) *SearchHandler {
+	return &SearchHandler{useCase: useCase}
+}
+
+// HandleSearch
+// @Summary Search
+// @Description Full-text search over task titles and descriptions, comments and project names and descriptions in the projects the user can access, best match first. The query supports "quoted phrases", OR and -excluded words.
+// @Tags Search
+// @Produce json
+// @Param q query string true "Search text"
+// @Param project_id query int false "Only results in this project"
+// @Param type query string false "Comma-separated result types: task, comment, project"
+// @Param assignee query string false "Only tasks assigned to this user ID, or me, and comments on them"
+// @Param is_completed query bool false "Only completed (true) or open (false) tasks and comments on them"
+// @Param limit query int false "Page size, 20 by default and at most 100"
+// @Param offset query int false "Number of results to skip"
+// @Success 200 {object} response.JSONResponse{data=[]dto.SearchResult}
+// @Failure 400 {object} response.JSONResponse
+// @Failure 401 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /search [get]
+func (h *SearchHandler) HandleSearch(ctx *gin.Context) {
+	query, err := parseSearchQuery(ctx)
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, err.Error())
+		return
+	}
+
+	results, err := h.useCase.Search(ctx, query)
+	if err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	response.JSON(ctx, http.StatusOK, true, results, "")
+}
+
+func parseSearchQuery(ctx *gin.Context) (*dto.SearchQuery, error) {
+	query := &dto.SearchQuery{Text: ctx.Query("q")}
+
+	intParams := []struct {
+		name  string
+		value *int
+	}{{"limit", &query.Limit}, {"offset", &query.Offset}}
+	for _, p := range intParams {
+		if raw := ctx.Query(p.name); raw != "" {
+			v, err := strconv.Atoi(raw)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s %q", p.name, raw)
+			}
+			*p.value = v
+		}
+	}
+
+	if raw := ctx.Query("project_id"); raw != "" {
+		projectID, err := strconv.Atoi(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid project_id %q", raw)
+		}
+		query.ProjectID = &projectID
+	}
+
+	if raw := ctx.Query("type"); raw != "" {
+		for _, t := range strings.Split(raw, ",") {
+			query.Types = append(query.Types, strings.TrimSpace(t))
+		}
+	}
+
+	if raw := ctx.Query("assignee"); raw == "me" {
+		query.AssigneeMe = true
+	} else if raw != "" {
+		assigneeID, err := strconv.Atoi(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid assignee %q", raw)
+		}
+		query.AssigneeID = &assigneeID
+	}
+
+	if raw := ctx.Query("is_completed"); raw != "" {
+		isCompleted, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid is_completed value %q", raw)
+		}
+		query.IsCompleted = &isCompleted
+	}
+
+	return query, nil
+}
@@ -0,0 +1,70 @@
+package task_bulk_handler
+
+import (
+	"DataTask/internal/controller/rest/rest_error"
+	"DataTask/internal/domain/dto"
+	"DataTask/internal/usecase/task_bulk_usecase"
+	"DataTask/pkg/http/response"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type TaskBulkHandler struct {
+	useCase task_bulk_usecase.TaskBulkUseCase
+}
+
+func NewTaskBulkHandler(useCase task_bulk_usecase.TaskBulkUseCase) *TaskBulkHandler {
+	return &TaskBulkHandler{useCase: useCase}
+}
+
+type BulkTasksRequestParam struct {
+	Operation string `json:"operation" binding:"required" enums:"move,assign,unassign,label,complete,delete"`
+	TaskIDs   []int  `json:"task_ids" binding:"required" example:"12,15,16"` // At most 500 tasks
+
+	KanbanID *int `json:"kanban_id"` // Board of the same project, required for move
+	UserID   *int `json:"user_id"`   // Project member, required for assign and unassign
+	LabelID  *int `json:"label_id"`  // Required for label
+
+	// Apply every task on its own; by default one failing task rolls back the whole request
+	Partial bool `json:"partial"`
+}
+
+// HandleBulkTasks
+// @Summary Bulk Task Operation
+// @Description Move, assign, unassign, label, complete or delete many tasks at once. Edit permission is checked for every task, tasks already in the requested state count as successful. By default all tasks are changed or none: when one fails, the response carries its error and the per-task results. With partial set, every task is applied on its own and the response lists which ones failed.
+// @Tags Task
+// @Accept json
+// @Produce json
+// @Param request body BulkTasksRequestParam true "Operation and tasks"
+// @Success 200 {object} response.JSONResponse{data=dto.BulkTaskResult}
+// @Failure 400 {object} response.JSONResponse{data=dto.BulkTaskResult}
+// @Failure 403 {object} response.JSONResponse{data=dto.BulkTaskResult}
+// @Failure 404 {object} response.JSONResponse{data=dto.BulkTaskResult}
+// @Failure 409 {object} response.JSONResponse{data=dto.BulkTaskResult}
+// @Failure 500 {object} response.JSONResponse
+// @Router /task/bulk [post]
+func (h *TaskBulkHandler) HandleBulkTasks(ctx *gin.Context) {
+	var param BulkTasksRequestParam
+	if err := ctx.ShouldBindJSON(&param); err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, err.Error())
+		return
+	}
+
+	request := dto.BulkTaskRequest{
+		Operation: param.Operation,
+		TaskIDs:   param.TaskIDs,
+		KanbanID:  param.KanbanID,
+		UserID:    param.UserID,
+		LabelID:   param.LabelID,
+		Partial:   param.Partial,
+	}
+
+	result, err := h.useCase.ApplyBulk(ctx, &request)
+	if err != nil {
+		// result is set when a task rolled back the batch, so clients can tell which one failed.
+		response.JSON(ctx, rest_error.Status(err), false, result, err.Error())
+		return
+	}
+
+	response.JSON(ctx, http.StatusOK, true, result, "")
+}
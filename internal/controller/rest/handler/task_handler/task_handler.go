@@ -1,12 +1,17 @@
 package task_handler
 
 import (
+	"DataTask/internal/controller/rest/rest_error"
+	"DataTask/internal/controller/rest/rest_query"
 	"DataTask/internal/domain/dto"
 	"DataTask/internal/usecase/task_usecase"
 	"DataTask/pkg/http/response"
+	"fmt"
 	"github.com/gin-gonic/gin"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 )
 
 type TaskHandler struct {
@@ -19,18 +24,105 @@ func NewTaskHandler(useCase task_usecase.TaskUseCase) *TaskHandler {
 
 // CreateTaskRequestParam for request parameters to avoid binding unwanted fields
 type CreateTaskRequestParam struct {
-	Title       string `json:"title" binding:"required"`
-	Description string `json:"description" binding:"required"`
+	// Title and description are required unless the task is created from a template
+	Title       string `json:"title"`
+	Description string `json:"description"`
 	IsCompleted bool   `json:"is_completed"`
 	// Add other necessary fields for creation here, e.g., ProjectID, KanbanID, etc.
 	// Assuming these might come from the route or other means if not in the body
 	KanbanID int `json:"kanban_id" binding:"required"` // Example: assuming kanban_id is required in body for task creation
+
+	// Dates are RFC 3339 timestamps with an offset, e.g. 2025-05-20T18:00:00+03:00
+	StartAt  *time.Time `json:"start_at"`
+	DueAt    *time.Time `json:"due_at"`
+	TimeZone string     `json:"time_zone" example:"Europe/Moscow"` // IANA name, defaults to UTC
+
+	Priority string `json:"priority" enums:"none,low,medium,high,urgent"` // Defaults to none
+
+	Estimate *float64 `json:"estimate" example:"3.5"` // Hours or story points, see the project settings
+
+	// Workflow status of the project; is_completed then follows its category. Defaults to the
+	// board's status, or the first status agreeing with is_completed
+	StatusID *int `json:"status_id"`
+
+	ParentTaskID *int `json:"parent_task_id"` // Makes the task a subtask; the parent must be in the same project
+
+	// Fills the title from the template's pattern, where {title} stands for the given title, and
+	// unset fields from the template; its labels, checklist and assignees are added to the task
+	TemplateID *int `json:"template_id"`
 }
 
 type UpdateTaskRequestParam struct {
 	Title       string `json:"title"`
 	Description string `json:"description"`
 	IsCompleted *bool  `json:"is_completed"` // Use pointer to distinguish between false and not provided
+
+	StartAt      *time.Time `json:"start_at"`
+	DueAt        *time.Time `json:"due_at"`
+	ClearStartAt bool       `json:"clear_start_at"` // Remove start_at from the task
+	ClearDueAt   bool       `json:"clear_due_at"`   // Remove due_at from the task
+	TimeZone     *string    `json:"time_zone"`
+
+	Priority *string `json:"priority" enums:"none,low,medium,high,urgent"`
+
+	Estimate      *float64 `json:"estimate" example:"3.5"`
+	ClearEstimate bool     `json:"clear_estimate"` // Remove the estimate from the task
+
+	// Must be allowed by the workflow's transitions. Without it, changing is_completed moves the
+	// task to the first reachable status of the matching category
+	StatusID *int `json:"status_id"`
+
+	ParentTaskID      *int `json:"parent_task_id"`
+	ClearParentTaskID bool `json:"clear_parent_task_id"` // Turn the subtask into a top-level task
+}
+
+// parseTaskFilter reads the optional list filters from the query string.
+func parseTaskFilter(ctx *gin.Context) (*dto.TaskFilter, error) {
+	filter := &dto.TaskFilter{}
+
+	if overdueStr := ctx.Query("overdue"); overdueStr != "" {
+		overdue, err := strconv.ParseBool(overdueStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid overdue value %q", overdueStr)
+		}
+		filter.Overdue = &overdue
+	}
+
+	if priorityStr := ctx.Query("priority"); priorityStr != "" {
+		filter.Priorities = strings.Split(priorityStr, ",")
+	}
+
+	if labelStr := ctx.Query("label"); labelStr != "" {
+		for _, idStr := range strings.Split(labelStr, ",") {
+			labelID, err := strconv.Atoi(strings.TrimSpace(idStr))
+			if err != nil {
+				return nil, fmt.Errorf("invalid label id %q", idStr)
+			}
+			filter.LabelIDs = append(filter.LabelIDs, labelID)
+		}
+	}
+
+	// Custom fields are filtered as field[<field id>]=<value>.
+	for idStr, value := range ctx.QueryMap("field") {
+		fieldID, err := strconv.Atoi(idStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid custom field id %q", idStr)
+		}
+		if filter.CustomFields == nil {
+			filter.CustomFields = make(map[int]string)
+		}
+		filter.CustomFields[fieldID] = value
+	}
+
+	filter.Query = ctx.Query("filter")
+
+	sort, err := task_usecase.ParseTaskSort(ctx.Query("sort"))
+	if err != nil {
+		return nil, err
+	}
+	filter.Sort = sort
+
+	return filter, nil
 }
 
 // HandleCreateTask
@@ -50,6 +142,10 @@ func (h *TaskHandler) HandleCreateTask(ctx *gin.Context) {
 		response.JSON(ctx, http.StatusBadRequest, false, nil, err.Error())
 		return
 	}
+	if param.TemplateID == nil && (param.Title == "" || param.Description == "") {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "title and description are required without template_id")
+		return
+	}
 
 	// Map request param data to dto.Task for use case
 	task := dto.Task{
@@ -57,11 +153,20 @@ func (h *TaskHandler) HandleCreateTask(ctx *gin.Context) {
 		Description: param.Description,
 		IsCompleted: param.IsCompleted,
 		KanbanID:    param.KanbanID,
+		StartAt:     param.StartAt,
+		DueAt:       param.DueAt,
+		TimeZone:    param.TimeZone,
+		Priority:    param.Priority,
+		Estimate:    param.Estimate,
+		StatusID:    param.StatusID,
+
+		ParentTaskID: param.ParentTaskID,
+		TemplateID:   param.TemplateID,
 	}
 
 	createdTask, err := h.useCase.CreateTask(ctx, &task)
 	if err != nil {
-		response.JSON(ctx, http.StatusInternalServerError, false, nil, err.Error())
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
 		return
 	}
 
@@ -70,10 +175,11 @@ func (h *TaskHandler) HandleCreateTask(ctx *gin.Context) {
 
 // HandleGetTaskByID
 // @Summary Get Task by ID
-// @Description Get a Task by its ID
+// @Description Get a Task by its ID or key such as DT-123, including checklist and subtask progress
 // @Tags Task
 // @Produce json
-// @Param id path int true "Task ID"
+// @Param id path string true "Task ID or key"
+// @Param render query string false "html adds description_html, the description as sanitized HTML" Enums(html)
 // @Success 200 {object} response.JSONResponse{data=dto.Task}
 // @Failure 400 {object} response.JSONResponse
 // @Failure 404 {object} response.JSONResponse
@@ -87,9 +193,15 @@ func (h *TaskHandler) HandleGetTaskByID(ctx *gin.Context) {
 		return
 	}
 
+	render, err := rest_query.ParseRender(ctx)
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, err.Error())
+		return
+	}
+
 	task, err := h.useCase.GetTaskByID(ctx, id)
 	if err != nil {
-		response.JSON(ctx, http.StatusInternalServerError, false, nil, err.Error())
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
 		return
 	}
 	if task == nil {
@@ -97,6 +209,10 @@ func (h *TaskHandler) HandleGetTaskByID(ctx *gin.Context) {
 		return
 	}
 
+	if render && !h.renderDescriptions(ctx, []*dto.Task{task}) {
+		return
+	}
+
 	response.JSON(ctx, http.StatusOK, true, task, "")
 }
 
@@ -110,6 +226,7 @@ func (h *TaskHandler) HandleGetTaskByID(ctx *gin.Context) {
 // @Param request body UpdateTaskRequestParam true "Updated Task data"
 // @Success 200 {object} response.JSONResponse{data=dto.Task}
 // @Failure 400 {object} response.JSONResponse
+// @Failure 409 {object} response.JSONResponse "Completing a task with open subtasks under the block policy"
 // @Failure 500 {object} response.JSONResponse
 // @Router /task/{id} [put]
 func (h *TaskHandler) HandleUpdateTask(ctx *gin.Context) {
@@ -126,26 +243,37 @@ func (h *TaskHandler) HandleUpdateTask(ctx *gin.Context) {
 		return
 	}
 
-	// Map request param data to a struct for use case
+	// Map request param data to a partial update for use case
 	// Only include fields that were potentially provided
-	updateData := dto.Task{ID: id} // Start with the ID from the path
+	updateData := dto.TaskUpdate{
+		IsCompleted:  param.IsCompleted,
+		StartAt:      param.StartAt,
+		DueAt:        param.DueAt,
+		ClearStartAt: param.ClearStartAt,
+		ClearDueAt:   param.ClearDueAt,
+		TimeZone:     param.TimeZone,
+		Priority:     param.Priority,
+
+		Estimate:      param.Estimate,
+		ClearEstimate: param.ClearEstimate,
+
+		StatusID: param.StatusID,
+
+		ParentTaskID:      param.ParentTaskID,
+		ClearParentTaskID: param.ClearParentTaskID,
+	}
 
-	// Check if fields were provided in the request body and update the struct
 	// This logic can be more sophisticated if needed (e.g., distinguishing empty string vs not provided)
 	if param.Title != "" { // Assuming empty string means not provided or cleared
-		updateData.Title = param.Title
+		updateData.Title = &param.Title
 	}
 	if param.Description != "" { // Assuming empty string means not provided or cleared
-		updateData.Description = param.Description
-	}
-	if param.IsCompleted != nil { // Check if the pointer is not nil
-		updateData.IsCompleted = *param.IsCompleted
+		updateData.Description = &param.Description
 	}
 
-	// The use case should handle partial updates based on the provided fields
-	updatedTask, err := h.useCase.UpdateTask(ctx, &updateData)
+	updatedTask, err := h.useCase.UpdateTask(ctx, id, &updateData)
 	if err != nil {
-		response.JSON(ctx, http.StatusInternalServerError, false, nil, err.Error())
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
 		return
 	}
 
@@ -154,7 +282,7 @@ func (h *TaskHandler) HandleUpdateTask(ctx *gin.Context) {
 
 // HandleDeleteTask
 // @Summary Delete Task
-// @Description Delete a Task by its ID
+// @Description Move a Task with its subtasks to the trash
 // @Tags Task
 // @Produce json
 // @Param id path int true "Task ID"
@@ -179,12 +307,58 @@ func (h *TaskHandler) HandleDeleteTask(ctx *gin.Context) {
 	ctx.Status(http.StatusNoContent) // 204 No Content for successful deletion
 }
 
+// HandleGetSubtasks
+// @Summary Get Subtasks
+// @Description Get the direct subtasks of a task
+// @Tags Task
+// @Produce json
+// @Param id path int true "Task ID"
+// @Param render query string false "html adds description_html, the description as sanitized HTML" Enums(html)
+// @Success 200 {object} response.JSONResponse{data=[]dto.Task}
+// @Failure 400 {object} response.JSONResponse
+// @Failure 404 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /task/{id}/subtasks [get]
+func (h *TaskHandler) HandleGetSubtasks(ctx *gin.Context) {
+	idStr := ctx.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Task ID")
+		return
+	}
+
+	render, err := rest_query.ParseRender(ctx)
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, err.Error())
+		return
+	}
+
+	tasks, err := h.useCase.GetSubtasks(ctx, id)
+	if err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	if render && !h.renderDescriptions(ctx, tasks) {
+		return
+	}
+
+	response.JSON(ctx, http.StatusOK, true, tasks, "")
+}
+
 // HandleGetTasksByKanbanID
 // @Summary Get Tasks by Kanban ID
 // @Description Get all tasks associated with a Kanban board
 // @Tags Task
 // @Produce json
 // @Param kanban_id path int true "Kanban Board ID"
+// @Param overdue query bool false "Only overdue (true) or not overdue (false) tasks"
+// @Param priority query string false "Comma-separated priorities, e.g. high,urgent"
+// @Param label query string false "Comma-separated label IDs; tasks with any of them match"
+// @Param field[id] query string false "Custom field value, e.g. field[3]=prod; multi-select takes comma-separated options that must all be set"
+// @Param filter query string false "Filter expression, e.g. assignee:me AND is_completed:false AND updated>2025-05-01; see the README"
+// @Param sort query string false "Comma-separated keys: title, priority, due_at, created_at, updated_at, estimate or field.<id>; prefix with - to sort descending"
+// @Param render query string false "html adds description_html, the description as sanitized HTML" Enums(html)
 // @Success 200 {object} response.JSONResponse{data=[]dto.Task}
 // @Failure 400 {object} response.JSONResponse
 // @Failure 500 {object} response.JSONResponse
@@ -197,9 +371,25 @@ func (h *TaskHandler) HandleGetTasksByKanbanID(ctx *gin.Context) {
 		return
 	}
 
-	tasks, err := h.useCase.GetTasksByKanbanID(ctx, kanbanID)
+	render, err := rest_query.ParseRender(ctx)
 	if err != nil {
-		response.JSON(ctx, http.StatusInternalServerError, false, nil, err.Error())
+		response.JSON(ctx, http.StatusBadRequest, false, nil, err.Error())
+		return
+	}
+
+	filter, err := parseTaskFilter(ctx)
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, err.Error())
+		return
+	}
+
+	tasks, err := h.useCase.GetTasksByKanbanID(ctx, kanbanID, filter)
+	if err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	if render && !h.renderDescriptions(ctx, tasks) {
 		return
 	}
 
@@ -212,6 +402,13 @@ func (h *TaskHandler) HandleGetTasksByKanbanID(ctx *gin.Context) {
 // @Tags Task
 // @Produce json
 // @Param user_id path int true "User ID"
+// @Param overdue query bool false "Only overdue (true) or not overdue (false) tasks"
+// @Param priority query string false "Comma-separated priorities, e.g. high,urgent"
+// @Param label query string false "Comma-separated label IDs; tasks with any of them match"
+// @Param field[id] query string false "Custom field value, e.g. field[3]=prod; multi-select takes comma-separated options that must all be set"
+// @Param filter query string false "Filter expression, e.g. assignee:me AND is_completed:false AND updated>2025-05-01; see the README"
+// @Param sort query string false "Comma-separated keys: title, priority, due_at, created_at, updated_at, estimate or field.<id>; prefix with - to sort descending"
+// @Param render query string false "html adds description_html, the description as sanitized HTML" Enums(html)
 // @Success 200 {object} response.JSONResponse{data=[]dto.Task}
 // @Failure 400 {object} response.JSONResponse
 // @Failure 500 {object} response.JSONResponse
@@ -224,9 +421,25 @@ func (h *TaskHandler) HandleGetTasksByUserID(ctx *gin.Context) {
 		return
 	}
 
-	tasks, err := h.useCase.GetTasksByUserID(ctx, userID)
+	render, err := rest_query.ParseRender(ctx)
 	if err != nil {
-		response.JSON(ctx, http.StatusInternalServerError, false, nil, err.Error())
+		response.JSON(ctx, http.StatusBadRequest, false, nil, err.Error())
+		return
+	}
+
+	filter, err := parseTaskFilter(ctx)
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, err.Error())
+		return
+	}
+
+	tasks, err := h.useCase.GetTasksByUserID(ctx, userID, filter)
+	if err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	if render && !h.renderDescriptions(ctx, tasks) {
 		return
 	}
 
@@ -235,7 +448,7 @@ func (h *TaskHandler) HandleGetTasksByUserID(ctx *gin.Context) {
 
 // HandleAssignUserToTask
 // @Summary Assign User to Task
-// @Description Assign a user to a task
+// @Description Assign a project member to a task. Prefer POST /task/{task_id}/assignees, which returns the assignee list
 // @Tags Task
 // @Accept json
 // @Produce json
@@ -243,6 +456,8 @@ func (h *TaskHandler) HandleGetTasksByUserID(ctx *gin.Context) {
 // @Param request body map[string]int true "User ID to assign"
 // @Success 200 {object} response.JSONResponse
 // @Failure 400 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 409 {object} response.JSONResponse
 // @Failure 500 {object} response.JSONResponse
 // @Router /task/{task_id}/assign [post]
 func (h *TaskHandler) HandleAssignUserToTask(ctx *gin.Context) {
@@ -267,7 +482,7 @@ func (h *TaskHandler) HandleAssignUserToTask(ctx *gin.Context) {
 
 	err = h.useCase.AssignUserToTask(ctx, taskID, userID)
 	if err != nil {
-		response.JSON(ctx, http.StatusInternalServerError, false, nil, err.Error())
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
 		return
 	}
 
@@ -281,6 +496,13 @@ func (h *TaskHandler) HandleAssignUserToTask(ctx *gin.Context) {
 //	@Tags Task
 //	@Produce json
 //	@Param project_id path int true "Project ID"
+//	@Param overdue query bool false "Only overdue (true) or not overdue (false) tasks"
+//	@Param priority query string false "Comma-separated priorities, e.g. high,urgent"
+//	@Param label query string false "Comma-separated label IDs; tasks with any of them match"
+//	@Param field[id] query string false "Custom field value, e.g. field[3]=prod; multi-select takes comma-separated options that must all be set"
+//	@Param filter query string false "Filter expression, e.g. assignee:me AND is_completed:false AND updated>2025-05-01; see the README"
+//	@Param sort query string false "Comma-separated keys: title, priority, due_at, created_at, updated_at, estimate or field.<id>; prefix with - to sort descending"
+//	@Param render query string false "html adds description_html, the description as sanitized HTML" Enums(html)
 //	@Success 200 {object} response.JSONResponse{data=[]dto.Task}
 //	@Failure 400 {object} response.JSONResponse
 //	@Failure 500 {object} response.JSONResponse
@@ -293,11 +515,36 @@ func (h *TaskHandler) HandleGetTasksByProjectID(ctx *gin.Context) {
 		return
 	}
 
-	tasks, err := h.useCase.GetTasksByProjectID(ctx, projectID)
+	render, err := rest_query.ParseRender(ctx)
 	if err != nil {
-		response.JSON(ctx, http.StatusInternalServerError, false, nil, err.Error())
+		response.JSON(ctx, http.StatusBadRequest, false, nil, err.Error())
+		return
+	}
+
+	filter, err := parseTaskFilter(ctx)
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, err.Error())
+		return
+	}
+
+	tasks, err := h.useCase.GetTasksByProjectID(ctx, projectID, filter)
+	if err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	if render && !h.renderDescriptions(ctx, tasks) {
 		return
 	}
 
 	response.JSON(ctx, http.StatusOK, true, tasks, "")
 }
+
+// renderDescriptions adds the description HTML to tasks, writing the error response if it fails.
+func (h *TaskHandler) renderDescriptions(ctx *gin.Context, tasks []*dto.Task) bool {
+	if err := h.useCase.RenderDescriptions(ctx, tasks); err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return false
+	}
+	return true
+}
@@ -0,0 +1,130 @@
+package task_link_handler
+
+import (
+	"DataTask/internal/controller/rest/rest_error"
+	"DataTask/internal/domain/dto"
+	"DataTask/internal/usecase/task_link_usecase"
+	"DataTask/pkg/http/response"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+type TaskLinkHandler struct {
+	useCase task_link_usecase.TaskLinkUseCase
+}
+
+func NewTaskLinkHandler(useCase task_link_usecase.TaskLinkUseCase) *TaskLinkHandler {
+	return &TaskLinkHandler{useCase: useCase}
+}
+
+type CreateTaskLinkRequestParam struct {
+	Type   string `json:"type" binding:"required" enums:"blocks,blocked_by,relates_to,duplicates"`
+	TaskID int    `json:"task_id" binding:"required"` // The task to link to; may belong to another project
+}
+
+// HandleCreateTaskLink
+// @Summary Link Tasks
+// @Description Link the task to another one. Blocking links that would form a cycle are rejected
+// @Tags Task Link
+// @Accept json
+// @Produce json
+// @Param task_id path int true "Task ID"
+// @Param request body CreateTaskLinkRequestParam true "Link data"
+// @Success 201 {object} response.JSONResponse{data=dto.TaskLink}
+// @Failure 400 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 409 {object} response.JSONResponse "Duplicate link or dependency cycle"
+// @Failure 500 {object} response.JSONResponse
+// @Router /task/{task_id}/links [post]
+func (h *TaskLinkHandler) HandleCreateTaskLink(ctx *gin.Context) {
+	taskIDStr := ctx.Param("task_id")
+	taskID, err := strconv.Atoi(taskIDStr)
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Task ID")
+		return
+	}
+
+	var param CreateTaskLinkRequestParam
+	if err := ctx.ShouldBindJSON(&param); err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, err.Error())
+		return
+	}
+
+	link := dto.TaskLinkCreate{
+		Type:   param.Type,
+		TaskID: param.TaskID,
+	}
+
+	createdLink, err := h.useCase.CreateLink(ctx, taskID, &link)
+	if err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	response.JSON(ctx, http.StatusCreated, true, createdLink, "")
+}
+
+// HandleGetTaskLinks
+// @Summary Get Task Links
+// @Description Get the outgoing and incoming links of a task
+// @Tags Task Link
+// @Produce json
+// @Param id path int true "Task ID"
+// @Success 200 {object} response.JSONResponse{data=dto.TaskLinks}
+// @Failure 400 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /task/{id}/links [get]
+func (h *TaskLinkHandler) HandleGetTaskLinks(ctx *gin.Context) {
+	idStr := ctx.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Task ID")
+		return
+	}
+
+	links, err := h.useCase.GetLinks(ctx, id)
+	if err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	response.JSON(ctx, http.StatusOK, true, links, "")
+}
+
+// HandleDeleteTaskLink
+// @Summary Unlink Tasks
+// @Description Remove a link from the task
+// @Tags Task Link
+// @Produce json
+// @Param id path int true "Task ID"
+// @Param link_id path int true "Link ID"
+// @Success 204 {object} response.JSONResponse
+// @Failure 400 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 404 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /task/{id}/links/{link_id} [delete]
+func (h *TaskLinkHandler) HandleDeleteTaskLink(ctx *gin.Context) {
+	idStr := ctx.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Task ID")
+		return
+	}
+
+	linkIDStr := ctx.Param("link_id")
+	linkID, err := strconv.Atoi(linkIDStr)
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Link ID")
+		return
+	}
+
+	if err := h.useCase.DeleteLink(ctx, id, linkID); err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
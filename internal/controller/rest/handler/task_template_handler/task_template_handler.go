@@ -0,0 +1,211 @@
+package task_template_handler
+
+import (
+	"DataTask/internal/controller/rest/rest_error"
+	"DataTask/internal/domain/dto"
+	"DataTask/internal/usecase/task_template_usecase"
+	"DataTask/pkg/http/response"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+type TaskTemplateHandler struct {
+	useCase task_template_usecase.TaskTemplateUseCase
+}
+
+func NewTaskTemplateHandler(useCase task_template_usecase.TaskTemplateUseCase) *TaskTemplateHandler {
+	return &TaskTemplateHandler{useCase: useCase}
+}
+
+type CreateTaskTemplateRequestParam struct {
+	ProjectID int    `json:"project_id" binding:"required"`
+	Name      string `json:"name" binding:"required" example:"Bug report"`
+	// {title} is replaced by the title given when creating a task, {date} by the current date
+	// and {project} by the project name
+	TitlePattern string   `json:"title_pattern" binding:"required" example:"Bug: {title}"`
+	Description  string   `json:"description"`
+	Priority     string   `json:"priority" enums:"none,low,medium,high,urgent"` // Defaults to none
+	LabelIDs     []int    `json:"label_ids"`                                    // Labels available in the project
+	Checklist    []string `json:"checklist" example:"Steps to reproduce,Expected result"`
+	AssigneeIDs  []int    `json:"assignee_ids"` // Project members
+}
+
+type UpdateTaskTemplateRequestParam struct {
+	Name         *string  `json:"name"`
+	TitlePattern *string  `json:"title_pattern"`
+	Description  *string  `json:"description"`
+	Priority     *string  `json:"priority" enums:"none,low,medium,high,urgent"`
+	LabelIDs     []int    `json:"label_ids"`    // Replaces the labels when given
+	Checklist    []string `json:"checklist"`    // Replaces the checklist when given
+	AssigneeIDs  []int    `json:"assignee_ids"` // Replaces the assignees when given
+}
+
+// HandleCreateTaskTemplate
+// @Summary Create Task Template
+// @Description Define a task template for a project and its subprojects
+// @Tags TaskTemplate
+// @Accept json
+// @Produce json
+// @Param request body CreateTaskTemplateRequestParam true "Task template data"
+// @Success 201 {object} response.JSONResponse{data=dto.TaskTemplate}
+// @Failure 400 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 409 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /task_template [post]
+func (h *TaskTemplateHandler) HandleCreateTaskTemplate(ctx *gin.Context) {
+	var param CreateTaskTemplateRequestParam
+	if err := ctx.ShouldBindJSON(&param); err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, err.Error())
+		return
+	}
+
+	template := dto.TaskTemplate{
+		ProjectID:    param.ProjectID,
+		Name:         param.Name,
+		TitlePattern: param.TitlePattern,
+		Description:  param.Description,
+		Priority:     param.Priority,
+		LabelIDs:     param.LabelIDs,
+		Checklist:    param.Checklist,
+		AssigneeIDs:  param.AssigneeIDs,
+	}
+
+	createdTemplate, err := h.useCase.CreateTemplate(ctx, &template)
+	if err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	response.JSON(ctx, http.StatusCreated, true, createdTemplate, "")
+}
+
+// HandleGetTaskTemplateByID
+// @Summary Get Task Template by ID
+// @Description Get a task template by its ID
+// @Tags TaskTemplate
+// @Produce json
+// @Param id path int true "Task Template ID"
+// @Success 200 {object} response.JSONResponse{data=dto.TaskTemplate}
+// @Failure 400 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 404 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /task_template/{id} [get]
+func (h *TaskTemplateHandler) HandleGetTaskTemplateByID(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Task Template ID")
+		return
+	}
+
+	template, err := h.useCase.GetTemplateByID(ctx, id)
+	if err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	response.JSON(ctx, http.StatusOK, true, template, "")
+}
+
+// HandleGetTaskTemplatesByProjectID
+// @Summary Get Task Templates by Project ID
+// @Description Get the task templates usable in a project, including the ones inherited from parent projects
+// @Tags TaskTemplate
+// @Produce json
+// @Param project_id path int true "Project ID"
+// @Success 200 {object} response.JSONResponse{data=[]dto.TaskTemplate}
+// @Failure 400 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /task_template/project/{project_id} [get]
+func (h *TaskTemplateHandler) HandleGetTaskTemplatesByProjectID(ctx *gin.Context) {
+	projectID, err := strconv.Atoi(ctx.Param("project_id"))
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Project ID")
+		return
+	}
+
+	templates, err := h.useCase.GetTemplatesByProjectID(ctx, projectID)
+	if err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	response.JSON(ctx, http.StatusOK, true, templates, "")
+}
+
+// HandleUpdateTaskTemplate
+// @Summary Update Task Template
+// @Description Change a task template. Tasks created from it earlier stay unchanged
+// @Tags TaskTemplate
+// @Accept json
+// @Produce json
+// @Param id path int true "Task Template ID"
+// @Param request body UpdateTaskTemplateRequestParam true "Fields to change"
+// @Success 200 {object} response.JSONResponse{data=dto.TaskTemplate}
+// @Failure 400 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 404 {object} response.JSONResponse
+// @Failure 409 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /task_template/{id} [put]
+func (h *TaskTemplateHandler) HandleUpdateTaskTemplate(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Task Template ID")
+		return
+	}
+
+	var param UpdateTaskTemplateRequestParam
+	if err := ctx.ShouldBindJSON(&param); err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, err.Error())
+		return
+	}
+
+	update := dto.TaskTemplateUpdate{
+		Name:         param.Name,
+		TitlePattern: param.TitlePattern,
+		Description:  param.Description,
+		Priority:     param.Priority,
+		LabelIDs:     param.LabelIDs,
+		Checklist:    param.Checklist,
+		AssigneeIDs:  param.AssigneeIDs,
+	}
+
+	template, err := h.useCase.UpdateTemplate(ctx, id, &update)
+	if err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	response.JSON(ctx, http.StatusOK, true, template, "")
+}
+
+// HandleDeleteTaskTemplate
+// @Summary Delete Task Template
+// @Description Delete a task template. Tasks created from it are kept
+// @Tags TaskTemplate
+// @Produce json
+// @Param id path int true "Task Template ID"
+// @Success 204 {object} response.JSONResponse
+// @Failure 400 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 404 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /task_template/{id} [delete]
+func (h *TaskTemplateHandler) HandleDeleteTaskTemplate(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Task Template ID")
+		return
+	}
+
+	if err := h.useCase.DeleteTemplate(ctx, id); err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,373 @@
+package timetracking_handler
+
+import (
+	"DataTask/internal/controller/rest/rest_error"
+	"DataTask/internal/domain/dto"
+	"DataTask/internal/usecase/timetracking_usecase"
+	"DataTask/pkg/http/response"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const dateLayout = "2006-01-02"
+
+type TimeTrackingHandler struct {
+	useCase timetracking_usecase.TimeTrackingUseCase
+}
+
+func NewTimeTrackingHandler(useCase timetracking_usecase.TimeTrackingUseCase) *TimeTrackingHandler {
+	return &TimeTrackingHandler{useCase: useCase}
+}
+
+type StopTimerRequestParam struct {
+	Note string `json:"note" example:"Reviewed the migration"`
+}
+
+type CreateWorklogRequestParam struct {
+	DurationMinutes int        `json:"duration_minutes" binding:"required,min=1" example:"90"`
+	StartedAt       *time.Time `json:"started_at"` // Defaults to now minus the duration
+	Note            string     `json:"note" example:"Pair programming"`
+}
+
+type UpdateWorklogRequestParam struct {
+	DurationMinutes *int       `json:"duration_minutes" example:"45"`
+	StartedAt       *time.Time `json:"started_at"`
+	Note            *string    `json:"note"`
+}
+
+// HandleStartTimer
+// @Summary Start Timer
+// @Description Start the current user's timer on the task. Fails with 409 while another timer is running
+// @Tags TimeTracking
+// @Produce json
+// @Param task_id path int true "Task ID"
+// @Success 201 {object} response.JSONResponse{data=dto.Worklog}
+// @Failure 400 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 409 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /task/{task_id}/timer/start [post]
+func (h *TimeTrackingHandler) HandleStartTimer(ctx *gin.Context) {
+	taskID, err := strconv.Atoi(ctx.Param("task_id"))
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Task ID")
+		return
+	}
+
+	timer, err := h.useCase.StartTimer(ctx, taskID)
+	if err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	response.JSON(ctx, http.StatusCreated, true, timer, "")
+}
+
+// HandleStopTimer
+// @Summary Stop Timer
+// @Description Stop the current user's timer on the task and keep it as a worklog
+// @Tags TimeTracking
+// @Accept json
+// @Produce json
+// @Param task_id path int true "Task ID"
+// @Param request body StopTimerRequestParam false "Worklog note"
+// @Success 200 {object} response.JSONResponse{data=dto.Worklog}
+// @Failure 400 {object} response.JSONResponse
+// @Failure 404 {object} response.JSONResponse
+// @Failure 409 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /task/{task_id}/timer/stop [post]
+func (h *TimeTrackingHandler) HandleStopTimer(ctx *gin.Context) {
+	taskID, err := strconv.Atoi(ctx.Param("task_id"))
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Task ID")
+		return
+	}
+
+	var param StopTimerRequestParam
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&param); err != nil {
+			response.JSON(ctx, http.StatusBadRequest, false, nil, err.Error())
+			return
+		}
+	}
+
+	worklog, err := h.useCase.StopTimer(ctx, taskID, param.Note)
+	if err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	response.JSON(ctx, http.StatusOK, true, worklog, "")
+}
+
+// HandleGetRunningTimer
+// @Summary Get Running Timer
+// @Description Get the current user's running timer
+// @Tags TimeTracking
+// @Produce json
+// @Success 200 {object} response.JSONResponse{data=dto.Worklog}
+// @Failure 404 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /timetracking/timer [get]
+func (h *TimeTrackingHandler) HandleGetRunningTimer(ctx *gin.Context) {
+	timer, err := h.useCase.GetRunningTimer(ctx)
+	if err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	response.JSON(ctx, http.StatusOK, true, timer, "")
+}
+
+// HandleCreateWorklog
+// @Summary Create Worklog
+// @Description Log time spent on the task manually
+// @Tags TimeTracking
+// @Accept json
+// @Produce json
+// @Param task_id path int true "Task ID"
+// @Param request body CreateWorklogRequestParam true "Worklog data"
+// @Success 201 {object} response.JSONResponse{data=dto.Worklog}
+// @Failure 400 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /task/{task_id}/worklogs [post]
+func (h *TimeTrackingHandler) HandleCreateWorklog(ctx *gin.Context) {
+	taskID, err := strconv.Atoi(ctx.Param("task_id"))
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Task ID")
+		return
+	}
+
+	var param CreateWorklogRequestParam
+	if err := ctx.ShouldBindJSON(&param); err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, err.Error())
+		return
+	}
+
+	worklog := dto.WorklogCreate{
+		TaskID:    taskID,
+		Duration:  time.Duration(param.DurationMinutes) * time.Minute,
+		StartedAt: param.StartedAt,
+		Note:      param.Note,
+	}
+
+	createdWorklog, err := h.useCase.CreateWorklog(ctx, &worklog)
+	if err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	response.JSON(ctx, http.StatusCreated, true, createdWorklog, "")
+}
+
+// HandleGetWorklogs
+// @Summary Get Task Worklogs
+// @Description Get the worklogs of the task, optionally limited to a period
+// @Tags TimeTracking
+// @Produce json
+// @Param id path int true "Task ID"
+// @Param from query string false "Start of the period, YYYY-MM-DD or RFC 3339"
+// @Param to query string false "End of the period (a date is inclusive), YYYY-MM-DD or RFC 3339"
+// @Success 200 {object} response.JSONResponse{data=[]dto.Worklog}
+// @Failure 400 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /task/{id}/worklogs [get]
+func (h *TimeTrackingHandler) HandleGetWorklogs(ctx *gin.Context) {
+	taskID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Task ID")
+		return
+	}
+
+	from, to, err := parsePeriod(ctx)
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, err.Error())
+		return
+	}
+
+	worklogs, err := h.useCase.GetWorklogsByTaskID(ctx, taskID, from, to)
+	if err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	response.JSON(ctx, http.StatusOK, true, worklogs, "")
+}
+
+// HandleUpdateWorklog
+// @Summary Update Worklog
+// @Description Change a finished worklog. Users edit their own worklogs, project owners any worklog
+// @Tags TimeTracking
+// @Accept json
+// @Produce json
+// @Param id path int true "Task ID"
+// @Param worklog_id path int true "Worklog ID"
+// @Param request body UpdateWorklogRequestParam true "Fields to change"
+// @Success 200 {object} response.JSONResponse{data=dto.Worklog}
+// @Failure 400 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 404 {object} response.JSONResponse
+// @Failure 409 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /task/{id}/worklogs/{worklog_id} [put]
+func (h *TimeTrackingHandler) HandleUpdateWorklog(ctx *gin.Context) {
+	taskID, worklogID, ok := parseWorklogPath(ctx)
+	if !ok {
+		return
+	}
+
+	var param UpdateWorklogRequestParam
+	if err := ctx.ShouldBindJSON(&param); err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, err.Error())
+		return
+	}
+
+	update := dto.WorklogUpdate{
+		StartedAt: param.StartedAt,
+		Note:      param.Note,
+	}
+	if param.DurationMinutes != nil {
+		duration := time.Duration(*param.DurationMinutes) * time.Minute
+		update.Duration = &duration
+	}
+
+	worklog, err := h.useCase.UpdateWorklog(ctx, taskID, worklogID, &update)
+	if err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	response.JSON(ctx, http.StatusOK, true, worklog, "")
+}
+
+// HandleDeleteWorklog
+// @Summary Delete Worklog
+// @Description Delete a worklog. Users delete their own worklogs, project owners any worklog
+// @Tags TimeTracking
+// @Produce json
+// @Param id path int true "Task ID"
+// @Param worklog_id path int true "Worklog ID"
+// @Success 204 {object} response.JSONResponse
+// @Failure 400 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 404 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /task/{id}/worklogs/{worklog_id} [delete]
+func (h *TimeTrackingHandler) HandleDeleteWorklog(ctx *gin.Context) {
+	taskID, worklogID, ok := parseWorklogPath(ctx)
+	if !ok {
+		return
+	}
+
+	if err := h.useCase.DeleteWorklog(ctx, taskID, worklogID); err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// HandleGetTimeTotals
+// @Summary Get Time Totals
+// @Description Sum the logged time per task and per user. One of project_id, task_id or user_id is required; running timers count up to now
+// @Tags TimeTracking
+// @Produce json
+// @Param project_id query int false "Project ID"
+// @Param task_id query int false "Task ID"
+// @Param user_id query int false "User ID"
+// @Param from query string false "Start of the period, YYYY-MM-DD or RFC 3339"
+// @Param to query string false "End of the period (a date is inclusive), YYYY-MM-DD or RFC 3339"
+// @Success 200 {object} response.JSONResponse{data=dto.TimeTotals}
+// @Failure 400 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /timetracking/totals [get]
+func (h *TimeTrackingHandler) HandleGetTimeTotals(ctx *gin.Context) {
+	filter := &dto.TimeTotalsFilter{}
+
+	for name, target := range map[string]**int{
+		"project_id": &filter.ProjectID,
+		"task_id":    &filter.TaskID,
+		"user_id":    &filter.UserID,
+	} {
+		idStr := ctx.Query(name)
+		if idStr == "" {
+			continue
+		}
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			response.JSON(ctx, http.StatusBadRequest, false, nil, fmt.Sprintf("invalid %s %q", name, idStr))
+			return
+		}
+		*target = &id
+	}
+
+	var err error
+	filter.From, filter.To, err = parsePeriod(ctx)
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, err.Error())
+		return
+	}
+
+	totals, err := h.useCase.GetTimeTotals(ctx, filter)
+	if err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	response.JSON(ctx, http.StatusOK, true, totals, "")
+}
+
+// parsePeriod reads the optional from/to query parameters. A plain date in "to" includes the whole day.
+func parsePeriod(ctx *gin.Context) (*time.Time, *time.Time, error) {
+	from, _, err := parseTime(ctx.Query("from"))
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid from value: %w", err)
+	}
+
+	to, isDate, err := parseTime(ctx.Query("to"))
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid to value: %w", err)
+	}
+	if to != nil && isDate {
+		endOfDay := to.Add(24 * time.Hour)
+		to = &endOfDay
+	}
+	return from, to, nil
+}
+
+func parseTime(value string) (*time.Time, bool, error) {
+	if value == "" {
+		return nil, false, nil
+	}
+	if t, err := time.Parse(dateLayout, value); err == nil {
+		return &t, true, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, false, err
+	}
+	return &t, false, nil
+}
+
+// parseWorklogPath reads the task id and worklog_id from the URL and answers 400 when either is malformed.
+func parseWorklogPath(ctx *gin.Context) (int, int, bool) {
+	taskID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Task ID")
+		return 0, 0, false
+	}
+
+	worklogID, err := strconv.Atoi(ctx.Param("worklog_id"))
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Worklog ID")
+		return 0, 0, false
+	}
+	return taskID, worklogID, true
+}
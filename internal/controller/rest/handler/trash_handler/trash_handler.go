@@ -0,0 +1,149 @@
+package trash_handler
+
+import (
+	"DataTask/internal/controller/rest/rest_error"
+	"DataTask/internal/usecase/trash_usecase"
+	"DataTask/pkg/http/response"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+type TrashHandler struct {
+	useCase trash_usecase.TrashUseCase
+}
+
+func NewTrashHandler(useCase trash_usecase.TrashUseCase) *TrashHandler {
+	return &TrashHandler{useCase: useCase}
+}
+
+// HandleGetProjectTrash
+// @Summary Get Project Trash
+// @Description Get the deleted subprojects, boards and tasks of a project, newest first. Tasks deleted together with their board or parent task are not listed on their own.
+// @Tags Trash
+// @Produce json
+// @Param id path int true "Project ID"
+// @Success 200 {object} response.JSONResponse{data=[]dto.TrashItem}
+// @Failure 400 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 404 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /project/{id}/trash [get]
+func (h *TrashHandler) HandleGetProjectTrash(ctx *gin.Context) {
+	projectID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Project ID")
+		return
+	}
+
+	items, err := h.useCase.GetProjectTrash(ctx, projectID)
+	if err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	response.JSON(ctx, http.StatusOK, true, items, "")
+}
+
+// HandleGetTrashedProjects
+// @Summary Get Trashed Projects
+// @Description Get the deleted projects owned by the current user
+// @Tags Trash
+// @Produce json
+// @Success 200 {object} response.JSONResponse{data=[]dto.TrashItem}
+// @Failure 401 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /project/trash [get]
+func (h *TrashHandler) HandleGetTrashedProjects(ctx *gin.Context) {
+	items, err := h.useCase.GetTrashedProjects(ctx)
+	if err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	response.JSON(ctx, http.StatusOK, true, items, "")
+}
+
+// HandleRestoreProject
+// @Summary Restore Project
+// @Description Restore a deleted project with the boards and tasks deleted along with it. Owner only.
+// @Tags Trash
+// @Produce json
+// @Param id path int true "Project ID"
+// @Success 204
+// @Failure 400 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 404 {object} response.JSONResponse
+// @Failure 409 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /project/{id}/restore [post]
+func (h *TrashHandler) HandleRestoreProject(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Project ID")
+		return
+	}
+
+	if err := h.useCase.RestoreProject(ctx, id); err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// HandleRestoreKanban
+// @Summary Restore Kanban
+// @Description Restore a deleted board with the tasks deleted along with it
+// @Tags Trash
+// @Produce json
+// @Param id path int true "Kanban ID"
+// @Success 204
+// @Failure 400 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 404 {object} response.JSONResponse
+// @Failure 409 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /kanban/{id}/restore [post]
+func (h *TrashHandler) HandleRestoreKanban(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Kanban ID")
+		return
+	}
+
+	if err := h.useCase.RestoreKanban(ctx, id); err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// HandleRestoreTask
+// @Summary Restore Task
+// @Description Restore a deleted task with the subtasks deleted along with it
+// @Tags Trash
+// @Produce json
+// @Param task_id path int true "Task ID"
+// @Success 204
+// @Failure 400 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 404 {object} response.JSONResponse
+// @Failure 409 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /task/{task_id}/restore [post]
+func (h *TrashHandler) HandleRestoreTask(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("task_id"))
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Task ID")
+		return
+	}
+
+	if err := h.useCase.RestoreTask(ctx, id); err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,327 @@
+package workflow_handler
+
+import (
+	"DataTask/internal/controller/rest/rest_error"
+	"DataTask/internal/domain/dto"
+	"DataTask/internal/usecase/workflow_usecase"
+	"DataTask/pkg/http/response"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+type WorkflowHandler struct {
+	useCase workflow_usecase.WorkflowUseCase
+}
+
+func NewWorkflowHandler(useCase workflow_usecase.WorkflowUseCase) *WorkflowHandler {
+	return &WorkflowHandler{useCase: useCase}
+}
+
+type CreateWorkflowStatusRequestParam struct {
+	Name     string `json:"name" binding:"required" example:"In Review"`
+	Category string `json:"category" binding:"required" enums:"todo,in_progress,done"`
+}
+
+type UpdateWorkflowStatusRequestParam struct {
+	Name *string `json:"name"`
+	// Tasks with the status are completed or reopened to match the new category
+	Category *string `json:"category" enums:"todo,in_progress,done"`
+}
+
+type ReorderWorkflowStatusesRequestParam struct {
+	StatusIDs []int `json:"status_ids" binding:"required"` // Every status of the project, in the new order
+}
+
+type WorkflowTransitionRequestParam struct {
+	FromStatusID int `json:"from_status_id" binding:"required"`
+	ToStatusID   int `json:"to_status_id" binding:"required"`
+}
+
+type SetWorkflowTransitionsRequestParam struct {
+	// Replaces the allowed transitions; an empty list allows every change
+	Transitions []WorkflowTransitionRequestParam `json:"transitions" binding:"dive"`
+}
+
+type SetKanbanStatusRequestParam struct {
+	StatusID *int `json:"status_id"` // null unmaps the kanban
+}
+
+// HandleGetWorkflow
+// @Summary Get Workflow
+// @Description Get the statuses of a project, the transitions allowed between them and the status each kanban is mapped to
+// @Tags Workflow
+// @Produce json
+// @Param id path int true "Project ID"
+// @Success 200 {object} response.JSONResponse{data=dto.Workflow}
+// @Failure 400 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /project/{id}/workflow [get]
+func (h *WorkflowHandler) HandleGetWorkflow(ctx *gin.Context) {
+	projectID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Project ID")
+		return
+	}
+
+	workflow, err := h.useCase.GetWorkflow(ctx, projectID)
+	if err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	response.JSON(ctx, http.StatusOK, true, workflow, "")
+}
+
+// HandleCreateWorkflowStatus
+// @Summary Create Workflow Status
+// @Description Add a status to a project's workflow, after the existing ones
+// @Tags Workflow
+// @Accept json
+// @Produce json
+// @Param id path int true "Project ID"
+// @Param request body CreateWorkflowStatusRequestParam true "Status data"
+// @Success 201 {object} response.JSONResponse{data=dto.WorkflowStatus}
+// @Failure 400 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 409 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /project/{id}/workflow/statuses [post]
+func (h *WorkflowHandler) HandleCreateWorkflowStatus(ctx *gin.Context) {
+	projectID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Project ID")
+		return
+	}
+
+	var param CreateWorkflowStatusRequestParam
+	if err := ctx.ShouldBindJSON(&param); err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, err.Error())
+		return
+	}
+
+	status := dto.WorkflowStatus{
+		ProjectID: projectID,
+		Name:      param.Name,
+		Category:  param.Category,
+	}
+
+	createdStatus, err := h.useCase.CreateStatus(ctx, &status)
+	if err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	response.JSON(ctx, http.StatusCreated, true, createdStatus, "")
+}
+
+// HandleUpdateWorkflowStatus
+// @Summary Update Workflow Status
+// @Description Rename a status or change its category
+// @Tags Workflow
+// @Accept json
+// @Produce json
+// @Param id path int true "Project ID"
+// @Param status_id path int true "Status ID"
+// @Param request body UpdateWorkflowStatusRequestParam true "Fields to change"
+// @Success 200 {object} response.JSONResponse{data=dto.WorkflowStatus}
+// @Failure 400 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 404 {object} response.JSONResponse
+// @Failure 409 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /project/{id}/workflow/statuses/{status_id} [put]
+func (h *WorkflowHandler) HandleUpdateWorkflowStatus(ctx *gin.Context) {
+	projectID, statusID, ok := parseStatusPath(ctx)
+	if !ok {
+		return
+	}
+
+	var param UpdateWorkflowStatusRequestParam
+	if err := ctx.ShouldBindJSON(&param); err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, err.Error())
+		return
+	}
+
+	update := dto.WorkflowStatusUpdate{
+		Name:     param.Name,
+		Category: param.Category,
+	}
+
+	status, err := h.useCase.UpdateStatus(ctx, projectID, statusID, &update)
+	if err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	response.JSON(ctx, http.StatusOK, true, status, "")
+}
+
+// HandleDeleteWorkflowStatus
+// @Summary Delete Workflow Status
+// @Description Delete a status. Its tasks and kanbans move to the replacement status, which is required while tasks still have the status
+// @Tags Workflow
+// @Produce json
+// @Param id path int true "Project ID"
+// @Param status_id path int true "Status ID"
+// @Param replacement_id query int false "Status taking over the tasks and kanbans"
+// @Success 204 {object} response.JSONResponse
+// @Failure 400 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 404 {object} response.JSONResponse
+// @Failure 409 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /project/{id}/workflow/statuses/{status_id} [delete]
+func (h *WorkflowHandler) HandleDeleteWorkflowStatus(ctx *gin.Context) {
+	projectID, statusID, ok := parseStatusPath(ctx)
+	if !ok {
+		return
+	}
+
+	var replacementID *int
+	if replacementStr := ctx.Query("replacement_id"); replacementStr != "" {
+		id, err := strconv.Atoi(replacementStr)
+		if err != nil {
+			response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Replacement Status ID")
+			return
+		}
+		replacementID = &id
+	}
+
+	if err := h.useCase.DeleteStatus(ctx, projectID, statusID, replacementID); err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// HandleReorderWorkflowStatuses
+// @Summary Reorder Workflow Statuses
+// @Description Set the order of a project's statuses
+// @Tags Workflow
+// @Accept json
+// @Produce json
+// @Param id path int true "Project ID"
+// @Param request body ReorderWorkflowStatusesRequestParam true "Status IDs in the new order"
+// @Success 200 {object} response.JSONResponse{data=dto.Workflow}
+// @Failure 400 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /project/{id}/workflow/statuses/reorder [post]
+func (h *WorkflowHandler) HandleReorderWorkflowStatuses(ctx *gin.Context) {
+	projectID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Project ID")
+		return
+	}
+
+	var param ReorderWorkflowStatusesRequestParam
+	if err := ctx.ShouldBindJSON(&param); err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, err.Error())
+		return
+	}
+
+	workflow, err := h.useCase.ReorderStatuses(ctx, projectID, param.StatusIDs)
+	if err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	response.JSON(ctx, http.StatusOK, true, workflow, "")
+}
+
+// HandleSetWorkflowTransitions
+// @Summary Set Workflow Transitions
+// @Description Replace the status changes allowed in a project
+// @Tags Workflow
+// @Accept json
+// @Produce json
+// @Param id path int true "Project ID"
+// @Param request body SetWorkflowTransitionsRequestParam true "Allowed transitions"
+// @Success 200 {object} response.JSONResponse{data=dto.Workflow}
+// @Failure 400 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /project/{id}/workflow/transitions [put]
+func (h *WorkflowHandler) HandleSetWorkflowTransitions(ctx *gin.Context) {
+	projectID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Project ID")
+		return
+	}
+
+	var param SetWorkflowTransitionsRequestParam
+	if err := ctx.ShouldBindJSON(&param); err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, err.Error())
+		return
+	}
+
+	transitions := make([]*dto.WorkflowTransition, 0, len(param.Transitions))
+	for _, t := range param.Transitions {
+		transitions = append(transitions, &dto.WorkflowTransition{
+			FromStatusID: t.FromStatusID,
+			ToStatusID:   t.ToStatusID,
+		})
+	}
+
+	workflow, err := h.useCase.SetTransitions(ctx, projectID, transitions)
+	if err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	response.JSON(ctx, http.StatusOK, true, workflow, "")
+}
+
+// HandleSetKanbanStatus
+// @Summary Map Kanban to Status
+// @Description Set the status tasks take when moved onto a kanban
+// @Tags Workflow
+// @Accept json
+// @Produce json
+// @Param id path int true "Kanban ID"
+// @Param request body SetKanbanStatusRequestParam true "Status of the kanban"
+// @Success 200 {object} response.JSONResponse{data=dto.WorkflowColumn}
+// @Failure 400 {object} response.JSONResponse
+// @Failure 403 {object} response.JSONResponse
+// @Failure 404 {object} response.JSONResponse
+// @Failure 500 {object} response.JSONResponse
+// @Router /kanban/{id}/status [put]
+func (h *WorkflowHandler) HandleSetKanbanStatus(ctx *gin.Context) {
+	kanbanID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Kanban ID")
+		return
+	}
+
+	var param SetKanbanStatusRequestParam
+	if err := ctx.ShouldBindJSON(&param); err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, err.Error())
+		return
+	}
+
+	column, err := h.useCase.SetColumnStatus(ctx, kanbanID, param.StatusID)
+	if err != nil {
+		response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+		return
+	}
+
+	response.JSON(ctx, http.StatusOK, true, column, "")
+}
+
+func parseStatusPath(ctx *gin.Context) (int, int, bool) {
+	projectID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Project ID")
+		return 0, 0, false
+	}
+
+	statusID, err := strconv.Atoi(ctx.Param("status_id"))
+	if err != nil {
+		response.JSON(ctx, http.StatusBadRequest, false, nil, "Invalid Status ID")
+		return 0, 0, false
+	}
+	return projectID, statusID, true
+}
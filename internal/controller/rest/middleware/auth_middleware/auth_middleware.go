@@ -58,3 +58,36 @@ func (m *AuthMiddleware) Middleware() gin.HandlerFunc {
 		ctx.Next()
 	}
 }
+
+// OptionalMiddleware signs in the user of a valid access token like Middleware, for public routes
+// that answer differently for signed in users. Requests without a valid token go through as
+// anonymous.
+func (m *AuthMiddleware) OptionalMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		authorization := ctx.GetHeader("Authorization")
+		if authorization == "" {
+			ctx.Next()
+			return
+		}
+
+		claims, err := jwt.VerifyJWT(authorization, m.jwtSecretKey)
+		if err != nil {
+			ctx.Next()
+			return
+		}
+		userEmail, ok := claims.(jwtLib.MapClaims)["user_email"].(string)
+		if !ok {
+			ctx.Next()
+			return
+		}
+
+		u, err := m.useCase.GetUserEntityByEmail(ctx, userEmail)
+		if err == nil && u != nil {
+			ctx.Set("user", claims)
+			ctx.Set("user_email", userEmail)
+			ctx.Set("user_id", u.ID)
+		}
+
+		ctx.Next()
+	}
+}
@@ -0,0 +1,46 @@
+package task_key_middleware
+
+import (
+	"DataTask/internal/controller/rest/rest_error"
+	"DataTask/internal/usecase/task_usecase"
+	"DataTask/pkg/http/response"
+	"DataTask/pkg/taskkey"
+	"github.com/gin-gonic/gin"
+	"strconv"
+)
+
+// taskParams are the path parameters holding a task ID on the task routes.
+var taskParams = map[string]bool{"id": true, "task_id": true}
+
+// TaskKeyMiddleware lets task routes take a task key such as DT-123 wherever they take a task ID,
+// by replacing the key with the ID of its task before the handler runs.
+type TaskKeyMiddleware struct {
+	useCase task_usecase.TaskUseCase
+}
+
+func NewTaskKeyMiddleware(useCase task_usecase.TaskUseCase) *TaskKeyMiddleware {
+	return &TaskKeyMiddleware{useCase: useCase}
+}
+
+func (m *TaskKeyMiddleware) Middleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		for i, param := range ctx.Params {
+			if !taskParams[param.Key] {
+				continue
+			}
+			if _, _, ok := taskkey.Parse(param.Value); !ok {
+				continue
+			}
+
+			id, err := m.useCase.ResolveTaskKey(ctx, param.Value)
+			if err != nil {
+				ctx.Abort()
+				response.JSON(ctx, rest_error.Status(err), false, nil, err.Error())
+				return
+			}
+			ctx.Params[i].Value = strconv.Itoa(id)
+		}
+
+		ctx.Next()
+	}
+}
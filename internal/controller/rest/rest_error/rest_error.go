@@ -0,0 +1,25 @@
+package rest_error
+
+import (
+	"DataTask/internal/domain/domain_error"
+	"errors"
+	"net/http"
+)
+
+// Status maps a usecase error to an HTTP status code. Unknown errors are reported as 500.
+func Status(err error) int {
+	switch {
+	case errors.Is(err, domain_error.ErrValidation):
+		return http.StatusBadRequest
+	case errors.Is(err, domain_error.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain_error.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, domain_error.ErrUnauthenticated):
+		return http.StatusUnauthorized
+	case errors.Is(err, domain_error.ErrForbidden):
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,19 @@
+package rest_query
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ParseRender reports whether the request asks for sanitized HTML with render=html.
+func ParseRender(ctx *gin.Context) (bool, error) {
+	switch render := ctx.Query("render"); render {
+	case "":
+		return false, nil
+	case "html":
+		return true, nil
+	default:
+		return false, fmt.Errorf("invalid render value %q, expected html", render)
+	}
+}
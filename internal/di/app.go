@@ -2,12 +2,31 @@ package di
 
 import (
 	"DataTask/internal/config"
+	"DataTask/internal/controller/rest/handler/assignee_handler"
+	"DataTask/internal/controller/rest/handler/attachment_handler"
+	"DataTask/internal/controller/rest/handler/board_handler"
+	"DataTask/internal/controller/rest/handler/checklist_handler"
+	"DataTask/internal/controller/rest/handler/clone_handler"
 	"DataTask/internal/controller/rest/handler/comment_handler"
+	"DataTask/internal/controller/rest/handler/custom_field_handler"
+	"DataTask/internal/controller/rest/handler/history_handler"
 	"DataTask/internal/controller/rest/handler/kanban_handler"
+	"DataTask/internal/controller/rest/handler/label_handler"
 	"DataTask/internal/controller/rest/handler/project_handler"
+	"DataTask/internal/controller/rest/handler/recurrence_handler"
+	"DataTask/internal/controller/rest/handler/saved_view_handler"
+	"DataTask/internal/controller/rest/handler/search_handler"
+	"DataTask/internal/controller/rest/handler/task_bulk_handler"
 	"DataTask/internal/controller/rest/handler/task_handler"
+	"DataTask/internal/controller/rest/handler/task_link_handler"
+	"DataTask/internal/controller/rest/handler/task_template_handler"
+	"DataTask/internal/controller/rest/handler/timetracking_handler"
+	"DataTask/internal/controller/rest/handler/trash_handler"
 	"DataTask/internal/controller/rest/handler/users_handler"
+	"DataTask/internal/controller/rest/handler/workflow_handler"
 	"DataTask/internal/controller/rest/middleware/auth_middleware"
+	"DataTask/internal/controller/rest/middleware/task_key_middleware"
+	"DataTask/internal/worker"
 	"database/sql"
 	"github.com/gin-gonic/gin"
 )
@@ -24,8 +43,30 @@ type App struct {
 	TaskHandler    *task_handler.TaskHandler
 	ProjectHandler *project_handler.ProjectHandler
 	CommentHandler *comment_handler.CommentHandler
+	LabelHandler   *label_handler.LabelHandler
 
-	AuthMiddleware *auth_middleware.AuthMiddleware
+	ChecklistHandler *checklist_handler.ChecklistHandler
+	TaskLinkHandler  *task_link_handler.TaskLinkHandler
+
+	RecurrenceHandler   *recurrence_handler.RecurrenceHandler
+	TimeTrackingHandler *timetracking_handler.TimeTrackingHandler
+	CustomFieldHandler  *custom_field_handler.CustomFieldHandler
+	AttachmentHandler   *attachment_handler.AttachmentHandler
+	AssigneeHandler     *assignee_handler.AssigneeHandler
+	HistoryHandler      *history_handler.HistoryHandler
+	TrashHandler        *trash_handler.TrashHandler
+	TaskTemplateHandler *task_template_handler.TaskTemplateHandler
+	TaskBulkHandler     *task_bulk_handler.TaskBulkHandler
+	SearchHandler       *search_handler.SearchHandler
+	SavedViewHandler    *saved_view_handler.SavedViewHandler
+	CloneHandler        *clone_handler.CloneHandler
+	WorkflowHandler     *workflow_handler.WorkflowHandler
+	BoardHandler        *board_handler.BoardHandler
+
+	AuthMiddleware    *auth_middleware.AuthMiddleware
+	TaskKeyMiddleware *task_key_middleware.TaskKeyMiddleware
+
+	Workers []*worker.PeriodicWorker
 }
 
 func InitializeApp(cfg *config.Config) (*App, error) {
@@ -38,12 +79,59 @@ func InitializeApp(cfg *config.Config) (*App, error) {
 	prometheusHandler := InitializePrometheusHandler()
 	swaggerHandler := InitializeSwaggerHandler(cfg, "DataTask")
 	usersHandler := InitializeUsersHandler(db, cfg.JWT.Secret)
-	kanbanHandler := InitializeKanbanHandler(db)
-	taskHandler := InitializeTaskHandler(db)
-	projectHandler := InitializeProjectHandler(db)
-	commentHandler := InitializeCommentHandler(db)
+
+	notifier := InitializeNotifier(cfg)
+
+	accessUseCase := InitializeAccessUseCase(db)
+	kanbanUseCase := InitializeKanbanUseCase(db, accessUseCase)
+	kanbanHandler := InitializeKanbanHandler(kanbanUseCase)
+	historyUseCase := InitializeHistoryUseCase(db, accessUseCase)
+	historyHandler := InitializeHistoryHandler(historyUseCase)
+	mentionUseCase := InitializeMentionUseCase(db, accessUseCase, notifier)
+	markdownUseCase := InitializeMarkdownUseCase(db, cfg.Markdown)
+	commentHandler := InitializeCommentHandler(db, historyUseCase, mentionUseCase, markdownUseCase)
+	taskLinkUseCase := InitializeTaskLinkUseCase(db, accessUseCase)
+	taskLinkHandler := InitializeTaskLinkHandler(taskLinkUseCase)
+	recurrenceUseCase := InitializeRecurrenceUseCase(db, cfg.Recurrence, accessUseCase)
+	recurrenceHandler := InitializeRecurrenceHandler(recurrenceUseCase)
+	timeTrackingUseCase := InitializeTimeTrackingUseCase(db, accessUseCase)
+	timeTrackingHandler := InitializeTimeTrackingHandler(timeTrackingUseCase)
+	customFieldUseCase := InitializeCustomFieldUseCase(db, accessUseCase)
+	customFieldHandler := InitializeCustomFieldHandler(customFieldUseCase)
+	blobStorage, err := InitializeBlobStorage(cfg.Attachments)
+	if err != nil {
+		return nil, err
+	}
+	attachmentUseCase := InitializeAttachmentUseCase(db, cfg.Attachments, blobStorage, accessUseCase)
+	attachmentHandler := InitializeAttachmentHandler(attachmentUseCase, cfg.Attachments)
+	assigneeUseCase := InitializeAssigneeUseCase(db, accessUseCase, historyUseCase, notifier)
+	assigneeHandler := InitializeAssigneeHandler(assigneeUseCase)
+	taskTemplateUseCase := InitializeTaskTemplateUseCase(db, accessUseCase)
+	taskTemplateHandler := InitializeTaskTemplateHandler(taskTemplateUseCase)
+	taskUseCase := InitializeTaskUseCase(
+		db, cfg, taskLinkUseCase, recurrenceUseCase, timeTrackingUseCase, customFieldUseCase, assigneeUseCase,
+		historyUseCase, taskTemplateUseCase, mentionUseCase, markdownUseCase,
+	)
+	taskHandler := InitializeTaskHandler(taskUseCase)
+	projectHandler := InitializeProjectHandler(db, accessUseCase)
+	labelUseCase := InitializeLabelUseCase(db, accessUseCase)
+	labelHandler := InitializeLabelHandler(labelUseCase)
+	taskBulkUseCase := InitializeTaskBulkUseCase(db, taskUseCase, assigneeUseCase, labelUseCase, accessUseCase)
+	taskBulkHandler := InitializeTaskBulkHandler(taskBulkUseCase)
+	searchHandler := InitializeSearchHandler(db, accessUseCase)
+	savedViewHandler := InitializeSavedViewHandler(db, taskUseCase, accessUseCase)
+	checklistHandler := InitializeChecklistHandler(db, accessUseCase)
+	trashUseCase := InitializeTrashUseCase(db, cfg.Trash, accessUseCase)
+	trashHandler := InitializeTrashHandler(trashUseCase)
+	cloneUseCase := InitializeCloneUseCase(db, cfg.Clone, accessUseCase)
+	cloneHandler := InitializeCloneHandler(cloneUseCase)
+	workflowHandler := InitializeWorkflowHandler(db, accessUseCase)
+	boardHandler := InitializeBoardHandler(db, kanbanUseCase, taskUseCase, accessUseCase)
 
 	authMiddleware := InitializeAuthMiddleware(db, cfg.JWT.Secret)
+	taskKeyMiddleware := InitializeTaskKeyMiddleware(taskUseCase)
+
+	workers := InitializeWorkers(db, cfg, notifier, attachmentUseCase, trashUseCase, cloneUseCase)
 
 	return &App{
 		Config: cfg,
@@ -55,7 +143,28 @@ func InitializeApp(cfg *config.Config) (*App, error) {
 		TaskHandler:       taskHandler,
 		ProjectHandler:    projectHandler,
 		CommentHandler:    commentHandler,
+		LabelHandler:      labelHandler,
+		ChecklistHandler:  checklistHandler,
+		TaskLinkHandler:   taskLinkHandler,
+		RecurrenceHandler: recurrenceHandler,
+
+		TimeTrackingHandler: timeTrackingHandler,
+		CustomFieldHandler:  customFieldHandler,
+		AttachmentHandler:   attachmentHandler,
+		AssigneeHandler:     assigneeHandler,
+		HistoryHandler:      historyHandler,
+		TrashHandler:        trashHandler,
+		TaskTemplateHandler: taskTemplateHandler,
+		TaskBulkHandler:     taskBulkHandler,
+		SearchHandler:       searchHandler,
+		SavedViewHandler:    savedViewHandler,
+		CloneHandler:        cloneHandler,
+		WorkflowHandler:     workflowHandler,
+		BoardHandler:        boardHandler,
+
+		AuthMiddleware:    authMiddleware,
+		TaskKeyMiddleware: taskKeyMiddleware,
 
-		AuthMiddleware: authMiddleware,
+		Workers: workers,
 	}, nil
 }
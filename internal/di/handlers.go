@@ -2,23 +2,80 @@ package di
 
 import (
 	"DataTask/internal/config"
+	"DataTask/internal/controller/rest/handler/assignee_handler"
+	"DataTask/internal/controller/rest/handler/attachment_handler"
+	"DataTask/internal/controller/rest/handler/board_handler"
+	"DataTask/internal/controller/rest/handler/checklist_handler"
+	"DataTask/internal/controller/rest/handler/clone_handler"
 	"DataTask/internal/controller/rest/handler/comment_handler"
+	"DataTask/internal/controller/rest/handler/custom_field_handler"
+	"DataTask/internal/controller/rest/handler/history_handler"
 	"DataTask/internal/controller/rest/handler/kanban_handler"
+	"DataTask/internal/controller/rest/handler/label_handler"
 	project_handler "DataTask/internal/controller/rest/handler/project_handler"
+	"DataTask/internal/controller/rest/handler/recurrence_handler"
+	"DataTask/internal/controller/rest/handler/saved_view_handler"
+	"DataTask/internal/controller/rest/handler/search_handler"
+	"DataTask/internal/controller/rest/handler/task_bulk_handler"
 	"DataTask/internal/controller/rest/handler/task_handler"
+	"DataTask/internal/controller/rest/handler/task_link_handler"
+	"DataTask/internal/controller/rest/handler/task_template_handler"
+	"DataTask/internal/controller/rest/handler/timetracking_handler"
+	"DataTask/internal/controller/rest/handler/trash_handler"
 	"DataTask/internal/controller/rest/handler/users_handler"
+	"DataTask/internal/controller/rest/handler/workflow_handler"
+	"DataTask/internal/notifier"
+	"DataTask/internal/repository/assignee_repository"
+	"DataTask/internal/repository/attachment_repository"
+	"DataTask/internal/repository/checklist_repository"
+	"DataTask/internal/repository/clone_repository"
 	"DataTask/internal/repository/comment_repository"
+	"DataTask/internal/repository/custom_field_repository"
+	"DataTask/internal/repository/database"
+	"DataTask/internal/repository/history_repository"
 	"DataTask/internal/repository/kanban_repository"
+	"DataTask/internal/repository/label_repository"
+	"DataTask/internal/repository/mention_repository"
 	"DataTask/internal/repository/project_repository"
+	"DataTask/internal/repository/recurrence_repository"
+	"DataTask/internal/repository/saved_view_repository"
+	"DataTask/internal/repository/search_repository"
+	"DataTask/internal/repository/task_link_repository"
 	"DataTask/internal/repository/task_repository"
+	"DataTask/internal/repository/task_template_repository"
+	"DataTask/internal/repository/trash_repository"
 	"DataTask/internal/repository/user_repository"
+	"DataTask/internal/repository/workflow_repository"
+	"DataTask/internal/repository/worklog_repository"
+	"DataTask/internal/usecase/access_usecase"
+	"DataTask/internal/usecase/assignee_usecase"
+	"DataTask/internal/usecase/attachment_usecase"
+	"DataTask/internal/usecase/board_usecase"
+	"DataTask/internal/usecase/checklist_usecase"
+	"DataTask/internal/usecase/clone_usecase"
 	"DataTask/internal/usecase/comment_usecase"
+	"DataTask/internal/usecase/custom_field_usecase"
+	"DataTask/internal/usecase/history_usecase"
 	"DataTask/internal/usecase/kanban_usecase"
+	"DataTask/internal/usecase/label_usecase"
+	"DataTask/internal/usecase/markdown_usecase"
+	"DataTask/internal/usecase/mention_usecase"
 	"DataTask/internal/usecase/project_usecase"
+	"DataTask/internal/usecase/recurrence_usecase"
+	"DataTask/internal/usecase/saved_view_usecase"
+	"DataTask/internal/usecase/search_usecase"
+	"DataTask/internal/usecase/task_bulk_usecase"
+	"DataTask/internal/usecase/task_link_usecase"
+	"DataTask/internal/usecase/task_template_usecase"
 	"DataTask/internal/usecase/task_usecase"
+	"DataTask/internal/usecase/timetracking_usecase"
+	"DataTask/internal/usecase/trash_usecase"
 	"DataTask/internal/usecase/user_usecase"
+	"DataTask/internal/usecase/workflow_usecase"
+	"DataTask/pkg/blob"
 	"DataTask/pkg/logger"
 	"database/sql"
+	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	log "github.com/sirupsen/logrus"
@@ -52,30 +109,288 @@ func InitializeUsersHandler(db *sql.DB, jwtSecretKey string) *users_handler.User
 	return handler
 }
 
-func InitializeKanbanHandler(db *sql.DB) *kanban_handler.KanbanHandler {
+func InitializeKanbanUseCase(db *sql.DB, access access_usecase.AccessUseCase) *kanban_usecase.KanbanUseCaseImpl {
 	repo := kanban_repository.NewPostgresKanbanRepository(db)
-	useCase := kanban_usecase.NewKanbanUseCase(repo)
+	return kanban_usecase.NewKanbanUseCase(repo, access)
+}
+
+func InitializeKanbanHandler(useCase kanban_usecase.KanbanUseCase) *kanban_handler.KanbanHandler {
 	handler := kanban_handler.NewKanbanHandler(useCase)
 	return handler
 }
 
-func InitializeCommentHandler(db *sql.DB) *comment_handler.CommentHandler {
+func InitializeCommentHandler(
+	db *sql.DB,
+	historyUseCase history_usecase.HistoryUseCase,
+	mentionUseCase mention_usecase.MentionUseCase,
+	markdownUseCase markdown_usecase.MarkdownUseCase,
+) *comment_handler.CommentHandler {
 	repo := comment_repository.NewPostgresCommentRepository(db)
-	useCase := comment_usecase.NewCommentUseCase(repo)
+	transactor := database.NewPostgresTransactor(db)
+	useCase := comment_usecase.NewCommentUseCase(repo, historyUseCase, mentionUseCase, markdownUseCase, transactor)
 	handler := comment_handler.NewCommentHandler(useCase)
 	return handler
 }
 
-func InitializeTaskHandler(db *sql.DB) *task_handler.TaskHandler {
+func InitializeTaskUseCase(
+	db *sql.DB,
+	cfg *config.Config,
+	linkUseCase task_link_usecase.TaskLinkUseCase,
+	recurrenceUseCase recurrence_usecase.RecurrenceUseCase,
+	timeTrackingUseCase timetracking_usecase.TimeTrackingUseCase,
+	customFieldUseCase custom_field_usecase.CustomFieldUseCase,
+	assigneeUseCase assignee_usecase.AssigneeUseCase,
+	historyUseCase history_usecase.HistoryUseCase,
+	templateUseCase task_template_usecase.TaskTemplateUseCase,
+	mentionUseCase mention_usecase.MentionUseCase,
+	markdownUseCase markdown_usecase.MarkdownUseCase,
+) *task_usecase.TaskUseCaseImpl {
 	repo := task_repository.NewPostgresTaskRepository(db)
-	useCase := task_usecase.NewTaskUseCase(repo)
-	handler := task_handler.NewTaskHandler(useCase)
-	return handler
+	labelRepo := label_repository.NewPostgresLabelRepository(db)
+	projectRepo := project_repository.NewPostgresProjectRepository(db)
+	workflowRepo := workflow_repository.NewPostgresWorkflowRepository(db)
+	kanbanRepo := kanban_repository.NewPostgresKanbanRepository(db)
+	transactor := database.NewPostgresTransactor(db)
+	return task_usecase.NewTaskUseCase(
+		repo, labelRepo, projectRepo, workflowRepo, kanbanRepo, linkUseCase, recurrenceUseCase, timeTrackingUseCase, customFieldUseCase,
+		assigneeUseCase, historyUseCase, templateUseCase, mentionUseCase, markdownUseCase, transactor,
+		cfg.Tasks.ParentCompletionPolicy,
+	)
+}
+
+func InitializeTaskHandler(useCase task_usecase.TaskUseCase) *task_handler.TaskHandler {
+	return task_handler.NewTaskHandler(useCase)
+}
+
+func InitializeTaskBulkUseCase(
+	db *sql.DB,
+	taskUseCase task_usecase.TaskUseCase,
+	assigneeUseCase assignee_usecase.AssigneeUseCase,
+	labelUseCase label_usecase.LabelUseCase,
+	access access_usecase.AccessUseCase,
+) *task_bulk_usecase.TaskBulkUseCaseImpl {
+	taskRepo := task_repository.NewPostgresTaskRepository(db)
+	transactor := database.NewPostgresTransactor(db)
+	return task_bulk_usecase.NewTaskBulkUseCase(taskRepo, taskUseCase, assigneeUseCase, labelUseCase, access, transactor)
+}
+
+func InitializeTaskBulkHandler(useCase task_bulk_usecase.TaskBulkUseCase) *task_bulk_handler.TaskBulkHandler {
+	return task_bulk_handler.NewTaskBulkHandler(useCase)
 }
 
-func InitializeProjectHandler(db *sql.DB) *project_handler.ProjectHandler {
+func InitializeProjectHandler(db *sql.DB, access access_usecase.AccessUseCase) *project_handler.ProjectHandler {
 	repo := project_repository.NewPostgresProjectRepository(db)
-	useCase := project_usecase.NewProjectUseCase(repo)
+	workflowRepo := workflow_repository.NewPostgresWorkflowRepository(db)
+	transactor := database.NewPostgresTransactor(db)
+	useCase := project_usecase.NewProjectUseCase(repo, workflowRepo, access, transactor)
 	handler := project_handler.NewProjectHandler(useCase)
 	return handler
 }
+
+func InitializeAccessUseCase(db *sql.DB) *access_usecase.AccessUseCaseImpl {
+	repo := project_repository.NewPostgresProjectRepository(db)
+	return access_usecase.NewAccessUseCase(repo)
+}
+
+func InitializeLabelUseCase(db *sql.DB, access access_usecase.AccessUseCase) *label_usecase.LabelUseCaseImpl {
+	repo := label_repository.NewPostgresLabelRepository(db)
+	return label_usecase.NewLabelUseCase(repo, access)
+}
+
+func InitializeLabelHandler(useCase label_usecase.LabelUseCase) *label_handler.LabelHandler {
+	return label_handler.NewLabelHandler(useCase)
+}
+
+func InitializeChecklistHandler(db *sql.DB, access access_usecase.AccessUseCase) *checklist_handler.ChecklistHandler {
+	repo := checklist_repository.NewPostgresChecklistRepository(db)
+	useCase := checklist_usecase.NewChecklistUseCase(repo, access)
+	handler := checklist_handler.NewChecklistHandler(useCase)
+	return handler
+}
+
+func InitializeTaskLinkUseCase(db *sql.DB, access access_usecase.AccessUseCase) *task_link_usecase.TaskLinkUseCaseImpl {
+	repo := task_link_repository.NewPostgresTaskLinkRepository(db)
+	transactor := database.NewPostgresTransactor(db)
+	return task_link_usecase.NewTaskLinkUseCase(repo, access, transactor)
+}
+
+func InitializeTaskLinkHandler(useCase task_link_usecase.TaskLinkUseCase) *task_link_handler.TaskLinkHandler {
+	return task_link_handler.NewTaskLinkHandler(useCase)
+}
+
+func InitializeRecurrenceUseCase(db *sql.DB, cfg config.Recurrence, access access_usecase.AccessUseCase) *recurrence_usecase.RecurrenceUseCaseImpl {
+	repo := recurrence_repository.NewPostgresRecurrenceRepository(db)
+	taskRepo := task_repository.NewPostgresTaskRepository(db)
+	transactor := database.NewPostgresTransactor(db)
+	return recurrence_usecase.NewRecurrenceUseCase(repo, taskRepo, access, transactor, cfg.Lookahead)
+}
+
+func InitializeRecurrenceHandler(useCase recurrence_usecase.RecurrenceUseCase) *recurrence_handler.RecurrenceHandler {
+	return recurrence_handler.NewRecurrenceHandler(useCase)
+}
+
+func InitializeTimeTrackingUseCase(db *sql.DB, access access_usecase.AccessUseCase) *timetracking_usecase.TimeTrackingUseCaseImpl {
+	repo := worklog_repository.NewPostgresWorklogRepository(db)
+	return timetracking_usecase.NewTimeTrackingUseCase(repo, access)
+}
+
+func InitializeTimeTrackingHandler(useCase timetracking_usecase.TimeTrackingUseCase) *timetracking_handler.TimeTrackingHandler {
+	return timetracking_handler.NewTimeTrackingHandler(useCase)
+}
+
+func InitializeCustomFieldUseCase(db *sql.DB, access access_usecase.AccessUseCase) *custom_field_usecase.CustomFieldUseCaseImpl {
+	repo := custom_field_repository.NewPostgresCustomFieldRepository(db)
+	transactor := database.NewPostgresTransactor(db)
+	return custom_field_usecase.NewCustomFieldUseCase(repo, access, transactor)
+}
+
+func InitializeCustomFieldHandler(useCase custom_field_usecase.CustomFieldUseCase) *custom_field_handler.CustomFieldHandler {
+	return custom_field_handler.NewCustomFieldHandler(useCase)
+}
+
+// InitializeBlobStorage opens the storage backend attachment files are kept in.
+func InitializeBlobStorage(cfg config.Attachments) (blob.Storage, error) {
+	switch cfg.Storage {
+	case "s3":
+		return blob.NewS3Storage(blob.S3Config{
+			Endpoint:  cfg.S3.Endpoint,
+			Region:    cfg.S3.Region,
+			Bucket:    cfg.S3.Bucket,
+			AccessKey: cfg.S3.AccessKey,
+			SecretKey: cfg.S3.SecretKey,
+			PathStyle: cfg.S3.PathStyle,
+		}, nil)
+	case "local", "":
+		return blob.NewLocalStorage(cfg.LocalDir)
+	default:
+		return nil, fmt.Errorf("unknown attachment storage %q", cfg.Storage)
+	}
+}
+
+func InitializeAttachmentUseCase(
+	db *sql.DB,
+	cfg config.Attachments,
+	storage blob.Storage,
+	access access_usecase.AccessUseCase,
+) *attachment_usecase.AttachmentUseCaseImpl {
+	repo := attachment_repository.NewPostgresAttachmentRepository(db)
+	return attachment_usecase.NewAttachmentUseCase(repo, access, storage, cfg.MaxSize, cfg.AllowedTypes)
+}
+
+func InitializeAttachmentHandler(useCase attachment_usecase.AttachmentUseCase, cfg config.Attachments) *attachment_handler.AttachmentHandler {
+	return attachment_handler.NewAttachmentHandler(useCase, cfg.MaxSize)
+}
+
+func InitializeAssigneeUseCase(
+	db *sql.DB,
+	access access_usecase.AccessUseCase,
+	historyUseCase history_usecase.HistoryUseCase,
+	notifier notifier.Notifier,
+) *assignee_usecase.AssigneeUseCaseImpl {
+	repo := assignee_repository.NewPostgresAssigneeRepository(db)
+	taskRepo := task_repository.NewPostgresTaskRepository(db)
+	transactor := database.NewPostgresTransactor(db)
+	return assignee_usecase.NewAssigneeUseCase(repo, taskRepo, access, historyUseCase, notifier, transactor)
+}
+
+func InitializeAssigneeHandler(useCase assignee_usecase.AssigneeUseCase) *assignee_handler.AssigneeHandler {
+	return assignee_handler.NewAssigneeHandler(useCase)
+}
+
+func InitializeMentionUseCase(
+	db *sql.DB,
+	access access_usecase.AccessUseCase,
+	notifier notifier.Notifier,
+) *mention_usecase.MentionUseCaseImpl {
+	repo := mention_repository.NewPostgresMentionRepository(db)
+	taskRepo := task_repository.NewPostgresTaskRepository(db)
+	projectRepo := project_repository.NewPostgresProjectRepository(db)
+	return mention_usecase.NewMentionUseCase(repo, taskRepo, projectRepo, access, notifier)
+}
+
+func InitializeMarkdownUseCase(db *sql.DB, cfg config.Markdown) *markdown_usecase.MarkdownUseCaseImpl {
+	mentionRepo := mention_repository.NewPostgresMentionRepository(db)
+	return markdown_usecase.NewMarkdownUseCase(mentionRepo, cfg.CacheSize, cfg.MentionURL)
+}
+
+func InitializeHistoryUseCase(db *sql.DB, access access_usecase.AccessUseCase) *history_usecase.HistoryUseCaseImpl {
+	repo := history_repository.NewPostgresHistoryRepository(db)
+	return history_usecase.NewHistoryUseCase(repo, access)
+}
+
+func InitializeHistoryHandler(useCase history_usecase.HistoryUseCase) *history_handler.HistoryHandler {
+	return history_handler.NewHistoryHandler(useCase)
+}
+
+func InitializeTaskTemplateUseCase(db *sql.DB, access access_usecase.AccessUseCase) *task_template_usecase.TaskTemplateUseCaseImpl {
+	repo := task_template_repository.NewPostgresTaskTemplateRepository(db)
+	labelRepo := label_repository.NewPostgresLabelRepository(db)
+	projectRepo := project_repository.NewPostgresProjectRepository(db)
+	return task_template_usecase.NewTaskTemplateUseCase(repo, labelRepo, projectRepo, access)
+}
+
+func InitializeTaskTemplateHandler(useCase task_template_usecase.TaskTemplateUseCase) *task_template_handler.TaskTemplateHandler {
+	return task_template_handler.NewTaskTemplateHandler(useCase)
+}
+
+func InitializeSearchHandler(db *sql.DB, access access_usecase.AccessUseCase) *search_handler.SearchHandler {
+	repo := search_repository.NewPostgresSearchRepository(db)
+	useCase := search_usecase.NewSearchUseCase(repo, access)
+	return search_handler.NewSearchHandler(useCase)
+}
+
+func InitializeSavedViewHandler(
+	db *sql.DB,
+	taskUseCase task_usecase.TaskUseCase,
+	access access_usecase.AccessUseCase,
+) *saved_view_handler.SavedViewHandler {
+	repo := saved_view_repository.NewPostgresSavedViewRepository(db)
+	kanbanRepo := kanban_repository.NewPostgresKanbanRepository(db)
+	useCase := saved_view_usecase.NewSavedViewUseCase(repo, kanbanRepo, taskUseCase, access)
+	return saved_view_handler.NewSavedViewHandler(useCase)
+}
+
+func InitializeBoardHandler(
+	db *sql.DB,
+	kanbanUseCase kanban_usecase.KanbanUseCase,
+	taskUseCase task_usecase.TaskUseCase,
+	access access_usecase.AccessUseCase,
+) *board_handler.BoardHandler {
+	taskRepo := task_repository.NewPostgresTaskRepository(db)
+	labelRepo := label_repository.NewPostgresLabelRepository(db)
+	fieldRepo := custom_field_repository.NewPostgresCustomFieldRepository(db)
+	projectRepo := project_repository.NewPostgresProjectRepository(db)
+	useCase := board_usecase.NewBoardUseCase(taskRepo, labelRepo, fieldRepo, projectRepo, kanbanUseCase, taskUseCase, access)
+	return board_handler.NewBoardHandler(useCase)
+}
+
+func InitializeCloneUseCase(db *sql.DB, cfg config.Clone, access access_usecase.AccessUseCase) *clone_usecase.CloneUseCaseImpl {
+	repo := clone_repository.NewPostgresCloneRepository(db)
+	taskRepo := task_repository.NewPostgresTaskRepository(db)
+	kanbanRepo := kanban_repository.NewPostgresKanbanRepository(db)
+	projectRepo := project_repository.NewPostgresProjectRepository(db)
+	transactor := database.NewPostgresTransactor(db)
+	return clone_usecase.NewCloneUseCase(repo, taskRepo, kanbanRepo, projectRepo, access, transactor, cfg.AsyncThreshold)
+}
+
+func InitializeCloneHandler(useCase clone_usecase.CloneUseCase) *clone_handler.CloneHandler {
+	return clone_handler.NewCloneHandler(useCase)
+}
+
+func InitializeTrashUseCase(db *sql.DB, cfg config.Trash, access access_usecase.AccessUseCase) *trash_usecase.TrashUseCaseImpl {
+	repo := trash_repository.NewPostgresTrashRepository(db)
+	transactor := database.NewPostgresTransactor(db)
+	return trash_usecase.NewTrashUseCase(repo, access, transactor, cfg.Retention)
+}
+
+func InitializeTrashHandler(useCase trash_usecase.TrashUseCase) *trash_handler.TrashHandler {
+	return trash_handler.NewTrashHandler(useCase)
+}
+
+func InitializeWorkflowHandler(db *sql.DB, access access_usecase.AccessUseCase) *workflow_handler.WorkflowHandler {
+	repo := workflow_repository.NewPostgresWorkflowRepository(db)
+	projectRepo := project_repository.NewPostgresProjectRepository(db)
+	transactor := database.NewPostgresTransactor(db)
+	useCase := workflow_usecase.NewWorkflowUseCase(repo, projectRepo, access, transactor)
+	return workflow_handler.NewWorkflowHandler(useCase)
+}
@@ -2,7 +2,9 @@ package di
 
 import (
 	"DataTask/internal/controller/rest/middleware/auth_middleware"
+	"DataTask/internal/controller/rest/middleware/task_key_middleware"
 	"DataTask/internal/repository/user_repository"
+	"DataTask/internal/usecase/task_usecase"
 	"DataTask/internal/usecase/user_usecase"
 	"database/sql"
 )
@@ -13,3 +15,7 @@ func InitializeAuthMiddleware(db *sql.DB, jwtSecretKey string) *auth_middleware.
 	middleware := auth_middleware.NewAuthMiddleware(useCase, jwtSecretKey)
 	return middleware
 }
+
+func InitializeTaskKeyMiddleware(taskUseCase task_usecase.TaskUseCase) *task_key_middleware.TaskKeyMiddleware {
+	return task_key_middleware.NewTaskKeyMiddleware(taskUseCase)
+}
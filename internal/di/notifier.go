@@ -0,0 +1,35 @@
+package di
+
+import (
+	"DataTask/infra/queue"
+	"DataTask/internal/config"
+	"DataTask/internal/notifier"
+	"DataTask/pkg/logger"
+	"fmt"
+)
+
+// InitializeNotifier connects to RabbitMQ for notification delivery and falls back to
+// logging notifications when the broker is unreachable.
+func InitializeNotifier(cfg *config.Config) notifier.Notifier {
+	amqpURL := fmt.Sprintf(
+		"amqp://%s:%s@%s:%s/",
+		cfg.RabbitMQ.User,
+		cfg.RabbitMQ.Pass,
+		cfg.RabbitMQ.Host,
+		cfg.RabbitMQ.Port,
+	)
+
+	publisher, err := queue.NewRabbitMQ(amqpURL)
+	if err != nil {
+		logger.Log.Warnf("rabbitmq is not available, notifications will only be logged: %v", err)
+		return notifier.NewLogNotifier()
+	}
+
+	if err := publisher.DeclareExchange(cfg.Notifications.Exchange, "topic"); err != nil {
+		logger.Log.Warnf("declare notifications exchange, notifications will only be logged: %v", err)
+		publisher.Close()
+		return notifier.NewLogNotifier()
+	}
+
+	return notifier.NewQueueNotifier(publisher, cfg.Notifications.Exchange)
+}
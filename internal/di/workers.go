@@ -0,0 +1,55 @@
+package di
+
+import (
+	"DataTask/internal/config"
+	"DataTask/internal/notifier"
+	"DataTask/internal/repository/reminder_repository"
+	"DataTask/internal/usecase/attachment_usecase"
+	"DataTask/internal/usecase/clone_usecase"
+	"DataTask/internal/usecase/reminder_usecase"
+	"DataTask/internal/usecase/trash_usecase"
+	"DataTask/internal/worker"
+	"database/sql"
+)
+
+func InitializeWorkers(
+	db *sql.DB,
+	cfg *config.Config,
+	notifier notifier.Notifier,
+	attachmentUseCase attachment_usecase.AttachmentUseCase,
+	trashUseCase trash_usecase.TrashUseCase,
+	cloneUseCase clone_usecase.CloneUseCase,
+) []*worker.PeriodicWorker {
+	var workers []*worker.PeriodicWorker
+
+	if cfg.Reminders.Enabled && cfg.Reminders.Interval > 0 {
+		workers = append(workers, InitializeReminderWorker(db, cfg.Reminders, notifier))
+	}
+	if cfg.Recurrence.Enabled && cfg.Recurrence.Interval > 0 {
+		workers = append(workers, InitializeRecurrenceWorker(db, cfg.Recurrence))
+	}
+	if cfg.Attachments.CleanupInterval > 0 {
+		workers = append(workers, worker.NewPeriodicWorker(
+			"attachment_cleanup", cfg.Attachments.CleanupInterval, attachmentUseCase.PurgeDeletedBlobs,
+		))
+	}
+	if cfg.Trash.Retention > 0 && cfg.Trash.PurgeInterval > 0 {
+		workers = append(workers, worker.NewPeriodicWorker("trash_purge", cfg.Trash.PurgeInterval, trashUseCase.PurgeExpired))
+	}
+	if cfg.Clone.Interval > 0 {
+		workers = append(workers, worker.NewPeriodicWorker("clone_jobs", cfg.Clone.Interval, cloneUseCase.RunQueuedJobs))
+	}
+
+	return workers
+}
+
+func InitializeReminderWorker(db *sql.DB, cfg config.Reminders, notifier notifier.Notifier) *worker.PeriodicWorker {
+	repo := reminder_repository.NewPostgresReminderRepository(db)
+	useCase := reminder_usecase.NewReminderUseCase(repo, notifier, cfg.Offsets)
+	return worker.NewPeriodicWorker("task_reminders", cfg.Interval, useCase.SendDueReminders)
+}
+
+func InitializeRecurrenceWorker(db *sql.DB, cfg config.Recurrence) *worker.PeriodicWorker {
+	useCase := InitializeRecurrenceUseCase(db, cfg, InitializeAccessUseCase(db))
+	return worker.NewPeriodicWorker("task_recurrences", cfg.Interval, useCase.GenerateUpcoming)
+}
@@ -0,0 +1,13 @@
+package domain_error
+
+import "errors"
+
+// Usecases wrap these errors so handlers can pick a response status without
+// inspecting error messages.
+var (
+	ErrValidation      = errors.New("validation failed")
+	ErrNotFound        = errors.New("not found")
+	ErrConflict        = errors.New("conflict")
+	ErrUnauthenticated = errors.New("user is not authenticated")
+	ErrForbidden       = errors.New("access denied")
+)
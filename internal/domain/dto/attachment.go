@@ -0,0 +1,26 @@
+package dto
+
+import (
+	"io"
+	"time"
+)
+
+type Attachment struct {
+	ID          int       `json:"id"`
+	TaskID      int       `json:"task_id"`
+	CommentID   *int      `json:"comment_id"`
+	FileName    string    `json:"file_name"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	UploadedBy  *int      `json:"uploaded_by"`
+	CreatedAt   time.Time `json:"created_at,omitempty"`
+}
+
+// AttachmentUpload is a file uploaded to a task or, when CommentID is set, to a comment.
+type AttachmentUpload struct {
+	TaskID    int
+	CommentID *int
+	FileName  string
+	Size      int64
+	Content   io.Reader
+}
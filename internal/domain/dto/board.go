@@ -0,0 +1,32 @@
+package dto
+
+// Board is a project's tasks split into swimlanes and kanban columns. A task with several
+// assignees, labels or multi_select options is in the lane of each.
+type Board struct {
+	ProjectID int         `json:"project_id"`
+	Swimlanes string      `json:"swimlanes" example:"assignee"`
+	Columns   []*Kanban   `json:"columns"` // In board order
+	Lanes     []*Swimlane `json:"lanes"`
+}
+
+type Swimlane struct {
+	// Key is the user, label or epic task ID, the priority or the field value; empty for tasks
+	// without one
+	Key       string       `json:"key"`
+	Name      string       `json:"name"`
+	TaskCount int          `json:"task_count"`
+	Cells     []*BoardCell `json:"cells"` // One per column, in column order
+}
+
+type BoardCell struct {
+	KanbanID  int     `json:"kanban_id"`
+	TaskCount int     `json:"task_count"`
+	Tasks     []*Task `json:"tasks"`
+}
+
+// BoardQuery picks the tasks of a board and the swimlanes they are split into.
+type BoardQuery struct {
+	Swimlanes  string // One of the entity.Swimlane* groupings, or field.<field ID>
+	EmptyLanes bool   // Also return lanes without tasks, such as unused labels
+	Filter     *TaskFilter
+}
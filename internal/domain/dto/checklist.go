@@ -0,0 +1,22 @@
+package dto
+
+import "time"
+
+type ChecklistItem struct {
+	ID         int       `json:"id"`
+	TaskID     int       `json:"task_id"`
+	Text       string    `json:"text"`
+	IsDone     bool      `json:"is_done"`
+	Position   int       `json:"position"`
+	AssigneeID *int      `json:"assignee_id"`
+	CreatedAt  time.Time `json:"created_at,omitempty"`
+	UpdatedAt  time.Time `json:"updated_at,omitempty"`
+}
+
+// ChecklistItemUpdate is a partial checklist item update. Nil fields are left unchanged.
+type ChecklistItemUpdate struct {
+	Text          *string
+	IsDone        *bool
+	AssigneeID    *int
+	ClearAssignee bool
+}
@@ -0,0 +1,38 @@
+package dto
+
+import "time"
+
+type CloneOptions struct {
+	Name               string `json:"name"`
+	TargetKanbanID     *int   `json:"target_kanban_id"`
+	TargetProjectID    *int   `json:"target_project_id"`
+	ParentProjectID    *int   `json:"parent_project_id"`
+	IncludeComments    bool   `json:"include_comments"`
+	IncludeAssignees   bool   `json:"include_assignees"`
+	IncludeSubprojects bool   `json:"include_subprojects"`
+	IncludeMembers     bool   `json:"include_members"`
+}
+
+// CloneResult holds the ID of the copy when the clone ran right away, or the background job
+// running it otherwise.
+type CloneResult struct {
+	Type     string    `json:"type" enums:"task,kanban,project"`
+	ResultID *int      `json:"result_id,omitempty"`
+	Job      *CloneJob `json:"job,omitempty"`
+}
+
+type CloneJob struct {
+	ID         int          `json:"id"`
+	Type       string       `json:"type" enums:"task,kanban,project"`
+	SourceID   int          `json:"source_id"`
+	Options    CloneOptions `json:"options"`
+	Status     string       `json:"status" enums:"queued,running,completed,failed"`
+	Total      int          `json:"total"`     // Tasks to copy
+	Done       int          `json:"done"`      // Tasks copied so far
+	Progress   float64      `json:"progress"`  // Share of the tasks copied, 0 to 1
+	ResultID   *int         `json:"result_id"` // ID of the copy once completed
+	Error      *string      `json:"error"`
+	CreatedAt  time.Time    `json:"created_at"`
+	StartedAt  *time.Time   `json:"started_at"`
+	FinishedAt *time.Time   `json:"finished_at"`
+}
@@ -3,10 +3,12 @@ package dto
 import "time"
 
 type Comment struct {
-	ID        int       `json:"id"`
-	Author    *User     `json:"author"`
-	Text      string    `json:"text"`
-	TaskID    int       `json:"task_id"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID        int        `json:"id"`
+	Author    *User      `json:"author"`
+	Text      string     `json:"text"`
+	TextHTML  *string    `json:"text_html,omitempty"` // Sanitized HTML, only returned with render=html
+	TaskID    int        `json:"task_id"`
+	Mentions  []*Mention `json:"mentions"` // Project members @mentioned in the text
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
 }
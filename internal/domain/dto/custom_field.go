@@ -0,0 +1,37 @@
+package dto
+
+import "time"
+
+type CustomField struct {
+	ID        int       `json:"id"`
+	ProjectID int       `json:"project_id"`
+	Name      string    `json:"name"`
+	Type      string    `json:"type" enums:"text,number,date,single_select,multi_select,user,url"`
+	Options   []string  `json:"options"` // Choices of the select types
+	Position  int       `json:"position"`
+	Inherited bool      `json:"inherited"` // Defined on a parent project
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+}
+
+// CustomFieldUpdate is a partial field update. The type of a field cannot change.
+type CustomFieldUpdate struct {
+	Name     *string
+	Options  []string // Replaces the choices when not nil
+	Position *int
+}
+
+// CustomFieldValue is a field value of a task. Value is a string for text, url, single_select
+// and date (YYYY-MM-DD), a number, a list of strings for multi_select and a user ID for user.
+type CustomFieldValue struct {
+	FieldID   int    `json:"field_id"`
+	FieldName string `json:"field_name"`
+	Type      string `json:"type"`
+	Value     any    `json:"value" swaggertype:"object"`
+}
+
+// CustomFieldValueInput sets one field of a task. A nil Value clears the field.
+type CustomFieldValueInput struct {
+	FieldID int
+	Value   any
+}
@@ -6,6 +6,19 @@ type Kanban struct {
 	ID        int       `json:"id"`
 	Name      string    `json:"name"`
 	ProjectID int       `json:"project_id"`
+	Position  int       `json:"position"`
+	WIPLimit  *int      `json:"wip_limit"`
+	TaskCount int       `json:"task_count"`
+	OverLimit bool      `json:"over_limit"` // The kanban holds more tasks than its WIP limit
 	CreatedAt time.Time `json:"created_at,omitempty"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
+
+// KanbanOrder is a new order of a project's kanbans: either every kanban in the new order, or one
+// kanban moved right before or after another.
+type KanbanOrder struct {
+	KanbanIDs []int
+	KanbanID  *int
+	BeforeID  *int
+	AfterID   *int
+}
@@ -0,0 +1,13 @@
+package dto
+
+import "time"
+
+type Label struct {
+	ID        int       `json:"id"`
+	ProjectID int       `json:"project_id"`
+	Name      string    `json:"name"`
+	Color     string    `json:"color"`
+	Inherited bool      `json:"inherited"` // Defined on a parent project
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+}
@@ -0,0 +1,7 @@
+package dto
+
+// Mention links the handle written after an @ in the text to the mentioned user.
+type Mention struct {
+	Handle string `json:"handle" example:"alice"`
+	User   *User  `json:"user"`
+}
@@ -3,14 +3,16 @@ package dto
 import "time"
 
 type Project struct {
-	ID              int       `json:"id"`
-	OwnerID         int       `json:"owner_id"`
-	Name            string    `json:"name"`
-	Description     string    `json:"description"`
-	Color           string    `json:"color"`
-	ParentProjectID *int      `json:"parent_project_id"`
-	CreatedAt       time.Time `json:"created_at,omitempty"` //  omitempty, чтобы не возвращать null
-	UpdatedAt       time.Time `json:"updated_at,omitempty"` //  omitempty, чтобы не возвращать null
+	ID              int              `json:"id"`
+	OwnerID         int              `json:"owner_id"`
+	Name            string           `json:"name"`
+	Description     string           `json:"description"`
+	Color           string           `json:"color"`
+	ParentProjectID *int             `json:"parent_project_id"`
+	Key             string           `json:"key" example:"DT"`     // Prefix of the task keys, e.g. DT in DT-123
+	Estimate        *ProjectEstimate `json:"estimate,omitempty"`   // Only returned in project lists
+	CreatedAt       time.Time        `json:"created_at,omitempty"` //  omitempty, чтобы не возвращать null
+	UpdatedAt       time.Time        `json:"updated_at,omitempty"` //  omitempty, чтобы не возвращать null
 }
 
 type ProjectUser struct {
@@ -28,3 +30,16 @@ type ProjectUserInvite struct {
 	UserEmail  string `json:"user_email"`
 	Permission string `json:"permission"`
 }
+
+// ProjectEstimate rolls the task estimates of a project and all its subprojects up.
+// Subprojects estimating in another unit cannot be added up and are listed in SkippedProjectIDs.
+type ProjectEstimate struct {
+	ProjectID          int     `json:"project_id"`
+	Unit               string  `json:"unit" enums:"hours,story_points"`
+	Total              float64 `json:"total"`
+	Completed          float64 `json:"completed"`
+	Remaining          float64 `json:"remaining"`
+	TaskCount          int     `json:"task_count"`
+	EstimatedTaskCount int     `json:"estimated_task_count"`
+	SkippedProjectIDs  []int   `json:"skipped_project_ids"`
+}
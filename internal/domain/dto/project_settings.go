@@ -0,0 +1,18 @@
+package dto
+
+import "time"
+
+type ProjectSettings struct {
+	ProjectID               int       `json:"project_id"`
+	BlockedCompletionPolicy string    `json:"blocked_completion_policy" enums:"warn,fail"`
+	EstimateUnit            string    `json:"estimate_unit" enums:"hours,story_points"`
+	WIPLimitPolicy          string    `json:"wip_limit_policy" enums:"warn,fail"`
+	UpdatedAt               time.Time `json:"updated_at,omitempty"`
+}
+
+// ProjectSettingsUpdate is a partial settings update. Nil fields are left unchanged.
+type ProjectSettingsUpdate struct {
+	BlockedCompletionPolicy *string
+	EstimateUnit            *string
+	WIPLimitPolicy          *string
+}
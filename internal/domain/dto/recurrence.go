@@ -0,0 +1,18 @@
+package dto
+
+import "time"
+
+type TaskRecurrence struct {
+	ID               int        `json:"id"`
+	RRule            string     `json:"rrule" example:"FREQ=WEEKLY;BYDAY=MO"`
+	DTStart          time.Time  `json:"dtstart"`
+	TimeZone         string     `json:"time_zone"`
+	EndsAt           *time.Time `json:"ends_at"`
+	NextOccurrenceAt *time.Time `json:"next_occurrence_at"` // Nil when the series has ended
+}
+
+type RecurrenceUpdate struct {
+	RRule   string
+	DTStart *time.Time // Only used when the task does not recur yet; defaults to due_at, start_at or now
+	Scope   string     // this_and_future (default) or all
+}
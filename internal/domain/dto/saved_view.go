@@ -0,0 +1,46 @@
+package dto
+
+import "time"
+
+type SavedView struct {
+	ID        int    `json:"id"`
+	ProjectID int    `json:"project_id"`
+	OwnerID   int    `json:"owner_id"`
+	Name      string `json:"name"`
+	// Filter uses the syntax of the filter query parameter of the task lists
+	Filter string `json:"filter" example:"assignee:me AND is_completed:false"`
+	// Sort uses the syntax of the sort query parameter of the task lists
+	Sort      string    `json:"sort" example:"-priority,due_at"`
+	GroupBy   string    `json:"group_by" enums:",column,assignee,label,priority"`
+	Fields    []string  `json:"fields" example:"title,priority,assignees,field.3"`
+	Shared    bool      `json:"shared"`
+	Position  *int      `json:"position"` // In the current user's list, null when not ordered yet
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+}
+
+// SavedViewUpdate is a partial view update. Nil fields are left unchanged, a non-nil Fields
+// replaces the stored list.
+type SavedViewUpdate struct {
+	Name    *string
+	Filter  *string
+	Sort    *string
+	GroupBy *string
+	Fields  []string
+	Shared  *bool
+}
+
+// SavedViewTasks holds the tasks of a view, in the view's order. Grouped views return the
+// tasks in Groups as well; a task with several labels or assignees is in each of their groups.
+type SavedViewTasks struct {
+	View   *SavedView        `json:"view"`
+	Tasks  []*Task           `json:"tasks"`
+	Groups []*SavedViewGroup `json:"groups,omitempty"`
+}
+
+type SavedViewGroup struct {
+	// Key is the kanban, user or label ID, or the priority; empty for tasks without assignee or label
+	Key   string  `json:"key"`
+	Name  string  `json:"name"`
+	Tasks []*Task `json:"tasks"`
+}
@@ -0,0 +1,24 @@
+package dto
+
+type SearchQuery struct {
+	Text        string
+	ProjectID   *int
+	Types       []string
+	AssigneeID  *int
+	AssigneeMe  bool // Resolve the assignee to the current user
+	IsCompleted *bool
+	Limit       int
+	Offset      int
+}
+
+type SearchResult struct {
+	Type        string  `json:"type" enums:"task,comment,project"`
+	ID          int     `json:"id"`
+	ProjectID   int     `json:"project_id"`
+	ProjectName string  `json:"project_name"`
+	TaskID      *int    `json:"task_id"` // The task itself or the task a comment belongs to
+	Title       string  `json:"title"`   // Task title or project name
+	Snippet     string  `json:"snippet"` // HTML escaped text with the matched words in <mark> tags
+	IsCompleted *bool   `json:"is_completed"`
+	Rank        float64 `json:"rank"`
+}
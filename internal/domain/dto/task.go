@@ -3,11 +3,83 @@ package dto
 import "time"
 
 type Task struct {
-	ID          int       `json:"id"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	IsCompleted bool      `json:"is_completed"`
-	KanbanID    int       `json:"kanban_id"`
-	CreatedAt   time.Time `json:"created_at,omitempty"`
-	UpdatedAt   time.Time `json:"updated_at,omitempty"`
+	ID               int                 `json:"id"`
+	Number           int                 `json:"number"` // Sequential within the project
+	Key              string              `json:"key"`    // E.g. DT-123, accepted by the task routes in place of the ID
+	Title            string              `json:"title"`
+	Description      string              `json:"description"`
+	DescriptionHTML  *string             `json:"description_html,omitempty"` // Sanitized HTML, only returned with render=html
+	IsCompleted      bool                `json:"is_completed"`
+	KanbanID         int                 `json:"kanban_id"`
+	StartAt          *time.Time          `json:"start_at"`
+	DueAt            *time.Time          `json:"due_at"`
+	TimeZone         string              `json:"time_zone"`
+	IsOverdue        bool                `json:"is_overdue"`
+	Priority         string              `json:"priority"`
+	Labels           []*Label            `json:"labels"`
+	Assignees        []*User             `json:"assignees"`
+	ParentTaskID     *int                `json:"parent_task_id"`
+	RecurrenceID     *int                `json:"recurrence_id"`
+	OccurrenceAt     *time.Time          `json:"occurrence_at"`
+	Estimate         *float64            `json:"estimate"`           // Hours or story points, see the project settings
+	StatusID         *int                `json:"status_id"`          // Workflow status; is_completed follows its category
+	Progress         *TaskProgress       `json:"progress,omitempty"` // Only returned for a single task
+	Links            *TaskLinks          `json:"links,omitempty"`    // Only returned for a single task
+	Mentions         []*Mention          `json:"mentions,omitempty"` // Only returned for a single task
+	CustomFields     []*CustomFieldValue `json:"custom_fields"`
+	TimeSpentSeconds *int64              `json:"time_spent_seconds,omitempty"` // Only returned for a single task
+	Warnings         []string            `json:"warnings,omitempty"`
+	TemplateID       *int                `json:"-"` // Template to create the task from, only read on creation
+	CreatedAt        time.Time           `json:"created_at,omitempty"`
+	UpdatedAt        time.Time           `json:"updated_at,omitempty"`
+}
+
+// TaskUpdate is a partial task update. Nil fields are left unchanged.
+type TaskUpdate struct {
+	Title        *string
+	Description  *string
+	IsCompleted  *bool
+	StartAt      *time.Time
+	DueAt        *time.Time
+	ClearStartAt bool
+	ClearDueAt   bool
+	TimeZone     *string
+	Priority     *string
+
+	Estimate      *float64
+	ClearEstimate bool
+
+	ParentTaskID      *int
+	ClearParentTaskID bool
+
+	KanbanID *int // Moves the task to another board of the same project
+	// StatusID changes the workflow status; without it, moving onto a kanban mapped to a status
+	// or changing IsCompleted picks the status.
+	StatusID *int
+}
+
+// TaskProgress rolls the checklist and the direct subtasks of a task up into one figure.
+type TaskProgress struct {
+	ChecklistTotal    int `json:"checklist_total"`
+	ChecklistDone     int `json:"checklist_done"`
+	SubtasksTotal     int `json:"subtasks_total"`
+	SubtasksCompleted int `json:"subtasks_completed"`
+	Percent           int `json:"percent"`
+}
+
+type TaskFilter struct {
+	Overdue    *bool
+	Priorities []string
+	LabelIDs   []int
+
+	CustomFields map[int]string // Field ID to the value as given in the query string
+	Query        string         // Filter expression, see the filterql package
+	Sort         []*TaskSort
+}
+
+// TaskSort orders task lists by a task column or, when CustomFieldID is set, a custom field.
+type TaskSort struct {
+	Field         string
+	CustomFieldID int
+	Desc          bool
 }
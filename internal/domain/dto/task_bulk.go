@@ -0,0 +1,27 @@
+package dto
+
+// BulkTaskRequest applies one operation to a list of tasks.
+type BulkTaskRequest struct {
+	Operation string
+	TaskIDs   []int
+	KanbanID  *int // Target board of move
+	UserID    *int // User of assign and unassign
+	LabelID   *int // Label of label
+
+	// Partial applies every task on its own instead of all or none.
+	Partial bool
+}
+
+type BulkTaskResult struct {
+	Operation string                `json:"operation"`
+	Partial   bool                  `json:"partial"`
+	Succeeded int                   `json:"succeeded"`
+	Failed    int                   `json:"failed"`
+	Items     []*BulkTaskItemResult `json:"items"` // In the order of the requested task IDs
+}
+
+type BulkTaskItemResult struct {
+	TaskID  int    `json:"task_id"`
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+}
@@ -0,0 +1,44 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Page selects a slice of a list ordered newest first.
+type Page struct {
+	Limit  int
+	Offset int
+}
+
+type TaskHistoryEntry struct {
+	ID        int64           `json:"id"`
+	TaskID    int             `json:"task_id"`
+	Actor     *User           `json:"actor"` // Null for system changes and deleted users
+	Field     string          `json:"field" example:"title"`
+	OldValue  json.RawMessage `json:"old_value" swaggertype:"object"`
+	NewValue  json.RawMessage `json:"new_value" swaggertype:"object"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+type TaskHistoryPage struct {
+	Entries []*TaskHistoryEntry `json:"entries"`
+	Total   int                 `json:"total"`
+	Limit   int                 `json:"limit"`
+	Offset  int                 `json:"offset"`
+}
+
+// TimelineItem is either a field change or a comment, as told by Kind.
+type TimelineItem struct {
+	Kind      string            `json:"kind" enums:"change,comment"`
+	CreatedAt time.Time         `json:"created_at"`
+	Change    *TaskHistoryEntry `json:"change,omitempty"`
+	Comment   *Comment          `json:"comment,omitempty"`
+}
+
+type TimelinePage struct {
+	Items  []*TimelineItem `json:"items"`
+	Total  int             `json:"total"`
+	Limit  int             `json:"limit"`
+	Offset int             `json:"offset"`
+}
@@ -0,0 +1,24 @@
+package dto
+
+import "time"
+
+// TaskLink is a link as seen from the task it is listed on.
+type TaskLink struct {
+	ID              int       `json:"id"`
+	Type            string    `json:"type" enums:"blocks,blocked_by,relates_to,duplicates,duplicated_by"`
+	TaskID          int       `json:"task_id"` // The task on the other end of the link
+	TaskTitle       string    `json:"task_title"`
+	TaskIsCompleted bool      `json:"task_is_completed"`
+	ProjectID       int       `json:"project_id"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+type TaskLinks struct {
+	Outgoing []*TaskLink `json:"outgoing"`
+	Incoming []*TaskLink `json:"incoming"`
+}
+
+type TaskLinkCreate struct {
+	Type   string // blocks, blocked_by, relates_to or duplicates
+	TaskID int    // The task to link to
+}
@@ -0,0 +1,31 @@
+package dto
+
+import "time"
+
+type TaskTemplate struct {
+	ID        int    `json:"id"`
+	ProjectID int    `json:"project_id"`
+	Name      string `json:"name"`
+	// TitlePattern may contain {title}, {date} and {project}
+	TitlePattern string    `json:"title_pattern" example:"Bug: {title}"`
+	Description  string    `json:"description"`
+	Priority     string    `json:"priority" enums:"none,low,medium,high,urgent"`
+	LabelIDs     []int     `json:"label_ids"`
+	Checklist    []string  `json:"checklist"`
+	AssigneeIDs  []int     `json:"assignee_ids"`
+	Inherited    bool      `json:"inherited"` // Defined on a parent project
+	CreatedAt    time.Time `json:"created_at,omitempty"`
+	UpdatedAt    time.Time `json:"updated_at,omitempty"`
+}
+
+// TaskTemplateUpdate is a partial template update. Nil fields are left unchanged, non-nil lists
+// replace the stored ones.
+type TaskTemplateUpdate struct {
+	Name         *string
+	TitlePattern *string
+	Description  *string
+	Priority     *string
+	LabelIDs     []int
+	Checklist    []string
+	AssigneeIDs  []int
+}
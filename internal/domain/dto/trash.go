@@ -0,0 +1,15 @@
+package dto
+
+import "time"
+
+type TrashItem struct {
+	Type      string     `json:"type" enums:"project,kanban,task"`
+	ID        int        `json:"id"`
+	Name      string     `json:"name"`
+	ProjectID int        `json:"project_id"`
+	ParentID  *int       `json:"parent_id"` // Parent project of a project, board of a task
+	DeletedAt time.Time  `json:"deleted_at"`
+	PurgeAt   *time.Time `json:"purge_at"` // Nil when trashed items are kept forever
+	// Restorable is false while the item's project, board or parent task is in the trash too.
+	Restorable bool `json:"restorable"`
+}
@@ -0,0 +1,38 @@
+package dto
+
+import "time"
+
+type WorkflowStatus struct {
+	ID        int       `json:"id"`
+	ProjectID int       `json:"project_id"`
+	Name      string    `json:"name"`
+	Category  string    `json:"category" enums:"todo,in_progress,done"`
+	Position  int       `json:"position"`
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+}
+
+// WorkflowStatusUpdate is a partial status update. Nil fields are left unchanged.
+type WorkflowStatusUpdate struct {
+	Name     *string
+	Category *string
+}
+
+type WorkflowTransition struct {
+	FromStatusID int `json:"from_status_id"`
+	ToStatusID   int `json:"to_status_id"`
+}
+
+type WorkflowColumn struct {
+	KanbanID int    `json:"kanban_id"`
+	Name     string `json:"name"`
+	StatusID *int   `json:"status_id"` // Status tasks take when moved onto the kanban
+}
+
+type Workflow struct {
+	ProjectID int               `json:"project_id"`
+	Statuses  []*WorkflowStatus `json:"statuses"`
+	// Allowed status changes; every change is allowed when empty
+	Transitions []*WorkflowTransition `json:"transitions"`
+	Columns     []*WorkflowColumn     `json:"columns"`
+}
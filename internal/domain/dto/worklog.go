@@ -0,0 +1,57 @@
+package dto
+
+import "time"
+
+type Worklog struct {
+	ID              int        `json:"id"`
+	TaskID          int        `json:"task_id"`
+	UserID          int        `json:"user_id"`
+	StartedAt       time.Time  `json:"started_at"`
+	EndedAt         *time.Time `json:"ended_at"` // Nil while the timer runs
+	DurationSeconds int64      `json:"duration_seconds"`
+	Note            string     `json:"note"`
+	Source          string     `json:"source" enums:"timer,manual"`
+	CreatedAt       time.Time  `json:"created_at,omitempty"`
+	UpdatedAt       time.Time  `json:"updated_at,omitempty"`
+}
+
+type WorklogCreate struct {
+	TaskID    int
+	Duration  time.Duration
+	StartedAt *time.Time // Defaults to now minus the duration
+	Note      string
+}
+
+// WorklogUpdate is a partial worklog update. Nil fields are left unchanged.
+type WorklogUpdate struct {
+	Duration  *time.Duration
+	StartedAt *time.Time
+	Note      *string
+}
+
+type TimeTotalsFilter struct {
+	ProjectID *int
+	TaskID    *int
+	UserID    *int
+	From      *time.Time
+	To        *time.Time
+}
+
+type TaskTimeTotal struct {
+	TaskID    int    `json:"task_id"`
+	TaskTitle string `json:"task_title"`
+	Seconds   int64  `json:"seconds"`
+}
+
+type UserTimeTotal struct {
+	UserID  int   `json:"user_id"`
+	Seconds int64 `json:"seconds"`
+}
+
+type TimeTotals struct {
+	From         *time.Time       `json:"from"`
+	To           *time.Time       `json:"to"`
+	TotalSeconds int64            `json:"total_seconds"`
+	ByTask       []*TaskTimeTotal `json:"by_task"`
+	ByUser       []*UserTimeTotal `json:"by_user"`
+}
@@ -0,0 +1,15 @@
+package entity
+
+import "time"
+
+type Attachment struct {
+	ID          int       `json:"id"`
+	TaskID      int       `json:"task_id"`
+	CommentID   *int      `json:"comment_id"` // Set for attachments of a comment on the task
+	FileName    string    `json:"file_name"`
+	ContentType string    `json:"content_type"` // As detected from the content
+	Size        int64     `json:"size"`
+	StorageKey  string    `json:"storage_key"`
+	UploadedBy  *int      `json:"uploaded_by"`
+	CreatedAt   time.Time `json:"created_at"`
+}
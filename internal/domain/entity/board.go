@@ -0,0 +1,33 @@
+package entity
+
+import "strings"
+
+// Swimlane groupings of boards. Custom fields are grouped by as field.<field ID>.
+const (
+	SwimlaneNone     = ""
+	SwimlaneAssignee = "assignee"
+	SwimlanePriority = "priority"
+	SwimlaneLabel    = "label"
+	SwimlaneEpic     = "epic" // By the top-level task of the task's subtask tree
+)
+
+// SwimlaneFieldPrefix starts the swimlane grouping by a custom field, such as field.3.
+const SwimlaneFieldPrefix = "field."
+
+// IsValidSwimlanes reports whether s is one of the known swimlane groupings. The field of a
+// custom field grouping is not checked.
+func IsValidSwimlanes(s string) bool {
+	switch s {
+	case SwimlaneNone, SwimlaneAssignee, SwimlanePriority, SwimlaneLabel, SwimlaneEpic:
+		return true
+	}
+	return strings.HasPrefix(s, SwimlaneFieldPrefix)
+}
+
+// TaskEpic is the epic of a task: the top-level task of its subtask tree. A top-level task is
+// its own epic when it has subtasks.
+type TaskEpic struct {
+	TaskID    int
+	EpicID    int
+	EpicTitle string
+}
@@ -0,0 +1,14 @@
+package entity
+
+import "time"
+
+type ChecklistItem struct {
+	ID         int       `json:"id"`
+	TaskID     int       `json:"task_id"`
+	Text       string    `json:"text"`
+	IsDone     bool      `json:"is_done"`
+	Position   int       `json:"position"`
+	AssigneeID *int      `json:"assignee_id"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
@@ -0,0 +1,79 @@
+package entity
+
+import "time"
+
+// What a clone copies.
+const (
+	CloneTask    = "task"    // A task with its subtasks
+	CloneKanban  = "kanban"  // A kanban with its tasks
+	CloneProject = "project" // A project with its kanbans and tasks, and optionally its subprojects
+)
+
+// States of a clone job.
+const (
+	CloneJobQueued    = "queued"
+	CloneJobRunning   = "running"
+	CloneJobCompleted = "completed"
+	CloneJobFailed    = "failed"
+)
+
+type CloneOptions struct {
+	Name string `json:"name,omitempty"` // Title or name of the copy, "<source> (copy)" when empty
+	// TargetKanbanID is the kanban a task copy goes to; the source's kanban when nil.
+	TargetKanbanID *int `json:"target_kanban_id,omitempty"`
+	// TargetProjectID is the project a kanban copy goes to; the source's project when nil.
+	TargetProjectID *int `json:"target_project_id,omitempty"`
+	// ParentProjectID is the parent of a project copy; the copy is a top-level project when nil.
+	ParentProjectID *int `json:"parent_project_id,omitempty"`
+
+	IncludeComments    bool `json:"include_comments,omitempty"`
+	IncludeAssignees   bool `json:"include_assignees,omitempty"`   // Assignees who are not members of the target project are skipped
+	IncludeSubprojects bool `json:"include_subprojects,omitempty"` // Project clones only
+	IncludeMembers     bool `json:"include_members,omitempty"`     // Project clones only
+}
+
+// ClonePlan is a clone resolved against the database: the rows to copy and where to.
+type ClonePlan struct {
+	Type     string
+	SourceID int
+	UserID   int
+	Options  CloneOptions
+
+	// ProjectIDs are the projects to copy, the source first; parents come before their subprojects.
+	ProjectIDs []int
+	// KanbanIDs are the kanbans to copy; their tasks are copied along.
+	KanbanIDs []int
+	// TaskIDs are the tasks to copy.
+	TaskIDs []int
+
+	// TargetKanbanID receives the tasks of a task clone.
+	TargetKanbanID int
+	// TargetProjectID is the project the copies end up in; for project clones the source's parent
+	// is replaced by Options.ParentProjectID.
+	TargetProjectID int
+	// SameProject is set when the copies stay in the source's project. Parent tasks, labels and
+	// custom fields that are not copied along are then kept; otherwise they are dropped.
+	SameProject bool
+}
+
+// CloneResult identifies the copy of the clone's source.
+type CloneResult struct {
+	Type string
+	ID   int
+}
+
+type CloneJob struct {
+	ID         int
+	UserID     int
+	Type       string
+	SourceID   int
+	Options    CloneOptions
+	Status     string
+	Total      int // Tasks to copy
+	Done       int // Tasks copied so far
+	ResultID   *int
+	Error      *string
+	CreatedAt  time.Time
+	StartedAt  *time.Time
+	FinishedAt *time.Time
+}
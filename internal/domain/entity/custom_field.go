@@ -0,0 +1,56 @@
+package entity
+
+import "time"
+
+const (
+	CustomFieldText         = "text"
+	CustomFieldNumber       = "number"
+	CustomFieldDate         = "date"
+	CustomFieldSingleSelect = "single_select"
+	CustomFieldMultiSelect  = "multi_select"
+	CustomFieldUser         = "user"
+	CustomFieldURL          = "url"
+)
+
+// IsValidCustomFieldType reports whether t is one of the known custom field types.
+func IsValidCustomFieldType(t string) bool {
+	switch t {
+	case CustomFieldText, CustomFieldNumber, CustomFieldDate, CustomFieldSingleSelect,
+		CustomFieldMultiSelect, CustomFieldUser, CustomFieldURL:
+		return true
+	}
+	return false
+}
+
+type CustomField struct {
+	ID        int       `json:"id"`
+	ProjectID int       `json:"project_id"`
+	Name      string    `json:"name"`
+	Type      string    `json:"type"`
+	Options   []string  `json:"options"` // Choices of the select types
+	Position  int       `json:"position"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CustomFieldValue is the value of a field on a task. Only the member matching FieldType is set.
+// Task filters use it as well, to match tasks holding the value.
+type CustomFieldValue struct {
+	TaskID    int
+	FieldID   int
+	FieldName string
+	FieldType string
+	Text      *string // text, url and single_select
+	Number    *float64
+	Date      *time.Time
+	Options   []string // multi_select
+	UserID    *int
+	UpdatedAt time.Time
+}
+
+// TaskSort orders task lists. Field is a task column, or empty when sorting by CustomField.
+type TaskSort struct {
+	Field       string
+	CustomField *CustomField
+	Desc        bool
+}
@@ -6,6 +6,9 @@ type Kanban struct {
 	ID        int       `json:"id"`
 	Name      string    `json:"name"`
 	ProjectID int       `json:"project_id"`
+	Position  int       `json:"position"`
+	WIPLimit  *int      `json:"wip_limit"`  // nil when the kanban has no limit
+	TaskCount int       `json:"task_count"` // Tasks on the kanban, subtasks included
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
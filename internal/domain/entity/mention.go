@@ -0,0 +1,13 @@
+package entity
+
+import "time"
+
+// Mention is a user mentioned in a task description or, when CommentID is set, in a comment on
+// the task.
+type Mention struct {
+	TaskID    int       `json:"task_id"`
+	CommentID *int      `json:"comment_id"`
+	Handle    string    `json:"handle"` // The text after the @, lower-cased
+	User      *User     `json:"user"`
+	CreatedAt time.Time `json:"created_at"`
+}
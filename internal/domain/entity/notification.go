@@ -0,0 +1,23 @@
+package entity
+
+import "time"
+
+const (
+	NotificationTaskReminder   = "task.reminder"
+	NotificationTaskAssigned   = "task.assigned"
+	NotificationTaskUnassigned = "task.unassigned"
+	// NotificationTaskChanged goes to the assignees and watchers of a changed task.
+	NotificationTaskChanged = "task.changed"
+	// NotificationTaskMentioned goes to a user newly mentioned in a task description or comment.
+	NotificationTaskMentioned = "task.mentioned"
+)
+
+// Notification is an event addressed to a single user.
+type Notification struct {
+	Type      string         `json:"type"`
+	UserID    int            `json:"user_id"`
+	TaskID    int            `json:"task_id,omitempty"`
+	Message   string         `json:"message"`
+	Payload   map[string]any `json:"payload,omitempty"`
+	CreatedAt time.Time      `json:"created_at"`
+}
@@ -2,6 +2,23 @@ package entity
 
 import "time"
 
+const (
+	PermissionRead  = "read"
+	PermissionEdit  = "edit"
+	PermissionOwner = "owner"
+)
+
+var permissionRank = map[string]int{
+	PermissionRead:  1,
+	PermissionEdit:  2,
+	PermissionOwner: 3,
+}
+
+// PermissionAllows reports whether granted is at least as strong as required.
+func PermissionAllows(granted, required string) bool {
+	return permissionRank[granted] > 0 && permissionRank[granted] >= permissionRank[required]
+}
+
 type Project struct {
 	ID              int       `json:"id"`
 	OwnerID         int       `json:"owner_id"`
@@ -9,6 +26,7 @@ type Project struct {
 	Description     string    `json:"description"`
 	Color           string    `json:"color"`
 	ParentProjectID *int      `json:"parent_project_id"`
+	Key             string    `json:"key"` // Prefix of the task keys, e.g. DT in DT-123
 	CreatedAt       time.Time `json:"created_at"`
 	UpdatedAt       time.Time `json:"updated_at"`
 }
@@ -22,3 +40,14 @@ type ProjectUser struct {
 	InvitedAt       time.Time  `json:"invited_at"`
 	JoinedAt        *time.Time `json:"joined_at"`
 }
+
+// ProjectEstimateRow sums the task estimates of one project of the tree below RootProjectID.
+type ProjectEstimateRow struct {
+	RootProjectID      int
+	ProjectID          int
+	Unit               string
+	Total              float64
+	Completed          float64
+	TaskCount          int
+	EstimatedTaskCount int
+}
@@ -0,0 +1,39 @@
+package entity
+
+import "time"
+
+// What happens when a task with open blockers is completed.
+const (
+	BlockedCompletionWarn = "warn" // Complete the task and report the open blockers
+	BlockedCompletionFail = "fail" // Reject the completion
+)
+
+// What happens when a task is created in or moved to a column at its WIP limit.
+const (
+	WIPLimitWarn = "warn" // Add the task and report the column as over its limit
+	WIPLimitFail = "fail" // Reject the task
+)
+
+// Units of task estimates.
+const (
+	EstimateUnitHours       = "hours"
+	EstimateUnitStoryPoints = "story_points"
+)
+
+// ProjectSettings holds per-project behaviour switches. Projects without a stored row use the defaults.
+type ProjectSettings struct {
+	ProjectID               int       `json:"project_id"`
+	BlockedCompletionPolicy string    `json:"blocked_completion_policy"`
+	EstimateUnit            string    `json:"estimate_unit"`
+	WIPLimitPolicy          string    `json:"wip_limit_policy"`
+	UpdatedAt               time.Time `json:"updated_at"`
+}
+
+func DefaultProjectSettings(projectID int) *ProjectSettings {
+	return &ProjectSettings{
+		ProjectID:               projectID,
+		BlockedCompletionPolicy: BlockedCompletionWarn,
+		EstimateUnit:            EstimateUnitHours,
+		WIPLimitPolicy:          WIPLimitWarn,
+	}
+}
@@ -0,0 +1,23 @@
+package entity
+
+import "time"
+
+// Scopes of a recurrence rule change made from one occurrence.
+const (
+	RecurrenceScopeAll           = "all"
+	RecurrenceScopeThisAndFuture = "this_and_future"
+)
+
+// TaskRecurrence is a series of tasks generated from an RFC 5545 rule.
+// New occurrences copy the latest existing occurrence of the series.
+type TaskRecurrence struct {
+	ID               int        `json:"id"`
+	RRule            string     `json:"rrule"`
+	DTStart          time.Time  `json:"dtstart"`
+	TimeZone         string     `json:"time_zone"`
+	EndsAt           *time.Time `json:"ends_at"` // Exclusive
+	LastOccurrenceAt time.Time  `json:"last_occurrence_at"`
+	CreatedBy        *int       `json:"created_by"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
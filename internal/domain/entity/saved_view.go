@@ -0,0 +1,42 @@
+package entity
+
+import "time"
+
+// How the tasks of a saved view are grouped.
+const (
+	ViewGroupNone     = ""
+	ViewGroupColumn   = "column" // By kanban column
+	ViewGroupAssignee = "assignee"
+	ViewGroupLabel    = "label"
+	ViewGroupPriority = "priority"
+)
+
+// IsValidViewGroupBy reports whether g is one of the known view groupings.
+func IsValidViewGroupBy(g string) bool {
+	switch g {
+	case ViewGroupNone, ViewGroupColumn, ViewGroupAssignee, ViewGroupLabel, ViewGroupPriority:
+		return true
+	}
+	return false
+}
+
+// ViewFields lists the task fields a view can show; custom fields are given as field.<field id>.
+var ViewFields = []string{
+	"title", "description", "is_completed", "kanban_id", "priority", "labels", "assignees", "start_at",
+	"due_at", "estimate", "parent_task_id", "created_at", "updated_at",
+}
+
+type SavedView struct {
+	ID        int
+	ProjectID int
+	OwnerID   int
+	Name      string
+	Filter    string // Filter expression, see the filterql package
+	Sort      string // Comma-separated sort keys such as "-priority,due_at"
+	GroupBy   string
+	Fields    []string // Visible fields, in order
+	Shared    bool     // Visible to all project members instead of the owner only
+	Position  *int     // Position in the list of the user who loaded the view, nil when not ordered yet
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
@@ -0,0 +1,40 @@
+package entity
+
+const (
+	SearchResultTask    = "task"
+	SearchResultComment = "comment"
+	SearchResultProject = "project"
+)
+
+// Snippets mark the matched words with these control characters, which cannot occur in
+// user text, so the markup can be added after escaping the snippet.
+const (
+	SearchHighlightStart = "\x01"
+	SearchHighlightStop  = "\x02"
+)
+
+// SearchQuery is a full-text search over the projects UserID can access.
+type SearchQuery struct {
+	Text   string
+	UserID int
+
+	ProjectID   *int
+	Types       []string // Empty searches all result types
+	AssigneeID  *int     // Tasks assigned to the user and comments on them
+	IsCompleted *bool    // Tasks in the state and comments on them
+
+	Limit  int
+	Offset int
+}
+
+type SearchResult struct {
+	Type        string
+	ID          int
+	ProjectID   int
+	ProjectName string
+	TaskID      *int // The task itself or the task of a comment
+	Title       string
+	Snippet     string
+	IsCompleted *bool
+	Rank        float64
+}
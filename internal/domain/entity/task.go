@@ -1,13 +1,103 @@
 package entity
 
-import "time"
+import (
+	"DataTask/pkg/filterql"
+	"time"
+)
+
+const (
+	PriorityNone   = "none"
+	PriorityLow    = "low"
+	PriorityMedium = "medium"
+	PriorityHigh   = "high"
+	PriorityUrgent = "urgent"
+)
+
+// What happens when a task with open subtasks is completed.
+const (
+	ParentCompletionBlock   = "block"   // Reject the completion
+	ParentCompletionCascade = "cascade" // Complete all subtasks as well
+	ParentCompletionAllow   = "allow"   // Complete the parent only
+)
+
+// IsValidPriority reports whether p is one of the known task priorities.
+func IsValidPriority(p string) bool {
+	switch p {
+	case PriorityNone, PriorityLow, PriorityMedium, PriorityHigh, PriorityUrgent:
+		return true
+	}
+	return false
+}
 
 type Task struct {
-	ID          int       `json:"id"`
-	Title       string    `json:"title"`
-	KanbanID    int       `json:"kanban_id"`
-	Description string    `json:"description"`
-	IsCompleted bool      `json:"is_completed"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID           int        `json:"id"`
+	Number       int        `json:"number"` // Sequential within the project, 0 for tasks without a kanban
+	Key          string     `json:"key"`    // Project key and number, e.g. DT-123
+	Title        string     `json:"title"`
+	KanbanID     int        `json:"kanban_id"`
+	Description  string     `json:"description"`
+	IsCompleted  bool       `json:"is_completed"`
+	StartAt      *time.Time `json:"start_at"`
+	DueAt        *time.Time `json:"due_at"`
+	TimeZone     string     `json:"time_zone"`
+	Priority     string     `json:"priority"`
+	ParentTaskID *int       `json:"parent_task_id"`
+	RecurrenceID *int       `json:"recurrence_id"`
+	OccurrenceAt *time.Time `json:"occurrence_at"` // The slot of the recurrence series this task stands for
+	Estimate     *float64   `json:"estimate"`      // In the unit of the project settings
+	StatusID     *int       `json:"status_id"`     // is_completed follows the category of the status
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// TaskFilter narrows task list queries. Nil fields are not applied.
+type TaskFilter struct {
+	Overdue    *bool
+	Priorities []string // Any of
+	LabelIDs   []int    // Tasks carrying any of the labels
+
+	CustomFields []*CustomFieldValue // Tasks holding all of the values
+
+	// Query is a filter expression such as "assignee:me AND is_completed:false"; me stands
+	// for CurrentUserID.
+	Query         filterql.Expr
+	CurrentUserID int
+
+	Sort []*TaskSort // By the first key, ties by the next
+}
+
+// Task columns lists can be sorted by.
+const (
+	TaskSortTitle     = "title"
+	TaskSortPriority  = "priority"
+	TaskSortDueAt     = "due_at"
+	TaskSortCreatedAt = "created_at"
+	TaskSortEstimate  = "estimate"
+	TaskSortUpdatedAt = "updated_at"
+)
+
+// IsValidTaskSortField reports whether task lists can be sorted by the column f.
+func IsValidTaskSortField(f string) bool {
+	switch f {
+	case TaskSortTitle, TaskSortPriority, TaskSortDueAt, TaskSortCreatedAt, TaskSortEstimate, TaskSortUpdatedAt:
+		return true
+	}
+	return false
+}
+
+// TaskProgress summarizes the checklist and the direct subtasks of a task.
+type TaskProgress struct {
+	ChecklistTotal    int
+	ChecklistDone     int
+	SubtasksTotal     int
+	SubtasksCompleted int
+}
+
+// TaskReminder is a reminder claimed for delivery to a task assignee.
+type TaskReminder struct {
+	TaskID        int       `json:"task_id"`
+	TaskTitle     string    `json:"task_title"`
+	UserID        int       `json:"user_id"`
+	OffsetMinutes int       `json:"offset_minutes"`
+	DueAt         time.Time `json:"due_at"`
 }
@@ -0,0 +1,11 @@
+package entity
+
+// Operations of the bulk task endpoint.
+const (
+	BulkOperationMove     = "move"
+	BulkOperationAssign   = "assign"
+	BulkOperationUnassign = "unassign"
+	BulkOperationLabel    = "label"
+	BulkOperationComplete = "complete"
+	BulkOperationDelete   = "delete"
+)
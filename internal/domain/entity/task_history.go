@@ -0,0 +1,41 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Fields recorded in the task history besides the task columns themselves.
+const (
+	TaskHistoryFieldAssignees = "assignees"
+	TaskHistoryFieldComment   = "comment"
+)
+
+const (
+	TimelineItemChange  = "change"
+	TimelineItemComment = "comment"
+)
+
+// TaskChange is a change about to be recorded. The values are stored as JSON.
+type TaskChange struct {
+	Field    string
+	OldValue any
+	NewValue any
+}
+
+type TaskHistoryEntry struct {
+	ID        int64           `json:"id"`
+	TaskID    int             `json:"task_id"`
+	Actor     *User           `json:"actor"` // Nil for system changes and deleted users
+	Field     string          `json:"field"`
+	OldValue  json.RawMessage `json:"old_value"`
+	NewValue  json.RawMessage `json:"new_value"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// TimelineItem is a history entry or a comment, depending on Kind.
+type TimelineItem struct {
+	Kind    string
+	Change  *TaskHistoryEntry
+	Comment *Comment
+}
@@ -0,0 +1,41 @@
+package entity
+
+import "time"
+
+const (
+	TaskLinkBlocks     = "blocks"
+	TaskLinkRelatesTo  = "relates_to"
+	TaskLinkDuplicates = "duplicates"
+)
+
+// Link types as seen from the target task. They are never stored.
+const (
+	TaskLinkBlockedBy    = "blocked_by"
+	TaskLinkDuplicatedBy = "duplicated_by"
+)
+
+const (
+	TaskLinkOutgoing = "outgoing"
+	TaskLinkIncoming = "incoming"
+)
+
+type TaskLink struct {
+	ID           int       `json:"id"`
+	SourceTaskID int       `json:"source_task_id"`
+	TargetTaskID int       `json:"target_task_id"`
+	Type         string    `json:"type"`
+	CreatedBy    *int      `json:"created_by"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// LinkedTask is a link as seen from one of its tasks, together with the task on the other end.
+type LinkedTask struct {
+	LinkID          int
+	Type            string // Stored link type
+	Direction       string // TaskLinkOutgoing when the task is the source of the link
+	TaskID          int
+	TaskTitle       string
+	TaskIsCompleted bool
+	ProjectID       int
+	CreatedAt       time.Time
+}
@@ -0,0 +1,24 @@
+package entity
+
+import "time"
+
+// Placeholders replaced in the title pattern of a task template.
+const (
+	TemplatePlaceholderTitle   = "{title}"   // The title given when creating the task
+	TemplatePlaceholderDate    = "{date}"    // The creation date as YYYY-MM-DD in the task's time zone
+	TemplatePlaceholderProject = "{project}" // The name of the task's project
+)
+
+type TaskTemplate struct {
+	ID           int       `json:"id"`
+	ProjectID    int       `json:"project_id"`
+	Name         string    `json:"name"`
+	TitlePattern string    `json:"title_pattern"`
+	Description  string    `json:"description"`
+	Priority     string    `json:"priority"`
+	LabelIDs     []int     `json:"label_ids"`
+	Checklist    []string  `json:"checklist"` // Texts of the checklist items, in order
+	AssigneeIDs  []int     `json:"assignee_ids"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
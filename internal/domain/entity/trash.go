@@ -0,0 +1,22 @@
+package entity
+
+import "time"
+
+const (
+	TrashItemProject = "project"
+	TrashItemKanban  = "kanban"
+	TrashItemTask    = "task"
+)
+
+// TrashItem is a soft deleted project, board or task.
+type TrashItem struct {
+	Type      string
+	ID        int
+	Name      string
+	ProjectID int  // The project the item belongs to; a project belongs to itself
+	ParentID  *int // Parent project of a project, board of a task
+	DeletedAt time.Time
+	// ContainerTrashed is set when the parent project, board or parent task is in the trash as well,
+	// in which case the item cannot be restored on its own.
+	ContainerTrashed bool
+}
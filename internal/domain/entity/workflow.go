@@ -0,0 +1,87 @@
+package entity
+
+import (
+	"slices"
+	"time"
+)
+
+// Categories of workflow statuses. Tasks in a done status are completed.
+const (
+	StatusCategoryTodo       = "todo"
+	StatusCategoryInProgress = "in_progress"
+	StatusCategoryDone       = "done"
+)
+
+func IsValidStatusCategory(c string) bool {
+	switch c {
+	case StatusCategoryTodo, StatusCategoryInProgress, StatusCategoryDone:
+		return true
+	}
+	return false
+}
+
+// DefaultWorkflowStatuses are the statuses new projects start with.
+var DefaultWorkflowStatuses = []WorkflowStatus{
+	{Name: "To Do", Category: StatusCategoryTodo, Position: 0},
+	{Name: "In Progress", Category: StatusCategoryInProgress, Position: 1},
+	{Name: "Done", Category: StatusCategoryDone, Position: 2},
+}
+
+type WorkflowStatus struct {
+	ID        int
+	ProjectID int
+	Name      string
+	Category  string
+	Position  int
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type WorkflowTransition struct {
+	FromStatusID int
+	ToStatusID   int
+}
+
+// WorkflowColumn is a kanban of the project and the status its tasks take, if any.
+type WorkflowColumn struct {
+	KanbanID int
+	Name     string
+	StatusID *int
+}
+
+// Workflow is the statuses of a project, in order, and the transitions allowed between them.
+type Workflow struct {
+	Statuses    []*WorkflowStatus
+	Transitions []*WorkflowTransition
+}
+
+func (w *Workflow) Status(id int) *WorkflowStatus {
+	for _, s := range w.Statuses {
+		if s.ID == id {
+			return s
+		}
+	}
+	return nil
+}
+
+// Allows reports whether a task may change from one status to another. Without transitions
+// every change is allowed, as is a change from no status.
+func (w *Workflow) Allows(from *int, to int) bool {
+	if from == nil || *from == to || len(w.Transitions) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(w.Transitions, func(t *WorkflowTransition) bool {
+		return t.FromStatusID == *from && t.ToStatusID == to
+	})
+}
+
+// FirstReachable returns the first status of the category that a task may change to from the
+// given status, or nil when there is none.
+func (w *Workflow) FirstReachable(from *int, category string) *WorkflowStatus {
+	for _, s := range w.Statuses {
+		if s.Category == category && w.Allows(from, s.ID) {
+			return s
+		}
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package entity
+
+import "time"
+
+const (
+	WorklogSourceTimer  = "timer"
+	WorklogSourceManual = "manual"
+)
+
+// Worklog is time a user spent on a task. Running timers have no EndedAt.
+type Worklog struct {
+	ID        int        `json:"id"`
+	TaskID    int        `json:"task_id"`
+	UserID    int        `json:"user_id"`
+	StartedAt time.Time  `json:"started_at"`
+	EndedAt   *time.Time `json:"ended_at"`
+	Note      string     `json:"note"`
+	Source    string     `json:"source"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// TimeTotalsFilter selects the worklogs to sum up. Nil fields are not applied;
+// worklogs are matched by their start time, From inclusive and To exclusive.
+type TimeTotalsFilter struct {
+	ProjectID *int
+	TaskID    *int
+	UserID    *int
+	From      *time.Time
+	To        *time.Time
+}
+
+// TimeTotalRow is the time one user spent on one task.
+type TimeTotalRow struct {
+	TaskID    int
+	TaskTitle string
+	UserID    int
+	Seconds   int64
+}
@@ -0,0 +1,56 @@
+package notifier
+
+import (
+	"DataTask/infra/queue"
+	"DataTask/internal/domain/entity"
+	"DataTask/pkg/logger"
+	"context"
+	"encoding/json"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+)
+
+// Notifier delivers user notifications to whatever transport is configured.
+type Notifier interface {
+	Notify(ctx context.Context, notification *entity.Notification) error
+}
+
+// QueueNotifier publishes notifications to a RabbitMQ exchange, using the
+// notification type as routing key.
+type QueueNotifier struct {
+	publisher *queue.RabbitMQ
+	exchange  string
+}
+
+func NewQueueNotifier(publisher *queue.RabbitMQ, exchange string) *QueueNotifier {
+	return &QueueNotifier{publisher: publisher, exchange: exchange}
+}
+
+func (n *QueueNotifier) Notify(ctx context.Context, notification *entity.Notification) error {
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return fmt.Errorf("marshal notification: %w", err)
+	}
+
+	if err := n.publisher.Publish(n.exchange, notification.Type, body); err != nil {
+		return fmt.Errorf("publish notification: %w", err)
+	}
+	return nil
+}
+
+// LogNotifier only writes notifications to the application log. It is used when
+// no message broker is available.
+type LogNotifier struct{}
+
+func NewLogNotifier() *LogNotifier {
+	return &LogNotifier{}
+}
+
+func (n *LogNotifier) Notify(ctx context.Context, notification *entity.Notification) error {
+	logger.Log.WithFields(log.Fields{
+		"type":    notification.Type,
+		"user_id": notification.UserID,
+		"task_id": notification.TaskID,
+	}).Info(notification.Message)
+	return nil
+}
@@ -0,0 +1,25 @@
+package assignee_repository
+
+import (
+	"DataTask/internal/domain/entity"
+	"context"
+)
+
+// AssigneeRepository keeps the users assigned to tasks and the users watching them.
+type AssigneeRepository interface {
+	GetAssignees(ctx context.Context, taskID int) ([]*entity.User, error)
+	GetAssigneesByTaskIDs(ctx context.Context, taskIDs []int) (map[int][]*entity.User, error)
+	// AddAssignee reports false when the user was already assigned.
+	AddAssignee(ctx context.Context, taskID int, userID int) (bool, error)
+	// RemoveAssignee reports false when the user was not assigned.
+	RemoveAssignee(ctx context.Context, taskID int, userID int) (bool, error)
+
+	GetWatchers(ctx context.Context, taskID int) ([]*entity.User, error)
+	// AddWatcher reports false when the user was already watching.
+	AddWatcher(ctx context.Context, taskID int, userID int) (bool, error)
+	// RemoveWatcher reports false when the user was not watching.
+	RemoveWatcher(ctx context.Context, taskID int, userID int) (bool, error)
+
+	// GetSubscriberIDs returns the assignees and watchers of a task.
+	GetSubscriberIDs(ctx context.Context, taskID int) ([]int, error)
+}
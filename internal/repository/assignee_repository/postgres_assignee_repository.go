@@ -0,0 +1,192 @@
+package assignee_repository
+
+import (
+	"DataTask/internal/domain/entity"
+	"DataTask/internal/repository/database"
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/lib/pq"
+)
+
+const userColumns = `u.id, u.name, u.surname, u.email, u.avatar_url, u.created_at, u.updated_at`
+
+type PostgresAssigneeRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresAssigneeRepository(db *sql.DB) *PostgresAssigneeRepository {
+	return &PostgresAssigneeRepository{db: db}
+}
+
+func (r *PostgresAssigneeRepository) GetAssignees(ctx context.Context, taskID int) ([]*entity.User, error) {
+	users, err := r.getTaskUsers(ctx, database.TaskUsersTable, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("get assignees: %w", err)
+	}
+	return users, nil
+}
+
+func (r *PostgresAssigneeRepository) GetAssigneesByTaskIDs(ctx context.Context, taskIDs []int) (map[int][]*entity.User, error) {
+	usersByTask := make(map[int][]*entity.User, len(taskIDs))
+	if len(taskIDs) == 0 {
+		return usersByTask, nil
+	}
+
+	q := fmt.Sprintf(`
+        SELECT tu.task_id, %s
+        FROM %s tu
+        JOIN %s u ON tu.user_id = u.id
+        WHERE tu.task_id = ANY($1)
+        ORDER BY u.name, u.surname, u.id;
+    `, userColumns, database.TaskUsersTable, database.UsersTable)
+
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, q, pq.Array(taskIDs))
+	if err != nil {
+		return nil, fmt.Errorf("get assignees by task ids: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var taskID int
+		var u entity.User
+		if err := rows.Scan(&taskID, &u.ID, &u.Name, &u.Surname, &u.Email, &u.AvatarURL, &u.CreatedAt, &u.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scan assignee: %w", err)
+		}
+		usersByTask[taskID] = append(usersByTask[taskID], &u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return usersByTask, nil
+}
+
+func (r *PostgresAssigneeRepository) AddAssignee(ctx context.Context, taskID int, userID int) (bool, error) {
+	added, err := r.addTaskUser(ctx, database.TaskUsersTable, taskID, userID)
+	if err != nil {
+		return false, fmt.Errorf("add assignee: %w", err)
+	}
+	return added, nil
+}
+
+func (r *PostgresAssigneeRepository) RemoveAssignee(ctx context.Context, taskID int, userID int) (bool, error) {
+	removed, err := r.removeTaskUser(ctx, database.TaskUsersTable, taskID, userID)
+	if err != nil {
+		return false, fmt.Errorf("remove assignee: %w", err)
+	}
+	return removed, nil
+}
+
+func (r *PostgresAssigneeRepository) GetWatchers(ctx context.Context, taskID int) ([]*entity.User, error) {
+	users, err := r.getTaskUsers(ctx, database.TaskWatchersTable, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("get watchers: %w", err)
+	}
+	return users, nil
+}
+
+func (r *PostgresAssigneeRepository) AddWatcher(ctx context.Context, taskID int, userID int) (bool, error) {
+	added, err := r.addTaskUser(ctx, database.TaskWatchersTable, taskID, userID)
+	if err != nil {
+		return false, fmt.Errorf("add watcher: %w", err)
+	}
+	return added, nil
+}
+
+func (r *PostgresAssigneeRepository) RemoveWatcher(ctx context.Context, taskID int, userID int) (bool, error) {
+	removed, err := r.removeTaskUser(ctx, database.TaskWatchersTable, taskID, userID)
+	if err != nil {
+		return false, fmt.Errorf("remove watcher: %w", err)
+	}
+	return removed, nil
+}
+
+func (r *PostgresAssigneeRepository) GetSubscriberIDs(ctx context.Context, taskID int) ([]int, error) {
+	q := fmt.Sprintf(`
+        SELECT user_id FROM %s WHERE task_id = $1
+        UNION
+        SELECT user_id FROM %s WHERE task_id = $1;
+    `, database.TaskUsersTable, database.TaskWatchersTable)
+
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, q, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("get subscriber ids: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan subscriber id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return ids, nil
+}
+
+// getTaskUsers lists the users linked to a task through one of the (task_id, user_id) tables.
+func (r *PostgresAssigneeRepository) getTaskUsers(ctx context.Context, table string, taskID int) ([]*entity.User, error) {
+	q := fmt.Sprintf(`
+        SELECT %s
+        FROM %s tu
+        JOIN %s u ON tu.user_id = u.id
+        WHERE tu.task_id = $1
+        ORDER BY u.name, u.surname, u.id;
+    `, userColumns, table, database.UsersTable)
+
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, q, taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []*entity.User{}
+	for rows.Next() {
+		var u entity.User
+		if err := rows.Scan(&u.ID, &u.Name, &u.Surname, &u.Email, &u.AvatarURL, &u.CreatedAt, &u.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scan user: %w", err)
+		}
+		users = append(users, &u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return users, nil
+}
+
+func (r *PostgresAssigneeRepository) addTaskUser(ctx context.Context, table string, taskID int, userID int) (bool, error) {
+	q := fmt.Sprintf(`
+        INSERT INTO %s (task_id, user_id) VALUES ($1, $2)
+        ON CONFLICT DO NOTHING;
+    `, table)
+
+	res, err := database.Conn(ctx, r.db).ExecContext(ctx, q, taskID, userID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+func (r *PostgresAssigneeRepository) removeTaskUser(ctx context.Context, table string, taskID int, userID int) (bool, error) {
+	q := fmt.Sprintf(`
+        DELETE FROM %s WHERE task_id = $1 AND user_id = $2;
+    `, table)
+
+	res, err := database.Conn(ctx, r.db).ExecContext(ctx, q, taskID, userID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
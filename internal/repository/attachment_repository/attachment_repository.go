@@ -0,0 +1,23 @@
+package attachment_repository
+
+import (
+	"DataTask/internal/domain/entity"
+	"context"
+)
+
+type AttachmentRepository interface {
+	CreateAttachment(ctx context.Context, attachment *entity.Attachment) (*entity.Attachment, error)
+	GetAttachmentByID(ctx context.Context, id int) (*entity.Attachment, error)
+	// GetAttachmentsByTaskID returns the attachments of the task and of its comments.
+	GetAttachmentsByTaskID(ctx context.Context, taskID int) ([]*entity.Attachment, error)
+	GetAttachmentsByCommentID(ctx context.Context, commentID int) ([]*entity.Attachment, error)
+	// DeleteAttachment removes the metadata; the blob is queued for deletion by the database.
+	DeleteAttachment(ctx context.Context, id int) error
+
+	// GetCommentTask returns the task a comment belongs to and the comment's author.
+	GetCommentTask(ctx context.Context, commentID int) (taskID int, authorID int, err error)
+
+	// GetQueuedBlobDeletions returns storage keys of deleted attachments whose blobs still exist.
+	GetQueuedBlobDeletions(ctx context.Context, limit int) ([]string, error)
+	DequeueBlobDeletion(ctx context.Context, storageKey string) error
+}
@@ -0,0 +1,177 @@
+package attachment_repository
+
+import (
+	"DataTask/internal/domain/domain_error"
+	"DataTask/internal/domain/entity"
+	"DataTask/internal/repository/database"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+const attachmentColumns = `id, task_id, comment_id, file_name, content_type, size, storage_key, uploaded_by, created_at`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+type PostgresAttachmentRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresAttachmentRepository(db *sql.DB) *PostgresAttachmentRepository {
+	return &PostgresAttachmentRepository{db: db}
+}
+
+func scanAttachment(row rowScanner) (*entity.Attachment, error) {
+	var a entity.Attachment
+	err := row.Scan(&a.ID, &a.TaskID, &a.CommentID, &a.FileName, &a.ContentType, &a.Size, &a.StorageKey,
+		&a.UploadedBy, &a.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func scanAttachments(rows *sql.Rows) ([]*entity.Attachment, error) {
+	var attachments []*entity.Attachment
+	for rows.Next() {
+		a, err := scanAttachment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan attachment: %w", err)
+		}
+		attachments = append(attachments, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return attachments, nil
+}
+
+func (r *PostgresAttachmentRepository) CreateAttachment(ctx context.Context, attachment *entity.Attachment) (*entity.Attachment, error) {
+	q := fmt.Sprintf(`
+        INSERT INTO %s (task_id, comment_id, file_name, content_type, size, storage_key, uploaded_by)
+        VALUES ($1, $2, $3, $4, $5, $6, $7)
+        RETURNING %s;
+    `, database.AttachmentsTable, attachmentColumns)
+
+	created, err := scanAttachment(database.Conn(ctx, r.db).QueryRowContext(ctx, q,
+		attachment.TaskID, attachment.CommentID, attachment.FileName, attachment.ContentType, attachment.Size,
+		attachment.StorageKey, attachment.UploadedBy,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("create attachment: %w", err)
+	}
+	return created, nil
+}
+
+func (r *PostgresAttachmentRepository) GetAttachmentByID(ctx context.Context, id int) (*entity.Attachment, error) {
+	q := fmt.Sprintf(`
+        SELECT %s FROM %s WHERE id = $1;
+    `, attachmentColumns, database.AttachmentsTable)
+
+	attachment, err := scanAttachment(database.Conn(ctx, r.db).QueryRowContext(ctx, q, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: attachment %d", domain_error.ErrNotFound, id)
+		}
+		return nil, fmt.Errorf("get attachment by id: %w", err)
+	}
+	return attachment, nil
+}
+
+func (r *PostgresAttachmentRepository) GetAttachmentsByTaskID(ctx context.Context, taskID int) ([]*entity.Attachment, error) {
+	q := fmt.Sprintf(`
+        SELECT %s FROM %s WHERE task_id = $1 ORDER BY created_at, id;
+    `, attachmentColumns, database.AttachmentsTable)
+
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, q, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("get attachments by task id: %w", err)
+	}
+	defer rows.Close()
+
+	return scanAttachments(rows)
+}
+
+func (r *PostgresAttachmentRepository) GetAttachmentsByCommentID(ctx context.Context, commentID int) ([]*entity.Attachment, error) {
+	q := fmt.Sprintf(`
+        SELECT %s FROM %s WHERE comment_id = $1 ORDER BY created_at, id;
+    `, attachmentColumns, database.AttachmentsTable)
+
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, q, commentID)
+	if err != nil {
+		return nil, fmt.Errorf("get attachments by comment id: %w", err)
+	}
+	defer rows.Close()
+
+	return scanAttachments(rows)
+}
+
+func (r *PostgresAttachmentRepository) DeleteAttachment(ctx context.Context, id int) error {
+	q := fmt.Sprintf(`
+        DELETE FROM %s WHERE id = $1;
+    `, database.AttachmentsTable)
+
+	if _, err := database.Conn(ctx, r.db).ExecContext(ctx, q, id); err != nil {
+		return fmt.Errorf("delete attachment: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresAttachmentRepository) GetCommentTask(ctx context.Context, commentID int) (int, int, error) {
+	q := fmt.Sprintf(`
+        SELECT ct.task_id, c.author
+        FROM %s ct
+        JOIN %s c ON c.id = ct.comment_id
+        WHERE ct.comment_id = $1;
+    `, database.CommentTaskTable, database.CommentTable)
+
+	var taskID int
+	var authorID sql.NullInt64
+	err := database.Conn(ctx, r.db).QueryRowContext(ctx, q, commentID).Scan(&taskID, &authorID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, 0, fmt.Errorf("%w: comment %d", domain_error.ErrNotFound, commentID)
+		}
+		return 0, 0, fmt.Errorf("get comment task: %w", err)
+	}
+	return taskID, int(authorID.Int64), nil
+}
+
+func (r *PostgresAttachmentRepository) GetQueuedBlobDeletions(ctx context.Context, limit int) ([]string, error) {
+	q := fmt.Sprintf(`
+        SELECT storage_key FROM %s ORDER BY queued_at LIMIT $1;
+    `, database.AttachmentBlobDeletionsTable)
+
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, q, limit)
+	if err != nil {
+		return nil, fmt.Errorf("get queued blob deletions: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []string
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, fmt.Errorf("scan storage key: %w", err)
+		}
+		keys = append(keys, key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return keys, nil
+}
+
+func (r *PostgresAttachmentRepository) DequeueBlobDeletion(ctx context.Context, storageKey string) error {
+	q := fmt.Sprintf(`
+        DELETE FROM %s WHERE storage_key = $1;
+    `, database.AttachmentBlobDeletionsTable)
+
+	if _, err := database.Conn(ctx, r.db).ExecContext(ctx, q, storageKey); err != nil {
+		return fmt.Errorf("dequeue blob deletion: %w", err)
+	}
+	return nil
+}
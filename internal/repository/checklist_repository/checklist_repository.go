@@ -0,0 +1,17 @@
+package checklist_repository
+
+import (
+	"DataTask/internal/domain/entity"
+	"context"
+)
+
+type ChecklistRepository interface {
+	// CreateItem appends the item to the end of the task's checklist.
+	CreateItem(ctx context.Context, item *entity.ChecklistItem) (*entity.ChecklistItem, error)
+	GetItemByID(ctx context.Context, id int) (*entity.ChecklistItem, error)
+	GetItemsByTaskID(ctx context.Context, taskID int) ([]*entity.ChecklistItem, error)
+	UpdateItem(ctx context.Context, item *entity.ChecklistItem) (*entity.ChecklistItem, error)
+	DeleteItem(ctx context.Context, id int) error
+	// ReorderItems sets the position of every listed item to its index in itemIDs.
+	ReorderItems(ctx context.Context, taskID int, itemIDs []int) error
+}
@@ -0,0 +1,126 @@
+package checklist_repository
+
+import (
+	"DataTask/internal/domain/domain_error"
+	"DataTask/internal/domain/entity"
+	"DataTask/internal/repository/database"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/lib/pq"
+)
+
+type PostgresChecklistRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresChecklistRepository(db *sql.DB) *PostgresChecklistRepository {
+	return &PostgresChecklistRepository{db: db}
+}
+
+func (r *PostgresChecklistRepository) CreateItem(ctx context.Context, item *entity.ChecklistItem) (*entity.ChecklistItem, error) {
+	q := fmt.Sprintf(`
+        INSERT INTO %s (task_id, text, is_done, assignee_id, position)
+        VALUES ($1, $2, $3, $4, (SELECT COALESCE(MAX(position), -1) + 1 FROM %s WHERE task_id = $1))
+        RETURNING id, task_id, text, is_done, position, assignee_id, created_at, updated_at;
+    `, database.ChecklistItemsTable, database.ChecklistItemsTable)
+
+	err := database.Conn(ctx, r.db).QueryRowContext(ctx, q, item.TaskID, item.Text, item.IsDone, item.AssigneeID).Scan(
+		&item.ID, &item.TaskID, &item.Text, &item.IsDone, &item.Position, &item.AssigneeID, &item.CreatedAt, &item.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("create checklist item: %w", err)
+	}
+	return item, nil
+}
+
+func (r *PostgresChecklistRepository) GetItemByID(ctx context.Context, id int) (*entity.ChecklistItem, error) {
+	q := fmt.Sprintf(`
+        SELECT id, task_id, text, is_done, position, assignee_id, created_at, updated_at
+        FROM %s WHERE id = $1;
+    `, database.ChecklistItemsTable)
+
+	item := new(entity.ChecklistItem)
+	err := database.Conn(ctx, r.db).QueryRowContext(ctx, q, id).Scan(
+		&item.ID, &item.TaskID, &item.Text, &item.IsDone, &item.Position, &item.AssigneeID, &item.CreatedAt, &item.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: checklist item %d", domain_error.ErrNotFound, id)
+		}
+		return nil, fmt.Errorf("get checklist item by id: %w", err)
+	}
+	return item, nil
+}
+
+func (r *PostgresChecklistRepository) GetItemsByTaskID(ctx context.Context, taskID int) ([]*entity.ChecklistItem, error) {
+	q := fmt.Sprintf(`
+        SELECT id, task_id, text, is_done, position, assignee_id, created_at, updated_at
+        FROM %s WHERE task_id = $1
+        ORDER BY position, id;
+    `, database.ChecklistItemsTable)
+
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, q, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("get checklist items by task id: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]*entity.ChecklistItem, 0)
+	for rows.Next() {
+		var item entity.ChecklistItem
+		if err := rows.Scan(
+			&item.ID, &item.TaskID, &item.Text, &item.IsDone, &item.Position, &item.AssigneeID, &item.CreatedAt, &item.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan checklist item: %w", err)
+		}
+		items = append(items, &item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return items, nil
+}
+
+func (r *PostgresChecklistRepository) UpdateItem(ctx context.Context, item *entity.ChecklistItem) (*entity.ChecklistItem, error) {
+	q := fmt.Sprintf(`
+        UPDATE %s SET text = $1, is_done = $2, assignee_id = $3, updated_at = NOW()
+        WHERE id = $4
+        RETURNING id, task_id, text, is_done, position, assignee_id, created_at, updated_at;
+    `, database.ChecklistItemsTable)
+
+	err := database.Conn(ctx, r.db).QueryRowContext(ctx, q, item.Text, item.IsDone, item.AssigneeID, item.ID).Scan(
+		&item.ID, &item.TaskID, &item.Text, &item.IsDone, &item.Position, &item.AssigneeID, &item.CreatedAt, &item.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("update checklist item: %w", err)
+	}
+	return item, nil
+}
+
+func (r *PostgresChecklistRepository) DeleteItem(ctx context.Context, id int) error {
+	q := fmt.Sprintf(`
+        DELETE FROM %s WHERE id = $1;
+    `, database.ChecklistItemsTable)
+
+	_, err := database.Conn(ctx, r.db).ExecContext(ctx, q, id)
+	if err != nil {
+		return fmt.Errorf("delete checklist item: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresChecklistRepository) ReorderItems(ctx context.Context, taskID int, itemIDs []int) error {
+	q := fmt.Sprintf(`
+        UPDATE %s c SET position = o.ord - 1, updated_at = NOW()
+        FROM unnest($2::int[]) WITH ORDINALITY AS o(id, ord)
+        WHERE c.id = o.id AND c.task_id = $1;
+    `, database.ChecklistItemsTable)
+
+	_, err := database.Conn(ctx, r.db).ExecContext(ctx, q, taskID, pq.Array(itemIDs))
+	if err != nil {
+		return fmt.Errorf("reorder checklist items: %w", err)
+	}
+	return nil
+}
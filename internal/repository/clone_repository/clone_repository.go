@@ -0,0 +1,44 @@
+package clone_repository
+
+import (
+	"DataTask/internal/domain/entity"
+	"context"
+	"time"
+)
+
+// CloneRepository copies projects, kanbans and tasks. The copy methods keep track of the rows
+// copied so far in a table that lives until the transaction ends, so one clone has to run from
+// BeginClone to FinishClone inside a single transaction.
+type CloneRepository interface {
+	// GetProjectTree returns the project and its descendants that are not deleted, parents first.
+	GetProjectTree(ctx context.Context, projectID int) ([]*entity.Project, error)
+	GetKanbanIDsByProjectIDs(ctx context.Context, projectIDs []int) ([]int, error)
+	// GetTaskTree returns the task and its subtasks at any depth that are not deleted.
+	GetTaskTree(ctx context.Context, taskID int) ([]int, error)
+	GetTaskIDsByKanbanIDs(ctx context.Context, kanbanIDs []int) ([]int, error)
+
+	BeginClone(ctx context.Context) error
+	// CloneProjects copies the plan's projects with their settings, labels, custom fields and task
+	// templates, and their members when the options ask for it.
+	CloneProjects(ctx context.Context, plan *entity.ClonePlan) error
+	// CloneKanbans copies the plan's kanbans into the copies of their projects, or into the target
+	// project when their project is not copied.
+	CloneKanbans(ctx context.Context, plan *entity.ClonePlan) error
+	// CloneTasks copies a batch of the plan's tasks with their checklists, labels and custom field
+	// values, and their comments and assignees when the options ask for it.
+	CloneTasks(ctx context.Context, plan *entity.ClonePlan, taskIDs []int) error
+	// FinishClone links the copied tasks to their copied parents and copies the links between
+	// them, returning the ID of the copy of the plan's source.
+	FinishClone(ctx context.Context, plan *entity.ClonePlan) (int, error)
+
+	CreateJob(ctx context.Context, job *entity.CloneJob) (*entity.CloneJob, error)
+	GetJobByID(ctx context.Context, id int) (*entity.CloneJob, error)
+	// ClaimJob marks the oldest queued job as running and returns it, or nil when there is none.
+	// Jobs left running since before staleBefore are claimed again; their transaction was rolled
+	// back when their worker stopped.
+	ClaimJob(ctx context.Context, staleBefore time.Time) (*entity.CloneJob, error)
+	// UpdateJobProgress and FinishJob write outside of any transaction in ctx, so progress is
+	// visible while the clone's transaction is still open.
+	UpdateJobProgress(ctx context.Context, id int, done int, total int) error
+	FinishJob(ctx context.Context, id int, resultID *int, errMsg *string) error
+}
@@ -0,0 +1,632 @@
+package clone_repository
+
+import (
+	"DataTask/internal/domain/domain_error"
+	"DataTask/internal/domain/entity"
+	"DataTask/internal/repository/database"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/lib/pq"
+	"time"
+)
+
+// cloneMapTable maps the IDs of source rows to the IDs of their copies, per kind of row.
+const cloneMapTable = "clone_map"
+
+// Kinds of rows in the clone map.
+const (
+	mapProject = "project"
+	mapKanban  = "kanban"
+	mapTask    = "task"
+	mapLabel   = "label"
+	mapField   = "field"
+	mapComment = "comment"
+	mapStatus  = "status"
+)
+
+const jobColumns = `j.id, j.user_id, j.type, j.source_id, j.options, j.status, j.total, j.done, j.result_id,
+    j.error, j.created_at, j.started_at, j.finished_at`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+type PostgresCloneRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresCloneRepository(db *sql.DB) *PostgresCloneRepository {
+	return &PostgresCloneRepository{db: db}
+}
+
+// memberCond is an SQL condition checking that the user is the owner or a member of the project.
+func memberCond(userExpr, projectExpr string) string {
+	return fmt.Sprintf(`(EXISTS (SELECT 1 FROM %[3]s mp WHERE mp.id = %[2]s AND mp.owner_id = %[1]s)
+            OR EXISTS (SELECT 1 FROM %[4]s mu WHERE mu.project_id = %[2]s AND mu.user_id = %[1]s))`,
+		userExpr, projectExpr, database.ProjectsTable, database.ProjectUsersTable)
+}
+
+func (r *PostgresCloneRepository) GetProjectTree(ctx context.Context, projectID int) ([]*entity.Project, error) {
+	// UNION (not UNION ALL) stops the recursion should the project tree ever contain a cycle.
+	q := fmt.Sprintf(`
+        WITH RECURSIVE tree AS (
+            SELECT id, parent_project_id, 0 AS depth FROM %[1]s WHERE id = $1 AND deleted_at IS NULL
+            UNION
+            SELECT p.id, p.parent_project_id, t.depth + 1
+            FROM %[1]s p
+            JOIN tree t ON p.parent_project_id = t.id
+            WHERE p.deleted_at IS NULL
+        )
+        SELECT id, parent_project_id FROM tree ORDER BY depth, id;
+    `, database.ProjectsTable)
+
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, q, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("get project tree: %w", err)
+	}
+	defer rows.Close()
+
+	var projects []*entity.Project
+	for rows.Next() {
+		var p entity.Project
+		if err := rows.Scan(&p.ID, &p.ParentProjectID); err != nil {
+			return nil, fmt.Errorf("scan project: %w", err)
+		}
+		projects = append(projects, &p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	if len(projects) == 0 {
+		return nil, fmt.Errorf("%w: project %d", domain_error.ErrNotFound, projectID)
+	}
+	return projects, nil
+}
+
+func (r *PostgresCloneRepository) GetKanbanIDsByProjectIDs(ctx context.Context, projectIDs []int) ([]int, error) {
+	q := fmt.Sprintf(`
+        SELECT id FROM %s WHERE project_id = ANY($1) AND deleted_at IS NULL ORDER BY id;
+    `, database.KanbanTable)
+
+	return r.queryIDs(ctx, "get kanban ids", q, pq.Array(projectIDs))
+}
+
+func (r *PostgresCloneRepository) GetTaskTree(ctx context.Context, taskID int) ([]int, error) {
+	q := fmt.Sprintf(`
+        WITH RECURSIVE tree AS (
+            SELECT id FROM %[1]s WHERE id = $1 AND deleted_at IS NULL
+            UNION
+            SELECT t.id FROM %[1]s t JOIN tree ON t.parent_task_id = tree.id WHERE t.deleted_at IS NULL
+        )
+        SELECT id FROM tree ORDER BY id;
+    `, database.TaskTable)
+
+	ids, err := r.queryIDs(ctx, "get task tree", q, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("%w: task %d", domain_error.ErrNotFound, taskID)
+	}
+	return ids, nil
+}
+
+func (r *PostgresCloneRepository) GetTaskIDsByKanbanIDs(ctx context.Context, kanbanIDs []int) ([]int, error) {
+	q := fmt.Sprintf(`
+        SELECT id FROM %s WHERE kanban_id = ANY($1) AND deleted_at IS NULL ORDER BY id;
+    `, database.TaskTable)
+
+	return r.queryIDs(ctx, "get task ids", q, pq.Array(kanbanIDs))
+}
+
+func (r *PostgresCloneRepository) queryIDs(ctx context.Context, what string, q string, args ...any) ([]int, error) {
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", what, err)
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("%s: %w", what, err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return ids, nil
+}
+
+func (r *PostgresCloneRepository) BeginClone(ctx context.Context) error {
+	q := fmt.Sprintf(`
+        CREATE TEMPORARY TABLE %s
+        (
+            kind   VARCHAR(16) NOT NULL,
+            old_id INTEGER     NOT NULL,
+            new_id INTEGER     NOT NULL,
+            PRIMARY KEY (kind, old_id)
+        ) ON COMMIT DROP;
+    `, cloneMapTable)
+
+	if _, err := database.Conn(ctx, r.db).ExecContext(ctx, q); err != nil {
+		return fmt.Errorf("begin clone: %w", err)
+	}
+	return nil
+}
+
+// mapIDs assigns the IDs of the copies of the source rows of a table before they are inserted,
+// so rows referring to each other can be copied with plain INSERT ... SELECT statements.
+func (r *PostgresCloneRepository) mapIDs(ctx context.Context, kind string, table string, sourceQuery string, args ...any) error {
+	q := fmt.Sprintf(`
+        INSERT INTO %s (kind, old_id, new_id)
+        SELECT '%s', src.id, nextval(pg_get_serial_sequence('%s', 'id'))
+        FROM (%s) src
+        ON CONFLICT DO NOTHING;
+    `, cloneMapTable, kind, table, sourceQuery)
+
+	if _, err := database.Conn(ctx, r.db).ExecContext(ctx, q, args...); err != nil {
+		return fmt.Errorf("map %s ids: %w", kind, err)
+	}
+	return nil
+}
+
+func (r *PostgresCloneRepository) CloneProjects(ctx context.Context, plan *entity.ClonePlan) error {
+	conn := database.Conn(ctx, r.db)
+
+	err := r.mapIDs(ctx, mapProject, database.ProjectsTable, `SELECT unnest($1::INTEGER[]) AS id`, pq.Array(plan.ProjectIDs))
+	if err != nil {
+		return err
+	}
+	projectsQuery := fmt.Sprintf(`
+        INSERT INTO %[1]s (id, owner_id, name, description, color, parent_project_id)
+        SELECT m.new_id, $1, CASE WHEN p.id = $2 THEN $3 ELSE p.name END, p.description, p.color,
+            CASE WHEN p.id = $2 THEN $4::INTEGER ELSE pm.new_id END
+        FROM %[2]s m
+        JOIN %[1]s p ON p.id = m.old_id
+        LEFT JOIN %[2]s pm ON pm.kind = '%[3]s' AND pm.old_id = p.parent_project_id
+        WHERE m.kind = '%[3]s';
+    `, database.ProjectsTable, cloneMapTable, mapProject)
+	_, err = conn.ExecContext(ctx, projectsQuery,
+		plan.UserID, plan.SourceID, plan.Options.Name, plan.Options.ParentProjectID)
+	if err != nil {
+		return fmt.Errorf("clone projects: %w", err)
+	}
+
+	settingsQuery := fmt.Sprintf(`
+        INSERT INTO %s (project_id, blocked_completion_policy, estimate_unit, wip_limit_policy)
+        SELECT m.new_id, s.blocked_completion_policy, s.estimate_unit, s.wip_limit_policy
+        FROM %s s
+        JOIN %s m ON m.kind = '%s' AND m.old_id = s.project_id;
+    `, database.ProjectSettingsTable, database.ProjectSettingsTable, cloneMapTable, mapProject)
+	if _, err := conn.ExecContext(ctx, settingsQuery); err != nil {
+		return fmt.Errorf("clone project settings: %w", err)
+	}
+
+	if plan.Options.IncludeMembers {
+		// The owners of the source projects stay in charge of the copies as members.
+		membersQuery := fmt.Sprintf(`
+            INSERT INTO %[1]s (project_id, user_id, permission, invited_by_user_id, invited_at, joined_at)
+            SELECT m.new_id, pu.user_id, pu.permission, pu.invited_by_user_id, pu.invited_at, pu.joined_at
+            FROM %[1]s pu
+            JOIN %[2]s m ON m.kind = '%[4]s' AND m.old_id = pu.project_id
+            WHERE pu.user_id <> $1
+            UNION ALL
+            SELECT m.new_id, p.owner_id, '%[5]s', $1, NOW(), NOW()
+            FROM %[3]s p
+            JOIN %[2]s m ON m.kind = '%[4]s' AND m.old_id = p.id
+            WHERE p.owner_id <> $1
+            ON CONFLICT DO NOTHING;
+        `, database.ProjectUsersTable, cloneMapTable, database.ProjectsTable, mapProject, entity.PermissionOwner)
+		if _, err := conn.ExecContext(ctx, membersQuery, plan.UserID); err != nil {
+			return fmt.Errorf("clone project members: %w", err)
+		}
+	}
+
+	err = r.mapIDs(ctx, mapLabel, database.LabelsTable, fmt.Sprintf(
+		`SELECT l.id FROM %s l JOIN %s m ON m.kind = '%s' AND m.old_id = l.project_id`,
+		database.LabelsTable, cloneMapTable, mapProject,
+	))
+	if err != nil {
+		return err
+	}
+	labelsQuery := fmt.Sprintf(`
+        INSERT INTO %[1]s (id, project_id, name, color)
+        SELECT lm.new_id, pm.new_id, l.name, l.color
+        FROM %[2]s lm
+        JOIN %[1]s l ON l.id = lm.old_id
+        JOIN %[2]s pm ON pm.kind = '%[4]s' AND pm.old_id = l.project_id
+        WHERE lm.kind = '%[3]s';
+    `, database.LabelsTable, cloneMapTable, mapLabel, mapProject)
+	if _, err := conn.ExecContext(ctx, labelsQuery); err != nil {
+		return fmt.Errorf("clone labels: %w", err)
+	}
+
+	err = r.mapIDs(ctx, mapField, database.CustomFieldsTable, fmt.Sprintf(
+		`SELECT f.id FROM %s f JOIN %s m ON m.kind = '%s' AND m.old_id = f.project_id`,
+		database.CustomFieldsTable, cloneMapTable, mapProject,
+	))
+	if err != nil {
+		return err
+	}
+	fieldsQuery := fmt.Sprintf(`
+        INSERT INTO %[1]s (id, project_id, name, type, options, position)
+        SELECT fm.new_id, pm.new_id, f.name, f.type, f.options, f.position
+        FROM %[2]s fm
+        JOIN %[1]s f ON f.id = fm.old_id
+        JOIN %[2]s pm ON pm.kind = '%[4]s' AND pm.old_id = f.project_id
+        WHERE fm.kind = '%[3]s';
+    `, database.CustomFieldsTable, cloneMapTable, mapField, mapProject)
+	if _, err := conn.ExecContext(ctx, fieldsQuery); err != nil {
+		return fmt.Errorf("clone custom fields: %w", err)
+	}
+
+	err = r.mapIDs(ctx, mapStatus, database.WorkflowStatusesTable, fmt.Sprintf(
+		`SELECT ws.id FROM %s ws JOIN %s m ON m.kind = '%s' AND m.old_id = ws.project_id`,
+		database.WorkflowStatusesTable, cloneMapTable, mapProject,
+	))
+	if err != nil {
+		return err
+	}
+	statusesQuery := fmt.Sprintf(`
+        INSERT INTO %[1]s (id, project_id, name, category, position)
+        SELECT sm.new_id, pm.new_id, ws.name, ws.category, ws.position
+        FROM %[2]s sm
+        JOIN %[1]s ws ON ws.id = sm.old_id
+        JOIN %[2]s pm ON pm.kind = '%[4]s' AND pm.old_id = ws.project_id
+        WHERE sm.kind = '%[3]s';
+    `, database.WorkflowStatusesTable, cloneMapTable, mapStatus, mapProject)
+	if _, err := conn.ExecContext(ctx, statusesQuery); err != nil {
+		return fmt.Errorf("clone workflow statuses: %w", err)
+	}
+	transitionsQuery := fmt.Sprintf(`
+        INSERT INTO %[1]s (from_status_id, to_status_id)
+        SELECT fm.new_id, tm.new_id
+        FROM %[1]s wt
+        JOIN %[2]s fm ON fm.kind = '%[3]s' AND fm.old_id = wt.from_status_id
+        JOIN %[2]s tm ON tm.kind = '%[3]s' AND tm.old_id = wt.to_status_id;
+    `, database.WorkflowTransitionsTable, cloneMapTable, mapStatus)
+	if _, err := conn.ExecContext(ctx, transitionsQuery); err != nil {
+		return fmt.Errorf("clone workflow transitions: %w", err)
+	}
+
+	// Template labels point to the copied labels; labels inherited from outside the copy are kept.
+	templatesQuery := fmt.Sprintf(`
+        INSERT INTO %[1]s (project_id, name, title_pattern, description, priority, label_ids, checklist, assignee_ids)
+        SELECT pm.new_id, tt.name, tt.title_pattern, tt.description, tt.priority,
+            ARRAY(
+                SELECT COALESCE(lm.new_id, u.label_id)
+                FROM unnest(tt.label_ids) WITH ORDINALITY AS u (label_id, n)
+                LEFT JOIN %[2]s lm ON lm.kind = '%[4]s' AND lm.old_id = u.label_id
+                ORDER BY u.n
+            ),
+            tt.checklist, tt.assignee_ids
+        FROM %[1]s tt
+        JOIN %[2]s pm ON pm.kind = '%[3]s' AND pm.old_id = tt.project_id;
+    `, database.TaskTemplatesTable, cloneMapTable, mapProject, mapLabel)
+	if _, err := conn.ExecContext(ctx, templatesQuery); err != nil {
+		return fmt.Errorf("clone task templates: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresCloneRepository) CloneKanbans(ctx context.Context, plan *entity.ClonePlan) error {
+	err := r.mapIDs(ctx, mapKanban, database.KanbanTable, `SELECT unnest($1::INTEGER[]) AS id`, pq.Array(plan.KanbanIDs))
+	if err != nil {
+		return err
+	}
+
+	// Only a kanban clone renames its source.
+	renamed := 0
+	if plan.Type == entity.CloneKanban {
+		renamed = plan.SourceID
+	}
+	// Kanbans keep their status only while it is a status of their project. A cloned kanban goes
+	// to the end of its project; the kanbans of a cloned project keep their order.
+	q := fmt.Sprintf(`
+        INSERT INTO %[1]s (id, name, project_id, status_id, wip_limit, position)
+        SELECT km.new_id, CASE WHEN k.id = $1 THEN $2 ELSE k.name END, COALESCE(pm.new_id, $3),
+            COALESCE(sm.new_id, CASE WHEN $4 THEN k.status_id END), k.wip_limit,
+            CASE WHEN k.id = $1 THEN (SELECT COALESCE(MAX(e.position), -1) + 1 FROM %[1]s e WHERE e.project_id = $3)
+                ELSE k.position END
+        FROM %[2]s km
+        JOIN %[1]s k ON k.id = km.old_id
+        LEFT JOIN %[2]s pm ON pm.kind = '%[4]s' AND pm.old_id = k.project_id
+        LEFT JOIN %[2]s sm ON sm.kind = '%[5]s' AND sm.old_id = k.status_id
+        WHERE km.kind = '%[3]s';
+    `, database.KanbanTable, cloneMapTable, mapKanban, mapProject, mapStatus)
+	_, err = database.Conn(ctx, r.db).ExecContext(ctx, q, renamed, plan.Options.Name, plan.TargetProjectID, plan.SameProject)
+	if err != nil {
+		return fmt.Errorf("clone kanbans: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresCloneRepository) CloneTasks(ctx context.Context, plan *entity.ClonePlan, taskIDs []int) error {
+	conn := database.Conn(ctx, r.db)
+	batch := pq.Array(taskIDs)
+
+	if err := r.mapIDs(ctx, mapTask, database.TaskTable, `SELECT unnest($1::INTEGER[]) AS id`, batch); err != nil {
+		return err
+	}
+
+	// Parents are linked by FinishClone once all tasks exist. Copies do not join the recurrence
+	// series of their source. Copies leaving the project get a status of their new project
+	// matching is_completed.
+	renamed := 0
+	if plan.Type == entity.CloneTask {
+		renamed = plan.SourceID
+	}
+	tasksQuery := fmt.Sprintf(`
+        INSERT INTO %[1]s (id, title, description, is_completed, kanban_id, start_at, due_at, time_zone, priority,
+            estimate, status_id)
+        SELECT tm.new_id, CASE WHEN t.id = $2 THEN $3 ELSE t.title END, t.description, t.is_completed,
+            COALESCE(km.new_id, NULLIF($4, 0), t.kanban_id), t.start_at, t.due_at, t.time_zone, t.priority,
+            t.estimate, COALESCE(sm.new_id, CASE WHEN $5 THEN t.status_id END)
+        FROM %[2]s tm
+        JOIN %[1]s t ON t.id = tm.old_id
+        LEFT JOIN %[2]s km ON km.kind = '%[4]s' AND km.old_id = t.kanban_id
+        LEFT JOIN %[2]s sm ON sm.kind = '%[5]s' AND sm.old_id = t.status_id
+        WHERE tm.kind = '%[3]s' AND tm.old_id = ANY($1);
+    `, database.TaskTable, cloneMapTable, mapTask, mapKanban, mapStatus)
+	_, err := conn.ExecContext(ctx, tasksQuery, batch, renamed, plan.Options.Name, plan.TargetKanbanID,
+		plan.SameProject)
+	if err != nil {
+		return fmt.Errorf("clone tasks: %w", err)
+	}
+
+	// copies joins the copied tasks of the batch with their source (t) and the project of the copy
+	// (nk.project_id).
+	copies := fmt.Sprintf(`
+        %[2]s tm
+        JOIN %[1]s nt ON nt.id = tm.new_id
+        JOIN %[3]s nk ON nk.id = nt.kanban_id`,
+		database.TaskTable, cloneMapTable, database.KanbanTable)
+	batchCond := fmt.Sprintf("tm.kind = '%s' AND tm.old_id = ANY($1)", mapTask)
+
+	checklistQuery := fmt.Sprintf(`
+        INSERT INTO %[1]s (task_id, text, is_done, position, assignee_id)
+        SELECT tm.new_id, c.text, c.is_done, c.position,
+            CASE WHEN $2 AND %[4]s THEN c.assignee_id END
+        FROM %[1]s c
+        JOIN %[2]s ON tm.old_id = c.task_id
+        WHERE %[3]s;
+    `, database.ChecklistItemsTable, copies, batchCond, memberCond("c.assignee_id", "nk.project_id"))
+	if _, err := conn.ExecContext(ctx, checklistQuery, batch, plan.Options.IncludeAssignees); err != nil {
+		return fmt.Errorf("clone checklists: %w", err)
+	}
+
+	labelsQuery := fmt.Sprintf(`
+        INSERT INTO %[1]s (task_id, label_id)
+        SELECT tm.new_id, COALESCE(lm.new_id, tl.label_id)
+        FROM %[1]s tl
+        JOIN %[2]s ON tm.old_id = tl.task_id
+        LEFT JOIN %[3]s lm ON lm.kind = '%[5]s' AND lm.old_id = tl.label_id
+        WHERE %[4]s AND (lm.new_id IS NOT NULL OR $2)
+        ON CONFLICT DO NOTHING;
+    `, database.TaskLabelsTable, copies, cloneMapTable, batchCond, mapLabel)
+	if _, err := conn.ExecContext(ctx, labelsQuery, batch, plan.SameProject); err != nil {
+		return fmt.Errorf("clone task labels: %w", err)
+	}
+
+	valuesQuery := fmt.Sprintf(`
+        INSERT INTO %[1]s (task_id, field_id, text_value, number_value, date_value, options_value, user_id)
+        SELECT tm.new_id, COALESCE(fm.new_id, v.field_id), v.text_value, v.number_value, v.date_value,
+            v.options_value, v.user_id
+        FROM %[1]s v
+        JOIN %[2]s ON tm.old_id = v.task_id
+        LEFT JOIN %[3]s fm ON fm.kind = '%[5]s' AND fm.old_id = v.field_id
+        WHERE %[4]s AND (fm.new_id IS NOT NULL OR $2)
+        ON CONFLICT DO NOTHING;
+    `, database.TaskCustomFieldValuesTable, copies, cloneMapTable, batchCond, mapField)
+	if _, err := conn.ExecContext(ctx, valuesQuery, batch, plan.SameProject); err != nil {
+		return fmt.Errorf("clone custom field values: %w", err)
+	}
+
+	if plan.Options.IncludeAssignees {
+		assigneesQuery := fmt.Sprintf(`
+            INSERT INTO %[1]s (task_id, user_id)
+            SELECT tm.new_id, tu.user_id
+            FROM %[1]s tu
+            JOIN %[2]s ON tm.old_id = tu.task_id
+            WHERE %[3]s AND %[4]s
+            ON CONFLICT DO NOTHING;
+        `, database.TaskUsersTable, copies, batchCond, memberCond("tu.user_id", "nk.project_id"))
+		if _, err := conn.ExecContext(ctx, assigneesQuery, batch); err != nil {
+			return fmt.Errorf("clone assignees: %w", err)
+		}
+	}
+
+	if plan.Options.IncludeComments {
+		if err := r.cloneComments(ctx, batch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cloneComments copies the comments of a batch of copied tasks, keeping their authors and times.
+func (r *PostgresCloneRepository) cloneComments(ctx context.Context, batch any) error {
+	err := r.mapIDs(ctx, mapComment, database.CommentTable, fmt.Sprintf(
+		`SELECT DISTINCT comment_id AS id FROM %s WHERE task_id = ANY($1)`, database.CommentTaskTable,
+	), batch)
+	if err != nil {
+		return err
+	}
+
+	// A comment shared by tasks of several batches is only inserted with the first of them.
+	commentsQuery := fmt.Sprintf(`
+        INSERT INTO %[1]s (id, author, text, created_at, updated_at)
+        SELECT cm.new_id, c.author, c.text, c.created_at, c.updated_at
+        FROM %[2]s cm
+        JOIN %[1]s c ON c.id = cm.old_id
+        WHERE cm.kind = '%[3]s' AND NOT EXISTS (SELECT 1 FROM %[1]s x WHERE x.id = cm.new_id);
+    `, database.CommentTable, cloneMapTable, mapComment)
+	if _, err := database.Conn(ctx, r.db).ExecContext(ctx, commentsQuery); err != nil {
+		return fmt.Errorf("clone comments: %w", err)
+	}
+
+	linksQuery := fmt.Sprintf(`
+        INSERT INTO %[1]s (task_id, comment_id)
+        SELECT tm.new_id, cm.new_id
+        FROM %[1]s ct
+        JOIN %[2]s tm ON tm.kind = '%[3]s' AND tm.old_id = ct.task_id
+        JOIN %[2]s cm ON cm.kind = '%[4]s' AND cm.old_id = ct.comment_id
+        WHERE ct.task_id = ANY($1)
+        ON CONFLICT DO NOTHING;
+    `, database.CommentTaskTable, cloneMapTable, mapTask, mapComment)
+	if _, err := database.Conn(ctx, r.db).ExecContext(ctx, linksQuery, batch); err != nil {
+		return fmt.Errorf("clone comment links: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresCloneRepository) FinishClone(ctx context.Context, plan *entity.ClonePlan) (int, error) {
+	conn := database.Conn(ctx, r.db)
+
+	parentsQuery := fmt.Sprintf(`
+        UPDATE %[1]s nt
+        SET parent_task_id = COALESCE(pm.new_id, CASE WHEN $1 THEN t.parent_task_id END)
+        FROM %[2]s tm
+        JOIN %[1]s t ON t.id = tm.old_id
+        LEFT JOIN %[2]s pm ON pm.kind = '%[3]s' AND pm.old_id = t.parent_task_id
+        WHERE tm.kind = '%[3]s' AND nt.id = tm.new_id AND t.parent_task_id IS NOT NULL;
+    `, database.TaskTable, cloneMapTable, mapTask)
+	if _, err := conn.ExecContext(ctx, parentsQuery, plan.SameProject); err != nil {
+		return 0, fmt.Errorf("link cloned subtasks: %w", err)
+	}
+
+	linksQuery := fmt.Sprintf(`
+        INSERT INTO %[1]s (source_task_id, target_task_id, type, created_by)
+        SELECT sm.new_id, tm.new_id, l.type, l.created_by
+        FROM %[1]s l
+        JOIN %[2]s sm ON sm.kind = '%[3]s' AND sm.old_id = l.source_task_id
+        JOIN %[2]s tm ON tm.kind = '%[3]s' AND tm.old_id = l.target_task_id
+        ON CONFLICT DO NOTHING;
+    `, database.TaskLinksTable, cloneMapTable, mapTask)
+	if _, err := conn.ExecContext(ctx, linksQuery); err != nil {
+		return 0, fmt.Errorf("clone task links: %w", err)
+	}
+
+	q := fmt.Sprintf(`
+        SELECT new_id FROM %s WHERE kind = $1 AND old_id = $2;
+    `, cloneMapTable)
+	var id int
+	if err := conn.QueryRowContext(ctx, q, plan.Type, plan.SourceID).Scan(&id); err != nil {
+		return 0, fmt.Errorf("get clone id: %w", err)
+	}
+	return id, nil
+}
+
+func scanJob(row rowScanner) (*entity.CloneJob, error) {
+	var j entity.CloneJob
+	var options []byte
+	var errMsg sql.NullString
+	var resultID sql.NullInt64
+	err := row.Scan(&j.ID, &j.UserID, &j.Type, &j.SourceID, &options, &j.Status, &j.Total, &j.Done, &resultID,
+		&errMsg, &j.CreatedAt, &j.StartedAt, &j.FinishedAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(options, &j.Options); err != nil {
+		return nil, fmt.Errorf("decode clone options: %w", err)
+	}
+	if resultID.Valid {
+		id := int(resultID.Int64)
+		j.ResultID = &id
+	}
+	if errMsg.Valid {
+		j.Error = &errMsg.String
+	}
+	return &j, nil
+}
+
+func (r *PostgresCloneRepository) CreateJob(ctx context.Context, job *entity.CloneJob) (*entity.CloneJob, error) {
+	options, err := json.Marshal(job.Options)
+	if err != nil {
+		return nil, fmt.Errorf("encode clone options: %w", err)
+	}
+
+	q := fmt.Sprintf(`
+        INSERT INTO %s AS j (user_id, type, source_id, options, total)
+        VALUES ($1, $2, $3, $4, $5)
+        RETURNING %s;
+    `, database.CloneJobsTable, jobColumns)
+
+	created, err := scanJob(database.Conn(ctx, r.db).QueryRowContext(ctx, q,
+		job.UserID, job.Type, job.SourceID, string(options), job.Total))
+	if err != nil {
+		return nil, fmt.Errorf("create clone job: %w", err)
+	}
+	return created, nil
+}
+
+func (r *PostgresCloneRepository) GetJobByID(ctx context.Context, id int) (*entity.CloneJob, error) {
+	q := fmt.Sprintf(`
+        SELECT %s FROM %s j WHERE j.id = $1;
+    `, jobColumns, database.CloneJobsTable)
+
+	job, err := scanJob(database.Conn(ctx, r.db).QueryRowContext(ctx, q, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: clone job %d", domain_error.ErrNotFound, id)
+		}
+		return nil, fmt.Errorf("get clone job by id: %w", err)
+	}
+	return job, nil
+}
+
+func (r *PostgresCloneRepository) ClaimJob(ctx context.Context, staleBefore time.Time) (*entity.CloneJob, error) {
+	q := fmt.Sprintf(`
+        UPDATE %[1]s AS j SET status = '%[3]s', started_at = NOW(), done = 0
+        WHERE j.id = (
+            SELECT id FROM %[1]s
+            WHERE status = '%[4]s' OR (status = '%[3]s' AND started_at < $1)
+            ORDER BY id
+            LIMIT 1
+            FOR UPDATE SKIP LOCKED
+        )
+        RETURNING %[2]s;
+    `, database.CloneJobsTable, jobColumns, entity.CloneJobRunning, entity.CloneJobQueued)
+
+	job, err := scanJob(r.db.QueryRowContext(ctx, q, staleBefore))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("claim clone job: %w", err)
+	}
+	return job, nil
+}
+
+func (r *PostgresCloneRepository) UpdateJobProgress(ctx context.Context, id int, done int, total int) error {
+	q := fmt.Sprintf(`
+        UPDATE %s SET done = $1, total = $2 WHERE id = $3;
+    `, database.CloneJobsTable)
+
+	if _, err := r.db.ExecContext(ctx, q, done, total, id); err != nil {
+		return fmt.Errorf("update clone job progress: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresCloneRepository) FinishJob(ctx context.Context, id int, resultID *int, errMsg *string) error {
+	status := entity.CloneJobCompleted
+	if errMsg != nil {
+		status = entity.CloneJobFailed
+	}
+
+	q := fmt.Sprintf(`
+        UPDATE %s SET status = $1, result_id = $2, error = $3, finished_at = NOW() WHERE id = $4;
+    `, database.CloneJobsTable)
+
+	if _, err := r.db.ExecContext(ctx, q, status, resultID, errMsg, id); err != nil {
+		return fmt.Errorf("finish clone job: %w", err)
+	}
+	return nil
+}
@@ -24,7 +24,7 @@ func (r *PostgresCommentRepository) CreateCommentForTask(ctx context.Context, co
     `, database.CommentTable)
 
 	var authorID int
-	err := r.db.QueryRowContext(ctx, q,
+	err := database.Conn(ctx, r.db).QueryRowContext(ctx, q,
 		comment.Author.ID,
 		comment.Text,
 	).Scan(
@@ -40,7 +40,7 @@ func (r *PostgresCommentRepository) CreateCommentForTask(ctx context.Context, co
 
 	comment.Author = &entity.User{ID: authorID}
 
-	_, err = r.db.ExecContext(ctx, fmt.Sprintf(`
+	_, err = database.Conn(ctx, r.db).ExecContext(ctx, fmt.Sprintf(`
        INSERT INTO %s (task_id, comment_id)
        VALUES ($1, $2);
     `, database.CommentTaskTable), taskID, comment.ID)
@@ -77,7 +77,7 @@ func (r *PostgresCommentRepository) GetCommentsByTaskID(ctx context.Context, tas
 			c.created_at ASC;
 	`, database.CommentTaskTable, database.CommentTable, database.UsersTable)
 
-	rows, err := r.db.QueryContext(ctx, q, taskID)
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, q, taskID)
 	if err != nil {
 		return nil, fmt.Errorf("query comments by task id: %w", err)
 	}
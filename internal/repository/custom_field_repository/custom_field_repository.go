@@ -0,0 +1,23 @@
+package custom_field_repository
+
+import (
+	"DataTask/internal/domain/entity"
+	"context"
+)
+
+type CustomFieldRepository interface {
+	CreateField(ctx context.Context, field *entity.CustomField) (*entity.CustomField, error)
+	GetFieldByID(ctx context.Context, id int) (*entity.CustomField, error)
+	UpdateField(ctx context.Context, field *entity.CustomField) (*entity.CustomField, error)
+	DeleteField(ctx context.Context, id int) error
+	// GetAvailableFields returns the fields of the project and of all its ancestors, nearest first.
+	GetAvailableFields(ctx context.Context, projectID int) ([]*entity.CustomField, error)
+	GetFieldsByIDs(ctx context.Context, ids []int) ([]*entity.CustomField, error)
+
+	// GetValuesByTaskIDs returns the field values of the tasks keyed by task ID.
+	GetValuesByTaskIDs(ctx context.Context, taskIDs []int) (map[int][]*entity.CustomFieldValue, error)
+	UpsertValue(ctx context.Context, value *entity.CustomFieldValue) error
+	DeleteValue(ctx context.Context, taskID int, fieldID int) error
+	// PruneOptionValues drops choices that are no longer offered by a select field from its values.
+	PruneOptionValues(ctx context.Context, fieldID int, options []string) error
+}
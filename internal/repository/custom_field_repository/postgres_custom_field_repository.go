@@ -0,0 +1,249 @@
+package custom_field_repository
+
+import (
+	"DataTask/internal/domain/domain_error"
+	"DataTask/internal/domain/entity"
+	"DataTask/internal/repository/database"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/lib/pq"
+)
+
+const fieldColumns = `f.id, f.project_id, f.name, f.type, f.options, f.position, f.created_at, f.updated_at`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+type PostgresCustomFieldRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresCustomFieldRepository(db *sql.DB) *PostgresCustomFieldRepository {
+	return &PostgresCustomFieldRepository{db: db}
+}
+
+func scanField(row rowScanner) (*entity.CustomField, error) {
+	var f entity.CustomField
+	err := row.Scan(&f.ID, &f.ProjectID, &f.Name, &f.Type, pq.Array(&f.Options), &f.Position, &f.CreatedAt, &f.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+func scanFields(rows *sql.Rows) ([]*entity.CustomField, error) {
+	var fields []*entity.CustomField
+	for rows.Next() {
+		f, err := scanField(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan custom field: %w", err)
+		}
+		fields = append(fields, f)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return fields, nil
+}
+
+func (r *PostgresCustomFieldRepository) CreateField(ctx context.Context, field *entity.CustomField) (*entity.CustomField, error) {
+	q := fmt.Sprintf(`
+        INSERT INTO %s AS f (project_id, name, type, options, position)
+        VALUES ($1, $2, $3, $4, $5)
+        RETURNING %s;
+    `, database.CustomFieldsTable, fieldColumns)
+
+	created, err := scanField(database.Conn(ctx, r.db).QueryRowContext(ctx, q,
+		field.ProjectID, field.Name, field.Type, pq.Array(field.Options), field.Position,
+	))
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, fmt.Errorf("%w: custom field %q already exists in project", domain_error.ErrConflict, field.Name)
+		}
+		return nil, fmt.Errorf("create custom field: %w", err)
+	}
+	return created, nil
+}
+
+func (r *PostgresCustomFieldRepository) GetFieldByID(ctx context.Context, id int) (*entity.CustomField, error) {
+	q := fmt.Sprintf(`
+        SELECT %s FROM %s f WHERE f.id = $1;
+    `, fieldColumns, database.CustomFieldsTable)
+
+	field, err := scanField(database.Conn(ctx, r.db).QueryRowContext(ctx, q, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: custom field %d", domain_error.ErrNotFound, id)
+		}
+		return nil, fmt.Errorf("get custom field by id: %w", err)
+	}
+	return field, nil
+}
+
+func (r *PostgresCustomFieldRepository) UpdateField(ctx context.Context, field *entity.CustomField) (*entity.CustomField, error) {
+	q := fmt.Sprintf(`
+        UPDATE %s AS f SET name = $1, options = $2, position = $3
+        WHERE f.id = $4
+        RETURNING %s;
+    `, database.CustomFieldsTable, fieldColumns)
+
+	updated, err := scanField(database.Conn(ctx, r.db).QueryRowContext(ctx, q,
+		field.Name, pq.Array(field.Options), field.Position, field.ID,
+	))
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, fmt.Errorf("%w: custom field %q already exists in project", domain_error.ErrConflict, field.Name)
+		}
+		return nil, fmt.Errorf("update custom field: %w", err)
+	}
+	return updated, nil
+}
+
+func (r *PostgresCustomFieldRepository) DeleteField(ctx context.Context, id int) error {
+	q := fmt.Sprintf(`
+        DELETE FROM %s WHERE id = $1;
+    `, database.CustomFieldsTable)
+
+	if _, err := database.Conn(ctx, r.db).ExecContext(ctx, q, id); err != nil {
+		return fmt.Errorf("delete custom field: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresCustomFieldRepository) GetAvailableFields(ctx context.Context, projectID int) ([]*entity.CustomField, error) {
+	// UNION (not UNION ALL) stops the recursion should the project tree ever contain a cycle.
+	q := fmt.Sprintf(`
+        WITH RECURSIVE ancestors AS (
+            SELECT id, parent_project_id, 0 AS depth FROM %s WHERE id = $1
+            UNION
+            SELECT p.id, p.parent_project_id, a.depth + 1
+            FROM %s p
+            JOIN ancestors a ON p.id = a.parent_project_id
+        )
+        SELECT %s
+        FROM %s f
+        JOIN ancestors a ON f.project_id = a.id
+        ORDER BY a.depth, f.position, f.name;
+    `, database.ProjectsTable, database.ProjectsTable, fieldColumns, database.CustomFieldsTable)
+
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, q, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("get available custom fields: %w", err)
+	}
+	defer rows.Close()
+
+	return scanFields(rows)
+}
+
+func (r *PostgresCustomFieldRepository) GetFieldsByIDs(ctx context.Context, ids []int) ([]*entity.CustomField, error) {
+	q := fmt.Sprintf(`
+        SELECT %s FROM %s f WHERE f.id = ANY($1);
+    `, fieldColumns, database.CustomFieldsTable)
+
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, q, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("get custom fields by ids: %w", err)
+	}
+	defer rows.Close()
+
+	return scanFields(rows)
+}
+
+func (r *PostgresCustomFieldRepository) GetValuesByTaskIDs(ctx context.Context, taskIDs []int) (map[int][]*entity.CustomFieldValue, error) {
+	q := fmt.Sprintf(`
+        SELECT v.task_id, v.field_id, f.name, f.type, v.text_value, v.number_value, v.date_value,
+            v.options_value, v.user_id, v.updated_at
+        FROM %s v
+        JOIN %s f ON f.id = v.field_id
+        WHERE v.task_id = ANY($1)
+        ORDER BY v.task_id, f.position, f.name;
+    `, database.TaskCustomFieldValuesTable, database.CustomFieldsTable)
+
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, q, pq.Array(taskIDs))
+	if err != nil {
+		return nil, fmt.Errorf("get custom field values by task ids: %w", err)
+	}
+	defer rows.Close()
+
+	values := make(map[int][]*entity.CustomFieldValue)
+	for rows.Next() {
+		var v entity.CustomFieldValue
+		err := rows.Scan(&v.TaskID, &v.FieldID, &v.FieldName, &v.FieldType, &v.Text, &v.Number, &v.Date,
+			pq.Array(&v.Options), &v.UserID, &v.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("scan custom field value: %w", err)
+		}
+		values[v.TaskID] = append(values[v.TaskID], &v)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return values, nil
+}
+
+func (r *PostgresCustomFieldRepository) UpsertValue(ctx context.Context, value *entity.CustomFieldValue) error {
+	var options any
+	if value.Options != nil {
+		options = pq.Array(value.Options)
+	}
+
+	q := fmt.Sprintf(`
+        INSERT INTO %s (task_id, field_id, text_value, number_value, date_value, options_value, user_id)
+        VALUES ($1, $2, $3, $4, $5, $6, $7)
+        ON CONFLICT (task_id, field_id) DO UPDATE SET text_value = EXCLUDED.text_value,
+            number_value = EXCLUDED.number_value, date_value = EXCLUDED.date_value,
+            options_value = EXCLUDED.options_value, user_id = EXCLUDED.user_id, updated_at = NOW();
+    `, database.TaskCustomFieldValuesTable)
+
+	_, err := database.Conn(ctx, r.db).ExecContext(ctx, q, value.TaskID, value.FieldID, value.Text, value.Number,
+		value.Date, options, value.UserID)
+	if err != nil {
+		return fmt.Errorf("upsert custom field value: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresCustomFieldRepository) DeleteValue(ctx context.Context, taskID int, fieldID int) error {
+	q := fmt.Sprintf(`
+        DELETE FROM %s WHERE task_id = $1 AND field_id = $2;
+    `, database.TaskCustomFieldValuesTable)
+
+	if _, err := database.Conn(ctx, r.db).ExecContext(ctx, q, taskID, fieldID); err != nil {
+		return fmt.Errorf("delete custom field value: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresCustomFieldRepository) PruneOptionValues(ctx context.Context, fieldID int, options []string) error {
+	conn := database.Conn(ctx, r.db)
+
+	q := fmt.Sprintf(`
+        UPDATE %s SET options_value = ARRAY(
+            SELECT o FROM unnest(options_value) o WHERE o = ANY($2)
+        ), updated_at = NOW()
+        WHERE field_id = $1 AND options_value IS NOT NULL AND NOT options_value <@ $2;
+    `, database.TaskCustomFieldValuesTable)
+	if _, err := conn.ExecContext(ctx, q, fieldID, pq.Array(options)); err != nil {
+		return fmt.Errorf("prune custom field options: %w", err)
+	}
+
+	q = fmt.Sprintf(`
+        DELETE FROM %s
+        WHERE field_id = $1 AND (
+            (text_value IS NOT NULL AND NOT text_value = ANY($2))
+            OR cardinality(options_value) = 0
+        );
+    `, database.TaskCustomFieldValuesTable)
+	if _, err := conn.ExecContext(ctx, q, fieldID, pq.Array(options)); err != nil {
+		return fmt.Errorf("prune custom field options: %w", err)
+	}
+	return nil
+}
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
@@ -16,6 +16,39 @@ const (
 	ProjectUsersTable = "project_users"
 	KanbanTasksTable  = "kanban_tasks"
 	CommentTaskTable  = "comment_task"
+
+	TaskRemindersTable = "task_reminders"
+	LabelsTable        = "labels"
+	TaskLabelsTable    = "task_labels"
+
+	ChecklistItemsTable = "checklist_items"
+
+	ProjectSettingsTable = "project_settings"
+	TaskLinksTable       = "task_links"
+
+	TaskRecurrencesTable = "task_recurrences"
+	WorklogsTable        = "worklogs"
+
+	CustomFieldsTable          = "custom_fields"
+	TaskCustomFieldValuesTable = "task_custom_field_values"
+
+	AttachmentsTable             = "attachments"
+	AttachmentBlobDeletionsTable = "attachment_blob_deletions"
+
+	TaskWatchersTable = "task_watchers"
+	TaskHistoryTable  = "task_history"
+
+	TaskTemplatesTable = "task_templates"
+
+	SavedViewsTable         = "saved_views"
+	SavedViewPositionsTable = "saved_view_positions"
+
+	CloneJobsTable = "clone_jobs"
+
+	WorkflowStatusesTable    = "workflow_statuses"
+	WorkflowTransitionsTable = "workflow_transitions"
+
+	MentionsTable = "mentions"
 )
 
 func ConnectPostgres(dsn string) (*sql.DB, error) {
@@ -0,0 +1,79 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+type txKey struct{}
+
+type afterCommitKey struct{}
+
+// DBTX is the part of *sql.DB and *sql.Tx that repositories use.
+type DBTX interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// Conn returns the transaction started by Transactor.WithinTransaction for ctx, or db
+// when ctx is not part of a transaction.
+func Conn(ctx context.Context, db *sql.DB) DBTX {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx
+	}
+	return db
+}
+
+// AfterCommit runs fn once the transaction of ctx has been committed, or right away when ctx
+// is not part of a transaction. fn is dropped when the transaction is rolled back.
+func AfterCommit(ctx context.Context, fn func(ctx context.Context)) {
+	if hooks, ok := ctx.Value(afterCommitKey{}).(*[]func(ctx context.Context)); ok {
+		*hooks = append(*hooks, fn)
+		return
+	}
+	fn(ctx)
+}
+
+// Transactor lets usecases run several repository calls atomically.
+type Transactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type PostgresTransactor struct {
+	db *sql.DB
+}
+
+func NewPostgresTransactor(db *sql.DB) *PostgresTransactor {
+	return &PostgresTransactor{db: db}
+}
+
+// WithinTransaction runs fn in a transaction that repositories pick up through Conn.
+// The transaction is rolled back when fn returns an error. Nested calls join the
+// outer transaction.
+func (t *PostgresTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := t.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+
+	var hooks []func(ctx context.Context)
+	txCtx := context.WithValue(context.WithValue(ctx, txKey{}, tx), afterCommitKey{}, &hooks)
+	if err := fn(txCtx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	for _, hook := range hooks {
+		hook(ctx)
+	}
+	return nil
+}
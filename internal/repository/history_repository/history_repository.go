@@ -0,0 +1,15 @@
+package history_repository
+
+import (
+	"DataTask/internal/domain/entity"
+	"context"
+)
+
+type HistoryRepository interface {
+	// AppendEntries inserts history entries. Entries are never updated afterwards.
+	AppendEntries(ctx context.Context, entries []*entity.TaskHistoryEntry) error
+	// GetHistory returns a page of the task's history, newest first, and the total number of entries.
+	GetHistory(ctx context.Context, taskID int, limit int, offset int) ([]*entity.TaskHistoryEntry, int, error)
+	// GetTimeline merges the history and the comments of a task, newest first.
+	GetTimeline(ctx context.Context, taskID int, limit int, offset int) ([]*entity.TimelineItem, int, error)
+}
@@ -0,0 +1,196 @@
+package history_repository
+
+import (
+	"DataTask/internal/domain/entity"
+	"DataTask/internal/repository/database"
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+type PostgresHistoryRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresHistoryRepository(db *sql.DB) *PostgresHistoryRepository {
+	return &PostgresHistoryRepository{db: db}
+}
+
+func (r *PostgresHistoryRepository) AppendEntries(ctx context.Context, entries []*entity.TaskHistoryEntry) error {
+	q := fmt.Sprintf(`
+        INSERT INTO %s (task_id, actor_id, field, old_value, new_value)
+        VALUES ($1, $2, $3, $4, $5);
+    `, database.TaskHistoryTable)
+
+	conn := database.Conn(ctx, r.db)
+	for _, e := range entries {
+		var actorID *int
+		if e.Actor != nil {
+			actorID = &e.Actor.ID
+		}
+		if _, err := conn.ExecContext(ctx, q, e.TaskID, actorID, e.Field, jsonValue(e.OldValue), jsonValue(e.NewValue)); err != nil {
+			return fmt.Errorf("append task history: %w", err)
+		}
+	}
+	return nil
+}
+
+func (r *PostgresHistoryRepository) GetHistory(ctx context.Context, taskID int, limit int, offset int) ([]*entity.TaskHistoryEntry, int, error) {
+	conn := database.Conn(ctx, r.db)
+
+	var total int
+	countQuery := fmt.Sprintf(`SELECT COUNT(*) FROM %s WHERE task_id = $1;`, database.TaskHistoryTable)
+	if err := conn.QueryRowContext(ctx, countQuery, taskID).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count task history: %w", err)
+	}
+
+	q := fmt.Sprintf(`
+        SELECT h.id, h.field, h.old_value, h.new_value, h.created_at,
+               u.id, u.name, u.surname, u.email, u.avatar_url
+        FROM %s h
+        LEFT JOIN %s u ON u.id = h.actor_id
+        WHERE h.task_id = $1
+        ORDER BY h.created_at DESC, h.id DESC
+        LIMIT $2 OFFSET $3;
+    `, database.TaskHistoryTable, database.UsersTable)
+
+	rows, err := conn.QueryContext(ctx, q, taskID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("get task history: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []*entity.TaskHistoryEntry{}
+	for rows.Next() {
+		e := &entity.TaskHistoryEntry{TaskID: taskID}
+		var oldValue, newValue []byte
+		var actor nullableUser
+		if err := rows.Scan(&e.ID, &e.Field, &oldValue, &newValue, &e.CreatedAt,
+			&actor.ID, &actor.Name, &actor.Surname, &actor.Email, &actor.AvatarURL); err != nil {
+			return nil, 0, fmt.Errorf("scan task history: %w", err)
+		}
+		e.OldValue, e.NewValue = oldValue, newValue
+		e.Actor = actor.user()
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("rows error: %w", err)
+	}
+	return entries, total, nil
+}
+
+func (r *PostgresHistoryRepository) GetTimeline(ctx context.Context, taskID int, limit int, offset int) ([]*entity.TimelineItem, int, error) {
+	conn := database.Conn(ctx, r.db)
+
+	var total int
+	countQuery := fmt.Sprintf(`
+        SELECT (SELECT COUNT(*) FROM %s WHERE task_id = $1)
+             + (SELECT COUNT(*) FROM %s WHERE task_id = $1);
+    `, database.TaskHistoryTable, database.CommentTaskTable)
+	if err := conn.QueryRowContext(ctx, countQuery, taskID).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count task timeline: %w", err)
+	}
+
+	q := fmt.Sprintf(`
+        SELECT x.kind, x.id, x.field, x.old_value, x.new_value, x.text, x.created_at, x.updated_at,
+               u.id, u.name, u.surname, u.email, u.avatar_url
+        FROM (
+            SELECT '%s' AS kind, h.id, h.actor_id, h.field, h.old_value, h.new_value,
+                   NULL::TEXT AS text, h.created_at, NULL::TIMESTAMP AS updated_at
+            FROM %s h
+            WHERE h.task_id = $1
+            UNION ALL
+            SELECT '%s', c.id, c.author, NULL::VARCHAR, NULL::JSONB, NULL::JSONB,
+                   c.text, c.created_at, c.updated_at
+            FROM %s ct
+            JOIN %s c ON c.id = ct.comment_id
+            WHERE ct.task_id = $1
+        ) x
+        LEFT JOIN %s u ON u.id = x.actor_id
+        ORDER BY x.created_at DESC, x.id DESC
+        LIMIT $2 OFFSET $3;
+    `, entity.TimelineItemChange, database.TaskHistoryTable,
+		entity.TimelineItemComment, database.CommentTaskTable, database.CommentTable,
+		database.UsersTable)
+
+	rows, err := conn.QueryContext(ctx, q, taskID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("get task timeline: %w", err)
+	}
+	defer rows.Close()
+
+	items := []*entity.TimelineItem{}
+	for rows.Next() {
+		var (
+			kind               string
+			id                 int64
+			field, text        sql.NullString
+			oldValue, newValue []byte
+			createdAt          time.Time
+			updatedAt          sql.NullTime
+			actor              nullableUser
+		)
+		if err := rows.Scan(&kind, &id, &field, &oldValue, &newValue, &text, &createdAt, &updatedAt,
+			&actor.ID, &actor.Name, &actor.Surname, &actor.Email, &actor.AvatarURL); err != nil {
+			return nil, 0, fmt.Errorf("scan task timeline: %w", err)
+		}
+
+		item := &entity.TimelineItem{Kind: kind}
+		if kind == entity.TimelineItemComment {
+			item.Comment = &entity.Comment{
+				ID:        int(id),
+				Author:    actor.user(),
+				Text:      text.String,
+				TaskID:    taskID,
+				CreatedAt: createdAt,
+				UpdatedAt: updatedAt.Time,
+			}
+		} else {
+			item.Change = &entity.TaskHistoryEntry{
+				ID:        id,
+				TaskID:    taskID,
+				Actor:     actor.user(),
+				Field:     field.String,
+				OldValue:  oldValue,
+				NewValue:  newValue,
+				CreatedAt: createdAt,
+			}
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("rows error: %w", err)
+	}
+	return items, total, nil
+}
+
+// nullableUser scans the columns of a LEFT JOINed user.
+type nullableUser struct {
+	ID        sql.NullInt64
+	Name      sql.NullString
+	Surname   sql.NullString
+	Email     sql.NullString
+	AvatarURL *string
+}
+
+func (u nullableUser) user() *entity.User {
+	if !u.ID.Valid {
+		return nil
+	}
+	return &entity.User{
+		ID:        int(u.ID.Int64),
+		Name:      u.Name.String,
+		Surname:   u.Surname.String,
+		Email:     u.Email.String,
+		AvatarURL: u.AvatarURL,
+	}
+}
+
+// jsonValue passes a JSON document to a JSONB column, keeping SQL NULL for empty values.
+func jsonValue(value []byte) any {
+	if len(value) == 0 {
+		return nil
+	}
+	return string(value)
+}
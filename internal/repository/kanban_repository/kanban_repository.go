@@ -12,4 +12,8 @@ type KanbanRepository interface {
 	UpdateKanban(ctx context.Context, kanban *entity.Kanban) (*entity.Kanban, error)
 	DeleteKanban(ctx context.Context, id int) error
 	GetAllKanbans(ctx context.Context) ([]*entity.Kanban, error) // Added GetAll
+	// ReorderKanbans sets the position of every listed kanban to its index in kanbanIDs.
+	ReorderKanbans(ctx context.Context, projectID int, kanbanIDs []int) error
+	SetWIPLimit(ctx context.Context, id int, limit *int) (*entity.Kanban, error)
+	LockWIPLimit(ctx context.Context, id int) (limit *int, taskCount int, err error)
 }
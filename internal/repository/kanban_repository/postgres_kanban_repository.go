@@ -1,13 +1,24 @@
 package kanban_repository
 
 import (
+	"DataTask/internal/domain/domain_error"
 	"DataTask/internal/domain/entity"
 	"DataTask/internal/repository/database"
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"github.com/lib/pq"
 )
 
+// kanbanColumns is the column list shared by every kanban query. Keep it in sync with scanKanban.
+var kanbanColumns = fmt.Sprintf(`k.id, k.name, k.project_id, k.position, k.wip_limit, k.created_at, k.updated_at,
+        (SELECT COUNT(*) FROM %s t WHERE t.kanban_id = k.id AND t.deleted_at IS NULL)`, database.TaskTable)
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
 type PostgresKanbanRepository struct {
 	db *sql.DB
 }
@@ -16,28 +27,51 @@ func NewPostgresKanbanRepository(db *sql.DB) *PostgresKanbanRepository {
 	return &PostgresKanbanRepository{db: db}
 }
 
+func scanKanban(row rowScanner) (*entity.Kanban, error) {
+	var k entity.Kanban
+	if err := row.Scan(&k.ID, &k.Name, &k.ProjectID, &k.Position, &k.WIPLimit, &k.CreatedAt, &k.UpdatedAt, &k.TaskCount); err != nil {
+		return nil, err
+	}
+	return &k, nil
+}
+
+func scanKanbans(rows *sql.Rows) ([]*entity.Kanban, error) {
+	var kanbans []*entity.Kanban
+	for rows.Next() {
+		k, err := scanKanban(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan kanban: %w", err)
+		}
+		kanbans = append(kanbans, k)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return kanbans, nil
+}
+
+// CreateKanban appends the kanban to the end of its project. Trashed kanbans keep their position,
+// so they count too.
 func (r *PostgresKanbanRepository) CreateKanban(ctx context.Context, kanban *entity.Kanban) (*entity.Kanban, error) {
 	q := fmt.Sprintf(`
-        INSERT INTO %s (name, project_id) VALUES ($1, $2) RETURNING id, name, created_at, updated_at;
-    `, database.KanbanTable) // You'll need to define KanbanTable in your database package
+        INSERT INTO %[1]s AS k (name, project_id, position)
+        VALUES ($1, $2, (SELECT COALESCE(MAX(position), -1) + 1 FROM %[1]s WHERE project_id = $2))
+        RETURNING %[2]s;
+    `, database.KanbanTable, kanbanColumns)
 
-	err := r.db.QueryRowContext(ctx, q, kanban.Name, kanban.ProjectID).Scan(&kanban.ID, &kanban.Name, &kanban.CreatedAt, &kanban.UpdatedAt)
+	created, err := scanKanban(r.db.QueryRowContext(ctx, q, kanban.Name, kanban.ProjectID))
 	if err != nil {
 		return nil, fmt.Errorf("create kanban: %w", err)
 	}
-	return kanban, nil
+	return created, nil
 }
 
 func (r *PostgresKanbanRepository) GetKanbanByID(ctx context.Context, id int) (*entity.Kanban, error) {
 	q := fmt.Sprintf(`
-        SELECT id, name, created_at, updated_at FROM %s WHERE id = $1;
-    `, database.KanbanTable)
-
-	row := r.db.QueryRowContext(ctx, q, id)
+        SELECT %s FROM %s k WHERE k.id = $1 AND k.deleted_at IS NULL;
+    `, kanbanColumns, database.KanbanTable)
 
-	kanban := new(entity.Kanban) // Initialize kanban as a pointer
-
-	err := row.Scan(&kanban.ID, &kanban.Name, &kanban.CreatedAt, &kanban.UpdatedAt)
+	kanban, err := scanKanban(r.db.QueryRowContext(ctx, q, id))
 	if err != nil {
 		return nil, fmt.Errorf("get kanban by id: %w", err)
 	}
@@ -47,8 +81,8 @@ func (r *PostgresKanbanRepository) GetKanbanByID(ctx context.Context, id int) (*
 
 func (r *PostgresKanbanRepository) GetKanbansByProjectID(ctx context.Context, projectID int) ([]*entity.Kanban, error) {
 	q := fmt.Sprintf(`
-        SELECT id, name, created_at, updated_at FROM %s WHERE project_id = $1;
-    `, database.KanbanTable)
+        SELECT %s FROM %s k WHERE k.project_id = $1 AND k.deleted_at IS NULL ORDER BY k.position, k.id;
+    `, kanbanColumns, database.KanbanTable)
 
 	rows, err := r.db.QueryContext(ctx, q, projectID)
 	if err != nil {
@@ -56,37 +90,98 @@ func (r *PostgresKanbanRepository) GetKanbansByProjectID(ctx context.Context, pr
 	}
 	defer rows.Close()
 
-	var kanbans []*entity.Kanban
-	for rows.Next() {
-		var k entity.Kanban
-		if err := rows.Scan(&k.ID, &k.Name, &k.CreatedAt, &k.UpdatedAt); err != nil {
-			return nil, fmt.Errorf("scan kanban: %w", err)
-		}
-		kanbans = append(kanbans, &k)
+	return scanKanbans(rows)
+}
+
+func (r *PostgresKanbanRepository) UpdateKanban(ctx context.Context, kanban *entity.Kanban) (*entity.Kanban, error) {
+	q := fmt.Sprintf(`
+        UPDATE %s AS k SET name = $1, updated_at = NOW() WHERE k.id = $2 AND k.deleted_at IS NULL
+        RETURNING %s;
+    `, database.KanbanTable, kanbanColumns)
+
+	updated, err := scanKanban(r.db.QueryRowContext(ctx, q, kanban.Name, kanban.ID))
+	if err != nil {
+		return nil, fmt.Errorf("update kanban: %w", err)
 	}
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("rows error: %w", err)
+	return updated, nil
+}
+
+func (r *PostgresKanbanRepository) SetWIPLimit(ctx context.Context, id int, limit *int) (*entity.Kanban, error) {
+	q := fmt.Sprintf(`
+        UPDATE %s AS k SET wip_limit = $1, updated_at = NOW() WHERE k.id = $2 AND k.deleted_at IS NULL
+        RETURNING %s;
+    `, database.KanbanTable, kanbanColumns)
+
+	updated, err := scanKanban(r.db.QueryRowContext(ctx, q, limit, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: kanban %d", domain_error.ErrNotFound, id)
+		}
+		return nil, fmt.Errorf("set wip limit: %w", err)
 	}
-	return kanbans, nil
+	return updated, nil
 }
 
-func (r *PostgresKanbanRepository) UpdateKanban(ctx context.Context, kanban *entity.Kanban) (*entity.Kanban, error) {
+func (r *PostgresKanbanRepository) ReorderKanbans(ctx context.Context, projectID int, kanbanIDs []int) error {
 	q := fmt.Sprintf(`
-        UPDATE %s SET name = $1, updated_at = NOW() WHERE id = $2 
-        RETURNING id, name, created_at, updated_at;
+        UPDATE %s k SET position = o.ord - 1, updated_at = NOW()
+        FROM unnest($2::int[]) WITH ORDINALITY AS o(id, ord)
+        WHERE k.id = o.id AND k.project_id = $1;
     `, database.KanbanTable)
 
-	err := r.db.QueryRowContext(ctx, q, kanban.Name, kanban.ID).Scan(&kanban.ID, &kanban.Name, &kanban.CreatedAt, &kanban.UpdatedAt)
+	_, err := r.db.ExecContext(ctx, q, projectID, pq.Array(kanbanIDs))
 	if err != nil {
-		return nil, fmt.Errorf("update kanban: %w", err)
+		return fmt.Errorf("reorder kanbans: %w", err)
 	}
-	return kanban, nil
+	return nil
+}
+
+// LockWIPLimit locks the kanban until the transaction ends and returns its WIP limit with the
+// number of tasks on it. The tasks are counted after the lock is taken, in a statement of its own,
+// so that the count includes the tasks of transactions that held the lock before.
+func (r *PostgresKanbanRepository) LockWIPLimit(ctx context.Context, id int) (*int, int, error) {
+	conn := database.Conn(ctx, r.db)
+
+	lockQuery := fmt.Sprintf(`
+        SELECT wip_limit FROM %s WHERE id = $1 AND deleted_at IS NULL FOR UPDATE;
+    `, database.KanbanTable)
+
+	var limit *int
+	if err := conn.QueryRowContext(ctx, lockQuery, id).Scan(&limit); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, 0, fmt.Errorf("%w: kanban %d", domain_error.ErrNotFound, id)
+		}
+		return nil, 0, fmt.Errorf("lock kanban: %w", err)
+	}
+	if limit == nil {
+		return nil, 0, nil
+	}
+
+	countQuery := fmt.Sprintf(`
+        SELECT COUNT(*) FROM %s WHERE kanban_id = $1 AND deleted_at IS NULL;
+    `, database.TaskTable)
+
+	var count int
+	if err := conn.QueryRowContext(ctx, countQuery, id).Scan(&count); err != nil {
+		return nil, 0, fmt.Errorf("count kanban tasks: %w", err)
+	}
+	return limit, count, nil
 }
 
+// DeleteKanban moves the board and its tasks, subtasks included, to the trash under one deleted_at.
 func (r *PostgresKanbanRepository) DeleteKanban(ctx context.Context, id int) error {
 	q := fmt.Sprintf(`
-        DELETE FROM %s WHERE id = $1;
-    `, database.KanbanTable)
+        WITH RECURSIVE doomed AS (
+            SELECT t.id FROM %[2]s t
+            JOIN %[1]s k ON k.id = t.kanban_id
+            WHERE k.id = $1 AND k.deleted_at IS NULL AND t.deleted_at IS NULL
+            UNION
+            SELECT t.id FROM %[2]s t JOIN doomed d ON t.parent_task_id = d.id WHERE t.deleted_at IS NULL
+        ), trashed_tasks AS (
+            UPDATE %[2]s SET deleted_at = NOW() WHERE id IN (SELECT id FROM doomed)
+        )
+        UPDATE %[1]s SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL;
+    `, database.KanbanTable, database.TaskTable)
 
 	_, err := r.db.ExecContext(ctx, q, id)
 	if err != nil {
@@ -96,7 +191,9 @@ func (r *PostgresKanbanRepository) DeleteKanban(ctx context.Context, id int) err
 }
 
 func (r *PostgresKanbanRepository) GetAllKanbans(ctx context.Context) ([]*entity.Kanban, error) {
-	q := fmt.Sprintf(`SELECT id, name, created_at, updated_at FROM %s;`, database.KanbanTable)
+	q := fmt.Sprintf(`
+        SELECT %s FROM %s k WHERE k.deleted_at IS NULL ORDER BY k.project_id, k.position, k.id;
+    `, kanbanColumns, database.KanbanTable)
 
 	rows, err := r.db.QueryContext(ctx, q)
 	if err != nil {
@@ -104,16 +201,5 @@ func (r *PostgresKanbanRepository) GetAllKanbans(ctx context.Context) ([]*entity
 	}
 	defer rows.Close()
 
-	var kanbans []*entity.Kanban
-	for rows.Next() {
-		var k entity.Kanban
-		if err := rows.Scan(&k.ID, &k.Name, &k.CreatedAt, &k.UpdatedAt); err != nil {
-			return nil, fmt.Errorf("scan kanban: %w", err)
-		}
-		kanbans = append(kanbans, &k)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("rows error: %w", err)
-	}
-	return kanbans, nil
+	return scanKanbans(rows)
 }
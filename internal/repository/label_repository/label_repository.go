@@ -0,0 +1,19 @@
+package label_repository
+
+import (
+	"DataTask/internal/domain/entity"
+	"context"
+)
+
+type LabelRepository interface {
+	CreateLabel(ctx context.Context, label *entity.Label) (*entity.Label, error)
+	GetLabelByID(ctx context.Context, id int) (*entity.Label, error)
+	UpdateLabel(ctx context.Context, label *entity.Label) (*entity.Label, error)
+	DeleteLabel(ctx context.Context, id int) error
+	// GetAvailableLabels returns the labels of the project and of all its ancestors.
+	GetAvailableLabels(ctx context.Context, projectID int) ([]*entity.Label, error)
+
+	AttachLabelToTask(ctx context.Context, taskID int, labelID int) error
+	DetachLabelFromTask(ctx context.Context, taskID int, labelID int) error
+	GetLabelsByTaskIDs(ctx context.Context, taskIDs []int) (map[int][]*entity.Label, error)
+}
@@ -0,0 +1,185 @@
+package label_repository
+
+import (
+	"DataTask/internal/domain/domain_error"
+	"DataTask/internal/domain/entity"
+	"DataTask/internal/repository/database"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/lib/pq"
+)
+
+type PostgresLabelRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresLabelRepository(db *sql.DB) *PostgresLabelRepository {
+	return &PostgresLabelRepository{db: db}
+}
+
+func (r *PostgresLabelRepository) CreateLabel(ctx context.Context, label *entity.Label) (*entity.Label, error) {
+	q := fmt.Sprintf(`
+        INSERT INTO %s (project_id, name, color) VALUES ($1, $2, $3)
+        RETURNING id, project_id, name, color, created_at, updated_at;
+    `, database.LabelsTable)
+
+	err := r.db.QueryRowContext(ctx, q, label.ProjectID, label.Name, label.Color).Scan(
+		&label.ID, &label.ProjectID, &label.Name, &label.Color, &label.CreatedAt, &label.UpdatedAt,
+	)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, fmt.Errorf("%w: label %q already exists in project", domain_error.ErrConflict, label.Name)
+		}
+		return nil, fmt.Errorf("create label: %w", err)
+	}
+	return label, nil
+}
+
+func (r *PostgresLabelRepository) GetLabelByID(ctx context.Context, id int) (*entity.Label, error) {
+	q := fmt.Sprintf(`
+        SELECT id, project_id, name, color, created_at, updated_at FROM %s WHERE id = $1;
+    `, database.LabelsTable)
+
+	label := new(entity.Label)
+	err := r.db.QueryRowContext(ctx, q, id).Scan(
+		&label.ID, &label.ProjectID, &label.Name, &label.Color, &label.CreatedAt, &label.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: label %d", domain_error.ErrNotFound, id)
+		}
+		return nil, fmt.Errorf("get label by id: %w", err)
+	}
+	return label, nil
+}
+
+func (r *PostgresLabelRepository) UpdateLabel(ctx context.Context, label *entity.Label) (*entity.Label, error) {
+	q := fmt.Sprintf(`
+        UPDATE %s SET name = $1, color = $2, updated_at = NOW() WHERE id = $3
+        RETURNING id, project_id, name, color, created_at, updated_at;
+    `, database.LabelsTable)
+
+	err := r.db.QueryRowContext(ctx, q, label.Name, label.Color, label.ID).Scan(
+		&label.ID, &label.ProjectID, &label.Name, &label.Color, &label.CreatedAt, &label.UpdatedAt,
+	)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, fmt.Errorf("%w: label %q already exists in project", domain_error.ErrConflict, label.Name)
+		}
+		return nil, fmt.Errorf("update label: %w", err)
+	}
+	return label, nil
+}
+
+func (r *PostgresLabelRepository) DeleteLabel(ctx context.Context, id int) error {
+	q := fmt.Sprintf(`
+        DELETE FROM %s WHERE id = $1;
+    `, database.LabelsTable)
+
+	_, err := r.db.ExecContext(ctx, q, id)
+	if err != nil {
+		return fmt.Errorf("delete label: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresLabelRepository) GetAvailableLabels(ctx context.Context, projectID int) ([]*entity.Label, error) {
+	// UNION (not UNION ALL) stops the recursion should the project tree ever contain a cycle.
+	q := fmt.Sprintf(`
+        WITH RECURSIVE ancestors AS (
+            SELECT id, parent_project_id, 0 AS depth FROM %s WHERE id = $1
+            UNION
+            SELECT p.id, p.parent_project_id, a.depth + 1
+            FROM %s p
+            JOIN ancestors a ON p.id = a.parent_project_id
+        )
+        SELECT l.id, l.project_id, l.name, l.color, l.created_at, l.updated_at
+        FROM %s l
+        JOIN ancestors a ON l.project_id = a.id
+        ORDER BY a.depth, l.name;
+    `, database.ProjectsTable, database.ProjectsTable, database.LabelsTable)
+
+	rows, err := r.db.QueryContext(ctx, q, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("get available labels: %w", err)
+	}
+	defer rows.Close()
+
+	var labels []*entity.Label
+	for rows.Next() {
+		var l entity.Label
+		if err := rows.Scan(&l.ID, &l.ProjectID, &l.Name, &l.Color, &l.CreatedAt, &l.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scan label: %w", err)
+		}
+		labels = append(labels, &l)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return labels, nil
+}
+
+func (r *PostgresLabelRepository) AttachLabelToTask(ctx context.Context, taskID int, labelID int) error {
+	q := fmt.Sprintf(`
+        INSERT INTO %s (task_id, label_id) VALUES ($1, $2) ON CONFLICT DO NOTHING;
+    `, database.TaskLabelsTable)
+
+	_, err := database.Conn(ctx, r.db).ExecContext(ctx, q, taskID, labelID)
+	if err != nil {
+		return fmt.Errorf("attach label to task: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresLabelRepository) DetachLabelFromTask(ctx context.Context, taskID int, labelID int) error {
+	q := fmt.Sprintf(`
+        DELETE FROM %s WHERE task_id = $1 AND label_id = $2;
+    `, database.TaskLabelsTable)
+
+	_, err := database.Conn(ctx, r.db).ExecContext(ctx, q, taskID, labelID)
+	if err != nil {
+		return fmt.Errorf("detach label from task: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresLabelRepository) GetLabelsByTaskIDs(ctx context.Context, taskIDs []int) (map[int][]*entity.Label, error) {
+	labelsByTask := make(map[int][]*entity.Label, len(taskIDs))
+	if len(taskIDs) == 0 {
+		return labelsByTask, nil
+	}
+
+	q := fmt.Sprintf(`
+        SELECT tl.task_id, l.id, l.project_id, l.name, l.color, l.created_at, l.updated_at
+        FROM %s tl
+        JOIN %s l ON tl.label_id = l.id
+        WHERE tl.task_id = ANY($1)
+        ORDER BY l.name;
+    `, database.TaskLabelsTable, database.LabelsTable)
+
+	rows, err := r.db.QueryContext(ctx, q, pq.Array(taskIDs))
+	if err != nil {
+		return nil, fmt.Errorf("get labels by task ids: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var taskID int
+		var l entity.Label
+		if err := rows.Scan(&taskID, &l.ID, &l.ProjectID, &l.Name, &l.Color, &l.CreatedAt, &l.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scan label: %w", err)
+		}
+		labelsByTask[taskID] = append(labelsByTask[taskID], &l)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return labelsByTask, nil
+}
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
@@ -0,0 +1,17 @@
+package mention_repository
+
+import (
+	"DataTask/internal/domain/entity"
+	"context"
+)
+
+type MentionRepository interface {
+	// GetMentions lists the mentions in a task description, or in a comment when commentID is set.
+	GetMentions(ctx context.Context, taskID int, commentID *int) ([]*entity.Mention, error)
+	// GetDescriptionMentions lists the mentions in the descriptions of tasks by task ID.
+	GetDescriptionMentions(ctx context.Context, taskIDs []int) (map[int][]*entity.Mention, error)
+	// GetCommentMentions lists the mentions in the comments on a task by comment ID.
+	GetCommentMentions(ctx context.Context, taskID int) (map[int][]*entity.Mention, error)
+	// ReplaceMentions replaces the mentions in a task description, or in a comment when commentID is set.
+	ReplaceMentions(ctx context.Context, taskID int, commentID *int, mentions []*entity.Mention) error
+}
@@ -0,0 +1,170 @@
+package mention_repository
+
+import (
+	"DataTask/internal/domain/entity"
+	"DataTask/internal/repository/database"
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/lib/pq"
+)
+
+const mentionColumns = `m.task_id, m.comment_id, m.handle, m.created_at,
+    u.id, u.name, u.surname, u.email, u.avatar_url, u.created_at, u.updated_at`
+
+type PostgresMentionRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresMentionRepository(db *sql.DB) *PostgresMentionRepository {
+	return &PostgresMentionRepository{db: db}
+}
+
+func (r *PostgresMentionRepository) GetMentions(ctx context.Context, taskID int, commentID *int) ([]*entity.Mention, error) {
+	q := fmt.Sprintf(`
+        SELECT %s
+        FROM %s m
+        JOIN %s u ON u.id = m.user_id
+        WHERE m.task_id = $1 AND m.comment_id IS NOT DISTINCT FROM $2::INTEGER
+        ORDER BY m.id;
+    `, mentionColumns, database.MentionsTable, database.UsersTable)
+
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, q, taskID, commentID)
+	if err != nil {
+		return nil, fmt.Errorf("get mentions: %w", err)
+	}
+	defer rows.Close()
+
+	mentions := []*entity.Mention{}
+	for rows.Next() {
+		m, err := scanMention(rows)
+		if err != nil {
+			return nil, err
+		}
+		mentions = append(mentions, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return mentions, nil
+}
+
+func (r *PostgresMentionRepository) GetDescriptionMentions(
+	ctx context.Context,
+	taskIDs []int,
+) (map[int][]*entity.Mention, error) {
+	mentionsByTask := make(map[int][]*entity.Mention, len(taskIDs))
+	if len(taskIDs) == 0 {
+		return mentionsByTask, nil
+	}
+
+	q := fmt.Sprintf(`
+        SELECT %s
+        FROM %s m
+        JOIN %s u ON u.id = m.user_id
+        WHERE m.task_id = ANY($1) AND m.comment_id IS NULL
+        ORDER BY m.id;
+    `, mentionColumns, database.MentionsTable, database.UsersTable)
+
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, q, pq.Array(taskIDs))
+	if err != nil {
+		return nil, fmt.Errorf("get description mentions: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		m, err := scanMention(rows)
+		if err != nil {
+			return nil, err
+		}
+		mentionsByTask[m.TaskID] = append(mentionsByTask[m.TaskID], m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return mentionsByTask, nil
+}
+
+func (r *PostgresMentionRepository) GetCommentMentions(ctx context.Context, taskID int) (map[int][]*entity.Mention, error) {
+	q := fmt.Sprintf(`
+        SELECT %s
+        FROM %s m
+        JOIN %s u ON u.id = m.user_id
+        WHERE m.task_id = $1 AND m.comment_id IS NOT NULL
+        ORDER BY m.id;
+    `, mentionColumns, database.MentionsTable, database.UsersTable)
+
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, q, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("get comment mentions: %w", err)
+	}
+	defer rows.Close()
+
+	mentionsByComment := make(map[int][]*entity.Mention)
+	for rows.Next() {
+		m, err := scanMention(rows)
+		if err != nil {
+			return nil, err
+		}
+		mentionsByComment[*m.CommentID] = append(mentionsByComment[*m.CommentID], m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return mentionsByComment, nil
+}
+
+func (r *PostgresMentionRepository) ReplaceMentions(
+	ctx context.Context,
+	taskID int,
+	commentID *int,
+	mentions []*entity.Mention,
+) error {
+	conn := database.Conn(ctx, r.db)
+
+	deleteQuery := fmt.Sprintf(`
+        DELETE FROM %s WHERE task_id = $1 AND comment_id IS NOT DISTINCT FROM $2::INTEGER;
+    `, database.MentionsTable)
+	if _, err := conn.ExecContext(ctx, deleteQuery, taskID, commentID); err != nil {
+		return fmt.Errorf("delete mentions: %w", err)
+	}
+	if len(mentions) == 0 {
+		return nil
+	}
+
+	handles := make([]string, 0, len(mentions))
+	userIDs := make([]int64, 0, len(mentions))
+	for _, m := range mentions {
+		handles = append(handles, m.Handle)
+		userIDs = append(userIDs, int64(m.User.ID))
+	}
+
+	insertQuery := fmt.Sprintf(`
+        INSERT INTO %s (task_id, comment_id, handle, user_id)
+        SELECT $1, $2, item.handle, item.user_id
+        FROM unnest($3::TEXT[], $4::INTEGER[]) AS item (handle, user_id);
+    `, database.MentionsTable)
+	_, err := conn.ExecContext(ctx, insertQuery, taskID, commentID, pq.Array(handles), pq.Int64Array(userIDs))
+	if err != nil {
+		return fmt.Errorf("insert mentions: %w", err)
+	}
+	return nil
+}
+
+func scanMention(rows *sql.Rows) (*entity.Mention, error) {
+	m := &entity.Mention{User: &entity.User{}}
+	var commentID sql.NullInt64
+	err := rows.Scan(
+		&m.TaskID, &commentID, &m.Handle, &m.CreatedAt,
+		&m.User.ID, &m.User.Name, &m.User.Surname, &m.User.Email, &m.User.AvatarURL,
+		&m.User.CreatedAt, &m.User.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("scan mention: %w", err)
+	}
+	if commentID.Valid {
+		id := int(commentID.Int64)
+		m.CommentID = &id
+	}
+	return m, nil
+}
@@ -1,12 +1,14 @@
 package project_repository
 
 import (
+	"DataTask/internal/domain/domain_error"
 	"DataTask/internal/domain/entity"
 	"DataTask/internal/repository/database"
 	"context"
 	"database/sql"
 	"errors"
 	"fmt"
+	"github.com/lib/pq"
 )
 
 type PostgresProjectRepository struct {
@@ -19,17 +21,22 @@ func NewPostgresProjectRepository(db *sql.DB) *PostgresProjectRepository {
 
 func (r *PostgresProjectRepository) CreateProject(ctx context.Context, project *entity.Project) (*entity.Project, error) {
 	q := fmt.Sprintf(`
-        INSERT INTO %s (owner_id, name, description, color, parent_project_id) 
-        VALUES ($1, $2, $3, $4, $5)
-        RETURNING id, owner_id, name, description, color, parent_project_id, created_at, updated_at;
+        INSERT INTO %s (owner_id, name, description, color, parent_project_id, key)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        RETURNING id, owner_id, name, description, color, parent_project_id, key, created_at, updated_at;
     `, database.ProjectsTable) // Define ProjectsTable
 
-	err := r.db.QueryRowContext(ctx, q,
-		project.OwnerID, project.Name, project.Description, project.Color, project.ParentProjectID).Scan(
+	err := database.Conn(ctx, r.db).QueryRowContext(ctx, q,
+		project.OwnerID, project.Name, project.Description, project.Color, project.ParentProjectID,
+		sql.NullString{String: project.Key, Valid: project.Key != ""}).Scan(
 		&project.ID, &project.OwnerID, &project.Name, &project.Description, &project.Color,
-		&project.ParentProjectID, &project.CreatedAt, &project.UpdatedAt,
+		&project.ParentProjectID, &project.Key, &project.CreatedAt, &project.UpdatedAt,
 	)
 	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return nil, fmt.Errorf("%w: project key %q is already in use", domain_error.ErrConflict, project.Key)
+		}
 		return nil, fmt.Errorf("create project: %w", err)
 	}
 	return project, nil
@@ -37,8 +44,8 @@ func (r *PostgresProjectRepository) CreateProject(ctx context.Context, project *
 
 func (r *PostgresProjectRepository) GetProjectByID(ctx context.Context, id int) (*entity.Project, error) {
 	q := fmt.Sprintf(`
-        SELECT id, owner_id, name, description, color, parent_project_id, created_at, updated_at
-        FROM %s WHERE id = $1;
+        SELECT id, owner_id, name, description, color, parent_project_id, key, created_at, updated_at
+        FROM %s WHERE id = $1 AND deleted_at IS NULL;
     `, database.ProjectsTable)
 
 	row := r.db.QueryRowContext(ctx, q, id)
@@ -47,9 +54,12 @@ func (r *PostgresProjectRepository) GetProjectByID(ctx context.Context, id int)
 
 	err := row.Scan(
 		&project.ID, &project.OwnerID, &project.Name, &project.Description, &project.Color,
-		&project.ParentProjectID, &project.CreatedAt, &project.UpdatedAt,
+		&project.ParentProjectID, &project.Key, &project.CreatedAt, &project.UpdatedAt,
 	)
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: project %d", domain_error.ErrNotFound, id)
+		}
 		return nil, fmt.Errorf("get project by id: %w", err)
 	}
 	return project, nil
@@ -59,14 +69,14 @@ func (r *PostgresProjectRepository) UpdateProject(ctx context.Context, project *
 	q := fmt.Sprintf(`
         UPDATE %s SET 
             owner_id = $1, name = $2, description = $3, color = $4, parent_project_id = $5, updated_at = NOW()
-        WHERE id = $6
-        RETURNING id, owner_id, name, description, color, parent_project_id, created_at, updated_at;
+        WHERE id = $6 AND deleted_at IS NULL
+        RETURNING id, owner_id, name, description, color, parent_project_id, key, created_at, updated_at;
     `, database.ProjectsTable)
 
 	err := r.db.QueryRowContext(ctx, q,
 		project.OwnerID, project.Name, project.Description, project.Color, project.ParentProjectID, project.ID).Scan(
 		&project.ID, &project.OwnerID, &project.Name, &project.Description, &project.Color,
-		&project.ParentProjectID, &project.CreatedAt, &project.UpdatedAt,
+		&project.ParentProjectID, &project.Key, &project.CreatedAt, &project.UpdatedAt,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("update project: %w", err)
@@ -74,10 +84,30 @@ func (r *PostgresProjectRepository) UpdateProject(ctx context.Context, project *
 	return project, nil
 }
 
+// DeleteProject moves the project to the trash together with its subprojects, their boards and the
+// tasks on them, all stamped with the same deleted_at so a restore can bring the batch back.
 func (r *PostgresProjectRepository) DeleteProject(ctx context.Context, id int) error {
 	q := fmt.Sprintf(`
-        DELETE FROM %s WHERE id = $1;
-    `, database.ProjectsTable)
+        WITH RECURSIVE doomed_projects AS (
+            SELECT id FROM %[1]s WHERE id = $1 AND deleted_at IS NULL
+            UNION
+            SELECT p.id FROM %[1]s p JOIN doomed_projects d ON p.parent_project_id = d.id
+            WHERE p.deleted_at IS NULL
+        ), doomed AS (
+            SELECT t.id FROM %[3]s t
+            JOIN %[2]s k ON k.id = t.kanban_id
+            WHERE k.project_id IN (SELECT id FROM doomed_projects)
+              AND k.deleted_at IS NULL AND t.deleted_at IS NULL
+            UNION
+            SELECT t.id FROM %[3]s t JOIN doomed d ON t.parent_task_id = d.id WHERE t.deleted_at IS NULL
+        ), trashed_tasks AS (
+            UPDATE %[3]s SET deleted_at = NOW() WHERE id IN (SELECT id FROM doomed)
+        ), trashed_kanbans AS (
+            UPDATE %[2]s SET deleted_at = NOW()
+            WHERE project_id IN (SELECT id FROM doomed_projects) AND deleted_at IS NULL
+        )
+        UPDATE %[1]s SET deleted_at = NOW() WHERE id IN (SELECT id FROM doomed_projects);
+    `, database.ProjectsTable, database.KanbanTable, database.TaskTable)
 
 	_, err := r.db.ExecContext(ctx, q, id)
 	if err != nil {
@@ -88,8 +118,8 @@ func (r *PostgresProjectRepository) DeleteProject(ctx context.Context, id int) e
 
 func (r *PostgresProjectRepository) GetProjectsByOwnerID(ctx context.Context, ownerID int) ([]*entity.Project, error) {
 	q := fmt.Sprintf(`
-        SELECT id, owner_id, name, description, color, parent_project_id, created_at, updated_at
-        FROM %s WHERE owner_id = $1;
+        SELECT id, owner_id, name, description, color, parent_project_id, key, created_at, updated_at
+        FROM %s WHERE owner_id = $1 AND deleted_at IS NULL;
     `, database.ProjectsTable)
 
 	rows, err := r.db.QueryContext(ctx, q, ownerID)
@@ -103,7 +133,7 @@ func (r *PostgresProjectRepository) GetProjectsByOwnerID(ctx context.Context, ow
 		var p entity.Project
 		if err := rows.Scan(
 			&p.ID, &p.OwnerID, &p.Name, &p.Description, &p.Color,
-			&p.ParentProjectID, &p.CreatedAt, &p.UpdatedAt,
+			&p.ParentProjectID, &p.Key, &p.CreatedAt, &p.UpdatedAt,
 		); err != nil {
 			return nil, fmt.Errorf("scan project: %w", err)
 		}
@@ -117,10 +147,10 @@ func (r *PostgresProjectRepository) GetProjectsByOwnerID(ctx context.Context, ow
 
 func (r *PostgresProjectRepository) GetSharedProjectsByOwnerID(ctx context.Context, ownerID int) ([]*entity.Project, error) {
 	q := fmt.Sprintf(`
-        SELECT p.id, p.owner_id, p.name, p.description, p.color, p.parent_project_id, p.created_at, p.updated_at
+        SELECT p.id, p.owner_id, p.name, p.description, p.color, p.parent_project_id, p.key, p.created_at, p.updated_at
         FROM %s p
         JOIN %s pu ON p.id = pu.project_id
-        WHERE pu.user_id = $1;
+        WHERE pu.user_id = $1 AND p.deleted_at IS NULL;
     `, database.ProjectsTable, database.ProjectUsersTable)
 
 	rows, err := r.db.QueryContext(ctx, q, ownerID)
@@ -134,7 +164,7 @@ func (r *PostgresProjectRepository) GetSharedProjectsByOwnerID(ctx context.Conte
 		var p entity.Project
 		if err := rows.Scan(
 			&p.ID, &p.OwnerID, &p.Name, &p.Description, &p.Color,
-			&p.ParentProjectID, &p.CreatedAt, &p.UpdatedAt,
+			&p.ParentProjectID, &p.Key, &p.CreatedAt, &p.UpdatedAt,
 		); err != nil {
 			return nil, fmt.Errorf("scan project: %w", err)
 		}
@@ -148,8 +178,8 @@ func (r *PostgresProjectRepository) GetSharedProjectsByOwnerID(ctx context.Conte
 
 func (r *PostgresProjectRepository) GetSubprojects(ctx context.Context, parentProjectID int) ([]*entity.Project, error) {
 	q := fmt.Sprintf(`
-        SELECT id, owner_id, name, description, color, parent_project_id, created_at, updated_at
-        FROM %s WHERE parent_project_id = $1;
+        SELECT id, owner_id, name, description, color, parent_project_id, key, created_at, updated_at
+        FROM %s WHERE parent_project_id = $1 AND deleted_at IS NULL;
     `, database.ProjectsTable)
 
 	rows, err := r.db.QueryContext(ctx, q, parentProjectID)
@@ -163,7 +193,7 @@ func (r *PostgresProjectRepository) GetSubprojects(ctx context.Context, parentPr
 		var p entity.Project
 		if err := rows.Scan(
 			&p.ID, &p.OwnerID, &p.Name, &p.Description, &p.Color,
-			&p.ParentProjectID, &p.CreatedAt, &p.UpdatedAt,
+			&p.ParentProjectID, &p.Key, &p.CreatedAt, &p.UpdatedAt,
 		); err != nil {
 			return nil, fmt.Errorf("scan project: %w", err)
 		}
@@ -178,7 +208,7 @@ func (r *PostgresProjectRepository) GetSubprojects(ctx context.Context, parentPr
 /*
 func (r *PostgresProjectRepository) GetProjectsByTaskID(ctx context.Context, taskID int) ([]*entity.Project, error) {
     q := fmt.Sprintf(`
-        SELECT p.id, p.owner_id, p.name, p.description, p.color, p.parent_project_id, p.created_at, p.updated_at
+        SELECT p.id, p.owner_id, p.name, p.description, p.color, p.parent_project_id, p.key, p.created_at, p.updated_at
         FROM %s pt
         JOIN %s p ON pt.project_id = p.id
         WHERE pt.task_id = $1;
@@ -195,7 +225,7 @@ func (r *PostgresProjectRepository) GetProjectsByTaskID(ctx context.Context, tas
         var p entity.Project
         if err := rows.Scan(
             &p.ID, &p.OwnerID, &p.Name, &p.Description, &p.Color,
-            &p.ParentProjectID, &p.CreatedAt, &p.UpdatedAt,
+            &p.ParentProjectID, &p.Key, &p.CreatedAt, &p.UpdatedAt,
         ); err != nil {
             return nil, fmt.Errorf("scan project: %w", err)
         }
@@ -286,6 +316,38 @@ func (r *PostgresProjectRepository) GetUsersInProject(ctx context.Context, proje
 	return users, nil
 }
 
+func (r *PostgresProjectRepository) GetProjectMembers(ctx context.Context, projectID int) ([]*entity.User, error) {
+	q := fmt.Sprintf(`
+        SELECT u.id, u.name, u.surname, u.email, u.avatar_url, u.created_at, u.updated_at
+        FROM %s u
+        WHERE u.id IN (
+            SELECT owner_id FROM %s WHERE id = $1
+            UNION
+            SELECT user_id FROM %s WHERE project_id = $1
+        )
+        ORDER BY u.id;
+    `, database.UsersTable, database.ProjectsTable, database.ProjectUsersTable)
+
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, q, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("get project members: %w", err)
+	}
+	defer rows.Close()
+
+	var users []*entity.User
+	for rows.Next() {
+		var u entity.User
+		if err := rows.Scan(&u.ID, &u.Name, &u.Surname, &u.Email, &u.AvatarURL, &u.CreatedAt, &u.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scan user: %w", err)
+		}
+		users = append(users, &u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return users, nil
+}
+
 func (r *PostgresProjectRepository) AcceptProjectInvitation(ctx context.Context, projectID int, userID int) error {
 	q := fmt.Sprintf(`
         UPDATE %s SET joined_at = NOW()
@@ -298,3 +360,137 @@ func (r *PostgresProjectRepository) AcceptProjectInvitation(ctx context.Context,
 	}
 	return nil
 }
+
+func (r *PostgresProjectRepository) GetEffectiveProjectPermission(ctx context.Context, projectID int, userID int) (string, error) {
+	q := fmt.Sprintf(`
+        SELECT CASE WHEN p.owner_id = $2 THEN 'owner' ELSE COALESCE(pu.permission, '') END
+        FROM %s p
+        LEFT JOIN %s pu ON pu.project_id = p.id AND pu.user_id = $2
+        WHERE p.id = $1;
+    `, database.ProjectsTable, database.ProjectUsersTable)
+
+	var permission string
+	err := r.db.QueryRowContext(ctx, q, projectID, userID).Scan(&permission)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", fmt.Errorf("%w: project %d", domain_error.ErrNotFound, projectID)
+		}
+		return "", fmt.Errorf("get effective project permission: %w", err)
+	}
+	return permission, nil
+}
+
+func (r *PostgresProjectRepository) GetProjectIDByTaskID(ctx context.Context, taskID int) (int, error) {
+	q := fmt.Sprintf(`
+        SELECT k.project_id
+        FROM %s t
+        JOIN %s k ON t.kanban_id = k.id
+        WHERE t.id = $1 AND t.deleted_at IS NULL AND k.deleted_at IS NULL;
+    `, database.TaskTable, database.KanbanTable)
+
+	var projectID int
+	err := r.db.QueryRowContext(ctx, q, taskID).Scan(&projectID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, fmt.Errorf("%w: task %d or its project", domain_error.ErrNotFound, taskID)
+		}
+		return 0, fmt.Errorf("get project id by task id: %w", err)
+	}
+	return projectID, nil
+}
+
+func (r *PostgresProjectRepository) GetProjectIDByKanbanID(ctx context.Context, kanbanID int) (int, error) {
+	q := fmt.Sprintf(`
+        SELECT project_id FROM %s WHERE id = $1 AND deleted_at IS NULL;
+    `, database.KanbanTable)
+
+	var projectID int
+	err := r.db.QueryRowContext(ctx, q, kanbanID).Scan(&projectID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, fmt.Errorf("%w: kanban %d", domain_error.ErrNotFound, kanbanID)
+		}
+		return 0, fmt.Errorf("get project id by kanban id: %w", err)
+	}
+	return projectID, nil
+}
+
+func (r *PostgresProjectRepository) GetProjectSettings(ctx context.Context, projectID int) (*entity.ProjectSettings, error) {
+	q := fmt.Sprintf(`
+        SELECT project_id, blocked_completion_policy, estimate_unit, wip_limit_policy, updated_at
+        FROM %s WHERE project_id = $1;
+    `, database.ProjectSettingsTable)
+
+	settings := new(entity.ProjectSettings)
+	err := r.db.QueryRowContext(ctx, q, projectID).Scan(
+		&settings.ProjectID, &settings.BlockedCompletionPolicy, &settings.EstimateUnit, &settings.WIPLimitPolicy,
+		&settings.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.DefaultProjectSettings(projectID), nil
+		}
+		return nil, fmt.Errorf("get project settings: %w", err)
+	}
+	return settings, nil
+}
+
+func (r *PostgresProjectRepository) UpsertProjectSettings(ctx context.Context, settings *entity.ProjectSettings) (*entity.ProjectSettings, error) {
+	q := fmt.Sprintf(`
+        INSERT INTO %s (project_id, blocked_completion_policy, estimate_unit, wip_limit_policy)
+        VALUES ($1, $2, $3, $4)
+        ON CONFLICT (project_id) DO UPDATE SET blocked_completion_policy = EXCLUDED.blocked_completion_policy,
+            estimate_unit = EXCLUDED.estimate_unit, wip_limit_policy = EXCLUDED.wip_limit_policy
+        RETURNING project_id, blocked_completion_policy, estimate_unit, wip_limit_policy, updated_at;
+    `, database.ProjectSettingsTable)
+
+	saved := new(entity.ProjectSettings)
+	err := r.db.QueryRowContext(ctx, q,
+		settings.ProjectID, settings.BlockedCompletionPolicy, settings.EstimateUnit, settings.WIPLimitPolicy,
+	).Scan(&saved.ProjectID, &saved.BlockedCompletionPolicy, &saved.EstimateUnit, &saved.WIPLimitPolicy, &saved.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("upsert project settings: %w", err)
+	}
+	return saved, nil
+}
+
+func (r *PostgresProjectRepository) GetEstimateRows(ctx context.Context, rootProjectIDs []int) ([]*entity.ProjectEstimateRow, error) {
+	// UNION instead of UNION ALL stops the walk should parent_project_id ever form a loop.
+	q := fmt.Sprintf(`
+        WITH RECURSIVE tree AS (
+            SELECT p.id AS root_id, p.id AS project_id FROM %[1]s p WHERE p.id = ANY($1) AND p.deleted_at IS NULL
+            UNION
+            SELECT tree.root_id, p.id FROM %[1]s p JOIN tree ON p.parent_project_id = tree.project_id
+            WHERE p.deleted_at IS NULL
+        )
+        SELECT tree.root_id, tree.project_id, COALESCE(ps.estimate_unit, $2),
+            COALESCE(SUM(t.estimate), 0), COALESCE(SUM(t.estimate) FILTER (WHERE t.is_completed), 0),
+            COUNT(t.id), COUNT(t.estimate)
+        FROM tree
+        LEFT JOIN %[2]s ps ON ps.project_id = tree.project_id
+        LEFT JOIN %[3]s k ON k.project_id = tree.project_id AND k.deleted_at IS NULL
+        LEFT JOIN %[4]s t ON t.kanban_id = k.id AND t.deleted_at IS NULL
+        GROUP BY tree.root_id, tree.project_id, ps.estimate_unit;
+    `, database.ProjectsTable, database.ProjectSettingsTable, database.KanbanTable, database.TaskTable)
+
+	rows, err := r.db.QueryContext(ctx, q, pq.Array(rootProjectIDs), entity.EstimateUnitHours)
+	if err != nil {
+		return nil, fmt.Errorf("get estimate rows: %w", err)
+	}
+	defer rows.Close()
+
+	var estimateRows []*entity.ProjectEstimateRow
+	for rows.Next() {
+		var row entity.ProjectEstimateRow
+		err := rows.Scan(&row.RootProjectID, &row.ProjectID, &row.Unit, &row.Total, &row.Completed,
+			&row.TaskCount, &row.EstimatedTaskCount)
+		if err != nil {
+			return nil, fmt.Errorf("scan estimate row: %w", err)
+		}
+		estimateRows = append(estimateRows, &row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return estimateRows, nil
+}
@@ -18,5 +18,21 @@ type ProjectRepository interface {
 	InviteUserToProject(ctx context.Context, projectUser *entity.ProjectUser) error
 	GetUserPermissionsForProject(ctx context.Context, projectID int, userID int) (string, error)
 	GetUsersInProject(ctx context.Context, projectID int) ([]*entity.User, error)
+	// GetProjectMembers lists the owner and the members of a project.
+	GetProjectMembers(ctx context.Context, projectID int) ([]*entity.User, error)
 	AcceptProjectInvitation(ctx context.Context, projectID int, userID int) error
+
+	// GetEffectiveProjectPermission returns "owner" for the project owner, the invited
+	// permission for project members and an empty string for everybody else.
+	GetEffectiveProjectPermission(ctx context.Context, projectID int, userID int) (string, error)
+	GetProjectIDByTaskID(ctx context.Context, taskID int) (int, error)
+	GetProjectIDByKanbanID(ctx context.Context, kanbanID int) (int, error)
+
+	// GetProjectSettings returns the defaults when nothing was stored for the project yet.
+	GetProjectSettings(ctx context.Context, projectID int) (*entity.ProjectSettings, error)
+	UpsertProjectSettings(ctx context.Context, settings *entity.ProjectSettings) (*entity.ProjectSettings, error)
+
+	// GetEstimateRows sums the task estimates per project for each of the given projects and all
+	// their subprojects. Every project of a tree yields one row, also when it has no tasks.
+	GetEstimateRows(ctx context.Context, rootProjectIDs []int) ([]*entity.ProjectEstimateRow, error)
 }
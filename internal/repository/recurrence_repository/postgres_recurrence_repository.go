@@ -0,0 +1,251 @@
+package recurrence_repository
+
+import (
+	"DataTask/internal/domain/domain_error"
+	"DataTask/internal/domain/entity"
+	"DataTask/internal/repository/database"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type PostgresRecurrenceRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresRecurrenceRepository(db *sql.DB) *PostgresRecurrenceRepository {
+	return &PostgresRecurrenceRepository{db: db}
+}
+
+const recurrenceColumns = `id, rrule, dtstart, time_zone, ends_at, last_occurrence_at, created_by, created_at, updated_at`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanRecurrence(row rowScanner) (*entity.TaskRecurrence, error) {
+	var r entity.TaskRecurrence
+	err := row.Scan(
+		&r.ID, &r.RRule, &r.DTStart, &r.TimeZone, &r.EndsAt, &r.LastOccurrenceAt, &r.CreatedBy,
+		&r.CreatedAt, &r.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+func (r *PostgresRecurrenceRepository) CreateRecurrence(ctx context.Context, recurrence *entity.TaskRecurrence) (*entity.TaskRecurrence, error) {
+	q := fmt.Sprintf(`
+        INSERT INTO %s (rrule, dtstart, time_zone, ends_at, last_occurrence_at, created_by)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        RETURNING %s;
+    `, database.TaskRecurrencesTable, recurrenceColumns)
+
+	created, err := scanRecurrence(database.Conn(ctx, r.db).QueryRowContext(ctx, q,
+		recurrence.RRule, recurrence.DTStart, recurrence.TimeZone, recurrence.EndsAt, recurrence.LastOccurrenceAt,
+		recurrence.CreatedBy,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("create recurrence: %w", err)
+	}
+	return created, nil
+}
+
+func (r *PostgresRecurrenceRepository) GetRecurrenceByID(ctx context.Context, id int) (*entity.TaskRecurrence, error) {
+	q := fmt.Sprintf(`
+        SELECT %s FROM %s WHERE id = $1;
+    `, recurrenceColumns, database.TaskRecurrencesTable)
+
+	recurrence, err := scanRecurrence(database.Conn(ctx, r.db).QueryRowContext(ctx, q, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: recurrence %d", domain_error.ErrNotFound, id)
+		}
+		return nil, fmt.Errorf("get recurrence by id: %w", err)
+	}
+	return recurrence, nil
+}
+
+func (r *PostgresRecurrenceRepository) UpdateRecurrence(ctx context.Context, recurrence *entity.TaskRecurrence) (*entity.TaskRecurrence, error) {
+	q := fmt.Sprintf(`
+        UPDATE %s SET rrule = $1, ends_at = $2
+        WHERE id = $3
+        RETURNING %s;
+    `, database.TaskRecurrencesTable, recurrenceColumns)
+
+	updated, err := scanRecurrence(database.Conn(ctx, r.db).QueryRowContext(ctx, q,
+		recurrence.RRule, recurrence.EndsAt, recurrence.ID,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("update recurrence: %w", err)
+	}
+	return updated, nil
+}
+
+func (r *PostgresRecurrenceRepository) DeleteRecurrence(ctx context.Context, id int) error {
+	q := fmt.Sprintf(`
+        DELETE FROM %s WHERE id = $1;
+    `, database.TaskRecurrencesTable)
+
+	if _, err := database.Conn(ctx, r.db).ExecContext(ctx, q, id); err != nil {
+		return fmt.Errorf("delete recurrence: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresRecurrenceRepository) GetActiveRecurrences(ctx context.Context) ([]*entity.TaskRecurrence, error) {
+	q := fmt.Sprintf(`
+        SELECT %s FROM %s r
+        WHERE (r.ends_at IS NULL OR r.ends_at > r.last_occurrence_at)
+          AND EXISTS (SELECT 1 FROM %s t WHERE t.recurrence_id = r.id AND t.deleted_at IS NULL)
+        ORDER BY r.id;
+    `, recurrenceColumns, database.TaskRecurrencesTable, database.TaskTable)
+
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, q)
+	if err != nil {
+		return nil, fmt.Errorf("get active recurrences: %w", err)
+	}
+	defer rows.Close()
+
+	var recurrences []*entity.TaskRecurrence
+	for rows.Next() {
+		recurrence, err := scanRecurrence(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan recurrence: %w", err)
+		}
+		recurrences = append(recurrences, recurrence)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return recurrences, nil
+}
+
+func (r *PostgresRecurrenceRepository) ClaimOccurrence(ctx context.Context, recurrenceID int, occurrenceAt time.Time) (bool, error) {
+	q := fmt.Sprintf(`
+        UPDATE %s SET last_occurrence_at = $2
+        WHERE id = $1 AND last_occurrence_at < $2;
+    `, database.TaskRecurrencesTable)
+
+	res, err := database.Conn(ctx, r.db).ExecContext(ctx, q, recurrenceID, occurrenceAt)
+	if err != nil {
+		return false, fmt.Errorf("claim occurrence: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("claim occurrence: %w", err)
+	}
+	return affected == 1, nil
+}
+
+func (r *PostgresRecurrenceRepository) RefreshLastOccurrence(ctx context.Context, recurrenceID int) error {
+	q := fmt.Sprintf(`
+        UPDATE %s r SET last_occurrence_at = COALESCE(
+            (SELECT MAX(t.occurrence_at) FROM %s t WHERE t.recurrence_id = r.id), r.dtstart)
+        WHERE r.id = $1;
+    `, database.TaskRecurrencesTable, database.TaskTable)
+
+	if _, err := database.Conn(ctx, r.db).ExecContext(ctx, q, recurrenceID); err != nil {
+		return fmt.Errorf("refresh last occurrence: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresRecurrenceRepository) GetLatestOccurrenceTaskID(ctx context.Context, recurrenceID int) (int, error) {
+	q := fmt.Sprintf(`
+        SELECT id FROM %s
+        WHERE recurrence_id = $1 AND deleted_at IS NULL
+        ORDER BY occurrence_at DESC, id DESC
+        LIMIT 1;
+    `, database.TaskTable)
+
+	var taskID int
+	err := database.Conn(ctx, r.db).QueryRowContext(ctx, q, recurrenceID).Scan(&taskID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, fmt.Errorf("%w: occurrences of recurrence %d", domain_error.ErrNotFound, recurrenceID)
+		}
+		return 0, fmt.Errorf("get latest occurrence: %w", err)
+	}
+	return taskID, nil
+}
+
+func (r *PostgresRecurrenceRepository) SetTaskRecurrence(ctx context.Context, taskID int, recurrenceID *int, occurrenceAt *time.Time) error {
+	q := fmt.Sprintf(`
+        UPDATE %s SET recurrence_id = $1, occurrence_at = $2, updated_at = NOW()
+        WHERE id = $3;
+    `, database.TaskTable)
+
+	if _, err := database.Conn(ctx, r.db).ExecContext(ctx, q, recurrenceID, occurrenceAt, taskID); err != nil {
+		return fmt.Errorf("set task recurrence: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresRecurrenceRepository) MoveOccurrences(ctx context.Context, fromRecurrenceID int, toRecurrenceID int, from time.Time) error {
+	q := fmt.Sprintf(`
+        UPDATE %s SET recurrence_id = $2
+        WHERE recurrence_id = $1 AND occurrence_at >= $3;
+    `, database.TaskTable)
+
+	if _, err := database.Conn(ctx, r.db).ExecContext(ctx, q, fromRecurrenceID, toRecurrenceID, from); err != nil {
+		return fmt.Errorf("move occurrences: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresRecurrenceRepository) DeleteOpenOccurrencesAfter(ctx context.Context, recurrenceID int, after time.Time) error {
+	q := fmt.Sprintf(`
+        DELETE FROM %s
+        WHERE recurrence_id = $1 AND occurrence_at > $2 AND is_completed = FALSE;
+    `, database.TaskTable)
+
+	if _, err := database.Conn(ctx, r.db).ExecContext(ctx, q, recurrenceID, after); err != nil {
+		return fmt.Errorf("delete open occurrences: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresRecurrenceRepository) CopyTaskRelations(ctx context.Context, fromTaskID int, toTaskID int) error {
+	conn := database.Conn(ctx, r.db)
+
+	assigneesQuery := fmt.Sprintf(`
+        INSERT INTO %s (task_id, user_id)
+        SELECT $2, user_id FROM %s WHERE task_id = $1
+        ON CONFLICT DO NOTHING;
+    `, database.TaskUsersTable, database.TaskUsersTable)
+	if _, err := conn.ExecContext(ctx, assigneesQuery, fromTaskID, toTaskID); err != nil {
+		return fmt.Errorf("copy task assignees: %w", err)
+	}
+
+	watchersQuery := fmt.Sprintf(`
+        INSERT INTO %s (task_id, user_id)
+        SELECT $2, user_id FROM %s WHERE task_id = $1
+        ON CONFLICT DO NOTHING;
+    `, database.TaskWatchersTable, database.TaskWatchersTable)
+	if _, err := conn.ExecContext(ctx, watchersQuery, fromTaskID, toTaskID); err != nil {
+		return fmt.Errorf("copy task watchers: %w", err)
+	}
+
+	labelsQuery := fmt.Sprintf(`
+        INSERT INTO %s (task_id, label_id)
+        SELECT $2, label_id FROM %s WHERE task_id = $1
+        ON CONFLICT DO NOTHING;
+    `, database.TaskLabelsTable, database.TaskLabelsTable)
+	if _, err := conn.ExecContext(ctx, labelsQuery, fromTaskID, toTaskID); err != nil {
+		return fmt.Errorf("copy task labels: %w", err)
+	}
+
+	customFieldsQuery := fmt.Sprintf(`
+        INSERT INTO %s (task_id, field_id, text_value, number_value, date_value, options_value, user_id)
+        SELECT $2, field_id, text_value, number_value, date_value, options_value, user_id FROM %s WHERE task_id = $1
+        ON CONFLICT DO NOTHING;
+    `, database.TaskCustomFieldValuesTable, database.TaskCustomFieldValuesTable)
+	if _, err := conn.ExecContext(ctx, customFieldsQuery, fromTaskID, toTaskID); err != nil {
+		return fmt.Errorf("copy task custom fields: %w", err)
+	}
+	return nil
+}
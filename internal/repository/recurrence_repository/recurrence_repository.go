@@ -0,0 +1,32 @@
+package recurrence_repository
+
+import (
+	"DataTask/internal/domain/entity"
+	"context"
+	"time"
+)
+
+type RecurrenceRepository interface {
+	CreateRecurrence(ctx context.Context, recurrence *entity.TaskRecurrence) (*entity.TaskRecurrence, error)
+	GetRecurrenceByID(ctx context.Context, id int) (*entity.TaskRecurrence, error)
+	// UpdateRecurrence saves the rule and the end of the series.
+	UpdateRecurrence(ctx context.Context, recurrence *entity.TaskRecurrence) (*entity.TaskRecurrence, error)
+	DeleteRecurrence(ctx context.Context, id int) error
+	// GetActiveRecurrences returns the series that still have tasks and were not cut off by a split.
+	GetActiveRecurrences(ctx context.Context) ([]*entity.TaskRecurrence, error)
+
+	// ClaimOccurrence advances last_occurrence_at to occurrenceAt. It returns false when another
+	// generator already got that far, so every occurrence is created only once.
+	ClaimOccurrence(ctx context.Context, recurrenceID int, occurrenceAt time.Time) (bool, error)
+	// RefreshLastOccurrence resets last_occurrence_at to the latest remaining occurrence of the series.
+	RefreshLastOccurrence(ctx context.Context, recurrenceID int) error
+	GetLatestOccurrenceTaskID(ctx context.Context, recurrenceID int) (int, error)
+
+	SetTaskRecurrence(ctx context.Context, taskID int, recurrenceID *int, occurrenceAt *time.Time) error
+	// MoveOccurrences moves the occurrences at or after from into another series.
+	MoveOccurrences(ctx context.Context, fromRecurrenceID int, toRecurrenceID int, from time.Time) error
+	// DeleteOpenOccurrencesAfter removes uncompleted occurrences scheduled after the given time.
+	DeleteOpenOccurrencesAfter(ctx context.Context, recurrenceID int, after time.Time) error
+	// CopyTaskRelations gives the target task the assignees, labels and custom field values of the source task.
+	CopyTaskRelations(ctx context.Context, fromTaskID int, toTaskID int) error
+}
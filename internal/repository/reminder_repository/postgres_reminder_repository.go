@@ -0,0 +1,93 @@
+package reminder_repository
+
+import (
+	"DataTask/internal/domain/entity"
+	"DataTask/internal/repository/database"
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type PostgresReminderRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresReminderRepository(db *sql.DB) *PostgresReminderRepository {
+	return &PostgresReminderRepository{db: db}
+}
+
+func (r *PostgresReminderRepository) ClaimDueReminders(ctx context.Context, offsets []time.Duration) ([]*entity.TaskReminder, error) {
+	if len(offsets) == 0 {
+		return nil, nil
+	}
+
+	values := make([]string, len(offsets))
+	args := make([]any, len(offsets))
+	for i, offset := range offsets {
+		values[i] = fmt.Sprintf("($%d::INTEGER)", i+1)
+		args[i] = int(offset.Minutes())
+	}
+
+	// Every passed offset is claimed, but only the smallest one, the latest reminder, is sent: after
+	// downtime the earlier ones would only arrive as a burst. ON CONFLICT DO NOTHING makes the claim
+	// idempotent: only the instance whose insert succeeded gets the row back from RETURNING.
+	q := fmt.Sprintf(`
+        WITH due AS (
+            SELECT t.id AS task_id, tu.user_id, o.minutes, t.due_at,
+                o.minutes = MIN(o.minutes) OVER (PARTITION BY t.id, tu.user_id) AS latest
+            FROM %s t
+            JOIN %s tu ON tu.task_id = t.id
+            CROSS JOIN (VALUES %s) AS o (minutes)
+            WHERE t.is_completed = FALSE
+              AND t.deleted_at IS NULL
+              AND t.due_at IS NOT NULL
+              AND t.due_at > NOW()
+              AND t.due_at - make_interval(mins => o.minutes) <= NOW()
+        ), claimed AS (
+            INSERT INTO %s (task_id, user_id, offset_minutes, due_at)
+            SELECT task_id, user_id, minutes, due_at FROM due
+            ON CONFLICT DO NOTHING
+            RETURNING task_id, user_id, offset_minutes, due_at
+        )
+        SELECT c.task_id, t.title, c.user_id, c.offset_minutes, c.due_at
+        FROM claimed c
+        JOIN due d ON d.task_id = c.task_id AND d.user_id = c.user_id AND d.minutes = c.offset_minutes
+        JOIN %s t ON t.id = c.task_id
+        WHERE d.latest;
+    `, database.TaskTable, database.TaskUsersTable, strings.Join(values, ", "),
+		database.TaskRemindersTable, database.TaskTable)
+
+	rows, err := r.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("claim due reminders: %w", err)
+	}
+	defer rows.Close()
+
+	var reminders []*entity.TaskReminder
+	for rows.Next() {
+		var rem entity.TaskReminder
+		if err := rows.Scan(&rem.TaskID, &rem.TaskTitle, &rem.UserID, &rem.OffsetMinutes, &rem.DueAt); err != nil {
+			return nil, fmt.Errorf("scan reminder: %w", err)
+		}
+		reminders = append(reminders, &rem)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return reminders, nil
+}
+
+func (r *PostgresReminderRepository) ReleaseReminder(ctx context.Context, reminder *entity.TaskReminder) error {
+	q := fmt.Sprintf(`
+        DELETE FROM %s
+        WHERE task_id = $1 AND user_id = $2 AND offset_minutes = $3 AND due_at = $4;
+    `, database.TaskRemindersTable)
+
+	_, err := r.db.ExecContext(ctx, q, reminder.TaskID, reminder.UserID, reminder.OffsetMinutes, reminder.DueAt)
+	if err != nil {
+		return fmt.Errorf("release reminder: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,16 @@
+package reminder_repository
+
+import (
+	"DataTask/internal/domain/entity"
+	"context"
+	"time"
+)
+
+type ReminderRepository interface {
+	// ClaimDueReminders atomically marks every reminder that became due for one of the given
+	// offsets as sent and returns the latest one of each task and assignee. A reminder is returned
+	// to exactly one caller, even when several application instances poll concurrently.
+	ClaimDueReminders(ctx context.Context, offsets []time.Duration) ([]*entity.TaskReminder, error)
+	// ReleaseReminder drops a claim so the reminder is retried on the next poll.
+	ReleaseReminder(ctx context.Context, reminder *entity.TaskReminder) error
+}
@@ -0,0 +1,150 @@
+package saved_view_repository
+
+import (
+	"DataTask/internal/domain/domain_error"
+	"DataTask/internal/domain/entity"
+	"DataTask/internal/repository/database"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/lib/pq"
+)
+
+const viewColumns = `v.id, v.project_id, v.owner_id, v.name, v.filter, v.sort, v.group_by, v.fields, v.shared,
+    vp.position, v.created_at, v.updated_at`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+type PostgresSavedViewRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresSavedViewRepository(db *sql.DB) *PostgresSavedViewRepository {
+	return &PostgresSavedViewRepository{db: db}
+}
+
+func scanView(row rowScanner) (*entity.SavedView, error) {
+	var v entity.SavedView
+	var position sql.NullInt64
+	err := row.Scan(&v.ID, &v.ProjectID, &v.OwnerID, &v.Name, &v.Filter, &v.Sort, &v.GroupBy,
+		pq.Array(&v.Fields), &v.Shared, &position, &v.CreatedAt, &v.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if position.Valid {
+		p := int(position.Int64)
+		v.Position = &p
+	}
+	return &v, nil
+}
+
+func (r *PostgresSavedViewRepository) CreateView(ctx context.Context, view *entity.SavedView) (*entity.SavedView, error) {
+	q := fmt.Sprintf(`
+        INSERT INTO %s (project_id, owner_id, name, filter, sort, group_by, fields, shared)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+        RETURNING id;
+    `, database.SavedViewsTable)
+
+	var id int
+	err := database.Conn(ctx, r.db).QueryRowContext(ctx, q,
+		view.ProjectID, view.OwnerID, view.Name, view.Filter, view.Sort, view.GroupBy, pq.Array(view.Fields),
+		view.Shared,
+	).Scan(&id)
+	if err != nil {
+		return nil, fmt.Errorf("create saved view: %w", err)
+	}
+	return r.GetViewByID(ctx, id, view.OwnerID)
+}
+
+func (r *PostgresSavedViewRepository) GetViewByID(ctx context.Context, id int, userID int) (*entity.SavedView, error) {
+	q := fmt.Sprintf(`
+        SELECT %s
+        FROM %s v
+        LEFT JOIN %s vp ON vp.view_id = v.id AND vp.user_id = $2
+        WHERE v.id = $1;
+    `, viewColumns, database.SavedViewsTable, database.SavedViewPositionsTable)
+
+	view, err := scanView(database.Conn(ctx, r.db).QueryRowContext(ctx, q, id, userID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: saved view %d", domain_error.ErrNotFound, id)
+		}
+		return nil, fmt.Errorf("get saved view by id: %w", err)
+	}
+	return view, nil
+}
+
+func (r *PostgresSavedViewRepository) UpdateView(ctx context.Context, view *entity.SavedView) error {
+	q := fmt.Sprintf(`
+        UPDATE %s SET name = $1, filter = $2, sort = $3, group_by = $4, fields = $5, shared = $6
+        WHERE id = $7;
+    `, database.SavedViewsTable)
+
+	res, err := database.Conn(ctx, r.db).ExecContext(ctx, q,
+		view.Name, view.Filter, view.Sort, view.GroupBy, pq.Array(view.Fields), view.Shared, view.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("update saved view: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("%w: saved view %d", domain_error.ErrNotFound, view.ID)
+	}
+	return nil
+}
+
+func (r *PostgresSavedViewRepository) DeleteView(ctx context.Context, id int) error {
+	q := fmt.Sprintf(`
+        DELETE FROM %s WHERE id = $1;
+    `, database.SavedViewsTable)
+
+	if _, err := database.Conn(ctx, r.db).ExecContext(ctx, q, id); err != nil {
+		return fmt.Errorf("delete saved view: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresSavedViewRepository) GetVisibleViews(ctx context.Context, projectID int, userID int) ([]*entity.SavedView, error) {
+	q := fmt.Sprintf(`
+        SELECT %s
+        FROM %s v
+        LEFT JOIN %s vp ON vp.view_id = v.id AND vp.user_id = $2
+        WHERE v.project_id = $1 AND (v.shared OR v.owner_id = $2)
+        ORDER BY vp.position NULLS LAST, v.id;
+    `, viewColumns, database.SavedViewsTable, database.SavedViewPositionsTable)
+
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, q, projectID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get visible saved views: %w", err)
+	}
+	defer rows.Close()
+
+	views := []*entity.SavedView{}
+	for rows.Next() {
+		view, err := scanView(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan saved view: %w", err)
+		}
+		views = append(views, view)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return views, nil
+}
+
+func (r *PostgresSavedViewRepository) SetPositions(ctx context.Context, userID int, viewIDs []int) error {
+	q := fmt.Sprintf(`
+        INSERT INTO %s (user_id, view_id, position)
+        SELECT $1, item.view_id, item.position - 1
+        FROM unnest($2::INTEGER[]) WITH ORDINALITY AS item (view_id, position)
+        ON CONFLICT (user_id, view_id) DO UPDATE SET position = EXCLUDED.position;
+    `, database.SavedViewPositionsTable)
+
+	if _, err := database.Conn(ctx, r.db).ExecContext(ctx, q, userID, pq.Array(viewIDs)); err != nil {
+		return fmt.Errorf("set saved view positions: %w", err)
+	}
+	return nil
+}
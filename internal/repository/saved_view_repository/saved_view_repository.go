@@ -0,0 +1,19 @@
+package saved_view_repository
+
+import (
+	"DataTask/internal/domain/entity"
+	"context"
+)
+
+type SavedViewRepository interface {
+	CreateView(ctx context.Context, view *entity.SavedView) (*entity.SavedView, error)
+	// GetViewByID returns the view with its position in the list of userID.
+	GetViewByID(ctx context.Context, id int, userID int) (*entity.SavedView, error)
+	UpdateView(ctx context.Context, view *entity.SavedView) error
+	DeleteView(ctx context.Context, id int) error
+	// GetVisibleViews returns the project's shared views and the personal views of userID, in
+	// the user's order.
+	GetVisibleViews(ctx context.Context, projectID int, userID int) ([]*entity.SavedView, error)
+	// SetPositions sets the position of every listed view to its index in viewIDs for userID.
+	SetPositions(ctx context.Context, userID int, viewIDs []int) error
+}
@@ -0,0 +1,141 @@
+package search_repository
+
+import (
+	"DataTask/internal/domain/entity"
+	"DataTask/internal/repository/database"
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// headlineOptions shapes the ts_headline snippets: up to two fragments of about 10 to 30 words.
+var headlineOptions = fmt.Sprintf(
+	`StartSel=%s, StopSel=%s, MinWords=10, MaxWords=30, MaxFragments=2, FragmentDelimiter=" … "`,
+	entity.SearchHighlightStart, entity.SearchHighlightStop,
+)
+
+type PostgresSearchRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresSearchRepository(db *sql.DB) *PostgresSearchRepository {
+	return &PostgresSearchRepository{db: db}
+}
+
+func (r *PostgresSearchRepository) Search(ctx context.Context, query *entity.SearchQuery) ([]*entity.SearchResult, error) {
+	args := []any{query.Text, query.UserID, headlineOptions}
+
+	// Conditions on the task of task and comment results; the task's project is already
+	// limited to the accessible ones.
+	taskConds := []string{"t.deleted_at IS NULL", "k.deleted_at IS NULL"}
+	projectCond := "TRUE"
+	if query.ProjectID != nil {
+		args = append(args, *query.ProjectID)
+		taskConds = append(taskConds, fmt.Sprintf("k.project_id = $%d", len(args)))
+		projectCond = fmt.Sprintf("p.id = $%d", len(args))
+	}
+	if query.AssigneeID != nil {
+		args = append(args, *query.AssigneeID)
+		taskConds = append(taskConds, fmt.Sprintf(
+			"EXISTS (SELECT 1 FROM %s tu WHERE tu.task_id = t.id AND tu.user_id = $%d)",
+			database.TaskUsersTable, len(args),
+		))
+	}
+	if query.IsCompleted != nil {
+		args = append(args, *query.IsCompleted)
+		taskConds = append(taskConds, fmt.Sprintf("COALESCE(t.is_completed, FALSE) = $%d", len(args)))
+	}
+	taskWhere := strings.Join(taskConds, " AND ")
+
+	var branches []string
+	if wantsType(query, entity.SearchResultTask) {
+		branches = append(branches, fmt.Sprintf(`
+            SELECT '%[4]s' AS type, t.id AS id, a.id AS project_id, a.name AS project_name, t.id AS task_id,
+                t.title AS title,
+                ts_headline('simple', t.title || E'\n' || COALESCE(t.description, ''), query.q, $3) AS snippet,
+                t.is_completed AS is_completed, ts_rank(t.search_vector, query.q) AS rank
+            FROM %[1]s t
+            JOIN %[2]s k ON k.id = t.kanban_id
+            JOIN accessible a ON a.id = k.project_id
+            CROSS JOIN query
+            WHERE t.search_vector @@ query.q AND %[3]s`,
+			database.TaskTable, database.KanbanTable, taskWhere, entity.SearchResultTask))
+	}
+	if wantsType(query, entity.SearchResultComment) {
+		branches = append(branches, fmt.Sprintf(`
+            SELECT '%[6]s' AS type, c.id AS id, a.id AS project_id, a.name AS project_name, t.id AS task_id,
+                t.title AS title,
+                ts_headline('simple', COALESCE(c.text, ''), query.q, $3) AS snippet,
+                t.is_completed AS is_completed, ts_rank(c.search_vector, query.q) AS rank
+            FROM %[1]s c
+            JOIN %[2]s ct ON ct.comment_id = c.id
+            JOIN %[3]s t ON t.id = ct.task_id
+            JOIN %[4]s k ON k.id = t.kanban_id
+            JOIN accessible a ON a.id = k.project_id
+            CROSS JOIN query
+            WHERE c.search_vector @@ query.q AND %[5]s`,
+			database.CommentTable, database.CommentTaskTable, database.TaskTable, database.KanbanTable, taskWhere,
+			entity.SearchResultComment))
+	}
+	// Projects have neither assignees nor a completion state, so those filters rule them out.
+	if wantsType(query, entity.SearchResultProject) && query.AssigneeID == nil && query.IsCompleted == nil {
+		branches = append(branches, fmt.Sprintf(`
+            SELECT '%[3]s' AS type, p.id AS id, p.id AS project_id, p.name AS project_name, NULL::INTEGER AS task_id,
+                p.name AS title,
+                ts_headline('simple', p.name || E'\n' || COALESCE(p.description, ''), query.q, $3) AS snippet,
+                NULL::BOOLEAN AS is_completed, ts_rank(p.search_vector, query.q) AS rank
+            FROM %[1]s p
+            JOIN accessible a ON a.id = p.id
+            CROSS JOIN query
+            WHERE p.search_vector @@ query.q AND %[2]s`,
+			database.ProjectsTable, projectCond, entity.SearchResultProject))
+	}
+	if len(branches) == 0 {
+		return []*entity.SearchResult{}, nil
+	}
+
+	args = append(args, query.Limit, query.Offset)
+	q := fmt.Sprintf(`
+        WITH query AS (
+            SELECT websearch_to_tsquery('simple', $1) AS q
+        ), accessible AS (
+            SELECT p.id, p.name
+            FROM %[1]s p
+            LEFT JOIN %[2]s pu ON pu.project_id = p.id AND pu.user_id = $2
+            WHERE p.deleted_at IS NULL AND (p.owner_id = $2 OR pu.user_id IS NOT NULL)
+        )
+        SELECT type, id, project_id, project_name, task_id, title, snippet, is_completed, rank
+        FROM (%[3]s
+        ) results
+        ORDER BY rank DESC, type, id
+        LIMIT $%[4]d OFFSET $%[5]d;
+    `, database.ProjectsTable, database.ProjectUsersTable, strings.Join(branches, "\n            UNION ALL"),
+		len(args)-1, len(args))
+
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("search: %w", err)
+	}
+	defer rows.Close()
+
+	results := []*entity.SearchResult{}
+	for rows.Next() {
+		var result entity.SearchResult
+		err := rows.Scan(&result.Type, &result.ID, &result.ProjectID, &result.ProjectName, &result.TaskID,
+			&result.Title, &result.Snippet, &result.IsCompleted, &result.Rank)
+		if err != nil {
+			return nil, fmt.Errorf("scan search result: %w", err)
+		}
+		results = append(results, &result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return results, nil
+}
+
+func wantsType(query *entity.SearchQuery, resultType string) bool {
+	return len(query.Types) == 0 || slices.Contains(query.Types, resultType)
+}
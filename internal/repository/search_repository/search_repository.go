@@ -0,0 +1,11 @@
+package search_repository
+
+import (
+	"DataTask/internal/domain/entity"
+	"context"
+)
+
+type SearchRepository interface {
+	// Search ranks the tasks, comments and projects matching the query, best match first.
+	Search(ctx context.Context, query *entity.SearchQuery) ([]*entity.SearchResult, error)
+}
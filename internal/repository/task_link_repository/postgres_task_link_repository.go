@@ -0,0 +1,153 @@
+package task_link_repository
+
+import (
+	"DataTask/internal/domain/domain_error"
+	"DataTask/internal/domain/entity"
+	"DataTask/internal/repository/database"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/lib/pq"
+)
+
+// blockingGraphLockKey names the advisory lock guarding cycle checks on "blocks" edges.
+const blockingGraphLockKey = "task_links.blocks"
+
+type PostgresTaskLinkRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresTaskLinkRepository(db *sql.DB) *PostgresTaskLinkRepository {
+	return &PostgresTaskLinkRepository{db: db}
+}
+
+func (r *PostgresTaskLinkRepository) CreateLink(ctx context.Context, link *entity.TaskLink) (*entity.TaskLink, error) {
+	q := fmt.Sprintf(`
+        INSERT INTO %s (source_task_id, target_task_id, type, created_by)
+        VALUES ($1, $2, $3, $4)
+        RETURNING id, source_task_id, target_task_id, type, created_by, created_at;
+    `, database.TaskLinksTable)
+
+	created := new(entity.TaskLink)
+	err := database.Conn(ctx, r.db).QueryRowContext(ctx, q,
+		link.SourceTaskID, link.TargetTaskID, link.Type, link.CreatedBy).Scan(
+		&created.ID, &created.SourceTaskID, &created.TargetTaskID, &created.Type,
+		&created.CreatedBy, &created.CreatedAt,
+	)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return nil, fmt.Errorf("%w: the tasks are already linked this way", domain_error.ErrConflict)
+		}
+		return nil, fmt.Errorf("create task link: %w", err)
+	}
+	return created, nil
+}
+
+func (r *PostgresTaskLinkRepository) GetLinkByID(ctx context.Context, id int) (*entity.TaskLink, error) {
+	q := fmt.Sprintf(`
+        SELECT id, source_task_id, target_task_id, type, created_by, created_at
+        FROM %s WHERE id = $1;
+    `, database.TaskLinksTable)
+
+	link := new(entity.TaskLink)
+	err := database.Conn(ctx, r.db).QueryRowContext(ctx, q, id).Scan(
+		&link.ID, &link.SourceTaskID, &link.TargetTaskID, &link.Type, &link.CreatedBy, &link.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: task link %d", domain_error.ErrNotFound, id)
+		}
+		return nil, fmt.Errorf("get task link by id: %w", err)
+	}
+	return link, nil
+}
+
+func (r *PostgresTaskLinkRepository) DeleteLink(ctx context.Context, id int) error {
+	q := fmt.Sprintf(`
+        DELETE FROM %s WHERE id = $1;
+    `, database.TaskLinksTable)
+
+	if _, err := database.Conn(ctx, r.db).ExecContext(ctx, q, id); err != nil {
+		return fmt.Errorf("delete task link: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresTaskLinkRepository) GetLinkedTasks(ctx context.Context, taskID int) ([]*entity.LinkedTask, error) {
+	q := fmt.Sprintf(`
+        SELECT l.id, l.type, '%[4]s', t.id, t.title, t.is_completed, k.project_id, l.created_at
+        FROM %[1]s l
+        JOIN %[2]s t ON t.id = l.target_task_id
+        JOIN %[3]s k ON k.id = t.kanban_id
+        WHERE l.source_task_id = $1 AND t.deleted_at IS NULL AND k.deleted_at IS NULL
+        UNION ALL
+        SELECT l.id, l.type, '%[5]s', t.id, t.title, t.is_completed, k.project_id, l.created_at
+        FROM %[1]s l
+        JOIN %[2]s t ON t.id = l.source_task_id
+        JOIN %[3]s k ON k.id = t.kanban_id
+        WHERE l.target_task_id = $1 AND t.deleted_at IS NULL AND k.deleted_at IS NULL
+        ORDER BY 8, 1;
+    `, database.TaskLinksTable, database.TaskTable, database.KanbanTable, entity.TaskLinkOutgoing, entity.TaskLinkIncoming)
+
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, q, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("get linked tasks: %w", err)
+	}
+	defer rows.Close()
+
+	var linked []*entity.LinkedTask
+	for rows.Next() {
+		var l entity.LinkedTask
+		if err := rows.Scan(
+			&l.LinkID, &l.Type, &l.Direction, &l.TaskID, &l.TaskTitle, &l.TaskIsCompleted, &l.ProjectID, &l.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan linked task: %w", err)
+		}
+		linked = append(linked, &l)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return linked, nil
+}
+
+func (r *PostgresTaskLinkRepository) LockBlockingGraph(ctx context.Context) error {
+	if _, err := database.Conn(ctx, r.db).ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext($1));`, blockingGraphLockKey); err != nil {
+		return fmt.Errorf("lock blocking graph: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresTaskLinkRepository) BlockingPathExists(ctx context.Context, fromTaskID int, toTaskID int) (bool, error) {
+	q := fmt.Sprintf(`
+        WITH RECURSIVE blocked AS (
+            SELECT target_task_id AS id FROM %[1]s WHERE source_task_id = $1 AND type = '%[2]s'
+            UNION
+            SELECT l.target_task_id FROM %[1]s l JOIN blocked b ON l.source_task_id = b.id WHERE l.type = '%[2]s'
+        )
+        SELECT EXISTS (SELECT 1 FROM blocked WHERE id = $2);
+    `, database.TaskLinksTable, entity.TaskLinkBlocks)
+
+	var exists bool
+	if err := database.Conn(ctx, r.db).QueryRowContext(ctx, q, fromTaskID, toTaskID).Scan(&exists); err != nil {
+		return false, fmt.Errorf("check blocking path: %w", err)
+	}
+	return exists, nil
+}
+
+func (r *PostgresTaskLinkRepository) CountOpenBlockers(ctx context.Context, taskID int) (int, error) {
+	q := fmt.Sprintf(`
+        SELECT COUNT(*)
+        FROM %s l
+        JOIN %s t ON t.id = l.source_task_id
+        WHERE l.target_task_id = $1 AND l.type = '%s' AND t.is_completed = FALSE AND t.deleted_at IS NULL;
+    `, database.TaskLinksTable, database.TaskTable, entity.TaskLinkBlocks)
+
+	var count int
+	if err := database.Conn(ctx, r.db).QueryRowContext(ctx, q, taskID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("count open blockers: %w", err)
+	}
+	return count, nil
+}
@@ -0,0 +1,21 @@
+package task_link_repository
+
+import (
+	"DataTask/internal/domain/entity"
+	"context"
+)
+
+type TaskLinkRepository interface {
+	CreateLink(ctx context.Context, link *entity.TaskLink) (*entity.TaskLink, error)
+	GetLinkByID(ctx context.Context, id int) (*entity.TaskLink, error)
+	DeleteLink(ctx context.Context, id int) error
+	// GetLinkedTasks returns the outgoing and incoming links of the task.
+	GetLinkedTasks(ctx context.Context, taskID int) ([]*entity.LinkedTask, error)
+
+	// LockBlockingGraph serializes changes to "blocks" edges until the surrounding transaction ends.
+	LockBlockingGraph(ctx context.Context) error
+	// BlockingPathExists reports whether fromTaskID blocks toTaskID, directly or through other tasks.
+	BlockingPathExists(ctx context.Context, fromTaskID int, toTaskID int) (bool, error)
+	// CountOpenBlockers counts the uncompleted tasks that directly block the task.
+	CountOpenBlockers(ctx context.Context, taskID int) (int, error)
+}
@@ -1,11 +1,17 @@
 package task_repository
 
 import (
+	"DataTask/internal/domain/domain_error"
 	"DataTask/internal/domain/entity"
 	"DataTask/internal/repository/database"
+	"DataTask/pkg/filterql"
+	"DataTask/pkg/taskkey"
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"github.com/lib/pq"
+	"strings"
 )
 
 type PostgresTaskRepository struct {
@@ -16,166 +22,537 @@ func NewPostgresTaskRepository(db *sql.DB) *PostgresTaskRepository {
 	return &PostgresTaskRepository{db: db}
 }
 
+// taskColumns is the column list shared by every task query. Keep it in sync with scanTask.
+var taskColumns = fmt.Sprintf(`t.id, t.title, t.description, t.is_completed, t.created_at, t.updated_at, t.kanban_id,
+        t.start_at, t.due_at, t.time_zone, t.priority, t.parent_task_id, t.recurrence_id, t.occurrence_at, t.estimate,
+        t.status_id, t.number, (SELECT p.key FROM %s k JOIN %s p ON p.id = k.project_id WHERE k.id = t.kanban_id)`,
+	database.KanbanTable, database.ProjectsTable)
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanTask(row rowScanner) (*entity.Task, error) {
+	var t entity.Task
+	var kanbanID, number sql.NullInt64
+	var projectKey sql.NullString
+	err := row.Scan(
+		&t.ID, &t.Title, &t.Description, &t.IsCompleted, &t.CreatedAt, &t.UpdatedAt, &kanbanID,
+		&t.StartAt, &t.DueAt, &t.TimeZone, &t.Priority, &t.ParentTaskID, &t.RecurrenceID, &t.OccurrenceAt, &t.Estimate,
+		&t.StatusID, &number, &projectKey,
+	)
+	if err != nil {
+		return nil, err
+	}
+	t.KanbanID = int(kanbanID.Int64)
+	t.Number = int(number.Int64)
+	t.Key = taskkey.Format(projectKey.String, t.Number)
+	return &t, nil
+}
+
+func scanTasks(rows *sql.Rows) ([]*entity.Task, error) {
+	var tasks []*entity.Task
+	for rows.Next() {
+		t, err := scanTask(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan task: %w", err)
+		}
+		tasks = append(tasks, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return tasks, nil
+}
+
+// taskFilterFields whitelists the fields of task filter expressions, with short aliases
+// for the dates.
+var taskFilterFields = func() filterql.Schema {
+	dueAt := &filterql.Field{Type: filterql.Time, Column: "t.due_at", Nullable: true}
+	startAt := &filterql.Field{Type: filterql.Time, Column: "t.start_at", Nullable: true}
+	createdAt := &filterql.Field{Type: filterql.Time, Column: "t.created_at"}
+	updatedAt := &filterql.Field{Type: filterql.Time, Column: "t.updated_at"}
+
+	return filterql.Schema{
+		"title":        {Type: filterql.String, Column: "t.title"},
+		"description":  {Type: filterql.String, Column: "t.description"},
+		"is_completed": {Type: filterql.Bool, Column: "COALESCE(t.is_completed, FALSE)"},
+		"priority": {Type: filterql.Enum, Column: "t.priority", Values: []string{
+			entity.PriorityNone, entity.PriorityLow, entity.PriorityMedium, entity.PriorityHigh, entity.PriorityUrgent,
+		}},
+		"estimate":       {Type: filterql.Number, Column: "t.estimate", Nullable: true},
+		"kanban_id":      {Type: filterql.Int, Column: "t.kanban_id"},
+		"parent_task_id": {Type: filterql.Int, Column: "t.parent_task_id", Nullable: true},
+		"status_id":      {Type: filterql.Int, Column: "t.status_id", Nullable: true},
+		"status_category": {Type: filterql.Enum, Column: fmt.Sprintf(
+			"(SELECT ws.category FROM %s ws WHERE ws.id = t.status_id)", database.WorkflowStatusesTable,
+		), Values: []string{entity.StatusCategoryTodo, entity.StatusCategoryInProgress, entity.StatusCategoryDone}},
+		"assignee": {Type: filterql.Int, Me: true, Member: fmt.Sprintf(
+			"EXISTS (SELECT 1 FROM %s tu WHERE tu.task_id = t.id AND tu.user_id %%s)", database.TaskUsersTable,
+		)},
+		"label": {Type: filterql.Int, Member: fmt.Sprintf(
+			"EXISTS (SELECT 1 FROM %s tl WHERE tl.task_id = t.id AND tl.label_id %%s)", database.TaskLabelsTable,
+		)},
+		"due_at": dueAt, "due": dueAt,
+		"start_at": startAt, "start": startAt,
+		"created_at": createdAt, "created": createdAt,
+		"updated_at": updatedAt, "updated": updatedAt,
+	}
+}()
+
+// filterConditions renders the filter as additional "AND ..." conditions on the t alias.
+// Arguments are appended to args so placeholders continue the caller's numbering.
+func filterConditions(filter *entity.TaskFilter, args []any) (string, []any, error) {
+	if filter == nil {
+		return "", args, nil
+	}
+
+	var conds []string
+	if filter.Overdue != nil {
+		if *filter.Overdue {
+			conds = append(conds, "t.due_at < NOW() AND t.is_completed = FALSE")
+		} else {
+			conds = append(conds, "(t.due_at IS NULL OR t.due_at >= NOW() OR t.is_completed = TRUE)")
+		}
+	}
+	if len(filter.Priorities) > 0 {
+		args = append(args, pq.Array(filter.Priorities))
+		conds = append(conds, fmt.Sprintf("t.priority = ANY($%d)", len(args)))
+	}
+	if len(filter.LabelIDs) > 0 {
+		args = append(args, pq.Array(filter.LabelIDs))
+		conds = append(conds, fmt.Sprintf(
+			"EXISTS (SELECT 1 FROM %s tl WHERE tl.task_id = t.id AND tl.label_id = ANY($%d))",
+			database.TaskLabelsTable, len(args),
+		))
+	}
+	for _, value := range filter.CustomFields {
+		var cond string
+		cond, args = customFieldCondition(value, args)
+		conds = append(conds, cond)
+	}
+	if filter.Query != nil {
+		cond, queryArgs, err := filterql.Compile(filter.Query, taskFilterFields,
+			filterql.Options{UserID: filter.CurrentUserID}, args)
+		if err != nil {
+			if errors.Is(err, filterql.ErrNoCurrentUser) {
+				return "", nil, fmt.Errorf("%w: %v", domain_error.ErrUnauthenticated, err)
+			}
+			return "", nil, fmt.Errorf("%w: %v", domain_error.ErrValidation, err)
+		}
+		conds = append(conds, cond)
+		args = queryArgs
+	}
+
+	if len(conds) == 0 {
+		return "", args, nil
+	}
+	return " AND " + strings.Join(conds, " AND "), args, nil
+}
+
+func (r *PostgresTaskRepository) ValidateFilter(filter *entity.TaskFilter) error {
+	_, _, err := filterConditions(filter, nil)
+	return err
+}
+
 func (r *PostgresTaskRepository) CreateTask(ctx context.Context, task *entity.Task) (*entity.Task, error) {
 	q := fmt.Sprintf(`
-        INSERT INTO %s (title, description, is_completed, kanban_id) VALUES ($1, $2, $3, $4) 
-        RETURNING id, title, description, is_completed, created_at, updated_at, kanban_id;
-    `, database.TaskTable) // Define TaskTable in your database package
+        INSERT INTO %s AS t (title, description, is_completed, kanban_id, start_at, due_at, time_zone, priority,
+            parent_task_id, recurrence_id, occurrence_at, estimate, status_id)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+        RETURNING %s;
+    `, database.TaskTable, taskColumns)
 
-	err := r.db.QueryRowContext(ctx, q, task.Title, task.Description, task.IsCompleted, task.KanbanID).Scan(
-		&task.ID, &task.Title, &task.Description, &task.IsCompleted, &task.CreatedAt, &task.UpdatedAt, &task.KanbanID,
+	row := database.Conn(ctx, r.db).QueryRowContext(ctx, q,
+		task.Title, task.Description, task.IsCompleted, task.KanbanID, task.StartAt, task.DueAt, task.TimeZone,
+		task.Priority, task.ParentTaskID, task.RecurrenceID, task.OccurrenceAt, task.Estimate, task.StatusID,
 	)
+	created, err := scanTask(row)
 	if err != nil {
 		return nil, fmt.Errorf("create task: %w", err)
 	}
-	return task, nil
+	return created, nil
 }
 
 func (r *PostgresTaskRepository) GetTaskByID(ctx context.Context, id int) (*entity.Task, error) {
 	q := fmt.Sprintf(`
-        SELECT id, title, description, is_completed, created_at, updated_at FROM %s WHERE id = $1;
-    `, database.TaskTable)
+        SELECT %s FROM %s t WHERE t.id = $1 AND t.deleted_at IS NULL;
+    `, taskColumns, database.TaskTable)
 
-	row := r.db.QueryRowContext(ctx, q, id)
-
-	task := new(entity.Task) // Initialize task as a pointer
-
-	err := row.Scan(&task.ID, &task.Title, &task.Description, &task.IsCompleted, &task.CreatedAt, &task.UpdatedAt)
+	task, err := scanTask(database.Conn(ctx, r.db).QueryRowContext(ctx, q, id))
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: task %d", domain_error.ErrNotFound, id)
+		}
 		return nil, fmt.Errorf("get task by id: %w", err)
 	}
 	return task, nil
 }
 
+// GetTaskIDByKey resolves a task key, including tasks in the trash so they can be restored by key.
+func (r *PostgresTaskRepository) GetTaskIDByKey(ctx context.Context, projectKey string, number int) (int, error) {
+	q := fmt.Sprintf(`
+        SELECT t.id FROM %s t
+        JOIN %s k ON k.id = t.kanban_id
+        JOIN %s p ON p.id = k.project_id
+        WHERE p.key = $1 AND t.number = $2;
+    `, database.TaskTable, database.KanbanTable, database.ProjectsTable)
+
+	var id int
+	err := database.Conn(ctx, r.db).QueryRowContext(ctx, q, projectKey, number).Scan(&id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, fmt.Errorf("%w: task %s", domain_error.ErrNotFound, taskkey.Format(projectKey, number))
+		}
+		return 0, fmt.Errorf("get task id by key: %w", err)
+	}
+	return id, nil
+}
+
 func (r *PostgresTaskRepository) UpdateTask(ctx context.Context, task *entity.Task) (*entity.Task, error) {
 	q := fmt.Sprintf(`
-        UPDATE %s SET title = $1, description = $2, is_completed = $3, updated_at = NOW() WHERE id = $4
-        RETURNING id, title, description, is_completed, created_at, updated_at;
-    `, database.TaskTable)
+        UPDATE %s AS t SET title = $1, description = $2, is_completed = $3, start_at = $4, due_at = $5,
+            time_zone = $6, priority = $7, parent_task_id = $8, estimate = $9, kanban_id = $10, status_id = $11,
+            updated_at = NOW()
+        WHERE t.id = $12 AND t.deleted_at IS NULL
+        RETURNING %s;
+    `, database.TaskTable, taskColumns)
 
-	err := r.db.QueryRowContext(ctx, q, task.Title, task.Description, task.IsCompleted, task.ID).Scan(
-		&task.ID, &task.Title, &task.Description, &task.IsCompleted, &task.CreatedAt, &task.UpdatedAt,
+	row := database.Conn(ctx, r.db).QueryRowContext(ctx, q,
+		task.Title, task.Description, task.IsCompleted, task.StartAt, task.DueAt, task.TimeZone, task.Priority,
+		task.ParentTaskID, task.Estimate, task.KanbanID, task.StatusID, task.ID,
 	)
+	updated, err := scanTask(row)
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: task %d", domain_error.ErrNotFound, task.ID)
+		}
 		return nil, fmt.Errorf("update task: %w", err)
 	}
-	return task, nil
+	return updated, nil
 }
 
+// DeleteTask moves the task and all its subtasks to the trash under one deleted_at.
 func (r *PostgresTaskRepository) DeleteTask(ctx context.Context, id int) error {
 	q := fmt.Sprintf(`
-        DELETE FROM %s WHERE id = $1;
+        WITH RECURSIVE doomed AS (
+            SELECT id FROM %[1]s WHERE id = $1 AND deleted_at IS NULL
+            UNION
+            SELECT t.id FROM %[1]s t JOIN doomed d ON t.parent_task_id = d.id WHERE t.deleted_at IS NULL
+        )
+        UPDATE %[1]s SET deleted_at = NOW() WHERE id IN (SELECT id FROM doomed);
     `, database.TaskTable)
 
-	_, err := r.db.ExecContext(ctx, q, id)
+	_, err := database.Conn(ctx, r.db).ExecContext(ctx, q, id)
 	if err != nil {
 		return fmt.Errorf("delete task: %w", err)
 	}
 	return nil
 }
 
-func (r *PostgresTaskRepository) GetTasksByKanbanID(ctx context.Context, kanbanID int) ([]*entity.Task, error) {
-	fmt.Println("GetTasksByKanbanID", kanbanID)
+// customFieldCondition matches tasks holding the value in the field. Text and URL values
+// compare case-insensitively, multi-select values match tasks having all of the options.
+func customFieldCondition(value *entity.CustomFieldValue, args []any) (string, []any) {
+	args = append(args, value.FieldID)
+	fieldArg := len(args)
+
+	var match string
+	switch value.FieldType {
+	case entity.CustomFieldText, entity.CustomFieldURL:
+		args = append(args, *value.Text)
+		match = fmt.Sprintf("lower(v.text_value) = lower($%d)", len(args))
+	case entity.CustomFieldSingleSelect:
+		args = append(args, *value.Text)
+		match = fmt.Sprintf("v.text_value = $%d", len(args))
+	case entity.CustomFieldNumber:
+		args = append(args, *value.Number)
+		match = fmt.Sprintf("v.number_value = $%d", len(args))
+	case entity.CustomFieldDate:
+		args = append(args, value.Date.Format("2006-01-02"))
+		match = fmt.Sprintf("v.date_value = $%d::date", len(args))
+	case entity.CustomFieldUser:
+		args = append(args, *value.UserID)
+		match = fmt.Sprintf("v.user_id = $%d", len(args))
+	case entity.CustomFieldMultiSelect:
+		args = append(args, pq.Array(value.Options))
+		match = fmt.Sprintf("v.options_value @> $%d", len(args))
+	}
+
+	return fmt.Sprintf(
+		"EXISTS (SELECT 1 FROM %s v WHERE v.task_id = t.id AND v.field_id = $%d AND %s)",
+		database.TaskCustomFieldValuesTable, fieldArg, match,
+	), args
+}
+
+// orderClause renders the sort keys of the filter as an ORDER BY clause on the t alias.
+// Tasks without a value come last in both directions; the id keeps the order stable.
+func orderClause(filter *entity.TaskFilter, args []any) (string, []any) {
+	if filter == nil || len(filter.Sort) == 0 {
+		return "", args
+	}
+
+	keys := make([]string, 0, len(filter.Sort)+1)
+	for _, sort := range filter.Sort {
+		var key string
+		key, args = sortKey(sort, args)
+		keys = append(keys, key)
+	}
+	keys = append(keys, "t.id")
+	return " ORDER BY " + strings.Join(keys, ", "), args
+}
+
+func sortKey(sort *entity.TaskSort, args []any) (string, []any) {
+	var expr string
+	switch {
+	case sort.CustomField != nil:
+		args = append(args, sort.CustomField.ID)
+		expr = fmt.Sprintf("(SELECT v.%s FROM %s v WHERE v.task_id = t.id AND v.field_id = $%d)",
+			customFieldValueColumn(sort.CustomField.Type), database.TaskCustomFieldValuesTable, len(args))
+	case sort.Field == entity.TaskSortPriority:
+		expr = fmt.Sprintf(`CASE t.priority WHEN '%s' THEN 1 WHEN '%s' THEN 2 WHEN '%s' THEN 3 WHEN '%s' THEN 4 ELSE 0 END`,
+			entity.PriorityLow, entity.PriorityMedium, entity.PriorityHigh, entity.PriorityUrgent)
+	case sort.Field == entity.TaskSortTitle:
+		expr = "lower(t.title)"
+	case sort.Field == entity.TaskSortDueAt:
+		expr = "t.due_at"
+	case sort.Field == entity.TaskSortCreatedAt:
+		expr = "t.created_at"
+	case sort.Field == entity.TaskSortEstimate:
+		expr = "t.estimate"
+	case sort.Field == entity.TaskSortUpdatedAt:
+		expr = "t.updated_at"
+	default:
+		expr = "t.id"
+	}
+
+	direction := "ASC"
+	if sort.Desc {
+		direction = "DESC"
+	}
+	return fmt.Sprintf("%s %s NULLS LAST", expr, direction), args
+}
+
+func customFieldValueColumn(fieldType string) string {
+	switch fieldType {
+	case entity.CustomFieldNumber:
+		return "number_value"
+	case entity.CustomFieldDate:
+		return "date_value"
+	case entity.CustomFieldUser:
+		return "user_id"
+	case entity.CustomFieldMultiSelect:
+		return "options_value"
+	}
+	return "text_value"
+}
+
+func (r *PostgresTaskRepository) GetTasksByKanbanID(ctx context.Context, kanbanID int, filter *entity.TaskFilter) ([]*entity.Task, error) {
+	where, args, err := filterConditions(filter, []any{kanbanID})
+	if err != nil {
+		return nil, err
+	}
+	order, args := orderClause(filter, args)
 	q := fmt.Sprintf(`
-        SELECT id, title, description, is_completed, created_at, updated_at, kanban_id
-        FROM %s 
-        WHERE kanban_id = $1;
-    `, database.TaskTable)
+        SELECT %s
+        FROM %s t
+        WHERE t.kanban_id = $1 AND t.deleted_at IS NULL%s%s;
+    `, taskColumns, database.TaskTable, where, order)
 
-	rows, err := r.db.QueryContext(ctx, q, kanbanID)
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, q, args...)
 	if err != nil {
 		return nil, fmt.Errorf("get tasks by kanban id: %w", err)
 	}
 	defer rows.Close()
 
-	var tasks []*entity.Task
-	for rows.Next() {
-		var t entity.Task
-		if err := rows.Scan(&t.ID, &t.Title, &t.Description, &t.IsCompleted, &t.CreatedAt, &t.UpdatedAt, &t.KanbanID); err != nil {
-			return nil, fmt.Errorf("scan task: %w", err)
-		}
-		tasks = append(tasks, &t)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("rows error: %w", err)
-	}
-	return tasks, nil
+	return scanTasks(rows)
 }
 
-func (r *PostgresTaskRepository) GetTasksByUserID(ctx context.Context, userID int) ([]*entity.Task, error) {
-	q := `
-        SELECT DISTINCT t.id, t.title, t.description, t.is_completed, t.created_at, t.updated_at
+func (r *PostgresTaskRepository) GetTasksByUserID(ctx context.Context, userID int, filter *entity.TaskFilter) ([]*entity.Task, error) {
+	where, args, err := filterConditions(filter, []any{userID})
+	if err != nil {
+		return nil, err
+	}
+	order, args := orderClause(filter, args)
+	// The project check runs in EXISTS rather than as joins with DISTINCT, so the list can be
+	// ordered by expressions that are not selected.
+	q := fmt.Sprintf(`
+        SELECT %s
         FROM task t
-        LEFT JOIN kanban k ON t.kanban_id = k.id
-        LEFT JOIN projects p ON k.project_id = p.id
-        -- Присоединяем project_users, чтобы учесть приглашенных пользователей
-        LEFT JOIN project_users pu ON p.id = pu.project_id
-        WHERE
-            -- Задачи из проектов, где пользователь - владелец
-            p.owner_id = $1
-            -- ИЛИ задачи из проектов, куда пользователь приглашен
-            OR pu.user_id = $1
+        WHERE t.deleted_at IS NULL AND (
+            EXISTS (
+                SELECT 1
+                FROM kanban k
+                JOIN projects p ON k.project_id = p.id
+                -- Присоединяем project_users, чтобы учесть приглашенных пользователей
+                LEFT JOIN project_users pu ON p.id = pu.project_id
+                WHERE k.id = t.kanban_id AND k.deleted_at IS NULL AND (
+                    -- Задачи из проектов, где пользователь - владелец
+                    p.owner_id = $1
+                    -- ИЛИ задачи из проектов, куда пользователь приглашен
+                    OR pu.user_id = $1
+                )
+            )
             -- ИЛИ задачи, которые не привязаны к канбану/проекту (если такие задачи существуют и должны быть видны)
-            OR t.kanban_id IS NULL;
-    `
+            OR t.kanban_id IS NULL
+        )%s%s;
+    `, taskColumns, where, order)
 
-	rows, err := r.db.QueryContext(ctx, q, userID)
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, q, args...)
 	if err != nil {
 		return nil, fmt.Errorf("get tasks by user id: %w", err)
 	}
 	defer rows.Close()
 
-	var tasks []*entity.Task
-	for rows.Next() {
-		var t entity.Task
-		// Убедитесь, что все поля задачи, которые вы ожидаете, сканируются
-		if err := rows.Scan(&t.ID, &t.Title, &t.Description, &t.IsCompleted, &t.CreatedAt, &t.UpdatedAt); err != nil {
-			return nil, fmt.Errorf("scan task: %w", err)
-		}
-		tasks = append(tasks, &t)
+	return scanTasks(rows)
+}
+
+func (r *PostgresTaskRepository) GetTasksByProjectID(ctx context.Context, projectID int, filter *entity.TaskFilter) ([]*entity.Task, error) {
+	where, args, err := filterConditions(filter, []any{projectID})
+	if err != nil {
+		return nil, err
 	}
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("rows error: %w", err)
+	order, args := orderClause(filter, args)
+	// Tasks belong to a project through their kanban; project_tasks only holds legacy links.
+	q := fmt.Sprintf(`
+        SELECT %s
+        FROM %s t
+        LEFT JOIN %s k ON k.id = t.kanban_id
+        WHERE t.deleted_at IS NULL
+            AND ((k.project_id = $1 AND k.deleted_at IS NULL)
+                OR t.id IN (SELECT pt.task_id FROM %s pt WHERE pt.project_id = $1))%s%s;
+    `, taskColumns, database.TaskTable, database.KanbanTable, database.ProjectTasksTable, where, order)
+
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("get tasks by project id: %w", err)
 	}
-	return tasks, nil
+	defer rows.Close()
+
+	return scanTasks(rows)
 }
 
-func (r *PostgresTaskRepository) AssignUserToTask(ctx context.Context, taskID int, userID int) error {
+func (r *PostgresTaskRepository) GetSubtasks(ctx context.Context, parentTaskID int) ([]*entity.Task, error) {
 	q := fmt.Sprintf(`
-        INSERT INTO %s (task_id, user_id) VALUES ($1, $2);
-    `, database.TaskUsersTable) // Define TaskUsersTable
+        SELECT %s
+        FROM %s t
+        WHERE t.parent_task_id = $1 AND t.deleted_at IS NULL
+        ORDER BY t.created_at, t.id;
+    `, taskColumns, database.TaskTable)
 
-	_, err := r.db.ExecContext(ctx, q, taskID, userID)
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, q, parentTaskID)
 	if err != nil {
-		return fmt.Errorf("assign user to task: %w", err)
+		return nil, fmt.Errorf("get subtasks: %w", err)
 	}
-	return nil
+	defer rows.Close()
+
+	return scanTasks(rows)
 }
 
-func (r *PostgresTaskRepository) GetTasksByProjectID(ctx context.Context, projectID int) ([]*entity.Task, error) {
+func (r *PostgresTaskRepository) GetTaskProgress(ctx context.Context, taskID int) (*entity.TaskProgress, error) {
 	q := fmt.Sprintf(`
-        SELECT t.id, t.title, t.description, t.is_completed, t.created_at, t.updated_at
-        FROM %s pt
-        JOIN %s t ON pt.task_id = t.id
-        WHERE pt.project_id = $1;
-    `, database.ProjectTasksTable, database.TaskTable) // Define ProjectTasksTable
+        SELECT
+            (SELECT COUNT(*) FROM %s WHERE task_id = $1),
+            (SELECT COUNT(*) FROM %s WHERE task_id = $1 AND is_done = TRUE),
+            (SELECT COUNT(*) FROM %s WHERE parent_task_id = $1 AND deleted_at IS NULL),
+            (SELECT COUNT(*) FROM %s WHERE parent_task_id = $1 AND deleted_at IS NULL AND is_completed = TRUE);
+    `, database.ChecklistItemsTable, database.ChecklistItemsTable, database.TaskTable, database.TaskTable)
 
-	rows, err := r.db.QueryContext(ctx, q, projectID)
+	var p entity.TaskProgress
+	err := database.Conn(ctx, r.db).QueryRowContext(ctx, q, taskID).Scan(
+		&p.ChecklistTotal, &p.ChecklistDone, &p.SubtasksTotal, &p.SubtasksCompleted,
+	)
 	if err != nil {
-		return nil, fmt.Errorf("get tasks by project id: %w", err)
+		return nil, fmt.Errorf("get task progress: %w", err)
+	}
+	return &p, nil
+}
+
+// GetTaskAncestorIDs returns the IDs of the task's parent, grandparent and so on.
+func (r *PostgresTaskRepository) GetTaskAncestorIDs(ctx context.Context, taskID int) ([]int, error) {
+	q := fmt.Sprintf(`
+        WITH RECURSIVE ancestors AS (
+            SELECT parent_task_id AS id FROM %s WHERE id = $1
+            UNION
+            SELECT t.parent_task_id FROM %s t JOIN ancestors a ON t.id = a.id
+        )
+        SELECT id FROM ancestors WHERE id IS NOT NULL;
+    `, database.TaskTable, database.TaskTable)
+
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, q, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("get task ancestors: %w", err)
 	}
 	defer rows.Close()
 
-	var tasks []*entity.Task
+	var ids []int
 	for rows.Next() {
-		var t entity.Task
-		if err := rows.Scan(&t.ID, &t.Title, &t.Description, &t.IsCompleted, &t.CreatedAt, &t.UpdatedAt); err != nil {
-			return nil, fmt.Errorf("scan task: %w", err)
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan task ancestor: %w", err)
 		}
-		tasks = append(tasks, &t)
+		ids = append(ids, id)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("rows error: %w", err)
 	}
-	return tasks, nil
+	return ids, nil
+}
+
+// GetTaskEpics finds the top-level ancestor of every task. A top-level task is its own epic only
+// when it has live subtasks.
+func (r *PostgresTaskRepository) GetTaskEpics(ctx context.Context, taskIDs []int) (map[int]*entity.TaskEpic, error) {
+	// chain walks up from every task to its top-level ancestor; UNION stops at a parent loop.
+	q := fmt.Sprintf(`
+        WITH RECURSIVE chain AS (
+            SELECT t.id AS task_id, t.id, t.parent_task_id FROM %[1]s t WHERE t.id = ANY($1)
+            UNION
+            SELECT c.task_id, p.id, p.parent_task_id FROM chain c JOIN %[1]s p ON p.id = c.parent_task_id
+            WHERE p.deleted_at IS NULL
+        )
+        SELECT c.task_id, e.id, e.title
+        FROM chain c
+        JOIN %[1]s e ON e.id = c.id
+        WHERE c.parent_task_id IS NULL
+            AND (c.task_id <> e.id
+                OR EXISTS (SELECT 1 FROM %[1]s s WHERE s.parent_task_id = e.id AND s.deleted_at IS NULL));
+    `, database.TaskTable)
+
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, q, pq.Array(taskIDs))
+	if err != nil {
+		return nil, fmt.Errorf("get task epics: %w", err)
+	}
+	defer rows.Close()
+
+	epics := make(map[int]*entity.TaskEpic, len(taskIDs))
+	for rows.Next() {
+		var e entity.TaskEpic
+		if err := rows.Scan(&e.TaskID, &e.EpicID, &e.EpicTitle); err != nil {
+			return nil, fmt.Errorf("scan task epic: %w", err)
+		}
+		epics[e.TaskID] = &e
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return epics, nil
+}
+
+// descendantsCTE selects the IDs of all live subtasks of $1, at any depth.
+var descendantsCTE = fmt.Sprintf(`
+        WITH RECURSIVE descendants AS (
+            SELECT id FROM %s WHERE parent_task_id = $1 AND deleted_at IS NULL
+            UNION
+            SELECT t.id FROM %s t JOIN descendants d ON t.parent_task_id = d.id WHERE t.deleted_at IS NULL
+        )`, database.TaskTable, database.TaskTable)
+
+func (r *PostgresTaskRepository) CountOpenDescendants(ctx context.Context, taskID int) (int, error) {
+	q := fmt.Sprintf(`%s
+        SELECT COUNT(*) FROM %s t JOIN descendants d ON t.id = d.id WHERE t.is_completed = FALSE;
+    `, descendantsCTE, database.TaskTable)
+
+	var count int
+	if err := database.Conn(ctx, r.db).QueryRowContext(ctx, q, taskID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("count open subtasks: %w", err)
+	}
+	return count, nil
 }
@@ -8,10 +8,20 @@ import (
 type TaskRepository interface {
 	CreateTask(ctx context.Context, task *entity.Task) (*entity.Task, error)
 	GetTaskByID(ctx context.Context, id int) (*entity.Task, error)
+	// GetTaskIDByKey resolves a task key such as DT-123 split into project key and number.
+	GetTaskIDByKey(ctx context.Context, projectKey string, number int) (int, error)
 	UpdateTask(ctx context.Context, task *entity.Task) (*entity.Task, error)
 	DeleteTask(ctx context.Context, id int) error
-	GetTasksByKanbanID(ctx context.Context, kanbanID int) ([]*entity.Task, error)
-	GetTasksByUserID(ctx context.Context, userID int) ([]*entity.Task, error)
-	AssignUserToTask(ctx context.Context, taskID int, userID int) error
-	GetTasksByProjectID(ctx context.Context, projectID int) ([]*entity.Task, error)
+	GetTasksByKanbanID(ctx context.Context, kanbanID int, filter *entity.TaskFilter) ([]*entity.Task, error)
+	GetTasksByUserID(ctx context.Context, userID int, filter *entity.TaskFilter) ([]*entity.Task, error)
+	GetTasksByProjectID(ctx context.Context, projectID int, filter *entity.TaskFilter) ([]*entity.Task, error)
+	// ValidateFilter checks that the filter only refers to known fields with valid values.
+	ValidateFilter(filter *entity.TaskFilter) error
+
+	GetSubtasks(ctx context.Context, parentTaskID int) ([]*entity.Task, error)
+	GetTaskProgress(ctx context.Context, taskID int) (*entity.TaskProgress, error)
+	GetTaskAncestorIDs(ctx context.Context, taskID int) ([]int, error)
+	// GetTaskEpics returns the epics of the tasks by task ID. Tasks without an epic are left out.
+	GetTaskEpics(ctx context.Context, taskIDs []int) (map[int]*entity.TaskEpic, error)
+	CountOpenDescendants(ctx context.Context, taskID int) (int, error)
 }
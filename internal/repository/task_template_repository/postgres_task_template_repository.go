@@ -0,0 +1,196 @@
+package task_template_repository
+
+import (
+	"DataTask/internal/domain/domain_error"
+	"DataTask/internal/domain/entity"
+	"DataTask/internal/repository/database"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/lib/pq"
+)
+
+const templateColumns = `tt.id, tt.project_id, tt.name, tt.title_pattern, tt.description, tt.priority,
+    tt.label_ids, tt.checklist, tt.assignee_ids, tt.created_at, tt.updated_at`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+type PostgresTaskTemplateRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresTaskTemplateRepository(db *sql.DB) *PostgresTaskTemplateRepository {
+	return &PostgresTaskTemplateRepository{db: db}
+}
+
+func scanTemplate(row rowScanner) (*entity.TaskTemplate, error) {
+	var t entity.TaskTemplate
+	var labelIDs, assigneeIDs pq.Int64Array
+	err := row.Scan(&t.ID, &t.ProjectID, &t.Name, &t.TitlePattern, &t.Description, &t.Priority,
+		&labelIDs, pq.Array(&t.Checklist), &assigneeIDs, &t.CreatedAt, &t.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	t.LabelIDs = toInts(labelIDs)
+	t.AssigneeIDs = toInts(assigneeIDs)
+	return &t, nil
+}
+
+func scanTemplates(rows *sql.Rows) ([]*entity.TaskTemplate, error) {
+	var templates []*entity.TaskTemplate
+	for rows.Next() {
+		t, err := scanTemplate(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan task template: %w", err)
+		}
+		templates = append(templates, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return templates, nil
+}
+
+func (r *PostgresTaskTemplateRepository) CreateTemplate(ctx context.Context, template *entity.TaskTemplate) (*entity.TaskTemplate, error) {
+	q := fmt.Sprintf(`
+        INSERT INTO %s AS tt (project_id, name, title_pattern, description, priority, label_ids, checklist, assignee_ids)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+        RETURNING %s;
+    `, database.TaskTemplatesTable, templateColumns)
+
+	created, err := scanTemplate(database.Conn(ctx, r.db).QueryRowContext(ctx, q,
+		template.ProjectID, template.Name, template.TitlePattern, template.Description, template.Priority,
+		pq.Array(template.LabelIDs), pq.Array(template.Checklist), pq.Array(template.AssigneeIDs),
+	))
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, fmt.Errorf("%w: task template %q already exists in project", domain_error.ErrConflict, template.Name)
+		}
+		return nil, fmt.Errorf("create task template: %w", err)
+	}
+	return created, nil
+}
+
+func (r *PostgresTaskTemplateRepository) GetTemplateByID(ctx context.Context, id int) (*entity.TaskTemplate, error) {
+	q := fmt.Sprintf(`
+        SELECT %s FROM %s tt WHERE tt.id = $1;
+    `, templateColumns, database.TaskTemplatesTable)
+
+	template, err := scanTemplate(database.Conn(ctx, r.db).QueryRowContext(ctx, q, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: task template %d", domain_error.ErrNotFound, id)
+		}
+		return nil, fmt.Errorf("get task template by id: %w", err)
+	}
+	return template, nil
+}
+
+func (r *PostgresTaskTemplateRepository) UpdateTemplate(ctx context.Context, template *entity.TaskTemplate) (*entity.TaskTemplate, error) {
+	q := fmt.Sprintf(`
+        UPDATE %s AS tt SET name = $1, title_pattern = $2, description = $3, priority = $4, label_ids = $5,
+            checklist = $6, assignee_ids = $7
+        WHERE tt.id = $8
+        RETURNING %s;
+    `, database.TaskTemplatesTable, templateColumns)
+
+	updated, err := scanTemplate(database.Conn(ctx, r.db).QueryRowContext(ctx, q,
+		template.Name, template.TitlePattern, template.Description, template.Priority, pq.Array(template.LabelIDs),
+		pq.Array(template.Checklist), pq.Array(template.AssigneeIDs), template.ID,
+	))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: task template %d", domain_error.ErrNotFound, template.ID)
+		}
+		if isUniqueViolation(err) {
+			return nil, fmt.Errorf("%w: task template %q already exists in project", domain_error.ErrConflict, template.Name)
+		}
+		return nil, fmt.Errorf("update task template: %w", err)
+	}
+	return updated, nil
+}
+
+func (r *PostgresTaskTemplateRepository) DeleteTemplate(ctx context.Context, id int) error {
+	q := fmt.Sprintf(`
+        DELETE FROM %s WHERE id = $1;
+    `, database.TaskTemplatesTable)
+
+	if _, err := database.Conn(ctx, r.db).ExecContext(ctx, q, id); err != nil {
+		return fmt.Errorf("delete task template: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresTaskTemplateRepository) GetAvailableTemplates(ctx context.Context, projectID int) ([]*entity.TaskTemplate, error) {
+	// UNION (not UNION ALL) stops the recursion should the project tree ever contain a cycle.
+	q := fmt.Sprintf(`
+        WITH RECURSIVE ancestors AS (
+            SELECT id, parent_project_id, 0 AS depth FROM %s WHERE id = $1
+            UNION
+            SELECT p.id, p.parent_project_id, a.depth + 1
+            FROM %s p
+            JOIN ancestors a ON p.id = a.parent_project_id
+        )
+        SELECT %s
+        FROM %s tt
+        JOIN ancestors a ON tt.project_id = a.id
+        ORDER BY a.depth, tt.name;
+    `, database.ProjectsTable, database.ProjectsTable, templateColumns, database.TaskTemplatesTable)
+
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, q, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("get available task templates: %w", err)
+	}
+	defer rows.Close()
+
+	return scanTemplates(rows)
+}
+
+func (r *PostgresTaskTemplateRepository) ApplyToTask(ctx context.Context, taskID int, template *entity.TaskTemplate, assigneeIDs []int) error {
+	conn := database.Conn(ctx, r.db)
+
+	// Joining the labels drops IDs of labels deleted since the template was saved.
+	labelsQuery := fmt.Sprintf(`
+        INSERT INTO %s (task_id, label_id)
+        SELECT $1, l.id FROM %s l WHERE l.id = ANY($2)
+        ON CONFLICT DO NOTHING;
+    `, database.TaskLabelsTable, database.LabelsTable)
+	if _, err := conn.ExecContext(ctx, labelsQuery, taskID, pq.Array(template.LabelIDs)); err != nil {
+		return fmt.Errorf("apply template labels: %w", err)
+	}
+
+	checklistQuery := fmt.Sprintf(`
+        INSERT INTO %s (task_id, text, position)
+        SELECT $1, item.text, item.position - 1
+        FROM unnest($2::TEXT[]) WITH ORDINALITY AS item (text, position);
+    `, database.ChecklistItemsTable)
+	if _, err := conn.ExecContext(ctx, checklistQuery, taskID, pq.Array(template.Checklist)); err != nil {
+		return fmt.Errorf("apply template checklist: %w", err)
+	}
+
+	assigneesQuery := fmt.Sprintf(`
+        INSERT INTO %s (task_id, user_id)
+        SELECT $1, unnest($2::INTEGER[])
+        ON CONFLICT DO NOTHING;
+    `, database.TaskUsersTable)
+	if _, err := conn.ExecContext(ctx, assigneesQuery, taskID, pq.Array(assigneeIDs)); err != nil {
+		return fmt.Errorf("apply template assignees: %w", err)
+	}
+	return nil
+}
+
+func toInts(values pq.Int64Array) []int {
+	ints := make([]int, len(values))
+	for i, v := range values {
+		ints[i] = int(v)
+	}
+	return ints
+}
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
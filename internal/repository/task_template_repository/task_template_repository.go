@@ -0,0 +1,19 @@
+package task_template_repository
+
+import (
+	"DataTask/internal/domain/entity"
+	"context"
+)
+
+type TaskTemplateRepository interface {
+	CreateTemplate(ctx context.Context, template *entity.TaskTemplate) (*entity.TaskTemplate, error)
+	GetTemplateByID(ctx context.Context, id int) (*entity.TaskTemplate, error)
+	UpdateTemplate(ctx context.Context, template *entity.TaskTemplate) (*entity.TaskTemplate, error)
+	DeleteTemplate(ctx context.Context, id int) error
+	// GetAvailableTemplates returns the templates of the project and of all its ancestors, nearest first.
+	GetAvailableTemplates(ctx context.Context, projectID int) ([]*entity.TaskTemplate, error)
+
+	// ApplyToTask attaches the template's labels that still exist and its checklist to the task, and
+	// assigns assigneeIDs to it.
+	ApplyToTask(ctx context.Context, taskID int, template *entity.TaskTemplate, assigneeIDs []int) error
+}
@@ -0,0 +1,257 @@
+package trash_repository
+
+import (
+	"DataTask/internal/domain/domain_error"
+	"DataTask/internal/domain/entity"
+	"DataTask/internal/repository/database"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type PostgresTrashRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresTrashRepository(db *sql.DB) *PostgresTrashRepository {
+	return &PostgresTrashRepository{db: db}
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanTrashItem(row rowScanner) (*entity.TrashItem, error) {
+	var item entity.TrashItem
+	err := row.Scan(&item.Type, &item.ID, &item.Name, &item.ProjectID, &item.ParentID, &item.DeletedAt,
+		&item.ContainerTrashed)
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func scanTrashItems(rows *sql.Rows) ([]*entity.TrashItem, error) {
+	var items []*entity.TrashItem
+	for rows.Next() {
+		item, err := scanTrashItem(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan trash item: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return items, nil
+}
+
+func (r *PostgresTrashRepository) GetProjectTrash(ctx context.Context, projectID int) ([]*entity.TrashItem, error) {
+	// Rows trashed in one go share deleted_at, so a subproject, board or task stamped like its
+	// container is part of the container's entry rather than an entry of its own.
+	q := fmt.Sprintf(`
+        SELECT '%[4]s', p.id, p.name, p.id, p.parent_project_id, p.deleted_at, parent.deleted_at IS NOT NULL
+        FROM %[1]s p
+        JOIN %[1]s parent ON parent.id = p.parent_project_id
+        WHERE p.parent_project_id = $1 AND p.deleted_at IS NOT NULL
+          AND p.deleted_at IS DISTINCT FROM parent.deleted_at
+        UNION ALL
+        SELECT '%[5]s', k.id, k.name, k.project_id, k.project_id, k.deleted_at, p.deleted_at IS NOT NULL
+        FROM %[2]s k
+        JOIN %[1]s p ON p.id = k.project_id
+        WHERE k.project_id = $1 AND k.deleted_at IS NOT NULL AND k.deleted_at IS DISTINCT FROM p.deleted_at
+        UNION ALL
+        SELECT '%[6]s', t.id, t.title, k.project_id, t.kanban_id, t.deleted_at,
+            k.deleted_at IS NOT NULL OR parent.deleted_at IS NOT NULL
+        FROM %[3]s t
+        JOIN %[2]s k ON k.id = t.kanban_id
+        LEFT JOIN %[3]s parent ON parent.id = t.parent_task_id
+        WHERE k.project_id = $1 AND t.deleted_at IS NOT NULL
+          AND t.deleted_at IS DISTINCT FROM k.deleted_at
+          AND t.deleted_at IS DISTINCT FROM parent.deleted_at
+        ORDER BY 6 DESC, 1, 2;
+    `, database.ProjectsTable, database.KanbanTable, database.TaskTable,
+		entity.TrashItemProject, entity.TrashItemKanban, entity.TrashItemTask)
+
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, q, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("get project trash: %w", err)
+	}
+	defer rows.Close()
+
+	return scanTrashItems(rows)
+}
+
+func (r *PostgresTrashRepository) GetTrashedProjectsByOwnerID(ctx context.Context, ownerID int) ([]*entity.TrashItem, error) {
+	q := fmt.Sprintf(`
+        SELECT '%[2]s', p.id, p.name, p.id, p.parent_project_id, p.deleted_at, parent.deleted_at IS NOT NULL
+        FROM %[1]s p
+        LEFT JOIN %[1]s parent ON parent.id = p.parent_project_id
+        WHERE p.owner_id = $1 AND p.deleted_at IS NOT NULL
+          AND p.deleted_at IS DISTINCT FROM parent.deleted_at
+        ORDER BY p.deleted_at DESC, p.id;
+    `, database.ProjectsTable, entity.TrashItemProject)
+
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, q, ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("get trashed projects: %w", err)
+	}
+	defer rows.Close()
+
+	return scanTrashItems(rows)
+}
+
+func (r *PostgresTrashRepository) getTrashedItem(ctx context.Context, q string, itemType string, id int) (*entity.TrashItem, error) {
+	item, err := scanTrashItem(database.Conn(ctx, r.db).QueryRowContext(ctx, q, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %s %d in trash", domain_error.ErrNotFound, itemType, id)
+		}
+		return nil, fmt.Errorf("get trashed %s: %w", itemType, err)
+	}
+	return item, nil
+}
+
+func (r *PostgresTrashRepository) GetTrashedProject(ctx context.Context, id int) (*entity.TrashItem, error) {
+	q := fmt.Sprintf(`
+        SELECT '%[2]s', p.id, p.name, p.id, p.parent_project_id, p.deleted_at, parent.deleted_at IS NOT NULL
+        FROM %[1]s p
+        LEFT JOIN %[1]s parent ON parent.id = p.parent_project_id
+        WHERE p.id = $1 AND p.deleted_at IS NOT NULL;
+    `, database.ProjectsTable, entity.TrashItemProject)
+
+	return r.getTrashedItem(ctx, q, entity.TrashItemProject, id)
+}
+
+func (r *PostgresTrashRepository) GetTrashedKanban(ctx context.Context, id int) (*entity.TrashItem, error) {
+	q := fmt.Sprintf(`
+        SELECT '%[3]s', k.id, k.name, k.project_id, k.project_id, k.deleted_at, p.deleted_at IS NOT NULL
+        FROM %[2]s k
+        JOIN %[1]s p ON p.id = k.project_id
+        WHERE k.id = $1 AND k.deleted_at IS NOT NULL;
+    `, database.ProjectsTable, database.KanbanTable, entity.TrashItemKanban)
+
+	return r.getTrashedItem(ctx, q, entity.TrashItemKanban, id)
+}
+
+func (r *PostgresTrashRepository) GetTrashedTask(ctx context.Context, id int) (*entity.TrashItem, error) {
+	q := fmt.Sprintf(`
+        SELECT '%[3]s', t.id, t.title, k.project_id, t.kanban_id, t.deleted_at,
+            k.deleted_at IS NOT NULL OR parent.deleted_at IS NOT NULL
+        FROM %[2]s t
+        JOIN %[1]s k ON k.id = t.kanban_id
+        LEFT JOIN %[2]s parent ON parent.id = t.parent_task_id
+        WHERE t.id = $1 AND t.deleted_at IS NOT NULL;
+    `, database.KanbanTable, database.TaskTable, entity.TrashItemTask)
+
+	return r.getTrashedItem(ctx, q, entity.TrashItemTask, id)
+}
+
+// restoredTasksCTE selects the tasks of the batch stamped batch.deleted_at, starting from the
+// tasks matched by the root condition and following their subtasks.
+func restoredTasksCTE(root string) string {
+	return fmt.Sprintf(`
+        restored AS (
+            SELECT t.id FROM %[1]s t JOIN batch ON t.deleted_at = batch.deleted_at WHERE %[2]s
+            UNION
+            SELECT t.id FROM %[1]s t
+            JOIN restored r ON t.parent_task_id = r.id
+            JOIN batch ON t.deleted_at = batch.deleted_at
+        ), restored_tasks AS (
+            UPDATE %[1]s SET deleted_at = NULL WHERE id IN (SELECT id FROM restored)
+        )`, database.TaskTable, root)
+}
+
+func (r *PostgresTrashRepository) RestoreProject(ctx context.Context, id int) error {
+	q := fmt.Sprintf(`
+        WITH RECURSIVE batch AS (
+            SELECT deleted_at FROM %[1]s WHERE id = $1 AND deleted_at IS NOT NULL
+        ), restored_projects AS (
+            SELECT p.id FROM %[1]s p JOIN batch ON p.deleted_at = batch.deleted_at WHERE p.id = $1
+            UNION
+            SELECT p.id FROM %[1]s p
+            JOIN restored_projects r ON p.parent_project_id = r.id
+            JOIN batch ON p.deleted_at = batch.deleted_at
+        ),%[3]s, restored_kanbans AS (
+            UPDATE %[2]s k SET deleted_at = NULL FROM batch
+            WHERE k.project_id IN (SELECT id FROM restored_projects) AND k.deleted_at = batch.deleted_at
+        )
+        UPDATE %[1]s SET deleted_at = NULL WHERE id IN (SELECT id FROM restored_projects);
+    `, database.ProjectsTable, database.KanbanTable, restoredTasksCTE(fmt.Sprintf(
+		"t.kanban_id IN (SELECT id FROM %s WHERE project_id IN (SELECT id FROM restored_projects))", database.KanbanTable,
+	)))
+
+	if _, err := database.Conn(ctx, r.db).ExecContext(ctx, q, id); err != nil {
+		return fmt.Errorf("restore project: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresTrashRepository) RestoreKanban(ctx context.Context, id int) error {
+	q := fmt.Sprintf(`
+        WITH RECURSIVE batch AS (
+            SELECT deleted_at FROM %[1]s WHERE id = $1 AND deleted_at IS NOT NULL
+        ),%[2]s
+        UPDATE %[1]s SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL;
+    `, database.KanbanTable, restoredTasksCTE("t.kanban_id = $1"))
+
+	if _, err := database.Conn(ctx, r.db).ExecContext(ctx, q, id); err != nil {
+		return fmt.Errorf("restore kanban: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresTrashRepository) RestoreTask(ctx context.Context, id int) error {
+	q := fmt.Sprintf(`
+        WITH RECURSIVE batch AS (
+            SELECT deleted_at FROM %[1]s WHERE id = $1 AND deleted_at IS NOT NULL
+        ),%[2]s
+        SELECT COUNT(*) FROM restored;
+    `, database.TaskTable, restoredTasksCTE("t.id = $1"))
+
+	if _, err := database.Conn(ctx, r.db).ExecContext(ctx, q, id); err != nil {
+		return fmt.Errorf("restore task: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresTrashRepository) PurgeOlderThan(ctx context.Context, retention time.Duration) (int64, error) {
+	// Deleting a task cascades to its subtasks, a board to its tasks and a project to its boards,
+	// and a deleted project detaches its subprojects. Each table goes after the rows pointing at
+	// it, and rows that still have live children wait until those children are trashed as well.
+	purges := []struct {
+		table string
+		live  string
+	}{
+		{database.TaskTable, fmt.Sprintf(`SELECT 1 FROM %s c WHERE c.parent_task_id = p.id AND c.deleted_at IS NULL`, database.TaskTable)},
+		{database.KanbanTable, fmt.Sprintf(`SELECT 1 FROM %s c WHERE c.kanban_id = p.id AND c.deleted_at IS NULL`, database.TaskTable)},
+		{database.ProjectsTable, fmt.Sprintf(`
+            SELECT 1 FROM %s c WHERE c.project_id = p.id AND c.deleted_at IS NULL
+            UNION ALL
+            SELECT 1 FROM %s c WHERE c.parent_project_id = p.id AND c.deleted_at IS NULL`,
+			database.KanbanTable, database.ProjectsTable)},
+	}
+
+	var purged int64
+	conn := database.Conn(ctx, r.db)
+	for _, purge := range purges {
+		table := purge.table
+		q := fmt.Sprintf(`
+        DELETE FROM %s p
+        WHERE p.deleted_at < NOW() - make_interval(secs => $1)
+            AND NOT EXISTS (%s);
+    `, table, purge.live)
+		res, err := conn.ExecContext(ctx, q, retention.Seconds())
+		if err != nil {
+			return purged, fmt.Errorf("purge %s: %w", table, err)
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return purged, fmt.Errorf("purge %s: %w", table, err)
+		}
+		purged += affected
+	}
+	return purged, nil
+}
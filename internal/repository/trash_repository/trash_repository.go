@@ -0,0 +1,29 @@
+package trash_repository
+
+import (
+	"DataTask/internal/domain/entity"
+	"context"
+	"time"
+)
+
+// TrashRepository works on soft deleted rows, which the other repositories no longer see.
+type TrashRepository interface {
+	// GetProjectTrash lists the trashed subprojects, boards and tasks of a project, newest first.
+	// Items trashed together with their board or parent task are covered by that entry.
+	GetProjectTrash(ctx context.Context, projectID int) ([]*entity.TrashItem, error)
+	GetTrashedProjectsByOwnerID(ctx context.Context, ownerID int) ([]*entity.TrashItem, error)
+
+	GetTrashedProject(ctx context.Context, id int) (*entity.TrashItem, error)
+	GetTrashedKanban(ctx context.Context, id int) (*entity.TrashItem, error)
+	GetTrashedTask(ctx context.Context, id int) (*entity.TrashItem, error)
+
+	// RestoreProject, RestoreKanban and RestoreTask bring back the item together with everything
+	// that was trashed along with it.
+	RestoreProject(ctx context.Context, id int) error
+	RestoreKanban(ctx context.Context, id int) error
+	RestoreTask(ctx context.Context, id int) error
+
+	// PurgeOlderThan deletes tasks, boards and projects that have been in the trash longer than
+	// retention and have no live children for good and returns the number of removed rows.
+	PurgeOlderThan(ctx context.Context, retention time.Duration) (int64, error)
+}
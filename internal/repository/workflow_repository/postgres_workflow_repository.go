@@ -0,0 +1,316 @@
+package workflow_repository
+
+import (
+	"DataTask/internal/domain/domain_error"
+	"DataTask/internal/domain/entity"
+	"DataTask/internal/repository/database"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/lib/pq"
+)
+
+const statusColumns = `ws.id, ws.project_id, ws.name, ws.category, ws.position, ws.created_at, ws.updated_at`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+type PostgresWorkflowRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresWorkflowRepository(db *sql.DB) *PostgresWorkflowRepository {
+	return &PostgresWorkflowRepository{db: db}
+}
+
+func scanStatus(row rowScanner) (*entity.WorkflowStatus, error) {
+	var s entity.WorkflowStatus
+	err := row.Scan(&s.ID, &s.ProjectID, &s.Name, &s.Category, &s.Position, &s.CreatedAt, &s.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *PostgresWorkflowRepository) CreateDefaultStatuses(ctx context.Context, projectID int) error {
+	names := make([]string, 0, len(entity.DefaultWorkflowStatuses))
+	categories := make([]string, 0, len(entity.DefaultWorkflowStatuses))
+	for _, s := range entity.DefaultWorkflowStatuses {
+		names = append(names, s.Name)
+		categories = append(categories, s.Category)
+	}
+
+	q := fmt.Sprintf(`
+        INSERT INTO %s (project_id, name, category, position)
+        SELECT $1, item.name, item.category, item.position - 1
+        FROM unnest($2::TEXT[], $3::TEXT[]) WITH ORDINALITY AS item (name, category, position);
+    `, database.WorkflowStatusesTable)
+
+	_, err := database.Conn(ctx, r.db).ExecContext(ctx, q, projectID, pq.Array(names), pq.Array(categories))
+	if err != nil {
+		return fmt.Errorf("create default statuses: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresWorkflowRepository) CreateStatus(ctx context.Context, status *entity.WorkflowStatus) (*entity.WorkflowStatus, error) {
+	q := fmt.Sprintf(`
+        INSERT INTO %[1]s AS ws (project_id, name, category, position)
+        VALUES ($1, $2, $3, (SELECT COALESCE(MAX(position) + 1, 0) FROM %[1]s WHERE project_id = $1))
+        RETURNING %[2]s;
+    `, database.WorkflowStatusesTable, statusColumns)
+
+	created, err := scanStatus(database.Conn(ctx, r.db).QueryRowContext(ctx, q,
+		status.ProjectID, status.Name, status.Category))
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, fmt.Errorf("%w: status %q already exists in project", domain_error.ErrConflict, status.Name)
+		}
+		return nil, fmt.Errorf("create status: %w", err)
+	}
+	return created, nil
+}
+
+func (r *PostgresWorkflowRepository) GetStatusByID(ctx context.Context, id int) (*entity.WorkflowStatus, error) {
+	q := fmt.Sprintf(`
+        SELECT %s FROM %s ws WHERE ws.id = $1;
+    `, statusColumns, database.WorkflowStatusesTable)
+
+	status, err := scanStatus(database.Conn(ctx, r.db).QueryRowContext(ctx, q, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: status %d", domain_error.ErrNotFound, id)
+		}
+		return nil, fmt.Errorf("get status by id: %w", err)
+	}
+	return status, nil
+}
+
+func (r *PostgresWorkflowRepository) UpdateStatus(ctx context.Context, status *entity.WorkflowStatus) (*entity.WorkflowStatus, error) {
+	conn := database.Conn(ctx, r.db)
+
+	q := fmt.Sprintf(`
+        UPDATE %s AS ws SET name = $1, category = $2 WHERE ws.id = $3
+        RETURNING %s;
+    `, database.WorkflowStatusesTable, statusColumns)
+
+	updated, err := scanStatus(conn.QueryRowContext(ctx, q, status.Name, status.Category, status.ID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: status %d", domain_error.ErrNotFound, status.ID)
+		}
+		if isUniqueViolation(err) {
+			return nil, fmt.Errorf("%w: status %q already exists in project", domain_error.ErrConflict, status.Name)
+		}
+		return nil, fmt.Errorf("update status: %w", err)
+	}
+
+	tasksQuery := fmt.Sprintf(`
+        UPDATE %s SET is_completed = $1, updated_at = NOW() WHERE status_id = $2 AND is_completed IS DISTINCT FROM $1;
+    `, database.TaskTable)
+	_, err = conn.ExecContext(ctx, tasksQuery, updated.Category == entity.StatusCategoryDone, updated.ID)
+	if err != nil {
+		return nil, fmt.Errorf("update tasks of status: %w", err)
+	}
+	return updated, nil
+}
+
+func (r *PostgresWorkflowRepository) DeleteStatus(ctx context.Context, id int, replacementID *int) error {
+	conn := database.Conn(ctx, r.db)
+
+	if replacementID != nil {
+		// Trashed tasks move along so they can be restored.
+		tasksQuery := fmt.Sprintf(`
+            UPDATE %s SET status_id = $1, updated_at = NOW() WHERE status_id = $2;
+        `, database.TaskTable)
+		if _, err := conn.ExecContext(ctx, tasksQuery, *replacementID, id); err != nil {
+			return fmt.Errorf("move tasks to replacement status: %w", err)
+		}
+
+		kanbansQuery := fmt.Sprintf(`
+            UPDATE %s SET status_id = $1 WHERE status_id = $2;
+        `, database.KanbanTable)
+		if _, err := conn.ExecContext(ctx, kanbansQuery, *replacementID, id); err != nil {
+			return fmt.Errorf("move kanbans to replacement status: %w", err)
+		}
+	}
+
+	q := fmt.Sprintf(`
+        DELETE FROM %s WHERE id = $1;
+    `, database.WorkflowStatusesTable)
+
+	if _, err := conn.ExecContext(ctx, q, id); err != nil {
+		if isForeignKeyViolation(err) {
+			return fmt.Errorf("%w: status %d still holds tasks, give a replacement status", domain_error.ErrConflict, id)
+		}
+		return fmt.Errorf("delete status: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresWorkflowRepository) SetStatusPositions(ctx context.Context, projectID int, statusIDs []int) error {
+	q := fmt.Sprintf(`
+        UPDATE %s ws SET position = item.position - 1
+        FROM unnest($2::INTEGER[]) WITH ORDINALITY AS item (status_id, position)
+        WHERE ws.id = item.status_id AND ws.project_id = $1;
+    `, database.WorkflowStatusesTable)
+
+	if _, err := database.Conn(ctx, r.db).ExecContext(ctx, q, projectID, pq.Array(statusIDs)); err != nil {
+		return fmt.Errorf("set status positions: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresWorkflowRepository) GetWorkflow(ctx context.Context, projectID int) (*entity.Workflow, error) {
+	conn := database.Conn(ctx, r.db)
+
+	q := fmt.Sprintf(`
+        SELECT %s FROM %s ws WHERE ws.project_id = $1 ORDER BY ws.position, ws.id;
+    `, statusColumns, database.WorkflowStatusesTable)
+
+	rows, err := conn.QueryContext(ctx, q, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("get statuses: %w", err)
+	}
+	defer rows.Close()
+
+	workflow := &entity.Workflow{}
+	for rows.Next() {
+		s, err := scanStatus(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan status: %w", err)
+		}
+		workflow.Statuses = append(workflow.Statuses, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	transitionsQuery := fmt.Sprintf(`
+        SELECT wt.from_status_id, wt.to_status_id
+        FROM %s wt
+        JOIN %s ws ON ws.id = wt.from_status_id
+        WHERE ws.project_id = $1
+        ORDER BY wt.from_status_id, wt.to_status_id;
+    `, database.WorkflowTransitionsTable, database.WorkflowStatusesTable)
+
+	transitionRows, err := conn.QueryContext(ctx, transitionsQuery, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("get transitions: %w", err)
+	}
+	defer transitionRows.Close()
+
+	for transitionRows.Next() {
+		var t entity.WorkflowTransition
+		if err := transitionRows.Scan(&t.FromStatusID, &t.ToStatusID); err != nil {
+			return nil, fmt.Errorf("scan transition: %w", err)
+		}
+		workflow.Transitions = append(workflow.Transitions, &t)
+	}
+	if err := transitionRows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return workflow, nil
+}
+
+func (r *PostgresWorkflowRepository) SetTransitions(ctx context.Context, projectID int, transitions []*entity.WorkflowTransition) error {
+	conn := database.Conn(ctx, r.db)
+
+	deleteQuery := fmt.Sprintf(`
+        DELETE FROM %s wt USING %s ws WHERE ws.id = wt.from_status_id AND ws.project_id = $1;
+    `, database.WorkflowTransitionsTable, database.WorkflowStatusesTable)
+	if _, err := conn.ExecContext(ctx, deleteQuery, projectID); err != nil {
+		return fmt.Errorf("delete transitions: %w", err)
+	}
+	if len(transitions) == 0 {
+		return nil
+	}
+
+	from := make([]int64, 0, len(transitions))
+	to := make([]int64, 0, len(transitions))
+	for _, t := range transitions {
+		from = append(from, int64(t.FromStatusID))
+		to = append(to, int64(t.ToStatusID))
+	}
+
+	insertQuery := fmt.Sprintf(`
+        INSERT INTO %s (from_status_id, to_status_id)
+        SELECT * FROM unnest($1::INTEGER[], $2::INTEGER[])
+        ON CONFLICT DO NOTHING;
+    `, database.WorkflowTransitionsTable)
+	if _, err := conn.ExecContext(ctx, insertQuery, pq.Int64Array(from), pq.Int64Array(to)); err != nil {
+		return fmt.Errorf("create transitions: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresWorkflowRepository) GetColumns(ctx context.Context, projectID int) ([]*entity.WorkflowColumn, error) {
+	q := fmt.Sprintf(`
+        SELECT id, name, status_id FROM %s WHERE project_id = $1 AND deleted_at IS NULL ORDER BY position, id;
+    `, database.KanbanTable)
+
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, q, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("get columns: %w", err)
+	}
+	defer rows.Close()
+
+	var columns []*entity.WorkflowColumn
+	for rows.Next() {
+		var c entity.WorkflowColumn
+		if err := rows.Scan(&c.KanbanID, &c.Name, &c.StatusID); err != nil {
+			return nil, fmt.Errorf("scan column: %w", err)
+		}
+		columns = append(columns, &c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return columns, nil
+}
+
+func (r *PostgresWorkflowRepository) GetKanbanStatusID(ctx context.Context, kanbanID int) (*int, error) {
+	q := fmt.Sprintf(`
+        SELECT status_id FROM %s WHERE id = $1 AND deleted_at IS NULL;
+    `, database.KanbanTable)
+
+	var statusID *int
+	if err := database.Conn(ctx, r.db).QueryRowContext(ctx, q, kanbanID).Scan(&statusID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: kanban %d", domain_error.ErrNotFound, kanbanID)
+		}
+		return nil, fmt.Errorf("get kanban status: %w", err)
+	}
+	return statusID, nil
+}
+
+func (r *PostgresWorkflowRepository) SetKanbanStatus(ctx context.Context, kanbanID int, statusID *int) error {
+	q := fmt.Sprintf(`
+        UPDATE %s SET status_id = $1, updated_at = NOW() WHERE id = $2 AND deleted_at IS NULL;
+    `, database.KanbanTable)
+
+	res, err := database.Conn(ctx, r.db).ExecContext(ctx, q, statusID, kanbanID)
+	if err != nil {
+		return fmt.Errorf("set kanban status: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("set kanban status: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("%w: kanban %d", domain_error.ErrNotFound, kanbanID)
+	}
+	return nil
+}
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
+
+func isForeignKeyViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23503"
+}
@@ -0,0 +1,30 @@
+package workflow_repository
+
+import (
+	"DataTask/internal/domain/entity"
+	"context"
+)
+
+type WorkflowRepository interface {
+	// CreateDefaultStatuses gives a new project the entity.DefaultWorkflowStatuses.
+	CreateDefaultStatuses(ctx context.Context, projectID int) error
+	// CreateStatus appends a status to the end of the project's workflow.
+	CreateStatus(ctx context.Context, status *entity.WorkflowStatus) (*entity.WorkflowStatus, error)
+	GetStatusByID(ctx context.Context, id int) (*entity.WorkflowStatus, error)
+	// UpdateStatus renames or recategorizes a status; tasks in it are completed or reopened
+	// with the category.
+	UpdateStatus(ctx context.Context, status *entity.WorkflowStatus) (*entity.WorkflowStatus, error)
+	// DeleteStatus moves the tasks and kanbans of the status to the replacement before deleting it.
+	// Without a replacement a status still holding tasks is not deleted.
+	DeleteStatus(ctx context.Context, id int, replacementID *int) error
+	SetStatusPositions(ctx context.Context, projectID int, statusIDs []int) error
+
+	// GetWorkflow returns the project's statuses, in order, and its transitions.
+	GetWorkflow(ctx context.Context, projectID int) (*entity.Workflow, error)
+	// SetTransitions replaces the transitions of the project.
+	SetTransitions(ctx context.Context, projectID int, transitions []*entity.WorkflowTransition) error
+
+	GetColumns(ctx context.Context, projectID int) ([]*entity.WorkflowColumn, error)
+	GetKanbanStatusID(ctx context.Context, kanbanID int) (*int, error)
+	SetKanbanStatus(ctx context.Context, kanbanID int, statusID *int) error
+}
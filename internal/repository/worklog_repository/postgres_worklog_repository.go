@@ -0,0 +1,245 @@
+package worklog_repository
+
+import (
+	"DataTask/internal/domain/domain_error"
+	"DataTask/internal/domain/entity"
+	"DataTask/internal/repository/database"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/lib/pq"
+	"strings"
+	"time"
+)
+
+type PostgresWorklogRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresWorklogRepository(db *sql.DB) *PostgresWorklogRepository {
+	return &PostgresWorklogRepository{db: db}
+}
+
+const worklogColumns = `id, task_id, user_id, started_at, ended_at, note, source, created_at, updated_at`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanWorklog(row rowScanner) (*entity.Worklog, error) {
+	var w entity.Worklog
+	err := row.Scan(
+		&w.ID, &w.TaskID, &w.UserID, &w.StartedAt, &w.EndedAt, &w.Note, &w.Source, &w.CreatedAt, &w.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+func (r *PostgresWorklogRepository) CreateWorklog(ctx context.Context, worklog *entity.Worklog) (*entity.Worklog, error) {
+	q := fmt.Sprintf(`
+        INSERT INTO %s (task_id, user_id, started_at, ended_at, note, source)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        RETURNING %s;
+    `, database.WorklogsTable, worklogColumns)
+
+	created, err := scanWorklog(database.Conn(ctx, r.db).QueryRowContext(ctx, q,
+		worklog.TaskID, worklog.UserID, worklog.StartedAt, worklog.EndedAt, worklog.Note, worklog.Source,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("create worklog: %w", err)
+	}
+	return created, nil
+}
+
+func (r *PostgresWorklogRepository) GetWorklogByID(ctx context.Context, id int) (*entity.Worklog, error) {
+	q := fmt.Sprintf(`
+        SELECT %s FROM %s WHERE id = $1;
+    `, worklogColumns, database.WorklogsTable)
+
+	worklog, err := scanWorklog(database.Conn(ctx, r.db).QueryRowContext(ctx, q, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: worklog %d", domain_error.ErrNotFound, id)
+		}
+		return nil, fmt.Errorf("get worklog by id: %w", err)
+	}
+	return worklog, nil
+}
+
+func (r *PostgresWorklogRepository) UpdateWorklog(ctx context.Context, worklog *entity.Worklog) (*entity.Worklog, error) {
+	q := fmt.Sprintf(`
+        UPDATE %s SET started_at = $1, ended_at = $2, note = $3
+        WHERE id = $4
+        RETURNING %s;
+    `, database.WorklogsTable, worklogColumns)
+
+	updated, err := scanWorklog(database.Conn(ctx, r.db).QueryRowContext(ctx, q,
+		worklog.StartedAt, worklog.EndedAt, worklog.Note, worklog.ID,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("update worklog: %w", err)
+	}
+	return updated, nil
+}
+
+func (r *PostgresWorklogRepository) DeleteWorklog(ctx context.Context, id int) error {
+	q := fmt.Sprintf(`
+        DELETE FROM %s WHERE id = $1;
+    `, database.WorklogsTable)
+
+	if _, err := database.Conn(ctx, r.db).ExecContext(ctx, q, id); err != nil {
+		return fmt.Errorf("delete worklog: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresWorklogRepository) GetWorklogsByTaskID(ctx context.Context, taskID int, from *time.Time, to *time.Time) ([]*entity.Worklog, error) {
+	q := fmt.Sprintf(`
+        SELECT %s FROM %s
+        WHERE task_id = $1
+          AND ($2::TIMESTAMPTZ IS NULL OR started_at >= $2)
+          AND ($3::TIMESTAMPTZ IS NULL OR started_at < $3)
+        ORDER BY started_at, id;
+    `, worklogColumns, database.WorklogsTable)
+
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, q, taskID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("get worklogs by task id: %w", err)
+	}
+	defer rows.Close()
+
+	var worklogs []*entity.Worklog
+	for rows.Next() {
+		worklog, err := scanWorklog(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan worklog: %w", err)
+		}
+		worklogs = append(worklogs, worklog)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return worklogs, nil
+}
+
+func (r *PostgresWorklogRepository) StartTimer(ctx context.Context, taskID int, userID int) (*entity.Worklog, error) {
+	q := fmt.Sprintf(`
+        INSERT INTO %s (task_id, user_id, started_at, source)
+        VALUES ($1, $2, NOW(), '%s')
+        RETURNING %s;
+    `, database.WorklogsTable, entity.WorklogSourceTimer, worklogColumns)
+
+	timer, err := scanWorklog(database.Conn(ctx, r.db).QueryRowContext(ctx, q, taskID, userID))
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return nil, fmt.Errorf("%w: user %d already has a running timer", domain_error.ErrConflict, userID)
+		}
+		return nil, fmt.Errorf("start timer: %w", err)
+	}
+	return timer, nil
+}
+
+func (r *PostgresWorklogRepository) GetRunningTimer(ctx context.Context, userID int) (*entity.Worklog, error) {
+	q := fmt.Sprintf(`
+        SELECT %s FROM %s WHERE user_id = $1 AND ended_at IS NULL;
+    `, worklogColumns, database.WorklogsTable)
+
+	timer, err := scanWorklog(database.Conn(ctx, r.db).QueryRowContext(ctx, q, userID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: no running timer", domain_error.ErrNotFound)
+		}
+		return nil, fmt.Errorf("get running timer: %w", err)
+	}
+	return timer, nil
+}
+
+func (r *PostgresWorklogRepository) StopTimer(ctx context.Context, id int, note string) (*entity.Worklog, error) {
+	q := fmt.Sprintf(`
+        UPDATE %s SET ended_at = NOW(), note = $2
+        WHERE id = $1 AND ended_at IS NULL
+        RETURNING %s;
+    `, database.WorklogsTable, worklogColumns)
+
+	stopped, err := scanWorklog(database.Conn(ctx, r.db).QueryRowContext(ctx, q, id, note))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: timer %d is not running", domain_error.ErrConflict, id)
+		}
+		return nil, fmt.Errorf("stop timer: %w", err)
+	}
+	return stopped, nil
+}
+
+func (r *PostgresWorklogRepository) StopTimersOnTask(ctx context.Context, taskID int) error {
+	q := fmt.Sprintf(`
+        UPDATE %s SET ended_at = NOW()
+        WHERE task_id = $1 AND ended_at IS NULL;
+    `, database.WorklogsTable)
+
+	if _, err := database.Conn(ctx, r.db).ExecContext(ctx, q, taskID); err != nil {
+		return fmt.Errorf("stop timers on task: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresWorklogRepository) GetTimeTotals(ctx context.Context, filter *entity.TimeTotalsFilter) ([]*entity.TimeTotalRow, error) {
+	// Time booked on trashed tasks stays out of the totals until the task is restored.
+	conds := []string{"t.deleted_at IS NULL"}
+	var args []any
+	addCond := func(format string, value any) {
+		args = append(args, value)
+		conds = append(conds, fmt.Sprintf(format, len(args)))
+	}
+	if filter.ProjectID != nil {
+		addCond("k.project_id = $%d", *filter.ProjectID)
+	}
+	if filter.TaskID != nil {
+		addCond("w.task_id = $%d", *filter.TaskID)
+	}
+	if filter.UserID != nil {
+		addCond("w.user_id = $%d", *filter.UserID)
+	}
+	if filter.From != nil {
+		addCond("w.started_at >= $%d", *filter.From)
+	}
+	if filter.To != nil {
+		addCond("w.started_at < $%d", *filter.To)
+	}
+
+	where := "WHERE " + strings.Join(conds, " AND ")
+
+	q := fmt.Sprintf(`
+        SELECT w.task_id, t.title, w.user_id,
+            SUM(EXTRACT(EPOCH FROM (COALESCE(w.ended_at, NOW()) - w.started_at)))::BIGINT
+        FROM %s w
+        JOIN %s t ON t.id = w.task_id
+        JOIN %s k ON k.id = t.kanban_id
+        %s
+        GROUP BY w.task_id, t.title, w.user_id
+        ORDER BY w.task_id, w.user_id;
+    `, database.WorklogsTable, database.TaskTable, database.KanbanTable, where)
+
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("get time totals: %w", err)
+	}
+	defer rows.Close()
+
+	var totals []*entity.TimeTotalRow
+	for rows.Next() {
+		var row entity.TimeTotalRow
+		if err := rows.Scan(&row.TaskID, &row.TaskTitle, &row.UserID, &row.Seconds); err != nil {
+			return nil, fmt.Errorf("scan time total: %w", err)
+		}
+		totals = append(totals, &row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return totals, nil
+}
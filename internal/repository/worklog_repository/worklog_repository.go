@@ -0,0 +1,24 @@
+package worklog_repository
+
+import (
+	"DataTask/internal/domain/entity"
+	"context"
+	"time"
+)
+
+type WorklogRepository interface {
+	CreateWorklog(ctx context.Context, worklog *entity.Worklog) (*entity.Worklog, error)
+	GetWorklogByID(ctx context.Context, id int) (*entity.Worklog, error)
+	UpdateWorklog(ctx context.Context, worklog *entity.Worklog) (*entity.Worklog, error)
+	DeleteWorklog(ctx context.Context, id int) error
+	GetWorklogsByTaskID(ctx context.Context, taskID int, from *time.Time, to *time.Time) ([]*entity.Worklog, error)
+
+	// StartTimer fails with domain_error.ErrConflict when the user already has a running timer.
+	StartTimer(ctx context.Context, taskID int, userID int) (*entity.Worklog, error)
+	GetRunningTimer(ctx context.Context, userID int) (*entity.Worklog, error)
+	StopTimer(ctx context.Context, id int, note string) (*entity.Worklog, error)
+	StopTimersOnTask(ctx context.Context, taskID int) error
+
+	// GetTimeTotals sums the worklogs per task and user. Running timers count up to now.
+	GetTimeTotals(ctx context.Context, filter *entity.TimeTotalsFilter) ([]*entity.TimeTotalRow, error)
+}
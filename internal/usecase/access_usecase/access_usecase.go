@@ -0,0 +1,16 @@
+package access_usecase
+
+import "context"
+
+// AccessUseCase checks the authenticated user's permission on projects and on the
+// kanbans and tasks inside them. Every method returns an error wrapping
+// domain_error.ErrForbidden when the user lacks the required permission.
+type AccessUseCase interface {
+	CurrentUserID(ctx context.Context) (int, error)
+	RequireProjectPermission(ctx context.Context, projectID int, permission string) error
+	RequireKanbanPermission(ctx context.Context, kanbanID int, permission string) error
+	// RequireTaskPermission checks the task's project and returns its ID.
+	RequireTaskPermission(ctx context.Context, taskID int, permission string) (int, error)
+	// IsProjectMember reports whether userID owns the project or was invited to it.
+	IsProjectMember(ctx context.Context, projectID int, userID int) (bool, error)
+}
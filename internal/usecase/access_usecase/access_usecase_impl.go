@@ -0,0 +1,69 @@
+package access_usecase
+
+import (
+	"DataTask/internal/domain/domain_error"
+	"DataTask/internal/domain/entity"
+	"DataTask/internal/repository/project_repository"
+	"context"
+	"fmt"
+)
+
+type AccessUseCaseImpl struct {
+	repo project_repository.ProjectRepository
+}
+
+func NewAccessUseCase(repo project_repository.ProjectRepository) *AccessUseCaseImpl {
+	return &AccessUseCaseImpl{repo: repo}
+}
+
+func (uc *AccessUseCaseImpl) CurrentUserID(ctx context.Context) (int, error) {
+	userID, ok := ctx.Value("user_id").(int)
+	if !ok || userID == 0 {
+		return 0, domain_error.ErrUnauthenticated
+	}
+	return userID, nil
+}
+
+func (uc *AccessUseCaseImpl) RequireProjectPermission(ctx context.Context, projectID int, permission string) error {
+	userID, err := uc.CurrentUserID(ctx)
+	if err != nil {
+		return err
+	}
+
+	granted, err := uc.repo.GetEffectiveProjectPermission(ctx, projectID, userID)
+	if err != nil {
+		return err
+	}
+
+	if !entity.PermissionAllows(granted, permission) {
+		return fmt.Errorf("%w: %s permission on project %d is required", domain_error.ErrForbidden, permission, projectID)
+	}
+	return nil
+}
+
+func (uc *AccessUseCaseImpl) RequireKanbanPermission(ctx context.Context, kanbanID int, permission string) error {
+	projectID, err := uc.repo.GetProjectIDByKanbanID(ctx, kanbanID)
+	if err != nil {
+		return err
+	}
+	return uc.RequireProjectPermission(ctx, projectID, permission)
+}
+
+func (uc *AccessUseCaseImpl) RequireTaskPermission(ctx context.Context, taskID int, permission string) (int, error) {
+	projectID, err := uc.repo.GetProjectIDByTaskID(ctx, taskID)
+	if err != nil {
+		return 0, err
+	}
+	if err := uc.RequireProjectPermission(ctx, projectID, permission); err != nil {
+		return 0, err
+	}
+	return projectID, nil
+}
+
+func (uc *AccessUseCaseImpl) IsProjectMember(ctx context.Context, projectID int, userID int) (bool, error) {
+	granted, err := uc.repo.GetEffectiveProjectPermission(ctx, projectID, userID)
+	if err != nil {
+		return false, err
+	}
+	return granted != "", nil
+}
@@ -0,0 +1,27 @@
+package assignee_usecase
+
+import (
+	"DataTask/internal/domain/dto"
+	"context"
+)
+
+type AssigneeUseCase interface {
+	GetAssignees(ctx context.Context, taskID int) ([]*dto.User, error)
+	// AddAssignee assigns a project member to the task and returns the new assignee list.
+	AddAssignee(ctx context.Context, taskID int, userID int) ([]*dto.User, error)
+	RemoveAssignee(ctx context.Context, taskID int, userID int) error
+	// ReplaceAssignees makes userIDs the complete assignee list of the task.
+	ReplaceAssignees(ctx context.Context, taskID int, userIDs []int) ([]*dto.User, error)
+
+	GetWatchers(ctx context.Context, taskID int) ([]*dto.User, error)
+	// AddWatcher subscribes a project member, or the current user when userID is 0, to the
+	// task's changes and returns the new watcher list.
+	AddWatcher(ctx context.Context, taskID int, userID int) ([]*dto.User, error)
+	RemoveWatcher(ctx context.Context, taskID int, userID int) error
+
+	// GetAssigneesByTaskIDs loads the assignees of task lists, without permission checks.
+	GetAssigneesByTaskIDs(ctx context.Context, taskIDs []int) (map[int][]*dto.User, error)
+	// NotifyTaskChanged tells the task's assignees and watchers, except the current user,
+	// about a change. Delivery failures are logged, not returned.
+	NotifyTaskChanged(ctx context.Context, taskID int, message string)
+}
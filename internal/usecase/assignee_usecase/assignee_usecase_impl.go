@@ -0,0 +1,400 @@
+package assignee_usecase
+
+import (
+	"DataTask/internal/domain/domain_error"
+	"DataTask/internal/domain/dto"
+	"DataTask/internal/domain/entity"
+	"DataTask/internal/notifier"
+	"DataTask/internal/repository/assignee_repository"
+	"DataTask/internal/repository/database"
+	"DataTask/internal/repository/task_repository"
+	"DataTask/internal/usecase/access_usecase"
+	"DataTask/internal/usecase/history_usecase"
+	"DataTask/pkg/logger"
+	"context"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"sort"
+	"time"
+)
+
+type AssigneeUseCaseImpl struct {
+	repo       assignee_repository.AssigneeRepository
+	taskRepo   task_repository.TaskRepository
+	access     access_usecase.AccessUseCase
+	history    history_usecase.HistoryUseCase
+	notifier   notifier.Notifier
+	transactor database.Transactor
+}
+
+func NewAssigneeUseCase(
+	repo assignee_repository.AssigneeRepository,
+	taskRepo task_repository.TaskRepository,
+	access access_usecase.AccessUseCase,
+	history history_usecase.HistoryUseCase,
+	notifier notifier.Notifier,
+	transactor database.Transactor,
+) *AssigneeUseCaseImpl {
+	return &AssigneeUseCaseImpl{
+		repo:       repo,
+		taskRepo:   taskRepo,
+		access:     access,
+		history:    history,
+		notifier:   notifier,
+		transactor: transactor,
+	}
+}
+
+func (uc *AssigneeUseCaseImpl) GetAssignees(ctx context.Context, taskID int) ([]*dto.User, error) {
+	if _, err := uc.access.RequireTaskPermission(ctx, taskID, entity.PermissionRead); err != nil {
+		return nil, err
+	}
+
+	assignees, err := uc.repo.GetAssignees(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	return toUserDTOs(assignees), nil
+}
+
+func (uc *AssigneeUseCaseImpl) AddAssignee(ctx context.Context, taskID int, userID int) ([]*dto.User, error) {
+	projectID, err := uc.access.RequireTaskPermission(ctx, taskID, entity.PermissionEdit)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.requireMember(ctx, projectID, userID); err != nil {
+		return nil, err
+	}
+
+	err = uc.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		before, err := uc.repo.GetAssignees(ctx, taskID)
+		if err != nil {
+			return err
+		}
+		added, err := uc.repo.AddAssignee(ctx, taskID, userID)
+		if err != nil {
+			return err
+		}
+		if !added {
+			return fmt.Errorf("%w: user %d is already assigned to task %d", domain_error.ErrConflict, userID, taskID)
+		}
+		return uc.recordAssigneeChange(ctx, taskID, before)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	uc.notifyAssignmentChanges(ctx, taskID, []int{userID}, nil)
+	return uc.assignees(ctx, taskID)
+}
+
+func (uc *AssigneeUseCaseImpl) RemoveAssignee(ctx context.Context, taskID int, userID int) error {
+	if _, err := uc.access.RequireTaskPermission(ctx, taskID, entity.PermissionEdit); err != nil {
+		return err
+	}
+
+	err := uc.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		before, err := uc.repo.GetAssignees(ctx, taskID)
+		if err != nil {
+			return err
+		}
+		removed, err := uc.repo.RemoveAssignee(ctx, taskID, userID)
+		if err != nil {
+			return err
+		}
+		if !removed {
+			return fmt.Errorf("%w: user %d is not assigned to task %d", domain_error.ErrNotFound, userID, taskID)
+		}
+		return uc.recordAssigneeChange(ctx, taskID, before)
+	})
+	if err != nil {
+		return err
+	}
+
+	uc.notifyAssignmentChanges(ctx, taskID, nil, []int{userID})
+	return nil
+}
+
+func (uc *AssigneeUseCaseImpl) ReplaceAssignees(ctx context.Context, taskID int, userIDs []int) ([]*dto.User, error) {
+	projectID, err := uc.access.RequireTaskPermission(ctx, taskID, entity.PermissionEdit)
+	if err != nil {
+		return nil, err
+	}
+
+	wanted := make(map[int]bool, len(userIDs))
+	for _, userID := range userIDs {
+		if wanted[userID] {
+			return nil, fmt.Errorf("%w: user %d is listed twice", domain_error.ErrValidation, userID)
+		}
+		if err := uc.requireMember(ctx, projectID, userID); err != nil {
+			return nil, err
+		}
+		wanted[userID] = true
+	}
+
+	var added, removed []int
+	err = uc.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		current, err := uc.repo.GetAssignees(ctx, taskID)
+		if err != nil {
+			return err
+		}
+
+		assigned := make(map[int]bool, len(current))
+		for _, u := range current {
+			assigned[u.ID] = true
+			if wanted[u.ID] {
+				continue
+			}
+			if _, err := uc.repo.RemoveAssignee(ctx, taskID, u.ID); err != nil {
+				return err
+			}
+			removed = append(removed, u.ID)
+		}
+		for _, userID := range userIDs {
+			if assigned[userID] {
+				continue
+			}
+			if _, err := uc.repo.AddAssignee(ctx, taskID, userID); err != nil {
+				return err
+			}
+			added = append(added, userID)
+		}
+		return uc.recordAssigneeChange(ctx, taskID, current)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	uc.notifyAssignmentChanges(ctx, taskID, added, removed)
+	return uc.assignees(ctx, taskID)
+}
+
+func (uc *AssigneeUseCaseImpl) GetWatchers(ctx context.Context, taskID int) ([]*dto.User, error) {
+	if _, err := uc.access.RequireTaskPermission(ctx, taskID, entity.PermissionRead); err != nil {
+		return nil, err
+	}
+
+	watchers, err := uc.repo.GetWatchers(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	return toUserDTOs(watchers), nil
+}
+
+func (uc *AssigneeUseCaseImpl) AddWatcher(ctx context.Context, taskID int, userID int) ([]*dto.User, error) {
+	if userID == 0 {
+		currentUserID, err := uc.access.CurrentUserID(ctx)
+		if err != nil {
+			return nil, err
+		}
+		userID = currentUserID
+	}
+
+	projectID, err := uc.requireWatcherPermission(ctx, taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.requireMember(ctx, projectID, userID); err != nil {
+		return nil, err
+	}
+
+	added, err := uc.repo.AddWatcher(ctx, taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !added {
+		return nil, fmt.Errorf("%w: user %d already watches task %d", domain_error.ErrConflict, userID, taskID)
+	}
+
+	watchers, err := uc.repo.GetWatchers(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	return toUserDTOs(watchers), nil
+}
+
+func (uc *AssigneeUseCaseImpl) RemoveWatcher(ctx context.Context, taskID int, userID int) error {
+	if _, err := uc.requireWatcherPermission(ctx, taskID, userID); err != nil {
+		return err
+	}
+
+	removed, err := uc.repo.RemoveWatcher(ctx, taskID, userID)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return fmt.Errorf("%w: user %d does not watch task %d", domain_error.ErrNotFound, userID, taskID)
+	}
+	return nil
+}
+
+func (uc *AssigneeUseCaseImpl) GetAssigneesByTaskIDs(ctx context.Context, taskIDs []int) (map[int][]*dto.User, error) {
+	assigneesByTask, err := uc.repo.GetAssigneesByTaskIDs(ctx, taskIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[int][]*dto.User, len(taskIDs))
+	for _, taskID := range taskIDs {
+		result[taskID] = toUserDTOs(assigneesByTask[taskID])
+	}
+	return result, nil
+}
+
+func (uc *AssigneeUseCaseImpl) NotifyTaskChanged(ctx context.Context, taskID int, message string) {
+	uc.notify(ctx, taskID, entity.NotificationTaskChanged, message, nil)
+}
+
+// requireWatcherPermission lets users watch and unwatch tasks they can read; managing
+// other users' subscriptions needs edit permission.
+func (uc *AssigneeUseCaseImpl) requireWatcherPermission(ctx context.Context, taskID int, userID int) (int, error) {
+	currentUserID, err := uc.access.CurrentUserID(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	permission := entity.PermissionEdit
+	if userID == currentUserID {
+		permission = entity.PermissionRead
+	}
+	return uc.access.RequireTaskPermission(ctx, taskID, permission)
+}
+
+func (uc *AssigneeUseCaseImpl) requireMember(ctx context.Context, projectID int, userID int) error {
+	isMember, err := uc.access.IsProjectMember(ctx, projectID, userID)
+	if err != nil {
+		return err
+	}
+	if !isMember {
+		return fmt.Errorf("%w: user %d is not a member of the task's project", domain_error.ErrValidation, userID)
+	}
+	return nil
+}
+
+// recordAssigneeChange writes the assignee IDs before and after a change to the task history.
+func (uc *AssigneeUseCaseImpl) recordAssigneeChange(ctx context.Context, taskID int, before []*entity.User) error {
+	after, err := uc.repo.GetAssignees(ctx, taskID)
+	if err != nil {
+		return err
+	}
+	return uc.history.RecordChanges(ctx, taskID, []*entity.TaskChange{
+		{Field: entity.TaskHistoryFieldAssignees, OldValue: userIDs(before), NewValue: userIDs(after)},
+	})
+}
+
+func (uc *AssigneeUseCaseImpl) assignees(ctx context.Context, taskID int) ([]*dto.User, error) {
+	assignees, err := uc.repo.GetAssignees(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	return toUserDTOs(assignees), nil
+}
+
+// notifyAssignmentChanges tells added and removed users about their assignment and the
+// remaining subscribers that the assignees changed.
+func (uc *AssigneeUseCaseImpl) notifyAssignmentChanges(ctx context.Context, taskID int, added []int, removed []int) {
+	if len(added) == 0 && len(removed) == 0 {
+		return
+	}
+
+	notified := make(map[int]bool, len(added)+len(removed))
+	for _, userID := range added {
+		uc.notifyUser(ctx, taskID, userID, entity.NotificationTaskAssigned, "You were assigned to task %q")
+		notified[userID] = true
+	}
+	for _, userID := range removed {
+		uc.notifyUser(ctx, taskID, userID, entity.NotificationTaskUnassigned, "You were unassigned from task %q")
+		notified[userID] = true
+	}
+
+	uc.notify(ctx, taskID, entity.NotificationTaskChanged, "assignees changed", notified)
+}
+
+func (uc *AssigneeUseCaseImpl) notifyUser(ctx context.Context, taskID int, userID int, notificationType string, format string) {
+	if currentUserID, err := uc.access.CurrentUserID(ctx); err == nil && currentUserID == userID {
+		return
+	}
+	task, err := uc.taskRepo.GetTaskByID(ctx, taskID)
+	if err != nil {
+		logger.Log.WithFields(log.Fields{"task_id": taskID}).WithError(err).Error("failed to load task for notification")
+		return
+	}
+
+	uc.deliver(ctx, &entity.Notification{
+		Type:      notificationType,
+		UserID:    userID,
+		TaskID:    taskID,
+		Message:   fmt.Sprintf(format, task.Title),
+		CreatedAt: time.Now(),
+	})
+}
+
+// notify sends a notification to the assignees and watchers of the task, skipping the current
+// user and the users in skip.
+func (uc *AssigneeUseCaseImpl) notify(ctx context.Context, taskID int, notificationType string, message string, skip map[int]bool) {
+	subscriberIDs, err := uc.repo.GetSubscriberIDs(ctx, taskID)
+	if err != nil {
+		logger.Log.WithFields(log.Fields{"task_id": taskID}).WithError(err).Error("failed to load task subscribers")
+		return
+	}
+	if len(subscriberIDs) == 0 {
+		return
+	}
+	task, err := uc.taskRepo.GetTaskByID(ctx, taskID)
+	if err != nil {
+		logger.Log.WithFields(log.Fields{"task_id": taskID}).WithError(err).Error("failed to load task for notification")
+		return
+	}
+
+	currentUserID, _ := uc.access.CurrentUserID(ctx)
+	for _, userID := range subscriberIDs {
+		if userID == currentUserID || skip[userID] {
+			continue
+		}
+		uc.deliver(ctx, &entity.Notification{
+			Type:      notificationType,
+			UserID:    userID,
+			TaskID:    taskID,
+			Message:   fmt.Sprintf("Task %q: %s", task.Title, message),
+			Payload:   map[string]any{"changed_by": currentUserID},
+			CreatedAt: time.Now(),
+		})
+	}
+}
+
+// deliver sends the notification once the surrounding transaction, if any, has been committed.
+func (uc *AssigneeUseCaseImpl) deliver(ctx context.Context, notification *entity.Notification) {
+	database.AfterCommit(ctx, func(ctx context.Context) {
+		if err := uc.notifier.Notify(ctx, notification); err != nil {
+			logger.Log.WithFields(log.Fields{
+				"type":    notification.Type,
+				"user_id": notification.UserID,
+				"task_id": notification.TaskID,
+			}).WithError(err).Error("failed to deliver notification")
+		}
+	})
+}
+
+func userIDs(users []*entity.User) []int {
+	ids := make([]int, 0, len(users))
+	for _, u := range users {
+		ids = append(ids, u.ID)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+func toUserDTOs(users []*entity.User) []*dto.User {
+	result := make([]*dto.User, 0, len(users))
+	for _, u := range users {
+		result = append(result, &dto.User{
+			ID: u.ID,
+
+			Name:      u.Name,
+			Surname:   u.Surname,
+			Email:     u.Email,
+			AvatarURL: u.AvatarURL,
+		})
+	}
+	return result
+}
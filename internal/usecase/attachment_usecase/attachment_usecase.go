@@ -0,0 +1,22 @@
+package attachment_usecase
+
+import (
+	"DataTask/internal/domain/dto"
+	"context"
+	"io"
+)
+
+type AttachmentUseCase interface {
+	// UploadAttachment stores a file on a task, or on a comment when upload.CommentID is set.
+	UploadAttachment(ctx context.Context, upload *dto.AttachmentUpload) (*dto.Attachment, error)
+	GetAttachmentByID(ctx context.Context, id int) (*dto.Attachment, error)
+	GetAttachmentsByTaskID(ctx context.Context, taskID int) ([]*dto.Attachment, error)
+	GetAttachmentsByCommentID(ctx context.Context, commentID int) ([]*dto.Attachment, error)
+	// DownloadAttachment opens the attachment content. The caller closes the reader.
+	DownloadAttachment(ctx context.Context, id int) (*dto.Attachment, io.ReadCloser, error)
+	DeleteAttachment(ctx context.Context, id int) error
+
+	// PurgeDeletedBlobs removes the stored files of deleted attachments, including those
+	// removed together with their task or comment.
+	PurgeDeletedBlobs(ctx context.Context) error
+}
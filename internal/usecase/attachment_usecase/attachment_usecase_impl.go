@@ -0,0 +1,303 @@
+package attachment_usecase
+
+import (
+	"DataTask/internal/domain/domain_error"
+	"DataTask/internal/domain/dto"
+	"DataTask/internal/domain/entity"
+	"DataTask/internal/repository/attachment_repository"
+	"DataTask/internal/usecase/access_usecase"
+	"DataTask/pkg/blob"
+	"DataTask/pkg/logger"
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+const (
+	// sniffLength is the amount of content http.DetectContentType looks at.
+	sniffLength = 512
+	// purgeBatchSize bounds the blob deletions done in one worker run.
+	purgeBatchSize    = 100
+	maxFileNameLength = 255
+)
+
+type AttachmentUseCaseImpl struct {
+	repo         attachment_repository.AttachmentRepository
+	access       access_usecase.AccessUseCase
+	storage      blob.Storage
+	maxSize      int64
+	allowedTypes []string
+}
+
+func NewAttachmentUseCase(
+	repo attachment_repository.AttachmentRepository,
+	access access_usecase.AccessUseCase,
+	storage blob.Storage,
+	maxSize int64,
+	allowedTypes []string,
+) *AttachmentUseCaseImpl {
+	return &AttachmentUseCaseImpl{
+		repo:         repo,
+		access:       access,
+		storage:      storage,
+		maxSize:      maxSize,
+		allowedTypes: allowedTypes,
+	}
+}
+
+func (uc *AttachmentUseCaseImpl) UploadAttachment(ctx context.Context, upload *dto.AttachmentUpload) (*dto.Attachment, error) {
+	userID, err := uc.access.CurrentUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if upload.CommentID != nil {
+		// Comment attachments belong to the comment author, who only needs to see the task.
+		taskID, authorID, err := uc.repo.GetCommentTask(ctx, *upload.CommentID)
+		if err != nil {
+			return nil, err
+		}
+		if authorID != userID {
+			return nil, fmt.Errorf("%w: only the comment author can attach files to it", domain_error.ErrForbidden)
+		}
+		if _, err := uc.access.RequireTaskPermission(ctx, taskID, entity.PermissionRead); err != nil {
+			return nil, err
+		}
+		upload.TaskID = taskID
+	} else if _, err := uc.access.RequireTaskPermission(ctx, upload.TaskID, entity.PermissionEdit); err != nil {
+		return nil, err
+	}
+
+	if upload.Size <= 0 {
+		return nil, fmt.Errorf("%w: file is empty", domain_error.ErrValidation)
+	}
+	if uc.maxSize > 0 && upload.Size > uc.maxSize {
+		return nil, fmt.Errorf("%w: file exceeds the limit of %d bytes", domain_error.ErrValidation, uc.maxSize)
+	}
+	fileName, err := sanitizeFileName(upload.FileName)
+	if err != nil {
+		return nil, err
+	}
+
+	// The declared type of an upload is not trusted, the stored type comes from the content.
+	content := bufio.NewReaderSize(upload.Content, sniffLength)
+	head, err := content.Peek(sniffLength)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, bufio.ErrBufferFull) {
+		return nil, fmt.Errorf("read upload: %w", err)
+	}
+	contentType := http.DetectContentType(head)
+	if !uc.isAllowedType(contentType) {
+		return nil, fmt.Errorf("%w: file type %s is not allowed", domain_error.ErrValidation, contentType)
+	}
+
+	key, err := newStorageKey(upload.TaskID)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.storage.Put(ctx, key, content, upload.Size, contentType); err != nil {
+		return nil, fmt.Errorf("store attachment: %w", err)
+	}
+
+	created, err := uc.repo.CreateAttachment(ctx, &entity.Attachment{
+		TaskID:      upload.TaskID,
+		CommentID:   upload.CommentID,
+		FileName:    fileName,
+		ContentType: contentType,
+		Size:        upload.Size,
+		StorageKey:  key,
+		UploadedBy:  &userID,
+	})
+	if err != nil {
+		if delErr := uc.storage.Delete(ctx, key); delErr != nil {
+			logger.Log.WithFields(log.Fields{"key": key}).WithError(delErr).Error("failed to remove orphaned attachment")
+		}
+		return nil, err
+	}
+	return toAttachmentDTO(created), nil
+}
+
+func (uc *AttachmentUseCaseImpl) GetAttachmentByID(ctx context.Context, id int) (*dto.Attachment, error) {
+	attachment, err := uc.readableAttachment(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return toAttachmentDTO(attachment), nil
+}
+
+func (uc *AttachmentUseCaseImpl) GetAttachmentsByTaskID(ctx context.Context, taskID int) ([]*dto.Attachment, error) {
+	if _, err := uc.access.RequireTaskPermission(ctx, taskID, entity.PermissionRead); err != nil {
+		return nil, err
+	}
+
+	attachments, err := uc.repo.GetAttachmentsByTaskID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	return toAttachmentDTOs(attachments), nil
+}
+
+func (uc *AttachmentUseCaseImpl) GetAttachmentsByCommentID(ctx context.Context, commentID int) ([]*dto.Attachment, error) {
+	taskID, _, err := uc.repo.GetCommentTask(ctx, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := uc.access.RequireTaskPermission(ctx, taskID, entity.PermissionRead); err != nil {
+		return nil, err
+	}
+
+	attachments, err := uc.repo.GetAttachmentsByCommentID(ctx, commentID)
+	if err != nil {
+		return nil, err
+	}
+	return toAttachmentDTOs(attachments), nil
+}
+
+func (uc *AttachmentUseCaseImpl) DownloadAttachment(ctx context.Context, id int) (*dto.Attachment, io.ReadCloser, error) {
+	attachment, err := uc.readableAttachment(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	content, err := uc.storage.Get(ctx, attachment.StorageKey)
+	if err != nil {
+		if errors.Is(err, blob.ErrNotFound) {
+			return nil, nil, fmt.Errorf("%w: content of attachment %d", domain_error.ErrNotFound, id)
+		}
+		return nil, nil, fmt.Errorf("open attachment: %w", err)
+	}
+	return toAttachmentDTO(attachment), content, nil
+}
+
+func (uc *AttachmentUseCaseImpl) DeleteAttachment(ctx context.Context, id int) error {
+	userID, err := uc.access.CurrentUserID(ctx)
+	if err != nil {
+		return err
+	}
+	attachment, err := uc.repo.GetAttachmentByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	// Uploaders can remove their own files, anything else needs the project owner.
+	permission := entity.PermissionOwner
+	if attachment.UploadedBy != nil && *attachment.UploadedBy == userID {
+		permission = entity.PermissionEdit
+	}
+	if _, err := uc.access.RequireTaskPermission(ctx, attachment.TaskID, permission); err != nil {
+		return err
+	}
+
+	// The blob itself is queued for deletion by the database and removed by PurgeDeletedBlobs.
+	return uc.repo.DeleteAttachment(ctx, id)
+}
+
+func (uc *AttachmentUseCaseImpl) PurgeDeletedBlobs(ctx context.Context) error {
+	keys, err := uc.repo.GetQueuedBlobDeletions(ctx, purgeBatchSize)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		if err := uc.storage.Delete(ctx, key); err != nil {
+			// Keep the key queued so the next run retries it.
+			logger.Log.WithFields(log.Fields{"key": key}).WithError(err).Error("failed to delete attachment blob")
+			continue
+		}
+		if err := uc.repo.DequeueBlobDeletion(ctx, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (uc *AttachmentUseCaseImpl) readableAttachment(ctx context.Context, id int) (*entity.Attachment, error) {
+	attachment, err := uc.repo.GetAttachmentByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := uc.access.RequireTaskPermission(ctx, attachment.TaskID, entity.PermissionRead); err != nil {
+		return nil, err
+	}
+	return attachment, nil
+}
+
+// isAllowedType matches the media type against the allow-list, which may contain
+// wildcards such as "image/*". An empty allow-list accepts every type.
+func (uc *AttachmentUseCaseImpl) isAllowedType(contentType string) bool {
+	if len(uc.allowedTypes) == 0 {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, allowed := range uc.allowedTypes {
+		allowed = strings.ToLower(strings.TrimSpace(allowed))
+		if allowed == mediaType {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(allowed, "/*"); ok && strings.HasPrefix(mediaType, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// sanitizeFileName keeps the base name of the uploaded file without control characters.
+func sanitizeFileName(name string) (string, error) {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+
+	if name == "" || name == "." || name == "/" {
+		return "", fmt.Errorf("%w: file name is required", domain_error.ErrValidation)
+	}
+	if runes := []rune(name); len(runes) > maxFileNameLength {
+		name = string(runes[:maxFileNameLength])
+	}
+	return name, nil
+}
+
+func newStorageKey(taskID int) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate storage key: %w", err)
+	}
+	return fmt.Sprintf("tasks/%d/%s", taskID, hex.EncodeToString(b)), nil
+}
+
+func toAttachmentDTO(a *entity.Attachment) *dto.Attachment {
+	return &dto.Attachment{
+		ID:          a.ID,
+		TaskID:      a.TaskID,
+		CommentID:   a.CommentID,
+		FileName:    a.FileName,
+		ContentType: a.ContentType,
+		Size:        a.Size,
+		UploadedBy:  a.UploadedBy,
+		CreatedAt:   a.CreatedAt,
+	}
+}
+
+func toAttachmentDTOs(attachments []*entity.Attachment) []*dto.Attachment {
+	result := make([]*dto.Attachment, 0, len(attachments))
+	for _, a := range attachments {
+		result = append(result, toAttachmentDTO(a))
+	}
+	return result
+}
@@ -0,0 +1,12 @@
+package board_usecase
+
+import (
+	"DataTask/internal/domain/dto"
+	"context"
+)
+
+// BoardUseCase splits the tasks of a project's board into swimlanes by assignee, priority, label,
+// epic or custom field, and each lane into the kanban columns of the project.
+type BoardUseCase interface {
+	GetBoard(ctx context.Context, projectID int, query *dto.BoardQuery) (*dto.Board, error)
+}
@@ -0,0 +1,361 @@
+package board_usecase
+
+import (
+	"DataTask/internal/domain/domain_error"
+	"DataTask/internal/domain/dto"
+	"DataTask/internal/domain/entity"
+	"DataTask/internal/repository/custom_field_repository"
+	"DataTask/internal/repository/label_repository"
+	"DataTask/internal/repository/project_repository"
+	"DataTask/internal/repository/task_repository"
+	"DataTask/internal/usecase/access_usecase"
+	"DataTask/internal/usecase/kanban_usecase"
+	"DataTask/internal/usecase/task_usecase"
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// priorityLanes is the order of the lanes of boards split by priority.
+var priorityLanes = []string{
+	entity.PriorityUrgent, entity.PriorityHigh, entity.PriorityMedium, entity.PriorityLow, entity.PriorityNone,
+}
+
+type BoardUseCaseImpl struct {
+	taskRepo      task_repository.TaskRepository
+	labelRepo     label_repository.LabelRepository
+	fieldRepo     custom_field_repository.CustomFieldRepository
+	projectRepo   project_repository.ProjectRepository
+	kanbanUseCase kanban_usecase.KanbanUseCase
+	taskUseCase   task_usecase.TaskUseCase
+	access        access_usecase.AccessUseCase
+}
+
+func NewBoardUseCase(
+	taskRepo task_repository.TaskRepository,
+	labelRepo label_repository.LabelRepository,
+	fieldRepo custom_field_repository.CustomFieldRepository,
+	projectRepo project_repository.ProjectRepository,
+	kanbanUseCase kanban_usecase.KanbanUseCase,
+	taskUseCase task_usecase.TaskUseCase,
+	access access_usecase.AccessUseCase,
+) *BoardUseCaseImpl {
+	return &BoardUseCaseImpl{
+		taskRepo:      taskRepo,
+		labelRepo:     labelRepo,
+		fieldRepo:     fieldRepo,
+		projectRepo:   projectRepo,
+		kanbanUseCase: kanbanUseCase,
+		taskUseCase:   taskUseCase,
+		access:        access,
+	}
+}
+
+// lane is a swimlane a task belongs to.
+type lane struct {
+	key  string
+	name string
+}
+
+// grouping describes how the tasks of a board are split into lanes.
+type grouping struct {
+	// known lanes come first, in order, and are returned even without tasks when empty lanes are
+	// asked for. Lanes found on tasks only follow, ordered by less.
+	known []lane
+	less  func(a, b lane) bool
+	// none names the lane of tasks without a value, which comes last.
+	none    string
+	lanesOf func(t *dto.Task) []lane
+}
+
+func (uc *BoardUseCaseImpl) GetBoard(ctx context.Context, projectID int, query *dto.BoardQuery) (*dto.Board, error) {
+	if err := uc.access.RequireProjectPermission(ctx, projectID, entity.PermissionRead); err != nil {
+		return nil, err
+	}
+	if !entity.IsValidSwimlanes(query.Swimlanes) {
+		return nil, fmt.Errorf("%w: cannot split a board by %q", domain_error.ErrValidation, query.Swimlanes)
+	}
+
+	kanbans, err := uc.kanbanUseCase.GetKanbansByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := uc.taskUseCase.GetTasksByProjectID(ctx, projectID, query.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if kanbans == nil {
+		kanbans = []*dto.Kanban{}
+	}
+	columns := make(map[int]int, len(kanbans))
+	for i, k := range kanbans {
+		columns[k.ID] = i
+	}
+	// Tasks linked to the project through project_tasks alone have no column on the board.
+	onBoard := make([]*dto.Task, 0, len(tasks))
+	for _, t := range tasks {
+		if _, ok := columns[t.KanbanID]; ok {
+			onBoard = append(onBoard, t)
+		}
+	}
+
+	g, err := uc.grouping(ctx, projectID, query, onBoard)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.Board{
+		ProjectID: projectID,
+		Swimlanes: query.Swimlanes,
+		Columns:   kanbans,
+		Lanes:     splitTasks(g, onBoard, kanbans, columns, query.EmptyLanes),
+	}, nil
+}
+
+func (uc *BoardUseCaseImpl) grouping(ctx context.Context, projectID int, query *dto.BoardQuery, tasks []*dto.Task) (*grouping, error) {
+	g := &grouping{less: byName}
+	switch query.Swimlanes {
+	case entity.SwimlaneNone:
+		g.none = "All tasks"
+		g.lanesOf = func(*dto.Task) []lane { return nil }
+
+	case entity.SwimlaneAssignee:
+		if query.EmptyLanes {
+			members, err := uc.projectRepo.GetProjectMembers(ctx, projectID)
+			if err != nil {
+				return nil, err
+			}
+			for _, u := range members {
+				g.known = append(g.known, lane{key: strconv.Itoa(u.ID), name: userName(u.Name, u.Surname, u.Email)})
+			}
+			sort.SliceStable(g.known, func(i, j int) bool { return byName(g.known[i], g.known[j]) })
+		}
+		g.none = "Unassigned"
+		g.lanesOf = func(t *dto.Task) []lane {
+			lanes := make([]lane, 0, len(t.Assignees))
+			for _, u := range t.Assignees {
+				lanes = append(lanes, lane{key: strconv.Itoa(u.ID), name: userName(u.Name, u.Surname, u.Email)})
+			}
+			return lanes
+		}
+
+	case entity.SwimlanePriority:
+		for _, p := range priorityLanes {
+			g.known = append(g.known, lane{key: p, name: p})
+		}
+		g.lanesOf = func(t *dto.Task) []lane { return []lane{{key: t.Priority, name: t.Priority}} }
+
+	case entity.SwimlaneLabel:
+		if query.EmptyLanes {
+			labels, err := uc.labelRepo.GetAvailableLabels(ctx, projectID)
+			if err != nil {
+				return nil, err
+			}
+			for _, l := range labels {
+				g.known = append(g.known, lane{key: strconv.Itoa(l.ID), name: l.Name})
+			}
+			sort.SliceStable(g.known, func(i, j int) bool { return byName(g.known[i], g.known[j]) })
+		}
+		g.none = "No label"
+		g.lanesOf = func(t *dto.Task) []lane {
+			lanes := make([]lane, 0, len(t.Labels))
+			for _, l := range t.Labels {
+				lanes = append(lanes, lane{key: strconv.Itoa(l.ID), name: l.Name})
+			}
+			return lanes
+		}
+
+	case entity.SwimlaneEpic:
+		taskIDs := make([]int, 0, len(tasks))
+		for _, t := range tasks {
+			taskIDs = append(taskIDs, t.ID)
+		}
+		epics, err := uc.taskRepo.GetTaskEpics(ctx, taskIDs)
+		if err != nil {
+			return nil, err
+		}
+		g.none = "No epic"
+		g.lanesOf = func(t *dto.Task) []lane {
+			if e, ok := epics[t.ID]; ok {
+				return []lane{{key: strconv.Itoa(e.EpicID), name: e.EpicTitle}}
+			}
+			return nil
+		}
+
+	default:
+		return uc.fieldGrouping(ctx, projectID, query)
+	}
+	return g, nil
+}
+
+// fieldGrouping splits a board by a custom field of the project. Select fields keep the order of
+// their options and number fields are ordered by value.
+func (uc *BoardUseCaseImpl) fieldGrouping(ctx context.Context, projectID int, query *dto.BoardQuery) (*grouping, error) {
+	fieldID, err := strconv.Atoi(strings.TrimPrefix(query.Swimlanes, entity.SwimlaneFieldPrefix))
+	if err != nil {
+		return nil, fmt.Errorf("%w: cannot split a board by %q", domain_error.ErrValidation, query.Swimlanes)
+	}
+	fields, err := uc.fieldRepo.GetAvailableFields(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	var field *entity.CustomField
+	for _, f := range fields {
+		if f.ID == fieldID {
+			field = f
+		}
+	}
+	if field == nil {
+		return nil, fmt.Errorf("%w: custom field %d is not available in project %d",
+			domain_error.ErrValidation, fieldID, projectID)
+	}
+
+	g := &grouping{less: byName, none: "No " + field.Name}
+	switch field.Type {
+	case entity.CustomFieldSingleSelect, entity.CustomFieldMultiSelect:
+		for _, o := range field.Options {
+			g.known = append(g.known, lane{key: o, name: o})
+		}
+	case entity.CustomFieldNumber:
+		g.less = func(a, b lane) bool {
+			x, _ := strconv.ParseFloat(a.key, 64)
+			y, _ := strconv.ParseFloat(b.key, 64)
+			return x < y
+		}
+	case entity.CustomFieldUser:
+		members, err := uc.projectRepo.GetProjectMembers(ctx, projectID)
+		if err != nil {
+			return nil, err
+		}
+		names := make(map[string]string, len(members))
+		for _, u := range members {
+			key := strconv.Itoa(u.ID)
+			names[key] = userName(u.Name, u.Surname, u.Email)
+			if query.EmptyLanes {
+				g.known = append(g.known, lane{key: key, name: names[key]})
+			}
+		}
+		sort.SliceStable(g.known, func(i, j int) bool { return byName(g.known[i], g.known[j]) })
+		g.lanesOf = func(t *dto.Task) []lane {
+			lanes := fieldLanes(t, fieldID)
+			for i, l := range lanes {
+				if name, ok := names[l.key]; ok {
+					lanes[i].name = name
+				}
+			}
+			return lanes
+		}
+		return g, nil
+	}
+	g.lanesOf = func(t *dto.Task) []lane { return fieldLanes(t, fieldID) }
+	return g, nil
+}
+
+// fieldLanes returns the lanes of a task's value of a custom field, one per multi_select option.
+func fieldLanes(t *dto.Task, fieldID int) []lane {
+	for _, v := range t.CustomFields {
+		if v.FieldID != fieldID {
+			continue
+		}
+		switch value := v.Value.(type) {
+		case string:
+			return []lane{{key: value, name: value}}
+		case float64:
+			s := strconv.FormatFloat(value, 'f', -1, 64)
+			return []lane{{key: s, name: s}}
+		case int:
+			s := strconv.Itoa(value)
+			return []lane{{key: s, name: "User " + s}}
+		case []string:
+			lanes := make([]lane, 0, len(value))
+			for _, o := range value {
+				lanes = append(lanes, lane{key: o, name: o})
+			}
+			return lanes
+		}
+	}
+	return nil
+}
+
+// splitTasks puts every task into the cells of its lanes: the known lanes in order, then the other
+// lanes found on tasks and the lane of tasks without a value. Lanes without tasks are left out
+// unless emptyLanes is set.
+func splitTasks(g *grouping, tasks []*dto.Task, kanbans []*dto.Kanban, columns map[int]int, emptyLanes bool) []*dto.Swimlane {
+	newLane := func(l lane) *dto.Swimlane {
+		s := &dto.Swimlane{Key: l.key, Name: l.name, Cells: make([]*dto.BoardCell, 0, len(kanbans))}
+		for _, k := range kanbans {
+			s.Cells = append(s.Cells, &dto.BoardCell{KanbanID: k.ID, Tasks: []*dto.Task{}})
+		}
+		return s
+	}
+	add := func(s *dto.Swimlane, t *dto.Task) {
+		cell := s.Cells[columns[t.KanbanID]]
+		cell.Tasks = append(cell.Tasks, t)
+		cell.TaskCount++
+		s.TaskCount++
+	}
+
+	byKey := make(map[string]*dto.Swimlane, len(g.known))
+	known := make([]*dto.Swimlane, 0, len(g.known))
+	for _, l := range g.known {
+		if _, ok := byKey[l.key]; !ok {
+			byKey[l.key] = newLane(l)
+			known = append(known, byKey[l.key])
+		}
+	}
+	var found []*dto.Swimlane
+	var foundLanes []lane
+	none := newLane(lane{name: g.none})
+
+	for _, t := range tasks {
+		lanes := g.lanesOf(t)
+		if len(lanes) == 0 {
+			add(none, t)
+			continue
+		}
+		for _, l := range lanes {
+			s, ok := byKey[l.key]
+			if !ok {
+				s = newLane(l)
+				byKey[l.key] = s
+				found = append(found, s)
+				foundLanes = append(foundLanes, l)
+			}
+			add(s, t)
+		}
+	}
+
+	order := make([]int, len(found))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return g.less(foundLanes[order[i]], foundLanes[order[j]]) })
+
+	result := make([]*dto.Swimlane, 0, len(known)+len(found)+1)
+	for _, s := range known {
+		if emptyLanes || s.TaskCount > 0 {
+			result = append(result, s)
+		}
+	}
+	for _, i := range order {
+		result = append(result, found[i])
+	}
+	if emptyLanes || none.TaskCount > 0 {
+		result = append(result, none)
+	}
+	return result
+}
+
+func byName(a, b lane) bool {
+	return strings.ToLower(a.name) < strings.ToLower(b.name)
+}
+
+// userName is the full name of a user, or the email address when the user has no name.
+func userName(name string, surname string, email string) string {
+	if full := strings.TrimSpace(name + " " + surname); full != "" {
+		return full
+	}
+	return email
+}
@@ -0,0 +1,15 @@
+package checklist_usecase
+
+import (
+	"DataTask/internal/domain/dto"
+	"context"
+)
+
+type ChecklistUseCase interface {
+	CreateItem(ctx context.Context, item *dto.ChecklistItem) (*dto.ChecklistItem, error)
+	GetItemsByTaskID(ctx context.Context, taskID int) ([]*dto.ChecklistItem, error)
+	UpdateItem(ctx context.Context, taskID int, itemID int, update *dto.ChecklistItemUpdate) (*dto.ChecklistItem, error)
+	DeleteItem(ctx context.Context, taskID int, itemID int) error
+	// ReorderItems takes every item ID of the task's checklist in the new order.
+	ReorderItems(ctx context.Context, taskID int, itemIDs []int) ([]*dto.ChecklistItem, error)
+}
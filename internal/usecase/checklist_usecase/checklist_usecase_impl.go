@@ -0,0 +1,196 @@
+package checklist_usecase
+
+import (
+	"DataTask/internal/domain/domain_error"
+	"DataTask/internal/domain/dto"
+	"DataTask/internal/domain/entity"
+	"DataTask/internal/repository/checklist_repository"
+	"DataTask/internal/usecase/access_usecase"
+	"context"
+	"fmt"
+	"strings"
+)
+
+type ChecklistUseCaseImpl struct {
+	repo   checklist_repository.ChecklistRepository
+	access access_usecase.AccessUseCase
+}
+
+func NewChecklistUseCase(repo checklist_repository.ChecklistRepository, access access_usecase.AccessUseCase) *ChecklistUseCaseImpl {
+	return &ChecklistUseCaseImpl{
+		repo:   repo,
+		access: access,
+	}
+}
+
+func (uc *ChecklistUseCaseImpl) CreateItem(ctx context.Context, item *dto.ChecklistItem) (*dto.ChecklistItem, error) {
+	projectID, err := uc.access.RequireTaskPermission(ctx, item.TaskID, entity.PermissionEdit)
+	if err != nil {
+		return nil, err
+	}
+
+	entityItem := &entity.ChecklistItem{
+		TaskID:     item.TaskID,
+		Text:       strings.TrimSpace(item.Text),
+		IsDone:     item.IsDone,
+		AssigneeID: item.AssigneeID,
+	}
+	if err := uc.validateItem(ctx, projectID, entityItem); err != nil {
+		return nil, err
+	}
+
+	createdItem, err := uc.repo.CreateItem(ctx, entityItem)
+	if err != nil {
+		return nil, err
+	}
+	return toChecklistItemDTO(createdItem), nil
+}
+
+func (uc *ChecklistUseCaseImpl) GetItemsByTaskID(ctx context.Context, taskID int) ([]*dto.ChecklistItem, error) {
+	if _, err := uc.access.RequireTaskPermission(ctx, taskID, entity.PermissionRead); err != nil {
+		return nil, err
+	}
+
+	items, err := uc.repo.GetItemsByTaskID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	return toChecklistItemDTOs(items), nil
+}
+
+func (uc *ChecklistUseCaseImpl) UpdateItem(ctx context.Context, taskID int, itemID int, update *dto.ChecklistItemUpdate) (*dto.ChecklistItem, error) {
+	projectID, err := uc.access.RequireTaskPermission(ctx, taskID, entity.PermissionEdit)
+	if err != nil {
+		return nil, err
+	}
+
+	item, err := uc.getTaskItem(ctx, taskID, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	if update.Text != nil {
+		item.Text = strings.TrimSpace(*update.Text)
+	}
+	if update.IsDone != nil {
+		item.IsDone = *update.IsDone
+	}
+	if update.AssigneeID != nil {
+		item.AssigneeID = update.AssigneeID
+	}
+	if update.ClearAssignee {
+		item.AssigneeID = nil
+	}
+	if err := uc.validateItem(ctx, projectID, item); err != nil {
+		return nil, err
+	}
+
+	updatedItem, err := uc.repo.UpdateItem(ctx, item)
+	if err != nil {
+		return nil, err
+	}
+	return toChecklistItemDTO(updatedItem), nil
+}
+
+func (uc *ChecklistUseCaseImpl) DeleteItem(ctx context.Context, taskID int, itemID int) error {
+	if _, err := uc.access.RequireTaskPermission(ctx, taskID, entity.PermissionEdit); err != nil {
+		return err
+	}
+
+	if _, err := uc.getTaskItem(ctx, taskID, itemID); err != nil {
+		return err
+	}
+	return uc.repo.DeleteItem(ctx, itemID)
+}
+
+func (uc *ChecklistUseCaseImpl) ReorderItems(ctx context.Context, taskID int, itemIDs []int) ([]*dto.ChecklistItem, error) {
+	if _, err := uc.access.RequireTaskPermission(ctx, taskID, entity.PermissionEdit); err != nil {
+		return nil, err
+	}
+
+	items, err := uc.repo.GetItemsByTaskID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if err := sameItemSet(items, itemIDs); err != nil {
+		return nil, err
+	}
+
+	if err := uc.repo.ReorderItems(ctx, taskID, itemIDs); err != nil {
+		return nil, err
+	}
+
+	reordered, err := uc.repo.GetItemsByTaskID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	return toChecklistItemDTOs(reordered), nil
+}
+
+// getTaskItem loads the item and makes sure it belongs to the task from the URL.
+func (uc *ChecklistUseCaseImpl) getTaskItem(ctx context.Context, taskID int, itemID int) (*entity.ChecklistItem, error) {
+	item, err := uc.repo.GetItemByID(ctx, itemID)
+	if err != nil {
+		return nil, err
+	}
+	if item.TaskID != taskID {
+		return nil, fmt.Errorf("%w: checklist item %d of task %d", domain_error.ErrNotFound, itemID, taskID)
+	}
+	return item, nil
+}
+
+func (uc *ChecklistUseCaseImpl) validateItem(ctx context.Context, projectID int, item *entity.ChecklistItem) error {
+	if item.Text == "" {
+		return fmt.Errorf("%w: checklist item text is required", domain_error.ErrValidation)
+	}
+
+	if item.AssigneeID != nil {
+		isMember, err := uc.access.IsProjectMember(ctx, projectID, *item.AssigneeID)
+		if err != nil {
+			return err
+		}
+		if !isMember {
+			return fmt.Errorf("%w: user %d is not a member of project %d", domain_error.ErrValidation, *item.AssigneeID, projectID)
+		}
+	}
+	return nil
+}
+
+func sameItemSet(items []*entity.ChecklistItem, itemIDs []int) error {
+	if len(items) != len(itemIDs) {
+		return fmt.Errorf("%w: every checklist item must be listed exactly once", domain_error.ErrValidation)
+	}
+
+	known := make(map[int]bool, len(items))
+	for _, item := range items {
+		known[item.ID] = true
+	}
+	for _, id := range itemIDs {
+		if !known[id] {
+			return fmt.Errorf("%w: every checklist item must be listed exactly once", domain_error.ErrValidation)
+		}
+		delete(known, id)
+	}
+	return nil
+}
+
+func toChecklistItemDTO(item *entity.ChecklistItem) *dto.ChecklistItem {
+	return &dto.ChecklistItem{
+		ID:         item.ID,
+		TaskID:     item.TaskID,
+		Text:       item.Text,
+		IsDone:     item.IsDone,
+		Position:   item.Position,
+		AssigneeID: item.AssigneeID,
+		CreatedAt:  item.CreatedAt,
+		UpdatedAt:  item.UpdatedAt,
+	}
+}
+
+func toChecklistItemDTOs(items []*entity.ChecklistItem) []*dto.ChecklistItem {
+	dtoItems := make([]*dto.ChecklistItem, 0, len(items))
+	for _, item := range items {
+		dtoItems = append(dtoItems, toChecklistItemDTO(item))
+	}
+	return dtoItems
+}
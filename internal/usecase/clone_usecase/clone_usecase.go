@@ -0,0 +1,20 @@
+package clone_usecase
+
+import (
+	"DataTask/internal/domain/dto"
+	"context"
+)
+
+// CloneUseCase copies tasks with their subtasks, kanbans with their tasks and projects with their
+// kanbans, tasks and optionally subprojects. Every clone runs in a single transaction; clones of
+// more tasks than the configured threshold run as background jobs instead of right away.
+type CloneUseCase interface {
+	CloneTask(ctx context.Context, taskID int, options *dto.CloneOptions) (*dto.CloneResult, error)
+	CloneKanban(ctx context.Context, kanbanID int, options *dto.CloneOptions) (*dto.CloneResult, error)
+	CloneProject(ctx context.Context, projectID int, options *dto.CloneOptions) (*dto.CloneResult, error)
+	// GetJob returns a clone job started by the current user.
+	GetJob(ctx context.Context, id int) (*dto.CloneJob, error)
+
+	// RunQueuedJobs runs queued clone jobs one after another until none is left.
+	RunQueuedJobs(ctx context.Context) error
+}
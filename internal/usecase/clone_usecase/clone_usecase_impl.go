@@ -0,0 +1,426 @@
+package clone_usecase
+
+import (
+	"DataTask/internal/domain/domain_error"
+	"DataTask/internal/domain/dto"
+	"DataTask/internal/domain/entity"
+	"DataTask/internal/repository/clone_repository"
+	"DataTask/internal/repository/database"
+	"DataTask/internal/repository/kanban_repository"
+	"DataTask/internal/repository/project_repository"
+	"DataTask/internal/repository/task_repository"
+	"DataTask/internal/usecase/access_usecase"
+	"DataTask/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	maxNameLength = 255
+	copySuffix    = " (copy)"
+	// batchSize is how many tasks are copied at a time; jobs report their progress after each batch.
+	batchSize = 200
+	// staleJobAge is how long a job may run before it is taken for abandoned and claimed again.
+	staleJobAge = time.Hour
+)
+
+type CloneUseCaseImpl struct {
+	repo           clone_repository.CloneRepository
+	taskRepo       task_repository.TaskRepository
+	kanbanRepo     kanban_repository.KanbanRepository
+	projectRepo    project_repository.ProjectRepository
+	access         access_usecase.AccessUseCase
+	transactor     database.Transactor
+	asyncThreshold int // Clones of more tasks run as background jobs; zero runs every clone right away
+}
+
+func NewCloneUseCase(
+	repo clone_repository.CloneRepository,
+	taskRepo task_repository.TaskRepository,
+	kanbanRepo kanban_repository.KanbanRepository,
+	projectRepo project_repository.ProjectRepository,
+	access access_usecase.AccessUseCase,
+	transactor database.Transactor,
+	asyncThreshold int,
+) *CloneUseCaseImpl {
+	return &CloneUseCaseImpl{
+		repo:           repo,
+		taskRepo:       taskRepo,
+		kanbanRepo:     kanbanRepo,
+		projectRepo:    projectRepo,
+		access:         access,
+		transactor:     transactor,
+		asyncThreshold: asyncThreshold,
+	}
+}
+
+func (uc *CloneUseCaseImpl) CloneTask(ctx context.Context, taskID int, options *dto.CloneOptions) (*dto.CloneResult, error) {
+	return uc.clone(ctx, entity.CloneTask, taskID, options)
+}
+
+func (uc *CloneUseCaseImpl) CloneKanban(ctx context.Context, kanbanID int, options *dto.CloneOptions) (*dto.CloneResult, error) {
+	return uc.clone(ctx, entity.CloneKanban, kanbanID, options)
+}
+
+func (uc *CloneUseCaseImpl) CloneProject(ctx context.Context, projectID int, options *dto.CloneOptions) (*dto.CloneResult, error) {
+	return uc.clone(ctx, entity.CloneProject, projectID, options)
+}
+
+func (uc *CloneUseCaseImpl) GetJob(ctx context.Context, id int) (*dto.CloneJob, error) {
+	userID, err := uc.access.CurrentUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := uc.repo.GetJobByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	// Jobs of other users are not revealed.
+	if job.UserID != userID {
+		return nil, fmt.Errorf("%w: clone job %d", domain_error.ErrNotFound, id)
+	}
+	return toJobDTO(job), nil
+}
+
+func (uc *CloneUseCaseImpl) RunQueuedJobs(ctx context.Context) error {
+	for {
+		job, err := uc.repo.ClaimJob(ctx, time.Now().Add(-staleJobAge))
+		if err != nil {
+			return err
+		}
+		if job == nil {
+			return nil
+		}
+		uc.runJob(ctx, job)
+	}
+}
+
+func (uc *CloneUseCaseImpl) clone(
+	ctx context.Context,
+	cloneType string,
+	sourceID int,
+	options *dto.CloneOptions,
+) (*dto.CloneResult, error) {
+	userID, err := uc.access.CurrentUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := uc.plan(ctx, cloneType, sourceID, userID, toOptionsEntity(options))
+	if err != nil {
+		return nil, err
+	}
+
+	if uc.asyncThreshold > 0 && len(plan.TaskIDs) > uc.asyncThreshold {
+		job, err := uc.repo.CreateJob(ctx, &entity.CloneJob{
+			UserID:   userID,
+			Type:     cloneType,
+			SourceID: sourceID,
+			Options:  plan.Options,
+			Total:    len(plan.TaskIDs),
+		})
+		if err != nil {
+			return nil, err
+		}
+		return &dto.CloneResult{Type: cloneType, Job: toJobDTO(job)}, nil
+	}
+
+	id, err := uc.run(ctx, plan, 0)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.CloneResult{Type: cloneType, ResultID: &id}, nil
+}
+
+// runJob runs a claimed job on behalf of the user who started it, checking their permissions anew
+// since they may have changed while the job was queued.
+func (uc *CloneUseCaseImpl) runJob(ctx context.Context, job *entity.CloneJob) {
+	fields := log.Fields{"job_id": job.ID, "type": job.Type, "source_id": job.SourceID}
+	userCtx := context.WithValue(ctx, "user_id", job.UserID)
+
+	plan, err := uc.plan(userCtx, job.Type, job.SourceID, job.UserID, job.Options)
+	var id int
+	if err == nil {
+		id, err = uc.run(userCtx, plan, job.ID)
+	}
+
+	var resultID *int
+	var errMsg *string
+	if err != nil {
+		logger.Log.WithFields(fields).WithError(err).Warn("clone job failed")
+		msg := err.Error()
+		errMsg = &msg
+	} else {
+		logger.Log.WithFields(fields).WithField("result_id", id).Info("clone job completed")
+		resultID = &id
+	}
+
+	if err := uc.repo.FinishJob(ctx, job.ID, resultID, errMsg); err != nil {
+		logger.Log.WithFields(fields).WithError(err).Error("failed to finish clone job")
+	}
+}
+
+// run copies the plan in one transaction and returns the ID of the copy of its source. A non-zero
+// jobID receives the progress after each batch of tasks.
+func (uc *CloneUseCaseImpl) run(ctx context.Context, plan *entity.ClonePlan, jobID int) (int, error) {
+	var id int
+	err := uc.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := uc.repo.BeginClone(ctx); err != nil {
+			return err
+		}
+		if len(plan.ProjectIDs) > 0 {
+			if err := uc.repo.CloneProjects(ctx, plan); err != nil {
+				return err
+			}
+		}
+		if len(plan.KanbanIDs) > 0 {
+			if err := uc.repo.CloneKanbans(ctx, plan); err != nil {
+				return err
+			}
+		}
+
+		total := len(plan.TaskIDs)
+		for start := 0; start < total; start += batchSize {
+			end := min(start+batchSize, total)
+			if err := uc.repo.CloneTasks(ctx, plan, plan.TaskIDs[start:end]); err != nil {
+				return err
+			}
+			if jobID != 0 {
+				if err := uc.repo.UpdateJobProgress(ctx, jobID, end, total); err != nil {
+					logger.Log.WithField("job_id", jobID).WithError(err).Warn("failed to update clone job progress")
+				}
+			}
+		}
+
+		var err error
+		id, err = uc.repo.FinishClone(ctx, plan)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// plan checks the user's permissions for the clone and resolves what it copies and where to.
+func (uc *CloneUseCaseImpl) plan(
+	ctx context.Context,
+	cloneType string,
+	sourceID int,
+	userID int,
+	options entity.CloneOptions,
+) (*entity.ClonePlan, error) {
+	options.Name = strings.TrimSpace(options.Name)
+	if utf8.RuneCountInString(options.Name) > maxNameLength {
+		return nil, fmt.Errorf("%w: name must be at most %d characters", domain_error.ErrValidation, maxNameLength)
+	}
+
+	plan := &entity.ClonePlan{Type: cloneType, SourceID: sourceID, UserID: userID, Options: options}
+
+	var err error
+	switch cloneType {
+	case entity.CloneTask:
+		err = uc.planTask(ctx, plan)
+	case entity.CloneKanban:
+		err = uc.planKanban(ctx, plan)
+	case entity.CloneProject:
+		err = uc.planProject(ctx, plan)
+	default:
+		err = fmt.Errorf("%w: unknown clone type %q", domain_error.ErrValidation, cloneType)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+func (uc *CloneUseCaseImpl) planTask(ctx context.Context, plan *entity.ClonePlan) error {
+	sourceProjectID, err := uc.access.RequireTaskPermission(ctx, plan.SourceID, entity.PermissionRead)
+	if err != nil {
+		return err
+	}
+	task, err := uc.taskRepo.GetTaskByID(ctx, plan.SourceID)
+	if err != nil {
+		return err
+	}
+
+	plan.TargetKanbanID = task.KanbanID
+	if plan.Options.TargetKanbanID != nil {
+		plan.TargetKanbanID = *plan.Options.TargetKanbanID
+	}
+	if err := uc.access.RequireKanbanPermission(ctx, plan.TargetKanbanID, entity.PermissionEdit); err != nil {
+		return err
+	}
+	if plan.TargetProjectID, err = uc.projectRepo.GetProjectIDByKanbanID(ctx, plan.TargetKanbanID); err != nil {
+		return err
+	}
+	plan.SameProject = plan.TargetProjectID == sourceProjectID
+
+	if plan.TaskIDs, err = uc.repo.GetTaskTree(ctx, plan.SourceID); err != nil {
+		return err
+	}
+	if plan.Options.Name == "" {
+		plan.Options.Name = copyName(task.Title)
+	}
+	return nil
+}
+
+func (uc *CloneUseCaseImpl) planKanban(ctx context.Context, plan *entity.ClonePlan) error {
+	if err := uc.access.RequireKanbanPermission(ctx, plan.SourceID, entity.PermissionRead); err != nil {
+		return err
+	}
+	kanban, err := uc.kanbanRepo.GetKanbanByID(ctx, plan.SourceID)
+	if err != nil {
+		return err
+	}
+	sourceProjectID, err := uc.projectRepo.GetProjectIDByKanbanID(ctx, plan.SourceID)
+	if err != nil {
+		return err
+	}
+
+	plan.TargetProjectID = sourceProjectID
+	if plan.Options.TargetProjectID != nil {
+		plan.TargetProjectID = *plan.Options.TargetProjectID
+	}
+	if err := uc.access.RequireProjectPermission(ctx, plan.TargetProjectID, entity.PermissionEdit); err != nil {
+		return err
+	}
+	plan.SameProject = plan.TargetProjectID == sourceProjectID
+
+	plan.KanbanIDs = []int{plan.SourceID}
+	if plan.TaskIDs, err = uc.repo.GetTaskIDsByKanbanIDs(ctx, plan.KanbanIDs); err != nil {
+		return err
+	}
+	if plan.Options.Name == "" {
+		plan.Options.Name = copyName(kanban.Name)
+	}
+	return nil
+}
+
+func (uc *CloneUseCaseImpl) planProject(ctx context.Context, plan *entity.ClonePlan) error {
+	if err := uc.access.RequireProjectPermission(ctx, plan.SourceID, entity.PermissionRead); err != nil {
+		return err
+	}
+	project, err := uc.projectRepo.GetProjectByID(ctx, plan.SourceID)
+	if err != nil {
+		return err
+	}
+	if plan.Options.ParentProjectID != nil {
+		err := uc.access.RequireProjectPermission(ctx, *plan.Options.ParentProjectID, entity.PermissionEdit)
+		if err != nil {
+			return err
+		}
+	}
+
+	if plan.ProjectIDs, err = uc.projectIDs(ctx, plan); err != nil {
+		return err
+	}
+	if plan.KanbanIDs, err = uc.repo.GetKanbanIDsByProjectIDs(ctx, plan.ProjectIDs); err != nil {
+		return err
+	}
+	if len(plan.KanbanIDs) > 0 {
+		if plan.TaskIDs, err = uc.repo.GetTaskIDsByKanbanIDs(ctx, plan.KanbanIDs); err != nil {
+			return err
+		}
+	}
+	if plan.Options.Name == "" {
+		plan.Options.Name = copyName(project.Name)
+	}
+	return nil
+}
+
+// projectIDs returns the projects a project clone copies: the source, and with subprojects the
+// descendants the user can read whose parent is copied as well.
+func (uc *CloneUseCaseImpl) projectIDs(ctx context.Context, plan *entity.ClonePlan) ([]int, error) {
+	if !plan.Options.IncludeSubprojects {
+		return []int{plan.SourceID}, nil
+	}
+
+	tree, err := uc.repo.GetProjectTree(ctx, plan.SourceID)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := []int{plan.SourceID}
+	copied := map[int]bool{plan.SourceID: true}
+	for _, project := range tree {
+		if project.ID == plan.SourceID || project.ParentProjectID == nil || !copied[*project.ParentProjectID] {
+			continue
+		}
+		err := uc.access.RequireProjectPermission(ctx, project.ID, entity.PermissionRead)
+		if errors.Is(err, domain_error.ErrForbidden) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, project.ID)
+		copied[project.ID] = true
+	}
+	return ids, nil
+}
+
+// copyName appends the copy suffix to a name, shortening the name to keep within the length limit.
+func copyName(name string) string {
+	runes := []rune(name)
+	if limit := maxNameLength - utf8.RuneCountInString(copySuffix); len(runes) > limit {
+		runes = runes[:limit]
+	}
+	return string(runes) + copySuffix
+}
+
+func toOptionsEntity(options *dto.CloneOptions) entity.CloneOptions {
+	if options == nil {
+		return entity.CloneOptions{}
+	}
+	return entity.CloneOptions{
+		Name:               options.Name,
+		TargetKanbanID:     options.TargetKanbanID,
+		TargetProjectID:    options.TargetProjectID,
+		ParentProjectID:    options.ParentProjectID,
+		IncludeComments:    options.IncludeComments,
+		IncludeAssignees:   options.IncludeAssignees,
+		IncludeSubprojects: options.IncludeSubprojects,
+		IncludeMembers:     options.IncludeMembers,
+	}
+}
+
+func toJobDTO(job *entity.CloneJob) *dto.CloneJob {
+	progress := 0.0
+	if job.Status == entity.CloneJobCompleted {
+		progress = 1
+	} else if job.Total > 0 {
+		progress = float64(job.Done) / float64(job.Total)
+	}
+
+	return &dto.CloneJob{
+		ID:       job.ID,
+		Type:     job.Type,
+		SourceID: job.SourceID,
+		Options: dto.CloneOptions{
+			Name:               job.Options.Name,
+			TargetKanbanID:     job.Options.TargetKanbanID,
+			TargetProjectID:    job.Options.TargetProjectID,
+			ParentProjectID:    job.Options.ParentProjectID,
+			IncludeComments:    job.Options.IncludeComments,
+			IncludeAssignees:   job.Options.IncludeAssignees,
+			IncludeSubprojects: job.Options.IncludeSubprojects,
+			IncludeMembers:     job.Options.IncludeMembers,
+		},
+		Status:     job.Status,
+		Total:      job.Total,
+		Done:       job.Done,
+		Progress:   progress,
+		ResultID:   job.ResultID,
+		Error:      job.Error,
+		CreatedAt:  job.CreatedAt,
+		StartedAt:  job.StartedAt,
+		FinishedAt: job.FinishedAt,
+	}
+}
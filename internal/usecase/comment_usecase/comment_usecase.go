@@ -2,11 +2,12 @@ package comment_usecase
 
 import (
 	"DataTask/internal/domain/dto"
-	"DataTask/internal/domain/entity"
 	"context"
 )
 
 type CommentUseCase interface {
-	CreateComment(ctx context.Context, commentDTO *dto.Comment, taskID int) (*entity.Comment, error)
+	CreateComment(ctx context.Context, commentDTO *dto.Comment, taskID int) (*dto.Comment, error)
 	GetCommentsByTaskID(ctx context.Context, taskID int) ([]*dto.Comment, error)
+	// RenderComments adds the sanitized HTML of their text to comments.
+	RenderComments(ctx context.Context, comments []*dto.Comment) error
 }
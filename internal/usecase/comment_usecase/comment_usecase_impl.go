@@ -4,19 +4,39 @@ import (
 	"DataTask/internal/domain/dto"
 	"DataTask/internal/domain/entity"
 	"DataTask/internal/repository/comment_repository"
+	"DataTask/internal/repository/database"
+	"DataTask/internal/usecase/history_usecase"
+	"DataTask/internal/usecase/markdown_usecase"
+	"DataTask/internal/usecase/mention_usecase"
 	"context"
 	"fmt"
 )
 
 type CommentUseCaseImpl struct {
-	repo comment_repository.CommentRepository
+	repo       comment_repository.CommentRepository
+	history    history_usecase.HistoryUseCase
+	mentions   mention_usecase.MentionUseCase
+	markdown   markdown_usecase.MarkdownUseCase
+	transactor database.Transactor
 }
 
-func NewCommentUseCase(repo comment_repository.CommentRepository) *CommentUseCaseImpl {
-	return &CommentUseCaseImpl{repo: repo}
+func NewCommentUseCase(
+	repo comment_repository.CommentRepository,
+	history history_usecase.HistoryUseCase,
+	mentions mention_usecase.MentionUseCase,
+	markdown markdown_usecase.MarkdownUseCase,
+	transactor database.Transactor,
+) *CommentUseCaseImpl {
+	return &CommentUseCaseImpl{
+		repo:       repo,
+		history:    history,
+		mentions:   mentions,
+		markdown:   markdown,
+		transactor: transactor,
+	}
 }
 
-func (uc *CommentUseCaseImpl) CreateComment(ctx context.Context, commentDTO *dto.Comment, taskID int) (*entity.Comment, error) {
+func (uc *CommentUseCaseImpl) CreateComment(ctx context.Context, commentDTO *dto.Comment, taskID int) (*dto.Comment, error) {
 	if commentDTO.Author == nil || commentDTO.Author.ID == 0 {
 		return nil, fmt.Errorf("author ID is required")
 	}
@@ -28,11 +48,30 @@ func (uc *CommentUseCaseImpl) CreateComment(ctx context.Context, commentDTO *dto
 		Text: commentDTO.Text,
 	}
 
-	createdCommentEntity, err := uc.repo.CreateCommentForTask(ctx, commentEntity, taskID)
+	var createdCommentEntity *entity.Comment
+	var mentions []*dto.Mention
+	err := uc.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		createdCommentEntity, err = uc.repo.CreateCommentForTask(ctx, commentEntity, taskID)
+		if err != nil {
+			return fmt.Errorf("create comment in repository: %w", err)
+		}
+		mentions, err = uc.mentions.SyncCommentMentions(ctx, taskID, createdCommentEntity.ID, createdCommentEntity.Text)
+		if err != nil {
+			return err
+		}
+		return uc.history.RecordChanges(ctx, taskID, []*entity.TaskChange{
+			{Field: entity.TaskHistoryFieldComment, NewValue: createdCommentEntity.ID},
+		})
+	})
 	if err != nil {
-		return nil, fmt.Errorf("create comment in repository: %w", err)
+		return nil, err
 	}
-	return createdCommentEntity, nil
+
+	createdComment := toCommentDTO(createdCommentEntity)
+	createdComment.TaskID = taskID
+	createdComment.Mentions = mentions
+	return createdComment, nil
 }
 
 func (uc *CommentUseCaseImpl) GetCommentsByTaskID(ctx context.Context, taskID int) ([]*dto.Comment, error) {
@@ -41,23 +80,41 @@ func (uc *CommentUseCaseImpl) GetCommentsByTaskID(ctx context.Context, taskID in
 		return nil, fmt.Errorf("get comments from repository: %w", err)
 	}
 
+	mentionsByComment, err := uc.mentions.GetCommentMentions(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
 	commentDTOs := make([]*dto.Comment, len(comments))
 	for i, comment := range comments {
-		commentDTOs[i] = &dto.Comment{
-			ID: comment.ID,
-			Author: &dto.User{
-				ID:        comment.Author.ID,
-				Name:      comment.Author.Name,
-				Surname:   comment.Author.Surname,
-				Email:     comment.Author.Email,
-				AvatarURL: comment.Author.AvatarURL,
-				CreatedAt: comment.Author.CreatedAt,
-				UpdatedAt: comment.Author.UpdatedAt,
-			},
-			Text:      comment.Text,
-			CreatedAt: comment.CreatedAt,
-			UpdatedAt: comment.UpdatedAt,
+		commentDTOs[i] = toCommentDTO(comment)
+		commentDTOs[i].Mentions = mentionsByComment[comment.ID]
+		if commentDTOs[i].Mentions == nil {
+			commentDTOs[i].Mentions = []*dto.Mention{}
 		}
 	}
 	return commentDTOs, nil
 }
+
+func (uc *CommentUseCaseImpl) RenderComments(ctx context.Context, comments []*dto.Comment) error {
+	return uc.markdown.RenderComments(ctx, comments)
+}
+
+func toCommentDTO(comment *entity.Comment) *dto.Comment {
+	return &dto.Comment{
+		ID: comment.ID,
+		Author: &dto.User{
+			ID:        comment.Author.ID,
+			Name:      comment.Author.Name,
+			Surname:   comment.Author.Surname,
+			Email:     comment.Author.Email,
+			AvatarURL: comment.Author.AvatarURL,
+			CreatedAt: comment.Author.CreatedAt,
+			UpdatedAt: comment.Author.UpdatedAt,
+		},
+		Text:      comment.Text,
+		TaskID:    comment.TaskID,
+		CreatedAt: comment.CreatedAt,
+		UpdatedAt: comment.UpdatedAt,
+	}
+}
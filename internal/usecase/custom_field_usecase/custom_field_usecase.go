@@ -0,0 +1,27 @@
+package custom_field_usecase
+
+import (
+	"DataTask/internal/domain/dto"
+	"DataTask/internal/domain/entity"
+	"context"
+)
+
+type CustomFieldUseCase interface {
+	CreateField(ctx context.Context, field *dto.CustomField) (*dto.CustomField, error)
+	GetFieldByID(ctx context.Context, id int) (*dto.CustomField, error)
+	UpdateField(ctx context.Context, id int, update *dto.CustomFieldUpdate) (*dto.CustomField, error)
+	DeleteField(ctx context.Context, id int) error
+	// GetFieldsByProjectID returns the fields usable in the project, including inherited ones.
+	GetFieldsByProjectID(ctx context.Context, projectID int) ([]*dto.CustomField, error)
+
+	GetTaskValues(ctx context.Context, taskID int) ([]*dto.CustomFieldValue, error)
+	// SetTaskValues validates and stores the given values of a task; other fields stay unchanged.
+	SetTaskValues(ctx context.Context, taskID int, values []*dto.CustomFieldValueInput) ([]*dto.CustomFieldValue, error)
+
+	// GetValuesByTaskIDs loads the values for task lists. Callers check access to the tasks.
+	GetValuesByTaskIDs(ctx context.Context, taskIDs []int) (map[int][]*dto.CustomFieldValue, error)
+	// ParseFilter turns query string values keyed by field ID into task filter conditions.
+	ParseFilter(ctx context.Context, values map[int]string) ([]*entity.CustomFieldValue, error)
+	// GetSortField returns the field a task list is sorted by. Multi-select fields cannot be sorted by.
+	GetSortField(ctx context.Context, id int) (*entity.CustomField, error)
+}
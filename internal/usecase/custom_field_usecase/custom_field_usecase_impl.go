@@ -0,0 +1,478 @@
+package custom_field_usecase
+
+import (
+	"DataTask/internal/domain/domain_error"
+	"DataTask/internal/domain/dto"
+	"DataTask/internal/domain/entity"
+	"DataTask/internal/repository/custom_field_repository"
+	"DataTask/internal/repository/database"
+	"DataTask/internal/usecase/access_usecase"
+	"context"
+	"fmt"
+	"math"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	dateLayout = "2006-01-02"
+
+	maxTextLength = 1000
+	maxOptions    = 100
+)
+
+type CustomFieldUseCaseImpl struct {
+	repo       custom_field_repository.CustomFieldRepository
+	access     access_usecase.AccessUseCase
+	transactor database.Transactor
+}
+
+func NewCustomFieldUseCase(
+	repo custom_field_repository.CustomFieldRepository,
+	access access_usecase.AccessUseCase,
+	transactor database.Transactor,
+) *CustomFieldUseCaseImpl {
+	return &CustomFieldUseCaseImpl{
+		repo:       repo,
+		access:     access,
+		transactor: transactor,
+	}
+}
+
+func (uc *CustomFieldUseCaseImpl) CreateField(ctx context.Context, field *dto.CustomField) (*dto.CustomField, error) {
+	if err := uc.access.RequireProjectPermission(ctx, field.ProjectID, entity.PermissionEdit); err != nil {
+		return nil, err
+	}
+
+	entityField := &entity.CustomField{
+		ProjectID: field.ProjectID,
+		Name:      strings.TrimSpace(field.Name),
+		Type:      field.Type,
+		Options:   trimOptions(field.Options),
+		Position:  field.Position,
+	}
+	if err := validateField(entityField); err != nil {
+		return nil, err
+	}
+
+	createdField, err := uc.repo.CreateField(ctx, entityField)
+	if err != nil {
+		return nil, err
+	}
+	return toFieldDTO(createdField, createdField.ProjectID), nil
+}
+
+func (uc *CustomFieldUseCaseImpl) GetFieldByID(ctx context.Context, id int) (*dto.CustomField, error) {
+	field, err := uc.repo.GetFieldByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.access.RequireProjectPermission(ctx, field.ProjectID, entity.PermissionRead); err != nil {
+		return nil, err
+	}
+	return toFieldDTO(field, field.ProjectID), nil
+}
+
+func (uc *CustomFieldUseCaseImpl) UpdateField(ctx context.Context, id int, update *dto.CustomFieldUpdate) (*dto.CustomField, error) {
+	field, err := uc.repo.GetFieldByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.access.RequireProjectPermission(ctx, field.ProjectID, entity.PermissionEdit); err != nil {
+		return nil, err
+	}
+
+	if update.Name != nil {
+		field.Name = strings.TrimSpace(*update.Name)
+	}
+	if update.Position != nil {
+		field.Position = *update.Position
+	}
+	optionsChanged := update.Options != nil
+	if optionsChanged {
+		field.Options = trimOptions(update.Options)
+	}
+	if err := validateField(field); err != nil {
+		return nil, err
+	}
+
+	var updatedField *entity.CustomField
+	err = uc.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		updatedField, err = uc.repo.UpdateField(ctx, field)
+		if err != nil {
+			return err
+		}
+		if optionsChanged {
+			return uc.repo.PruneOptionValues(ctx, field.ID, field.Options)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toFieldDTO(updatedField, updatedField.ProjectID), nil
+}
+
+func (uc *CustomFieldUseCaseImpl) DeleteField(ctx context.Context, id int) error {
+	field, err := uc.repo.GetFieldByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := uc.access.RequireProjectPermission(ctx, field.ProjectID, entity.PermissionEdit); err != nil {
+		return err
+	}
+
+	return uc.repo.DeleteField(ctx, id)
+}
+
+func (uc *CustomFieldUseCaseImpl) GetFieldsByProjectID(ctx context.Context, projectID int) ([]*dto.CustomField, error) {
+	if err := uc.access.RequireProjectPermission(ctx, projectID, entity.PermissionRead); err != nil {
+		return nil, err
+	}
+
+	fields, err := uc.repo.GetAvailableFields(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	dtoFields := make([]*dto.CustomField, 0, len(fields))
+	for _, f := range fields {
+		dtoFields = append(dtoFields, toFieldDTO(f, projectID))
+	}
+	return dtoFields, nil
+}
+
+func (uc *CustomFieldUseCaseImpl) GetTaskValues(ctx context.Context, taskID int) ([]*dto.CustomFieldValue, error) {
+	if _, err := uc.access.RequireTaskPermission(ctx, taskID, entity.PermissionRead); err != nil {
+		return nil, err
+	}
+
+	values, err := uc.GetValuesByTaskIDs(ctx, []int{taskID})
+	if err != nil {
+		return nil, err
+	}
+	return values[taskID], nil
+}
+
+func (uc *CustomFieldUseCaseImpl) SetTaskValues(ctx context.Context, taskID int, values []*dto.CustomFieldValueInput) ([]*dto.CustomFieldValue, error) {
+	projectID, err := uc.access.RequireTaskPermission(ctx, taskID, entity.PermissionEdit)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only fields of the task's project or of one of its ancestors may be set.
+	available, err := uc.repo.GetAvailableFields(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	fields := make(map[int]*entity.CustomField, len(available))
+	for _, f := range available {
+		fields[f.ID] = f
+	}
+
+	var toStore []*entity.CustomFieldValue
+	var toClear []int
+	for _, v := range values {
+		field := fields[v.FieldID]
+		if field == nil {
+			return nil, fmt.Errorf("%w: custom field %d is not available in project %d", domain_error.ErrValidation, v.FieldID, projectID)
+		}
+		if v.Value == nil {
+			toClear = append(toClear, field.ID)
+			continue
+		}
+
+		value, err := uc.parseValue(ctx, projectID, field, v.Value)
+		if err != nil {
+			return nil, err
+		}
+		value.TaskID = taskID
+		toStore = append(toStore, value)
+	}
+
+	err = uc.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		for _, fieldID := range toClear {
+			if err := uc.repo.DeleteValue(ctx, taskID, fieldID); err != nil {
+				return err
+			}
+		}
+		for _, value := range toStore {
+			if err := uc.repo.UpsertValue(ctx, value); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	stored, err := uc.GetValuesByTaskIDs(ctx, []int{taskID})
+	if err != nil {
+		return nil, err
+	}
+	return stored[taskID], nil
+}
+
+func (uc *CustomFieldUseCaseImpl) GetValuesByTaskIDs(ctx context.Context, taskIDs []int) (map[int][]*dto.CustomFieldValue, error) {
+	values, err := uc.repo.GetValuesByTaskIDs(ctx, taskIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	dtoValues := make(map[int][]*dto.CustomFieldValue, len(taskIDs))
+	for _, taskID := range taskIDs {
+		dtoValues[taskID] = make([]*dto.CustomFieldValue, 0, len(values[taskID]))
+		for _, v := range values[taskID] {
+			dtoValues[taskID] = append(dtoValues[taskID], toValueDTO(v))
+		}
+	}
+	return dtoValues, nil
+}
+
+func (uc *CustomFieldUseCaseImpl) ParseFilter(ctx context.Context, values map[int]string) ([]*entity.CustomFieldValue, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	fieldIDs := make([]int, 0, len(values))
+	for id := range values {
+		fieldIDs = append(fieldIDs, id)
+	}
+	slices.Sort(fieldIDs)
+
+	fields, err := uc.repo.GetFieldsByIDs(ctx, fieldIDs)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[int]*entity.CustomField, len(fields))
+	for _, f := range fields {
+		byID[f.ID] = f
+	}
+
+	conditions := make([]*entity.CustomFieldValue, 0, len(fieldIDs))
+	for _, id := range fieldIDs {
+		field := byID[id]
+		if field == nil {
+			return nil, fmt.Errorf("%w: custom field %d", domain_error.ErrNotFound, id)
+		}
+
+		condition, err := parseFilterValue(field, values[id])
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, condition)
+	}
+	return conditions, nil
+}
+
+func (uc *CustomFieldUseCaseImpl) GetSortField(ctx context.Context, id int) (*entity.CustomField, error) {
+	field, err := uc.repo.GetFieldByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if field.Type == entity.CustomFieldMultiSelect {
+		return nil, fmt.Errorf("%w: cannot sort by multi-select field %q", domain_error.ErrValidation, field.Name)
+	}
+	return field, nil
+}
+
+// parseValue checks a JSON value against the field and converts it to the stored form.
+func (uc *CustomFieldUseCaseImpl) parseValue(ctx context.Context, projectID int, field *entity.CustomField, raw any) (*entity.CustomFieldValue, error) {
+	value := &entity.CustomFieldValue{FieldID: field.ID, FieldType: field.Type}
+	invalid := func(format string, args ...any) error {
+		return fmt.Errorf("%w: custom field %q: %s", domain_error.ErrValidation, field.Name, fmt.Sprintf(format, args...))
+	}
+
+	switch field.Type {
+	case entity.CustomFieldText, entity.CustomFieldURL, entity.CustomFieldSingleSelect, entity.CustomFieldDate:
+		s, ok := raw.(string)
+		if !ok {
+			return nil, invalid("expected a string")
+		}
+		s = strings.TrimSpace(s)
+		if s == "" || len(s) > maxTextLength {
+			return nil, invalid("must be 1 to %d characters long", maxTextLength)
+		}
+
+		switch field.Type {
+		case entity.CustomFieldURL:
+			if u, err := url.Parse(s); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return nil, invalid("expected an http or https URL")
+			}
+		case entity.CustomFieldSingleSelect:
+			if !slices.Contains(field.Options, s) {
+				return nil, invalid("%q is not one of the options", s)
+			}
+		case entity.CustomFieldDate:
+			d, err := time.Parse(dateLayout, s)
+			if err != nil {
+				return nil, invalid("expected a date like 2025-05-20")
+			}
+			value.Date = &d
+			return value, nil
+		}
+		value.Text = &s
+
+	case entity.CustomFieldNumber:
+		n, ok := raw.(float64)
+		if !ok || math.IsNaN(n) || math.IsInf(n, 0) {
+			return nil, invalid("expected a number")
+		}
+		value.Number = &n
+
+	case entity.CustomFieldMultiSelect:
+		list, ok := raw.([]any)
+		if !ok {
+			return nil, invalid("expected a list of options")
+		}
+		value.Options = make([]string, 0, len(list))
+		for _, item := range list {
+			s, ok := item.(string)
+			if !ok || !slices.Contains(field.Options, s) {
+				return nil, invalid("%v is not one of the options", item)
+			}
+			if !slices.Contains(value.Options, s) {
+				value.Options = append(value.Options, s)
+			}
+		}
+		if len(value.Options) == 0 {
+			return nil, invalid("pick at least one option or clear the field")
+		}
+
+	case entity.CustomFieldUser:
+		n, ok := raw.(float64)
+		if !ok || n != math.Trunc(n) || n <= 0 {
+			return nil, invalid("expected a user ID")
+		}
+		userID := int(n)
+		member, err := uc.access.IsProjectMember(ctx, projectID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if !member {
+			return nil, invalid("user %d is not a member of project %d", userID, projectID)
+		}
+		value.UserID = &userID
+	}
+	return value, nil
+}
+
+// parseFilterValue reads a query string value for matching tasks. Multi-select fields take a
+// comma separated list and match tasks holding all of the options.
+func parseFilterValue(field *entity.CustomField, s string) (*entity.CustomFieldValue, error) {
+	value := &entity.CustomFieldValue{FieldID: field.ID, FieldType: field.Type}
+	invalid := func(expected string) error {
+		return fmt.Errorf("%w: filter on custom field %q: expected %s", domain_error.ErrValidation, field.Name, expected)
+	}
+
+	switch field.Type {
+	case entity.CustomFieldNumber:
+		n, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, invalid("a number")
+		}
+		value.Number = &n
+	case entity.CustomFieldDate:
+		d, err := time.Parse(dateLayout, s)
+		if err != nil {
+			return nil, invalid("a date like 2025-05-20")
+		}
+		value.Date = &d
+	case entity.CustomFieldUser:
+		userID, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, invalid("a user ID")
+		}
+		value.UserID = &userID
+	case entity.CustomFieldMultiSelect:
+		value.Options = trimOptions(strings.Split(s, ","))
+	default:
+		s = strings.TrimSpace(s)
+		value.Text = &s
+	}
+	return value, nil
+}
+
+func validateField(field *entity.CustomField) error {
+	if field.Name == "" {
+		return fmt.Errorf("%w: custom field name is required", domain_error.ErrValidation)
+	}
+	if !entity.IsValidCustomFieldType(field.Type) {
+		return fmt.Errorf("%w: unknown custom field type %q", domain_error.ErrValidation, field.Type)
+	}
+
+	isSelect := field.Type == entity.CustomFieldSingleSelect || field.Type == entity.CustomFieldMultiSelect
+	switch {
+	case isSelect && len(field.Options) == 0:
+		return fmt.Errorf("%w: select fields need at least one option", domain_error.ErrValidation)
+	case !isSelect && len(field.Options) > 0:
+		return fmt.Errorf("%w: only select fields have options", domain_error.ErrValidation)
+	case len(field.Options) > maxOptions:
+		return fmt.Errorf("%w: a field has at most %d options", domain_error.ErrValidation, maxOptions)
+	}
+
+	for i, option := range field.Options {
+		if option == "" {
+			return fmt.Errorf("%w: options must not be empty", domain_error.ErrValidation)
+		}
+		if slices.Contains(field.Options[:i], option) {
+			return fmt.Errorf("%w: duplicate option %q", domain_error.ErrValidation, option)
+		}
+	}
+	return nil
+}
+
+func trimOptions(options []string) []string {
+	trimmed := make([]string, 0, len(options))
+	for _, o := range options {
+		trimmed = append(trimmed, strings.TrimSpace(o))
+	}
+	return trimmed
+}
+
+// toFieldDTO maps a field as seen from projectID; fields of other projects are inherited ones.
+func toFieldDTO(field *entity.CustomField, projectID int) *dto.CustomField {
+	options := field.Options
+	if options == nil {
+		options = []string{}
+	}
+
+	return &dto.CustomField{
+		ID:        field.ID,
+		ProjectID: field.ProjectID,
+		Name:      field.Name,
+		Type:      field.Type,
+		Options:   options,
+		Position:  field.Position,
+		Inherited: field.ProjectID != projectID,
+		CreatedAt: field.CreatedAt,
+		UpdatedAt: field.UpdatedAt,
+	}
+}
+
+func toValueDTO(v *entity.CustomFieldValue) *dto.CustomFieldValue {
+	value := &dto.CustomFieldValue{
+		FieldID:   v.FieldID,
+		FieldName: v.FieldName,
+		Type:      v.FieldType,
+	}
+
+	switch {
+	case v.Text != nil:
+		value.Value = *v.Text
+	case v.Number != nil:
+		value.Value = *v.Number
+	case v.Date != nil:
+		value.Value = v.Date.Format(dateLayout)
+	case v.Options != nil:
+		value.Value = v.Options
+	case v.UserID != nil:
+		value.Value = *v.UserID
+	}
+	return value
+}
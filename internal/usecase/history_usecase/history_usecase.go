@@ -0,0 +1,17 @@
+package history_usecase
+
+import (
+	"DataTask/internal/domain/dto"
+	"DataTask/internal/domain/entity"
+	"context"
+)
+
+type HistoryUseCase interface {
+	// RecordChanges appends the current user's changes of a task to its history, skipping
+	// values that did not change. Call it inside the transaction that makes the changes.
+	RecordChanges(ctx context.Context, taskID int, changes []*entity.TaskChange) error
+
+	GetTaskHistory(ctx context.Context, taskID int, page *dto.Page) (*dto.TaskHistoryPage, error)
+	// GetTaskTimeline returns the history and the comments of a task as one list.
+	GetTaskTimeline(ctx context.Context, taskID int, page *dto.Page) (*dto.TimelinePage, error)
+}
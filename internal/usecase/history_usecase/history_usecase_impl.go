@@ -0,0 +1,191 @@
+package history_usecase
+
+import (
+	"DataTask/internal/domain/domain_error"
+	"DataTask/internal/domain/dto"
+	"DataTask/internal/domain/entity"
+	"DataTask/internal/repository/history_repository"
+	"DataTask/internal/usecase/access_usecase"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 200
+)
+
+type HistoryUseCaseImpl struct {
+	repo   history_repository.HistoryRepository
+	access access_usecase.AccessUseCase
+}
+
+func NewHistoryUseCase(repo history_repository.HistoryRepository, access access_usecase.AccessUseCase) *HistoryUseCaseImpl {
+	return &HistoryUseCaseImpl{
+		repo:   repo,
+		access: access,
+	}
+}
+
+func (uc *HistoryUseCaseImpl) RecordChanges(ctx context.Context, taskID int, changes []*entity.TaskChange) error {
+	// Changes made outside of a request, e.g. by the recurrence scheduler, have no actor.
+	var actor *entity.User
+	if userID, err := uc.access.CurrentUserID(ctx); err == nil {
+		actor = &entity.User{ID: userID}
+	}
+
+	entries := make([]*entity.TaskHistoryEntry, 0, len(changes))
+	for _, change := range changes {
+		oldValue, err := marshalValue(change.OldValue)
+		if err != nil {
+			return fmt.Errorf("encode old %s: %w", change.Field, err)
+		}
+		newValue, err := marshalValue(change.NewValue)
+		if err != nil {
+			return fmt.Errorf("encode new %s: %w", change.Field, err)
+		}
+		if bytes.Equal(oldValue, newValue) {
+			continue
+		}
+
+		entries = append(entries, &entity.TaskHistoryEntry{
+			TaskID:   taskID,
+			Actor:    actor,
+			Field:    change.Field,
+			OldValue: oldValue,
+			NewValue: newValue,
+		})
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+	return uc.repo.AppendEntries(ctx, entries)
+}
+
+func (uc *HistoryUseCaseImpl) GetTaskHistory(ctx context.Context, taskID int, page *dto.Page) (*dto.TaskHistoryPage, error) {
+	if _, err := uc.access.RequireTaskPermission(ctx, taskID, entity.PermissionRead); err != nil {
+		return nil, err
+	}
+	limit, offset, err := normalizePage(page)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, total, err := uc.repo.GetHistory(ctx, taskID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dto.TaskHistoryPage{
+		Entries: make([]*dto.TaskHistoryEntry, 0, len(entries)),
+		Total:   total,
+		Limit:   limit,
+		Offset:  offset,
+	}
+	for _, e := range entries {
+		result.Entries = append(result.Entries, toHistoryEntryDTO(e))
+	}
+	return result, nil
+}
+
+func (uc *HistoryUseCaseImpl) GetTaskTimeline(ctx context.Context, taskID int, page *dto.Page) (*dto.TimelinePage, error) {
+	if _, err := uc.access.RequireTaskPermission(ctx, taskID, entity.PermissionRead); err != nil {
+		return nil, err
+	}
+	limit, offset, err := normalizePage(page)
+	if err != nil {
+		return nil, err
+	}
+
+	items, total, err := uc.repo.GetTimeline(ctx, taskID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dto.TimelinePage{
+		Items:  make([]*dto.TimelineItem, 0, len(items)),
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
+	}
+	for _, item := range items {
+		dtoItem := &dto.TimelineItem{Kind: item.Kind}
+		if item.Comment != nil {
+			dtoItem.CreatedAt = item.Comment.CreatedAt
+			dtoItem.Comment = &dto.Comment{
+				ID:        item.Comment.ID,
+				Author:    toUserDTO(item.Comment.Author),
+				Text:      item.Comment.Text,
+				TaskID:    item.Comment.TaskID,
+				CreatedAt: item.Comment.CreatedAt,
+				UpdatedAt: item.Comment.UpdatedAt,
+			}
+		} else {
+			dtoItem.CreatedAt = item.Change.CreatedAt
+			dtoItem.Change = toHistoryEntryDTO(item.Change)
+		}
+		result.Items = append(result.Items, dtoItem)
+	}
+	return result, nil
+}
+
+func normalizePage(page *dto.Page) (int, int, error) {
+	if page == nil {
+		return defaultPageSize, 0, nil
+	}
+	if page.Limit < 0 || page.Offset < 0 {
+		return 0, 0, fmt.Errorf("%w: limit and offset cannot be negative", domain_error.ErrValidation)
+	}
+
+	limit := page.Limit
+	if limit == 0 {
+		limit = defaultPageSize
+	}
+	if limit > maxPageSize {
+		limit = maxPageSize
+	}
+	return limit, page.Offset, nil
+}
+
+// marshalValue encodes a changed value. Nil values and nil pointers stay empty, so they are
+// stored as SQL NULL.
+func marshalValue(value any) ([]byte, error) {
+	if value == nil {
+		return nil, nil
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.Equal(encoded, []byte("null")) {
+		return nil, nil
+	}
+	return encoded, nil
+}
+
+func toHistoryEntryDTO(e *entity.TaskHistoryEntry) *dto.TaskHistoryEntry {
+	return &dto.TaskHistoryEntry{
+		ID:        e.ID,
+		TaskID:    e.TaskID,
+		Actor:     toUserDTO(e.Actor),
+		Field:     e.Field,
+		OldValue:  e.OldValue,
+		NewValue:  e.NewValue,
+		CreatedAt: e.CreatedAt,
+	}
+}
+
+func toUserDTO(u *entity.User) *dto.User {
+	if u == nil {
+		return nil
+	}
+	return &dto.User{
+		ID:        u.ID,
+		Name:      u.Name,
+		Surname:   u.Surname,
+		Email:     u.Email,
+		AvatarURL: u.AvatarURL,
+	}
+}
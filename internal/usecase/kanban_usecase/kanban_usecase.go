@@ -12,4 +12,8 @@ type KanbanUseCase interface {
 	UpdateKanban(ctx context.Context, kanban *dto.Kanban) (*dto.Kanban, error)
 	DeleteKanban(ctx context.Context, id int) error
 	GetAllKanbans(ctx context.Context) ([]*dto.Kanban, error)
+	// ReorderKanbans applies the new order and returns the kanbans of the project in it.
+	ReorderKanbans(ctx context.Context, projectID int, order *dto.KanbanOrder) ([]*dto.Kanban, error)
+	// SetWIPLimit sets the most tasks the kanban should hold. A nil limit removes it.
+	SetWIPLimit(ctx context.Context, id int, limit *int) (*dto.Kanban, error)
 }
@@ -1,18 +1,23 @@
 package kanban_usecase
 
 import (
+	"DataTask/internal/domain/domain_error"
 	"DataTask/internal/domain/dto"
 	"DataTask/internal/domain/entity"
 	"DataTask/internal/repository/kanban_repository"
+	"DataTask/internal/usecase/access_usecase"
 	"context"
+	"fmt"
+	"slices"
 )
 
 type KanbanUseCaseImpl struct {
-	repo kanban_repository.KanbanRepository
+	repo   kanban_repository.KanbanRepository
+	access access_usecase.AccessUseCase
 }
 
-func NewKanbanUseCase(repo kanban_repository.KanbanRepository) *KanbanUseCaseImpl {
-	return &KanbanUseCaseImpl{repo: repo}
+func NewKanbanUseCase(repo kanban_repository.KanbanRepository, access access_usecase.AccessUseCase) *KanbanUseCaseImpl {
+	return &KanbanUseCaseImpl{repo: repo, access: access}
 }
 
 func (uc *KanbanUseCaseImpl) CreateKanban(ctx context.Context, kanban *dto.Kanban) (*dto.Kanban, error) {
@@ -26,15 +31,7 @@ func (uc *KanbanUseCaseImpl) CreateKanban(ctx context.Context, kanban *dto.Kanba
 		return nil, err
 	}
 
-	dtoKanban := &dto.Kanban{
-		ID:        createdKanban.ID,
-		Name:      createdKanban.Name,
-		ProjectID: createdKanban.ProjectID,
-		CreatedAt: createdKanban.CreatedAt,
-		UpdatedAt: createdKanban.UpdatedAt,
-	}
-
-	return dtoKanban, nil
+	return toKanbanDTO(createdKanban), nil
 }
 
 func (uc *KanbanUseCaseImpl) GetKanbanByID(ctx context.Context, id int) (*dto.Kanban, error) {
@@ -43,14 +40,7 @@ func (uc *KanbanUseCaseImpl) GetKanbanByID(ctx context.Context, id int) (*dto.Ka
 		return nil, err
 	}
 
-	dtoKanban := &dto.Kanban{
-		ID:        kanban.ID,
-		Name:      kanban.Name,
-		CreatedAt: kanban.CreatedAt,
-		UpdatedAt: kanban.UpdatedAt,
-	}
-
-	return dtoKanban, nil
+	return toKanbanDTO(kanban), nil
 }
 
 func (uc *KanbanUseCaseImpl) GetKanbansByProjectID(ctx context.Context, projectID int) ([]*dto.Kanban, error) {
@@ -62,14 +52,7 @@ func (uc *KanbanUseCaseImpl) GetKanbansByProjectID(ctx context.Context, projectI
 	var dtoKanbans []*dto.Kanban
 
 	for _, kanban := range kanbans {
-		dtoKanban := &dto.Kanban{
-			ID:        kanban.ID,
-			Name:      kanban.Name,
-			CreatedAt: kanban.CreatedAt,
-			UpdatedAt: kanban.UpdatedAt,
-		}
-
-		dtoKanbans = append(dtoKanbans, dtoKanban)
+		dtoKanbans = append(dtoKanbans, toKanbanDTO(kanban))
 	}
 
 	return dtoKanbans, nil
@@ -86,14 +69,7 @@ func (uc *KanbanUseCaseImpl) UpdateKanban(ctx context.Context, kanban *dto.Kanba
 		return nil, err
 	}
 
-	dtoKanban := &dto.Kanban{
-		ID:        updatedKanban.ID,
-		Name:      updatedKanban.Name,
-		CreatedAt: updatedKanban.CreatedAt,
-		UpdatedAt: updatedKanban.UpdatedAt,
-	}
-
-	return dtoKanban, nil
+	return toKanbanDTO(updatedKanban), nil
 }
 
 func (uc *KanbanUseCaseImpl) DeleteKanban(ctx context.Context, id int) error {
@@ -112,13 +88,127 @@ func (uc *KanbanUseCaseImpl) GetAllKanbans(ctx context.Context) ([]*dto.Kanban,
 
 	var dtoKanbans []*dto.Kanban
 	for _, k := range kanbans {
-		dtoKanbans = append(dtoKanbans, &dto.Kanban{
-			ID:        k.ID,
-			Name:      k.Name,
-			CreatedAt: k.CreatedAt,
-			UpdatedAt: k.UpdatedAt,
-		})
+		dtoKanbans = append(dtoKanbans, toKanbanDTO(k))
+	}
+
+	return dtoKanbans, nil
+}
+
+func (uc *KanbanUseCaseImpl) ReorderKanbans(ctx context.Context, projectID int, order *dto.KanbanOrder) ([]*dto.Kanban, error) {
+	if err := uc.access.RequireProjectPermission(ctx, projectID, entity.PermissionEdit); err != nil {
+		return nil, err
+	}
+
+	kanbans, err := uc.repo.GetKanbansByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	current := make([]int, 0, len(kanbans))
+	for _, k := range kanbans {
+		current = append(current, k.ID)
+	}
+
+	kanbanIDs := order.KanbanIDs
+	switch {
+	case order.KanbanID != nil && order.KanbanIDs != nil:
+		return nil, fmt.Errorf("%w: give either kanban_ids or kanban_id with an anchor", domain_error.ErrValidation)
+	case order.KanbanID != nil:
+		if kanbanIDs, err = moveKanban(current, *order.KanbanID, order.BeforeID, order.AfterID); err != nil {
+			return nil, err
+		}
+	case order.BeforeID != nil || order.AfterID != nil:
+		return nil, fmt.Errorf("%w: kanban_id is required with before_id or after_id", domain_error.ErrValidation)
+	}
+	if err := sameKanbanSet(current, kanbanIDs); err != nil {
+		return nil, err
+	}
+
+	if err := uc.repo.ReorderKanbans(ctx, projectID, kanbanIDs); err != nil {
+		return nil, err
 	}
 
+	reordered, err := uc.repo.GetKanbansByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	dtoKanbans := make([]*dto.Kanban, 0, len(reordered))
+	for _, k := range reordered {
+		dtoKanbans = append(dtoKanbans, toKanbanDTO(k))
+	}
 	return dtoKanbans, nil
 }
+
+func (uc *KanbanUseCaseImpl) SetWIPLimit(ctx context.Context, id int, limit *int) (*dto.Kanban, error) {
+	if err := uc.access.RequireKanbanPermission(ctx, id, entity.PermissionEdit); err != nil {
+		return nil, err
+	}
+	if limit != nil && *limit <= 0 {
+		return nil, fmt.Errorf("%w: WIP limit must be positive", domain_error.ErrValidation)
+	}
+
+	kanban, err := uc.repo.SetWIPLimit(ctx, id, limit)
+	if err != nil {
+		return nil, err
+	}
+	return toKanbanDTO(kanban), nil
+}
+
+// moveKanban returns kanbanIDs with kanbanID moved right before beforeID or right after afterID.
+func moveKanban(kanbanIDs []int, kanbanID int, beforeID *int, afterID *int) ([]int, error) {
+	if (beforeID == nil) == (afterID == nil) {
+		return nil, fmt.Errorf("%w: exactly one of before_id and after_id is required", domain_error.ErrValidation)
+	}
+	anchor := beforeID
+	if afterID != nil {
+		anchor = afterID
+	}
+	if *anchor == kanbanID {
+		return nil, fmt.Errorf("%w: a kanban cannot be moved next to itself", domain_error.ErrValidation)
+	}
+
+	if !slices.Contains(kanbanIDs, kanbanID) {
+		return nil, fmt.Errorf("%w: kanban %d is not in the project", domain_error.ErrNotFound, kanbanID)
+	}
+	moved := slices.DeleteFunc(slices.Clone(kanbanIDs), func(id int) bool { return id == kanbanID })
+
+	i := slices.Index(moved, *anchor)
+	if i < 0 {
+		return nil, fmt.Errorf("%w: kanban %d is not in the project", domain_error.ErrNotFound, *anchor)
+	}
+	if afterID != nil {
+		i++
+	}
+	return slices.Insert(moved, i, kanbanID), nil
+}
+
+func sameKanbanSet(current []int, kanbanIDs []int) error {
+	if len(current) != len(kanbanIDs) {
+		return fmt.Errorf("%w: every kanban of the project must be listed exactly once", domain_error.ErrValidation)
+	}
+
+	known := make(map[int]bool, len(current))
+	for _, id := range current {
+		known[id] = true
+	}
+	for _, id := range kanbanIDs {
+		if !known[id] {
+			return fmt.Errorf("%w: every kanban of the project must be listed exactly once", domain_error.ErrValidation)
+		}
+		delete(known, id)
+	}
+	return nil
+}
+
+func toKanbanDTO(k *entity.Kanban) *dto.Kanban {
+	return &dto.Kanban{
+		ID:        k.ID,
+		Name:      k.Name,
+		ProjectID: k.ProjectID,
+		Position:  k.Position,
+		WIPLimit:  k.WIPLimit,
+		TaskCount: k.TaskCount,
+		OverLimit: k.WIPLimit != nil && k.TaskCount > *k.WIPLimit,
+		CreatedAt: k.CreatedAt,
+		UpdatedAt: k.UpdatedAt,
+	}
+}
@@ -0,0 +1,18 @@
+package label_usecase
+
+import (
+	"DataTask/internal/domain/dto"
+	"context"
+)
+
+type LabelUseCase interface {
+	CreateLabel(ctx context.Context, label *dto.Label) (*dto.Label, error)
+	GetLabelByID(ctx context.Context, id int) (*dto.Label, error)
+	UpdateLabel(ctx context.Context, label *dto.Label) (*dto.Label, error)
+	DeleteLabel(ctx context.Context, id int) error
+	// GetLabelsByProjectID returns the project's own labels followed by the ones inherited from parent projects.
+	GetLabelsByProjectID(ctx context.Context, projectID int) ([]*dto.Label, error)
+
+	AttachLabelToTask(ctx context.Context, taskID int, labelID int) error
+	DetachLabelFromTask(ctx context.Context, taskID int, labelID int) error
+}
@@ -0,0 +1,176 @@
+package label_usecase
+
+import (
+	"DataTask/internal/domain/domain_error"
+	"DataTask/internal/domain/dto"
+	"DataTask/internal/domain/entity"
+	"DataTask/internal/repository/label_repository"
+	"DataTask/internal/usecase/access_usecase"
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const defaultLabelColor = "#9E9E9E"
+
+var colorPattern = regexp.MustCompile(`^#[0-9A-Fa-f]{6}$`)
+
+type LabelUseCaseImpl struct {
+	repo   label_repository.LabelRepository
+	access access_usecase.AccessUseCase
+}
+
+func NewLabelUseCase(repo label_repository.LabelRepository, access access_usecase.AccessUseCase) *LabelUseCaseImpl {
+	return &LabelUseCaseImpl{
+		repo:   repo,
+		access: access,
+	}
+}
+
+func (uc *LabelUseCaseImpl) CreateLabel(ctx context.Context, label *dto.Label) (*dto.Label, error) {
+	if err := uc.access.RequireProjectPermission(ctx, label.ProjectID, entity.PermissionEdit); err != nil {
+		return nil, err
+	}
+
+	entityLabel := &entity.Label{
+		ProjectID: label.ProjectID,
+		Name:      strings.TrimSpace(label.Name),
+		Color:     label.Color,
+	}
+	if entityLabel.Color == "" {
+		entityLabel.Color = defaultLabelColor
+	}
+	if err := validateLabel(entityLabel); err != nil {
+		return nil, err
+	}
+
+	createdLabel, err := uc.repo.CreateLabel(ctx, entityLabel)
+	if err != nil {
+		return nil, err
+	}
+
+	return toLabelDTO(createdLabel, createdLabel.ProjectID), nil
+}
+
+func (uc *LabelUseCaseImpl) GetLabelByID(ctx context.Context, id int) (*dto.Label, error) {
+	label, err := uc.repo.GetLabelByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.access.RequireProjectPermission(ctx, label.ProjectID, entity.PermissionRead); err != nil {
+		return nil, err
+	}
+
+	return toLabelDTO(label, label.ProjectID), nil
+}
+
+func (uc *LabelUseCaseImpl) UpdateLabel(ctx context.Context, label *dto.Label) (*dto.Label, error) {
+	entityLabel, err := uc.repo.GetLabelByID(ctx, label.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.access.RequireProjectPermission(ctx, entityLabel.ProjectID, entity.PermissionEdit); err != nil {
+		return nil, err
+	}
+
+	if label.Name != "" {
+		entityLabel.Name = strings.TrimSpace(label.Name)
+	}
+	if label.Color != "" {
+		entityLabel.Color = label.Color
+	}
+	if err := validateLabel(entityLabel); err != nil {
+		return nil, err
+	}
+
+	updatedLabel, err := uc.repo.UpdateLabel(ctx, entityLabel)
+	if err != nil {
+		return nil, err
+	}
+
+	return toLabelDTO(updatedLabel, updatedLabel.ProjectID), nil
+}
+
+func (uc *LabelUseCaseImpl) DeleteLabel(ctx context.Context, id int) error {
+	label, err := uc.repo.GetLabelByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.access.RequireProjectPermission(ctx, label.ProjectID, entity.PermissionEdit); err != nil {
+		return err
+	}
+
+	return uc.repo.DeleteLabel(ctx, id)
+}
+
+func (uc *LabelUseCaseImpl) GetLabelsByProjectID(ctx context.Context, projectID int) ([]*dto.Label, error) {
+	if err := uc.access.RequireProjectPermission(ctx, projectID, entity.PermissionRead); err != nil {
+		return nil, err
+	}
+
+	labels, err := uc.repo.GetAvailableLabels(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	dtoLabels := make([]*dto.Label, 0, len(labels))
+	for _, l := range labels {
+		dtoLabels = append(dtoLabels, toLabelDTO(l, projectID))
+	}
+	return dtoLabels, nil
+}
+
+func (uc *LabelUseCaseImpl) AttachLabelToTask(ctx context.Context, taskID int, labelID int) error {
+	projectID, err := uc.access.RequireTaskPermission(ctx, taskID, entity.PermissionEdit)
+	if err != nil {
+		return err
+	}
+
+	// Only labels of the task's project or of one of its ancestors may be attached.
+	available, err := uc.repo.GetAvailableLabels(ctx, projectID)
+	if err != nil {
+		return err
+	}
+	for _, l := range available {
+		if l.ID == labelID {
+			return uc.repo.AttachLabelToTask(ctx, taskID, labelID)
+		}
+	}
+
+	return fmt.Errorf("%w: label %d is not available in project %d", domain_error.ErrValidation, labelID, projectID)
+}
+
+func (uc *LabelUseCaseImpl) DetachLabelFromTask(ctx context.Context, taskID int, labelID int) error {
+	if _, err := uc.access.RequireTaskPermission(ctx, taskID, entity.PermissionEdit); err != nil {
+		return err
+	}
+
+	return uc.repo.DetachLabelFromTask(ctx, taskID, labelID)
+}
+
+func validateLabel(label *entity.Label) error {
+	if label.Name == "" {
+		return fmt.Errorf("%w: label name is required", domain_error.ErrValidation)
+	}
+	if !colorPattern.MatchString(label.Color) {
+		return fmt.Errorf("%w: label color must look like #RRGGBB", domain_error.ErrValidation)
+	}
+	return nil
+}
+
+// toLabelDTO maps a label as seen from projectID; labels of other projects are inherited ones.
+func toLabelDTO(label *entity.Label, projectID int) *dto.Label {
+	return &dto.Label{
+		ID:        label.ID,
+		ProjectID: label.ProjectID,
+		Name:      label.Name,
+		Color:     label.Color,
+		Inherited: label.ProjectID != projectID,
+		CreatedAt: label.CreatedAt,
+		UpdatedAt: label.UpdatedAt,
+	}
+}
@@ -0,0 +1,15 @@
+package markdown_usecase
+
+import (
+	"DataTask/internal/domain/dto"
+	"context"
+)
+
+// MarkdownUseCase renders task descriptions and comments to sanitized HTML, with mentions linked
+// to the mentioned users.
+type MarkdownUseCase interface {
+	// RenderTasks sets the DescriptionHTML of the tasks.
+	RenderTasks(ctx context.Context, tasks []*dto.Task) error
+	// RenderComments sets the TextHTML of the comments from their Text and Mentions.
+	RenderComments(ctx context.Context, comments []*dto.Comment) error
+}
@@ -0,0 +1,103 @@
+package markdown_usecase
+
+import (
+	"DataTask/internal/domain/dto"
+	"DataTask/internal/repository/mention_repository"
+	"DataTask/pkg/markdown"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+type MarkdownUseCaseImpl struct {
+	mentionRepo mention_repository.MentionRepository
+	cache       *markdown.Cache
+
+	// mentionURL is the link of a mentioned user, with {id} standing for the user ID.
+	mentionURL string
+}
+
+func NewMarkdownUseCase(
+	mentionRepo mention_repository.MentionRepository,
+	cacheSize int,
+	mentionURL string,
+) *MarkdownUseCaseImpl {
+	return &MarkdownUseCaseImpl{
+		mentionRepo: mentionRepo,
+		cache:       markdown.NewCache(cacheSize),
+		mentionURL:  mentionURL,
+	}
+}
+
+func (uc *MarkdownUseCaseImpl) RenderTasks(ctx context.Context, tasks []*dto.Task) error {
+	taskIDs := make([]int, 0, len(tasks))
+	for _, t := range tasks {
+		taskIDs = append(taskIDs, t.ID)
+	}
+	mentionsByTask, err := uc.mentionRepo.GetDescriptionMentions(ctx, taskIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, t := range tasks {
+		users := make(map[string]int, len(mentionsByTask[t.ID]))
+		for _, m := range mentionsByTask[t.ID] {
+			users[m.Handle] = m.User.ID
+		}
+		html := uc.render(t.Description, users)
+		t.DescriptionHTML = &html
+	}
+	return nil
+}
+
+func (uc *MarkdownUseCaseImpl) RenderComments(ctx context.Context, comments []*dto.Comment) error {
+	for _, c := range comments {
+		users := make(map[string]int, len(c.Mentions))
+		for _, m := range c.Mentions {
+			users[m.Handle] = m.User.ID
+		}
+		html := uc.render(c.Text, users)
+		c.TextHTML = &html
+	}
+	return nil
+}
+
+// render returns the HTML of source, in which the handles of users are linked to them.
+func (uc *MarkdownUseCaseImpl) render(source string, users map[string]int) string {
+	version := contentVersion(source, users)
+	if html, ok := uc.cache.Get(version); ok {
+		return html
+	}
+
+	html := markdown.Render(source, markdown.Options{
+		Mention: func(handle string) (string, bool) {
+			userID, ok := users[handle]
+			if !ok {
+				return "", false
+			}
+			return strings.ReplaceAll(uc.mentionURL, "{id}", strconv.Itoa(userID)), true
+		},
+	})
+	uc.cache.Add(version, html)
+	return html
+}
+
+// contentVersion identifies what the HTML is rendered from: the source and the users its mentions
+// resolve to. Editing either yields a new version, so cached HTML never goes stale.
+func contentVersion(source string, users map[string]int) string {
+	handles := make([]string, 0, len(users))
+	for handle := range users {
+		handles = append(handles, handle)
+	}
+	slices.Sort(handles)
+
+	h := sha256.New()
+	h.Write([]byte(source))
+	for _, handle := range handles {
+		h.Write([]byte("\x00" + handle + "=" + strconv.Itoa(users[handle])))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,22 @@
+package mention_usecase
+
+import (
+	"DataTask/internal/domain/dto"
+	"context"
+)
+
+// MentionUseCase keeps track of the users @mentioned in task descriptions and comments. Handles
+// are resolved to members of the task's project; handles that match no member, or several, are
+// left as plain text.
+type MentionUseCase interface {
+	// SyncTaskMentions records the mentions in a task description and notifies newly mentioned
+	// users. It returns the mentions now stored.
+	SyncTaskMentions(ctx context.Context, taskID int, description string) ([]*dto.Mention, error)
+	// SyncCommentMentions records the mentions in a comment and notifies the mentioned users. It
+	// returns the mentions now stored.
+	SyncCommentMentions(ctx context.Context, taskID int, commentID int, text string) ([]*dto.Mention, error)
+
+	GetTaskMentions(ctx context.Context, taskID int) ([]*dto.Mention, error)
+	// GetCommentMentions returns the mentions in the comments on a task by comment ID.
+	GetCommentMentions(ctx context.Context, taskID int) (map[int][]*dto.Mention, error)
+}
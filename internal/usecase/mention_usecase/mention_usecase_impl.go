@@ -0,0 +1,219 @@
+package mention_usecase
+
+import (
+	"DataTask/internal/domain/dto"
+	"DataTask/internal/domain/entity"
+	"DataTask/internal/notifier"
+	"DataTask/internal/repository/database"
+	"DataTask/internal/repository/mention_repository"
+	"DataTask/internal/repository/project_repository"
+	"DataTask/internal/repository/task_repository"
+	"DataTask/internal/usecase/access_usecase"
+	"DataTask/pkg/logger"
+	"DataTask/pkg/mention"
+	"context"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"time"
+)
+
+type MentionUseCaseImpl struct {
+	repo        mention_repository.MentionRepository
+	taskRepo    task_repository.TaskRepository
+	projectRepo project_repository.ProjectRepository
+	access      access_usecase.AccessUseCase
+	notifier    notifier.Notifier
+}
+
+func NewMentionUseCase(
+	repo mention_repository.MentionRepository,
+	taskRepo task_repository.TaskRepository,
+	projectRepo project_repository.ProjectRepository,
+	access access_usecase.AccessUseCase,
+	notifier notifier.Notifier,
+) *MentionUseCaseImpl {
+	return &MentionUseCaseImpl{
+		repo:        repo,
+		taskRepo:    taskRepo,
+		projectRepo: projectRepo,
+		access:      access,
+		notifier:    notifier,
+	}
+}
+
+func (uc *MentionUseCaseImpl) SyncTaskMentions(ctx context.Context, taskID int, description string) ([]*dto.Mention, error) {
+	return uc.sync(ctx, taskID, nil, description)
+}
+
+func (uc *MentionUseCaseImpl) SyncCommentMentions(
+	ctx context.Context,
+	taskID int,
+	commentID int,
+	text string,
+) ([]*dto.Mention, error) {
+	return uc.sync(ctx, taskID, &commentID, text)
+}
+
+func (uc *MentionUseCaseImpl) GetTaskMentions(ctx context.Context, taskID int) ([]*dto.Mention, error) {
+	mentions, err := uc.repo.GetMentions(ctx, taskID, nil)
+	if err != nil {
+		return nil, err
+	}
+	return toMentionDTOs(mentions), nil
+}
+
+func (uc *MentionUseCaseImpl) GetCommentMentions(ctx context.Context, taskID int) (map[int][]*dto.Mention, error) {
+	mentionsByComment, err := uc.repo.GetCommentMentions(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[int][]*dto.Mention, len(mentionsByComment))
+	for commentID, mentions := range mentionsByComment {
+		result[commentID] = toMentionDTOs(mentions)
+	}
+	return result, nil
+}
+
+// sync replaces the stored mentions of a description or comment with the ones in text. Handles
+// that were already resolved keep their user, so a mention survives the user changing their
+// email address as long as the text still has the handle.
+func (uc *MentionUseCaseImpl) sync(ctx context.Context, taskID int, commentID *int, text string) ([]*dto.Mention, error) {
+	handles := mention.Parse(text)
+	existing, err := uc.repo.GetMentions(ctx, taskID, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if len(handles) == 0 && len(existing) == 0 {
+		return []*dto.Mention{}, nil
+	}
+
+	resolved := make(map[string]*entity.User, len(existing))
+	alreadyMentioned := make(map[int]bool, len(existing))
+	for _, m := range existing {
+		resolved[m.Handle] = m.User
+		alreadyMentioned[m.User.ID] = true
+	}
+
+	var members []*entity.User
+	mentions := make([]*entity.Mention, 0, len(handles))
+	for _, handle := range handles {
+		user := resolved[handle]
+		if user == nil {
+			if members == nil {
+				if members, err = uc.projectMembers(ctx, taskID); err != nil {
+					return nil, err
+				}
+			}
+			user = resolve(handle, members)
+		}
+		if user == nil {
+			continue
+		}
+		mentions = append(mentions, &entity.Mention{
+			TaskID:    taskID,
+			CommentID: commentID,
+			Handle:    handle,
+			User:      user,
+		})
+	}
+
+	if err := uc.repo.ReplaceMentions(ctx, taskID, commentID, mentions); err != nil {
+		return nil, err
+	}
+	uc.notify(ctx, taskID, commentID, mentions, alreadyMentioned)
+	return toMentionDTOs(mentions), nil
+}
+
+func (uc *MentionUseCaseImpl) projectMembers(ctx context.Context, taskID int) ([]*entity.User, error) {
+	projectID, err := uc.projectRepo.GetProjectIDByTaskID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	return uc.projectRepo.GetProjectMembers(ctx, projectID)
+}
+
+// resolve returns the only member the handle refers to, or nil.
+func resolve(handle string, members []*entity.User) *entity.User {
+	var found *entity.User
+	for _, u := range members {
+		if !mention.Matches(handle, u.Email) {
+			continue
+		}
+		if found != nil {
+			return nil
+		}
+		found = u
+	}
+	return found
+}
+
+// notify tells the users mentioned for the first time in the text, except the current user.
+func (uc *MentionUseCaseImpl) notify(
+	ctx context.Context,
+	taskID int,
+	commentID *int,
+	mentions []*entity.Mention,
+	alreadyMentioned map[int]bool,
+) {
+	currentUserID, _ := uc.access.CurrentUserID(ctx)
+	var task *entity.Task
+	for _, m := range mentions {
+		if alreadyMentioned[m.User.ID] || m.User.ID == currentUserID {
+			continue
+		}
+		alreadyMentioned[m.User.ID] = true
+
+		if task == nil {
+			var err error
+			if task, err = uc.taskRepo.GetTaskByID(ctx, taskID); err != nil {
+				logger.Log.WithFields(log.Fields{"task_id": taskID}).WithError(err).Error("failed to load task for notification")
+				return
+			}
+		}
+
+		notification := &entity.Notification{
+			Type:      entity.NotificationTaskMentioned,
+			UserID:    m.User.ID,
+			TaskID:    taskID,
+			Message:   fmt.Sprintf("You were mentioned in task %q", task.Title),
+			Payload:   map[string]any{"mentioned_by": currentUserID},
+			CreatedAt: time.Now(),
+		}
+		if commentID != nil {
+			notification.Message = fmt.Sprintf("You were mentioned in a comment on task %q", task.Title)
+			notification.Payload["comment_id"] = *commentID
+		}
+		uc.deliver(ctx, notification)
+	}
+}
+
+// deliver sends the notification once the surrounding transaction, if any, has been committed.
+func (uc *MentionUseCaseImpl) deliver(ctx context.Context, notification *entity.Notification) {
+	database.AfterCommit(ctx, func(ctx context.Context) {
+		if err := uc.notifier.Notify(ctx, notification); err != nil {
+			logger.Log.WithFields(log.Fields{
+				"type":    notification.Type,
+				"user_id": notification.UserID,
+				"task_id": notification.TaskID,
+			}).WithError(err).Error("failed to deliver notification")
+		}
+	})
+}
+
+func toMentionDTOs(mentions []*entity.Mention) []*dto.Mention {
+	result := make([]*dto.Mention, 0, len(mentions))
+	for _, m := range mentions {
+		result = append(result, &dto.Mention{
+			Handle: m.Handle,
+			User: &dto.User{
+				ID:        m.User.ID,
+				Name:      m.User.Name,
+				Surname:   m.User.Surname,
+				Email:     m.User.Email,
+				AvatarURL: m.User.AvatarURL,
+			},
+		})
+	}
+	return result
+}
@@ -19,4 +19,10 @@ type ProjectUseCase interface {
 	GetUserPermissionsForProject(ctx context.Context, projectID int, userID int) (string, error)
 	GetUsersInProject(ctx context.Context, projectID int) ([]*dto.User, error)
 	AcceptProjectInvitation(ctx context.Context, projectID int, userID int) error
+
+	GetProjectSettings(ctx context.Context, projectID int) (*dto.ProjectSettings, error)
+	UpdateProjectSettings(ctx context.Context, projectID int, update *dto.ProjectSettingsUpdate) (*dto.ProjectSettings, error)
+
+	// GetProjectEstimate rolls the task estimates of the project and all its subprojects up.
+	GetProjectEstimate(ctx context.Context, projectID int) (*dto.ProjectEstimate, error)
 }
@@ -1,19 +1,39 @@
 package project_usecase
 
 import (
+	"DataTask/internal/domain/domain_error"
 	"DataTask/internal/domain/dto"
 	"DataTask/internal/domain/entity"
+	"DataTask/internal/repository/database"
 	"DataTask/internal/repository/project_repository"
+	"DataTask/internal/repository/workflow_repository"
+	"DataTask/internal/usecase/access_usecase"
+	"DataTask/pkg/taskkey"
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 )
 
 type ProjectUseCaseImpl struct {
-	repo project_repository.ProjectRepository
+	repo         project_repository.ProjectRepository
+	workflowRepo workflow_repository.WorkflowRepository
+	access       access_usecase.AccessUseCase
+	transactor   database.Transactor
 }
 
-func NewProjectUseCase(repo project_repository.ProjectRepository) *ProjectUseCaseImpl {
-	return &ProjectUseCaseImpl{repo: repo}
+func NewProjectUseCase(
+	repo project_repository.ProjectRepository,
+	workflowRepo workflow_repository.WorkflowRepository,
+	access access_usecase.AccessUseCase,
+	transactor database.Transactor,
+) *ProjectUseCaseImpl {
+	return &ProjectUseCaseImpl{
+		repo:         repo,
+		workflowRepo: workflowRepo,
+		access:       access,
+		transactor:   transactor,
+	}
 }
 
 func (uc *ProjectUseCaseImpl) CreateProject(ctx context.Context, project *dto.Project) (*dto.Project, error) {
@@ -23,9 +43,23 @@ func (uc *ProjectUseCaseImpl) CreateProject(ctx context.Context, project *dto.Pr
 		Description:     project.Description,
 		Color:           project.Color,
 		ParentProjectID: project.ParentProjectID,
+		Key:             strings.ToUpper(strings.TrimSpace(project.Key)),
+	}
+	// Without a key, one is derived from the name when the project is stored.
+	if entityProject.Key != "" && !taskkey.ValidProjectKey(entityProject.Key) {
+		return nil, fmt.Errorf("%w: key must be 2 to 10 letters or digits starting with a letter, e.g. DT",
+			domain_error.ErrValidation)
 	}
 
-	createdProject, err := uc.repo.CreateProject(ctx, entityProject)
+	var createdProject *entity.Project
+	err := uc.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		createdProject, err = uc.repo.CreateProject(ctx, entityProject)
+		if err != nil {
+			return err
+		}
+		return uc.workflowRepo.CreateDefaultStatuses(ctx, createdProject.ID)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -37,6 +71,7 @@ func (uc *ProjectUseCaseImpl) CreateProject(ctx context.Context, project *dto.Pr
 		Description:     createdProject.Description,
 		Color:           createdProject.Color,
 		ParentProjectID: createdProject.ParentProjectID,
+		Key:             createdProject.Key,
 		CreatedAt:       createdProject.CreatedAt,
 		UpdatedAt:       createdProject.UpdatedAt,
 	}
@@ -57,6 +92,7 @@ func (uc *ProjectUseCaseImpl) GetProjectByID(ctx context.Context, id int) (*dto.
 		Description:     project.Description,
 		Color:           project.Color,
 		ParentProjectID: project.ParentProjectID,
+		Key:             project.Key,
 		CreatedAt:       project.CreatedAt,
 		UpdatedAt:       project.UpdatedAt,
 	}
@@ -86,6 +122,7 @@ func (uc *ProjectUseCaseImpl) UpdateProject(ctx context.Context, project *dto.Pr
 		Description:     updatedProject.Description,
 		Color:           updatedProject.Color,
 		ParentProjectID: updatedProject.ParentProjectID,
+		Key:             updatedProject.Key,
 		CreatedAt:       updatedProject.CreatedAt,
 		UpdatedAt:       updatedProject.UpdatedAt,
 	}
@@ -125,11 +162,15 @@ func (uc *ProjectUseCaseImpl) GetProjectsByOwnerID(ctx context.Context, ownerID
 			Description:     p.Description,
 			Color:           p.Color,
 			ParentProjectID: p.ParentProjectID,
+			Key:             p.Key,
 			CreatedAt:       p.CreatedAt,
 			UpdatedAt:       p.UpdatedAt,
 		})
 	}
 
+	if err := uc.attachEstimates(ctx, dtoProjects); err != nil {
+		return nil, err
+	}
 	return dtoProjects, nil
 }
 
@@ -148,11 +189,15 @@ func (uc *ProjectUseCaseImpl) GetSharedProjectsByOwnerID(ctx context.Context, ow
 			Description:     p.Description,
 			Color:           p.Color,
 			ParentProjectID: p.ParentProjectID,
+			Key:             p.Key,
 			CreatedAt:       p.CreatedAt,
 			UpdatedAt:       p.UpdatedAt,
 		})
 	}
 
+	if err := uc.attachEstimates(ctx, dtoProjects); err != nil {
+		return nil, err
+	}
 	return dtoProjects, nil
 }
 
@@ -171,11 +216,15 @@ func (uc *ProjectUseCaseImpl) GetSubprojects(ctx context.Context, parentProjectI
 			Description:     p.Description,
 			Color:           p.Color,
 			ParentProjectID: p.ParentProjectID,
+			Key:             p.Key,
 			CreatedAt:       p.CreatedAt,
 			UpdatedAt:       p.UpdatedAt,
 		})
 	}
 
+	if err := uc.attachEstimates(ctx, dtoProjects); err != nil {
+		return nil, err
+	}
 	return dtoProjects, nil
 }
 
@@ -195,6 +244,7 @@ func (uc *ProjectUseCaseImpl) GetProjectsByTaskID(ctx context.Context, taskID in
 			Description:     p.Description,
 			Color:           p.Color,
 			ParentProjectID: p.ParentProjectID,
+			Key:             p.Key,
 			CreatedAt:       p.CreatedAt,
 			UpdatedAt:       p.UpdatedAt,
 		})
@@ -254,3 +304,148 @@ func (uc *ProjectUseCaseImpl) AcceptProjectInvitation(ctx context.Context, proje
 	}
 	return nil
 }
+
+func (uc *ProjectUseCaseImpl) GetProjectSettings(ctx context.Context, projectID int) (*dto.ProjectSettings, error) {
+	if err := uc.access.RequireProjectPermission(ctx, projectID, entity.PermissionRead); err != nil {
+		return nil, err
+	}
+
+	settings, err := uc.repo.GetProjectSettings(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	return toProjectSettingsDTO(settings), nil
+}
+
+func (uc *ProjectUseCaseImpl) UpdateProjectSettings(ctx context.Context, projectID int, update *dto.ProjectSettingsUpdate) (*dto.ProjectSettings, error) {
+	if err := uc.access.RequireProjectPermission(ctx, projectID, entity.PermissionOwner); err != nil {
+		return nil, err
+	}
+
+	settings, err := uc.repo.GetProjectSettings(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if update.BlockedCompletionPolicy != nil {
+		settings.BlockedCompletionPolicy = *update.BlockedCompletionPolicy
+	}
+	if update.EstimateUnit != nil {
+		settings.EstimateUnit = *update.EstimateUnit
+	}
+	if update.WIPLimitPolicy != nil {
+		settings.WIPLimitPolicy = *update.WIPLimitPolicy
+	}
+	if err := validateProjectSettings(settings); err != nil {
+		return nil, err
+	}
+
+	savedSettings, err := uc.repo.UpsertProjectSettings(ctx, settings)
+	if err != nil {
+		return nil, err
+	}
+	return toProjectSettingsDTO(savedSettings), nil
+}
+
+func (uc *ProjectUseCaseImpl) GetProjectEstimate(ctx context.Context, projectID int) (*dto.ProjectEstimate, error) {
+	if err := uc.access.RequireProjectPermission(ctx, projectID, entity.PermissionRead); err != nil {
+		return nil, err
+	}
+
+	rows, err := uc.repo.GetEstimateRows(ctx, []int{projectID})
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%w: project %d", domain_error.ErrNotFound, projectID)
+	}
+	return rollUpEstimates(rows)[projectID], nil
+}
+
+// attachEstimates fills the estimate rollups of project list entries in one query.
+func (uc *ProjectUseCaseImpl) attachEstimates(ctx context.Context, projects []*dto.Project) error {
+	if len(projects) == 0 {
+		return nil
+	}
+
+	projectIDs := make([]int, 0, len(projects))
+	for _, p := range projects {
+		projectIDs = append(projectIDs, p.ID)
+	}
+
+	rows, err := uc.repo.GetEstimateRows(ctx, projectIDs)
+	if err != nil {
+		return err
+	}
+
+	estimates := rollUpEstimates(rows)
+	for _, p := range projects {
+		p.Estimate = estimates[p.ID]
+	}
+	return nil
+}
+
+// rollUpEstimates adds the per-project rows up to one estimate per root project. Only projects
+// estimating in the unit of the root are counted, the others are reported as skipped.
+func rollUpEstimates(rows []*entity.ProjectEstimateRow) map[int]*dto.ProjectEstimate {
+	estimates := make(map[int]*dto.ProjectEstimate)
+	for _, row := range rows {
+		if row.ProjectID == row.RootProjectID {
+			estimates[row.RootProjectID] = &dto.ProjectEstimate{
+				ProjectID:         row.RootProjectID,
+				Unit:              row.Unit,
+				SkippedProjectIDs: []int{},
+			}
+		}
+	}
+
+	for _, row := range rows {
+		estimate := estimates[row.RootProjectID]
+		if estimate == nil {
+			continue
+		}
+		if row.Unit != estimate.Unit {
+			estimate.SkippedProjectIDs = append(estimate.SkippedProjectIDs, row.ProjectID)
+			continue
+		}
+		estimate.Total += row.Total
+		estimate.Completed += row.Completed
+		estimate.TaskCount += row.TaskCount
+		estimate.EstimatedTaskCount += row.EstimatedTaskCount
+	}
+
+	for _, estimate := range estimates {
+		estimate.Remaining = estimate.Total - estimate.Completed
+		sort.Ints(estimate.SkippedProjectIDs)
+	}
+	return estimates
+}
+
+func validateProjectSettings(settings *entity.ProjectSettings) error {
+	switch settings.BlockedCompletionPolicy {
+	case entity.BlockedCompletionWarn, entity.BlockedCompletionFail:
+	default:
+		return fmt.Errorf("%w: unknown blocked completion policy %q", domain_error.ErrValidation, settings.BlockedCompletionPolicy)
+	}
+	switch settings.EstimateUnit {
+	case entity.EstimateUnitHours, entity.EstimateUnitStoryPoints:
+	default:
+		return fmt.Errorf("%w: unknown estimate unit %q", domain_error.ErrValidation, settings.EstimateUnit)
+	}
+	switch settings.WIPLimitPolicy {
+	case entity.WIPLimitWarn, entity.WIPLimitFail:
+	default:
+		return fmt.Errorf("%w: unknown WIP limit policy %q", domain_error.ErrValidation, settings.WIPLimitPolicy)
+	}
+	return nil
+}
+
+func toProjectSettingsDTO(settings *entity.ProjectSettings) *dto.ProjectSettings {
+	return &dto.ProjectSettings{
+		ProjectID:               settings.ProjectID,
+		BlockedCompletionPolicy: settings.BlockedCompletionPolicy,
+		EstimateUnit:            settings.EstimateUnit,
+		WIPLimitPolicy:          settings.WIPLimitPolicy,
+		UpdatedAt:               settings.UpdatedAt,
+	}
+}
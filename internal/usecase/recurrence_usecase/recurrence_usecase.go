@@ -0,0 +1,19 @@
+package recurrence_usecase
+
+import (
+	"DataTask/internal/domain/dto"
+	"context"
+)
+
+type RecurrenceUseCase interface {
+	// SetRecurrence makes the task recurring or changes the rule of its series.
+	SetRecurrence(ctx context.Context, taskID int, update *dto.RecurrenceUpdate) (*dto.TaskRecurrence, error)
+	GetRecurrence(ctx context.Context, taskID int) (*dto.TaskRecurrence, error)
+	// StopRecurrence ends the series at the task and removes its open future occurrences.
+	StopRecurrence(ctx context.Context, taskID int) error
+
+	// OnTaskCompleted creates the next occurrence after a recurring task was completed.
+	OnTaskCompleted(ctx context.Context, taskID int) error
+	// GenerateUpcoming creates the occurrences falling within the lookahead window of every series.
+	GenerateUpcoming(ctx context.Context) error
+}
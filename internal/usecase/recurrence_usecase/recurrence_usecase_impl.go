@@ -0,0 +1,410 @@
+package recurrence_usecase
+
+import (
+	"DataTask/internal/domain/domain_error"
+	"DataTask/internal/domain/dto"
+	"DataTask/internal/domain/entity"
+	"DataTask/internal/repository/database"
+	"DataTask/internal/repository/recurrence_repository"
+	"DataTask/internal/repository/task_repository"
+	"DataTask/internal/usecase/access_usecase"
+	"DataTask/pkg/logger"
+	"DataTask/pkg/rrule"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// maxOccurrencesPerRun keeps a series that was not generated for a long time from flooding its board.
+const maxOccurrencesPerRun = 50
+
+type RecurrenceUseCaseImpl struct {
+	repo       recurrence_repository.RecurrenceRepository
+	taskRepo   task_repository.TaskRepository
+	access     access_usecase.AccessUseCase
+	transactor database.Transactor
+	lookahead  time.Duration
+}
+
+func NewRecurrenceUseCase(
+	repo recurrence_repository.RecurrenceRepository,
+	taskRepo task_repository.TaskRepository,
+	access access_usecase.AccessUseCase,
+	transactor database.Transactor,
+	lookahead time.Duration,
+) *RecurrenceUseCaseImpl {
+	return &RecurrenceUseCaseImpl{
+		repo:       repo,
+		taskRepo:   taskRepo,
+		access:     access,
+		transactor: transactor,
+		lookahead:  lookahead,
+	}
+}
+
+func (uc *RecurrenceUseCaseImpl) SetRecurrence(ctx context.Context, taskID int, update *dto.RecurrenceUpdate) (*dto.TaskRecurrence, error) {
+	if _, err := uc.access.RequireTaskPermission(ctx, taskID, entity.PermissionEdit); err != nil {
+		return nil, err
+	}
+	userID, err := uc.access.CurrentUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := rrule.Parse(update.RRule); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain_error.ErrValidation, err)
+	}
+	scope := update.Scope
+	if scope == "" {
+		scope = entity.RecurrenceScopeThisAndFuture
+	}
+	if scope != entity.RecurrenceScopeThisAndFuture && scope != entity.RecurrenceScopeAll {
+		return nil, fmt.Errorf("%w: unknown scope %q", domain_error.ErrValidation, scope)
+	}
+
+	task, err := uc.taskRepo.GetTaskByID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	var series *entity.TaskRecurrence
+	err = uc.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		switch {
+		case task.RecurrenceID == nil:
+			series, err = uc.startSeries(ctx, task, update, userID)
+		case scope == entity.RecurrenceScopeAll:
+			series, err = uc.changeSeriesRule(ctx, task, update.RRule)
+		default:
+			series, err = uc.splitSeries(ctx, task, update.RRule, userID)
+		}
+		if err != nil {
+			return err
+		}
+
+		if task.IsCompleted {
+			return uc.OnTaskCompleted(ctx, taskID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toTaskRecurrenceDTO(series), nil
+}
+
+func (uc *RecurrenceUseCaseImpl) GetRecurrence(ctx context.Context, taskID int) (*dto.TaskRecurrence, error) {
+	if _, err := uc.access.RequireTaskPermission(ctx, taskID, entity.PermissionRead); err != nil {
+		return nil, err
+	}
+
+	task, err := uc.taskRepo.GetTaskByID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if task.RecurrenceID == nil {
+		return nil, fmt.Errorf("%w: task %d does not recur", domain_error.ErrNotFound, taskID)
+	}
+
+	series, err := uc.repo.GetRecurrenceByID(ctx, *task.RecurrenceID)
+	if err != nil {
+		return nil, err
+	}
+	return toTaskRecurrenceDTO(series), nil
+}
+
+func (uc *RecurrenceUseCaseImpl) StopRecurrence(ctx context.Context, taskID int) error {
+	if _, err := uc.access.RequireTaskPermission(ctx, taskID, entity.PermissionEdit); err != nil {
+		return err
+	}
+
+	task, err := uc.taskRepo.GetTaskByID(ctx, taskID)
+	if err != nil {
+		return err
+	}
+	if task.RecurrenceID == nil {
+		return fmt.Errorf("%w: task %d does not recur", domain_error.ErrNotFound, taskID)
+	}
+
+	series, err := uc.repo.GetRecurrenceByID(ctx, *task.RecurrenceID)
+	if err != nil {
+		return err
+	}
+
+	return uc.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := uc.repo.DeleteOpenOccurrencesAfter(ctx, series.ID, *task.OccurrenceAt); err != nil {
+			return err
+		}
+		if err := uc.repo.SetTaskRecurrence(ctx, task.ID, nil, nil); err != nil {
+			return err
+		}
+
+		// Earlier occurrences stay in the series for reference, it just does not continue.
+		series.EndsAt = task.OccurrenceAt
+		_, err := uc.repo.UpdateRecurrence(ctx, series)
+		return err
+	})
+}
+
+func (uc *RecurrenceUseCaseImpl) OnTaskCompleted(ctx context.Context, taskID int) error {
+	task, err := uc.taskRepo.GetTaskByID(ctx, taskID)
+	if err != nil {
+		return err
+	}
+	if task.RecurrenceID == nil {
+		return nil
+	}
+
+	series, err := uc.repo.GetRecurrenceByID(ctx, *task.RecurrenceID)
+	if err != nil {
+		return err
+	}
+	if series.LastOccurrenceAt.After(*task.OccurrenceAt) {
+		// The next occurrence was already generated on schedule.
+		return nil
+	}
+
+	_, err = uc.generateNext(ctx, series)
+	return err
+}
+
+func (uc *RecurrenceUseCaseImpl) GenerateUpcoming(ctx context.Context) error {
+	seriesList, err := uc.repo.GetActiveRecurrences(ctx)
+	if err != nil {
+		return err
+	}
+
+	horizon := time.Now().Add(uc.lookahead)
+	var errs []error
+	for _, series := range seriesList {
+		created := 0
+		for created < maxOccurrencesPerRun {
+			next, ok := nextOccurrence(series)
+			if !ok || next.After(horizon) {
+				break
+			}
+
+			generated, err := uc.generateNext(ctx, series)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("recurrence %d: %w", series.ID, err))
+				break
+			}
+			if !generated {
+				break
+			}
+			created++
+		}
+
+		if created > 0 {
+			logger.Log.Infof("Created %d occurrences of recurrence %d", created, series.ID)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// startSeries turns a plain task into the first occurrence of a new series.
+func (uc *RecurrenceUseCaseImpl) startSeries(ctx context.Context, task *entity.Task, update *dto.RecurrenceUpdate, userID int) (*entity.TaskRecurrence, error) {
+	dtstart := time.Now().Truncate(time.Minute)
+	switch {
+	case update.DTStart != nil:
+		dtstart = *update.DTStart
+	case task.DueAt != nil:
+		dtstart = *task.DueAt
+	case task.StartAt != nil:
+		dtstart = *task.StartAt
+	}
+
+	series, err := uc.repo.CreateRecurrence(ctx, &entity.TaskRecurrence{
+		RRule:            update.RRule,
+		DTStart:          dtstart,
+		TimeZone:         task.TimeZone,
+		LastOccurrenceAt: dtstart,
+		CreatedBy:        &userID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.repo.SetTaskRecurrence(ctx, task.ID, &series.ID, &dtstart); err != nil {
+		return nil, err
+	}
+	task.RecurrenceID, task.OccurrenceAt = &series.ID, &dtstart
+	return series, nil
+}
+
+// changeSeriesRule replaces the rule of the whole series. Open occurrences after the task are
+// dropped so the generator recreates them under the new rule.
+func (uc *RecurrenceUseCaseImpl) changeSeriesRule(ctx context.Context, task *entity.Task, rule string) (*entity.TaskRecurrence, error) {
+	series, err := uc.repo.GetRecurrenceByID(ctx, *task.RecurrenceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.repo.DeleteOpenOccurrencesAfter(ctx, series.ID, *task.OccurrenceAt); err != nil {
+		return nil, err
+	}
+	series.RRule = rule
+	if _, err := uc.repo.UpdateRecurrence(ctx, series); err != nil {
+		return nil, err
+	}
+	if err := uc.repo.RefreshLastOccurrence(ctx, series.ID); err != nil {
+		return nil, err
+	}
+	return uc.repo.GetRecurrenceByID(ctx, series.ID)
+}
+
+// splitSeries ends the current series before the task and continues from the task with the new rule.
+func (uc *RecurrenceUseCaseImpl) splitSeries(ctx context.Context, task *entity.Task, rule string, userID int) (*entity.TaskRecurrence, error) {
+	series, err := uc.repo.GetRecurrenceByID(ctx, *task.RecurrenceID)
+	if err != nil {
+		return nil, err
+	}
+	occurrenceAt := *task.OccurrenceAt
+	if occurrenceAt.Equal(series.DTStart) {
+		// Splitting at the first occurrence changes the whole series.
+		return uc.changeSeriesRule(ctx, task, rule)
+	}
+
+	if err := uc.repo.DeleteOpenOccurrencesAfter(ctx, series.ID, occurrenceAt); err != nil {
+		return nil, err
+	}
+
+	future, err := uc.repo.CreateRecurrence(ctx, &entity.TaskRecurrence{
+		RRule:            rule,
+		DTStart:          occurrenceAt,
+		TimeZone:         series.TimeZone,
+		LastOccurrenceAt: occurrenceAt,
+		CreatedBy:        &userID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.repo.MoveOccurrences(ctx, series.ID, future.ID, occurrenceAt); err != nil {
+		return nil, err
+	}
+	if err := uc.repo.RefreshLastOccurrence(ctx, future.ID); err != nil {
+		return nil, err
+	}
+
+	series.EndsAt = &occurrenceAt
+	if _, err := uc.repo.UpdateRecurrence(ctx, series); err != nil {
+		return nil, err
+	}
+
+	task.RecurrenceID = &future.ID
+	return uc.repo.GetRecurrenceByID(ctx, future.ID)
+}
+
+// generateNext creates the occurrence following the latest generated one as a copy of the
+// latest remaining task of the series. It reports false when the series has ended or another
+// generator created the occurrence first.
+func (uc *RecurrenceUseCaseImpl) generateNext(ctx context.Context, series *entity.TaskRecurrence) (bool, error) {
+	next, ok := nextOccurrence(series)
+	if !ok {
+		return false, nil
+	}
+
+	templateID, err := uc.repo.GetLatestOccurrenceTaskID(ctx, series.ID)
+	if err != nil {
+		if errors.Is(err, domain_error.ErrNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	template, err := uc.taskRepo.GetTaskByID(ctx, templateID)
+	if err != nil {
+		return false, err
+	}
+
+	generated := false
+	err = uc.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		claimed, err := uc.repo.ClaimOccurrence(ctx, series.ID, next)
+		if err != nil || !claimed {
+			return err
+		}
+
+		occurrence, err := uc.taskRepo.CreateTask(ctx, newOccurrence(template, series.ID, next))
+		if err != nil {
+			return err
+		}
+		if err := uc.repo.CopyTaskRelations(ctx, template.ID, occurrence.ID); err != nil {
+			return err
+		}
+
+		generated = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	series.LastOccurrenceAt = next
+	return generated, nil
+}
+
+// nextOccurrence returns the occurrence following the latest generated one, if the series continues.
+func nextOccurrence(series *entity.TaskRecurrence) (time.Time, bool) {
+	rule, err := rrule.Parse(series.RRule)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	loc, err := time.LoadLocation(series.TimeZone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	next, ok := rule.After(series.DTStart.In(loc), series.LastOccurrenceAt)
+	if !ok || (series.EndsAt != nil && !next.Before(*series.EndsAt)) {
+		return time.Time{}, false
+	}
+	return next, true
+}
+
+// newOccurrence copies the template task into the given slot. Start and due dates keep their
+// distance to the slot, so a chore due two hours after it starts stays that way.
+func newOccurrence(template *entity.Task, recurrenceID int, occurrenceAt time.Time) *entity.Task {
+	return &entity.Task{
+		Title:        template.Title,
+		Description:  template.Description,
+		KanbanID:     template.KanbanID,
+		TimeZone:     template.TimeZone,
+		Priority:     template.Priority,
+		Estimate:     template.Estimate,
+		ParentTaskID: template.ParentTaskID,
+		RecurrenceID: &recurrenceID,
+		OccurrenceAt: &occurrenceAt,
+		StartAt:      shiftToOccurrence(template.StartAt, template.OccurrenceAt, occurrenceAt),
+		DueAt:        shiftToOccurrence(template.DueAt, template.OccurrenceAt, occurrenceAt),
+	}
+}
+
+func shiftToOccurrence(t *time.Time, templateOccurrenceAt *time.Time, occurrenceAt time.Time) *time.Time {
+	if t == nil || templateOccurrenceAt == nil {
+		return t
+	}
+	shifted := occurrenceAt.Add(t.Sub(*templateOccurrenceAt))
+	return &shifted
+}
+
+func toTaskRecurrenceDTO(series *entity.TaskRecurrence) *dto.TaskRecurrence {
+	loc, err := time.LoadLocation(series.TimeZone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	recurrence := &dto.TaskRecurrence{
+		ID:       series.ID,
+		RRule:    series.RRule,
+		DTStart:  series.DTStart.In(loc),
+		TimeZone: series.TimeZone,
+		EndsAt:   series.EndsAt,
+	}
+	if next, ok := nextOccurrence(series); ok {
+		next = next.In(loc)
+		recurrence.NextOccurrenceAt = &next
+	}
+	return recurrence
+}
@@ -0,0 +1,10 @@
+package reminder_usecase
+
+import "context"
+
+type ReminderUseCase interface {
+	// SendDueReminders notifies assignees of every task whose due time falls within one
+	// of the configured reminder offsets. When several offsets passed since the last poll,
+	// only the latest one is sent.
+	SendDueReminders(ctx context.Context) error
+}
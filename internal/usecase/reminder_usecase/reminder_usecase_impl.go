@@ -0,0 +1,64 @@
+package reminder_usecase
+
+import (
+	"DataTask/internal/domain/entity"
+	"DataTask/internal/notifier"
+	"DataTask/internal/repository/reminder_repository"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type ReminderUseCaseImpl struct {
+	repo     reminder_repository.ReminderRepository
+	notifier notifier.Notifier
+	offsets  []time.Duration
+}
+
+func NewReminderUseCase(
+	repo reminder_repository.ReminderRepository,
+	notifier notifier.Notifier,
+	offsets []time.Duration,
+) *ReminderUseCaseImpl {
+	return &ReminderUseCaseImpl{
+		repo:     repo,
+		notifier: notifier,
+		offsets:  offsets,
+	}
+}
+
+func (uc *ReminderUseCaseImpl) SendDueReminders(ctx context.Context) error {
+	reminders, err := uc.repo.ClaimDueReminders(ctx, uc.offsets)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, reminder := range reminders {
+		if err := uc.notifier.Notify(ctx, reminderNotification(reminder)); err != nil {
+			errs = append(errs, fmt.Errorf("notify user %d about task %d: %w", reminder.UserID, reminder.TaskID, err))
+
+			// Let the next poll retry the delivery.
+			if err := uc.repo.ReleaseReminder(ctx, reminder); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func reminderNotification(reminder *entity.TaskReminder) *entity.Notification {
+	return &entity.Notification{
+		Type:    entity.NotificationTaskReminder,
+		UserID:  reminder.UserID,
+		TaskID:  reminder.TaskID,
+		Message: fmt.Sprintf("Task %q is due at %s", reminder.TaskTitle, reminder.DueAt.Format(time.RFC3339)),
+		Payload: map[string]any{
+			"due_at":         reminder.DueAt,
+			"offset_minutes": reminder.OffsetMinutes,
+		},
+		CreatedAt: time.Now(),
+	}
+}
@@ -0,0 +1,23 @@
+package saved_view_usecase
+
+import (
+	"DataTask/internal/domain/dto"
+	"context"
+)
+
+// SavedViewUseCase manages the saved task views of projects. Personal views are only visible
+// to their owner; shared views are visible to every project member and editable by the owner
+// and by members with edit permission.
+type SavedViewUseCase interface {
+	CreateView(ctx context.Context, view *dto.SavedView) (*dto.SavedView, error)
+	GetView(ctx context.Context, projectID int, viewID int) (*dto.SavedView, error)
+	UpdateView(ctx context.Context, projectID int, viewID int, update *dto.SavedViewUpdate) (*dto.SavedView, error)
+	DeleteView(ctx context.Context, projectID int, viewID int) error
+	// GetViews returns the views the current user sees in the project, in their order.
+	GetViews(ctx context.Context, projectID int) ([]*dto.SavedView, error)
+	// ReorderViews takes the IDs of every view the current user sees in the project, in the new
+	// order. The order is the user's own and does not change anybody else's list.
+	ReorderViews(ctx context.Context, projectID int, viewIDs []int) ([]*dto.SavedView, error)
+	// GetViewTasks lists the project's tasks matching the view, sorted and grouped by it.
+	GetViewTasks(ctx context.Context, projectID int, viewID int) (*dto.SavedViewTasks, error)
+}
@@ -0,0 +1,459 @@
+package saved_view_usecase
+
+import (
+	"DataTask/internal/domain/domain_error"
+	"DataTask/internal/domain/dto"
+	"DataTask/internal/domain/entity"
+	"DataTask/internal/repository/kanban_repository"
+	"DataTask/internal/repository/saved_view_repository"
+	"DataTask/internal/usecase/access_usecase"
+	"DataTask/internal/usecase/task_usecase"
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	maxNameLength = 255
+	maxSortLength = 255
+	maxFields     = 50
+)
+
+// priorityGroups is the order of the groups of views grouped by priority.
+var priorityGroups = []string{
+	entity.PriorityUrgent, entity.PriorityHigh, entity.PriorityMedium, entity.PriorityLow, entity.PriorityNone,
+}
+
+type SavedViewUseCaseImpl struct {
+	repo        saved_view_repository.SavedViewRepository
+	kanbanRepo  kanban_repository.KanbanRepository
+	taskUseCase task_usecase.TaskUseCase
+	access      access_usecase.AccessUseCase
+}
+
+func NewSavedViewUseCase(
+	repo saved_view_repository.SavedViewRepository,
+	kanbanRepo kanban_repository.KanbanRepository,
+	taskUseCase task_usecase.TaskUseCase,
+	access access_usecase.AccessUseCase,
+) *SavedViewUseCaseImpl {
+	return &SavedViewUseCaseImpl{
+		repo:        repo,
+		kanbanRepo:  kanbanRepo,
+		taskUseCase: taskUseCase,
+		access:      access,
+	}
+}
+
+func (uc *SavedViewUseCaseImpl) CreateView(ctx context.Context, view *dto.SavedView) (*dto.SavedView, error) {
+	userID, err := uc.access.CurrentUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	permission := entity.PermissionRead
+	if view.Shared {
+		permission = entity.PermissionEdit
+	}
+	if err := uc.access.RequireProjectPermission(ctx, view.ProjectID, permission); err != nil {
+		return nil, err
+	}
+
+	entityView := &entity.SavedView{
+		ProjectID: view.ProjectID,
+		OwnerID:   userID,
+		Name:      strings.TrimSpace(view.Name),
+		Filter:    strings.TrimSpace(view.Filter),
+		Sort:      strings.TrimSpace(view.Sort),
+		GroupBy:   view.GroupBy,
+		Fields:    trimFields(view.Fields),
+		Shared:    view.Shared,
+	}
+	if err := uc.validateView(ctx, entityView); err != nil {
+		return nil, err
+	}
+
+	createdView, err := uc.repo.CreateView(ctx, entityView)
+	if err != nil {
+		return nil, err
+	}
+	return toViewDTO(createdView), nil
+}
+
+func (uc *SavedViewUseCaseImpl) GetView(ctx context.Context, projectID int, viewID int) (*dto.SavedView, error) {
+	view, _, err := uc.visibleView(ctx, projectID, viewID)
+	if err != nil {
+		return nil, err
+	}
+	return toViewDTO(view), nil
+}
+
+func (uc *SavedViewUseCaseImpl) UpdateView(ctx context.Context, projectID int, viewID int, update *dto.SavedViewUpdate) (*dto.SavedView, error) {
+	view, userID, err := uc.visibleView(ctx, projectID, viewID)
+	if err != nil {
+		return nil, err
+	}
+	// Shared views affect every member: changing somebody else's view or sharing one takes edit permission.
+	sharing := !view.Shared && update.Shared != nil && *update.Shared
+	if (view.Shared && view.OwnerID != userID) || sharing {
+		if err := uc.access.RequireProjectPermission(ctx, projectID, entity.PermissionEdit); err != nil {
+			return nil, err
+		}
+	}
+
+	if update.Name != nil {
+		view.Name = strings.TrimSpace(*update.Name)
+	}
+	if update.Filter != nil {
+		view.Filter = strings.TrimSpace(*update.Filter)
+	}
+	if update.Sort != nil {
+		view.Sort = strings.TrimSpace(*update.Sort)
+	}
+	if update.GroupBy != nil {
+		view.GroupBy = *update.GroupBy
+	}
+	if update.Fields != nil {
+		view.Fields = trimFields(update.Fields)
+	}
+	if update.Shared != nil {
+		// Unsharing hides the view from everybody but its owner, so only the owner may do it.
+		if view.Shared && !*update.Shared && view.OwnerID != userID {
+			return nil, fmt.Errorf("%w: only the owner can stop sharing saved view %d", domain_error.ErrForbidden, viewID)
+		}
+		view.Shared = *update.Shared
+	}
+	if err := uc.validateView(ctx, view); err != nil {
+		return nil, err
+	}
+
+	if err := uc.repo.UpdateView(ctx, view); err != nil {
+		return nil, err
+	}
+	updatedView, err := uc.repo.GetViewByID(ctx, viewID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return toViewDTO(updatedView), nil
+}
+
+func (uc *SavedViewUseCaseImpl) DeleteView(ctx context.Context, projectID int, viewID int) error {
+	view, userID, err := uc.visibleView(ctx, projectID, viewID)
+	if err != nil {
+		return err
+	}
+	if view.OwnerID != userID {
+		if err := uc.access.RequireProjectPermission(ctx, projectID, entity.PermissionEdit); err != nil {
+			return err
+		}
+	}
+
+	return uc.repo.DeleteView(ctx, viewID)
+}
+
+func (uc *SavedViewUseCaseImpl) GetViews(ctx context.Context, projectID int) ([]*dto.SavedView, error) {
+	userID, err := uc.access.CurrentUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.access.RequireProjectPermission(ctx, projectID, entity.PermissionRead); err != nil {
+		return nil, err
+	}
+
+	views, err := uc.repo.GetVisibleViews(ctx, projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return toViewDTOs(views), nil
+}
+
+func (uc *SavedViewUseCaseImpl) ReorderViews(ctx context.Context, projectID int, viewIDs []int) ([]*dto.SavedView, error) {
+	userID, err := uc.access.CurrentUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.access.RequireProjectPermission(ctx, projectID, entity.PermissionRead); err != nil {
+		return nil, err
+	}
+
+	views, err := uc.repo.GetVisibleViews(ctx, projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := sameViewSet(views, viewIDs); err != nil {
+		return nil, err
+	}
+
+	if err := uc.repo.SetPositions(ctx, userID, viewIDs); err != nil {
+		return nil, err
+	}
+
+	reordered, err := uc.repo.GetVisibleViews(ctx, projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return toViewDTOs(reordered), nil
+}
+
+func (uc *SavedViewUseCaseImpl) GetViewTasks(ctx context.Context, projectID int, viewID int) (*dto.SavedViewTasks, error) {
+	view, _, err := uc.visibleView(ctx, projectID, viewID)
+	if err != nil {
+		return nil, err
+	}
+
+	// me in a shared view's filter stands for whoever looks at it.
+	filter, err := viewFilter(view)
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := uc.taskUseCase.GetTasksByProjectID(ctx, projectID, filter)
+	if err != nil {
+		return nil, err
+	}
+	if tasks == nil {
+		tasks = []*dto.Task{}
+	}
+
+	result := &dto.SavedViewTasks{View: toViewDTO(view), Tasks: tasks}
+	switch view.GroupBy {
+	case entity.ViewGroupColumn:
+		kanbans, err := uc.kanbanRepo.GetKanbansByProjectID(ctx, projectID)
+		if err != nil {
+			return nil, err
+		}
+		result.Groups = groupByColumn(tasks, kanbans)
+	case entity.ViewGroupAssignee:
+		result.Groups = groupByAssignee(tasks)
+	case entity.ViewGroupLabel:
+		result.Groups = groupByLabel(tasks)
+	case entity.ViewGroupPriority:
+		result.Groups = groupByPriority(tasks)
+	}
+	return result, nil
+}
+
+// visibleView loads a view of the project that the current user may see, returning the user's ID
+// as well. Other users' personal views are reported as not found.
+func (uc *SavedViewUseCaseImpl) visibleView(ctx context.Context, projectID int, viewID int) (*entity.SavedView, int, error) {
+	userID, err := uc.access.CurrentUserID(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := uc.access.RequireProjectPermission(ctx, projectID, entity.PermissionRead); err != nil {
+		return nil, 0, err
+	}
+
+	view, err := uc.repo.GetViewByID(ctx, viewID, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if view.ProjectID != projectID || (!view.Shared && view.OwnerID != userID) {
+		return nil, 0, fmt.Errorf("%w: saved view %d", domain_error.ErrNotFound, viewID)
+	}
+	return view, userID, nil
+}
+
+func (uc *SavedViewUseCaseImpl) validateView(ctx context.Context, view *entity.SavedView) error {
+	if view.Name == "" || utf8.RuneCountInString(view.Name) > maxNameLength {
+		return fmt.Errorf("%w: name must have 1 to %d characters", domain_error.ErrValidation, maxNameLength)
+	}
+	if utf8.RuneCountInString(view.Sort) > maxSortLength {
+		return fmt.Errorf("%w: sort must have at most %d characters", domain_error.ErrValidation, maxSortLength)
+	}
+	if !entity.IsValidViewGroupBy(view.GroupBy) {
+		return fmt.Errorf("%w: cannot group tasks by %q", domain_error.ErrValidation, view.GroupBy)
+	}
+
+	if len(view.Fields) > maxFields {
+		return fmt.Errorf("%w: a view shows at most %d fields", domain_error.ErrValidation, maxFields)
+	}
+	seen := make(map[string]bool, len(view.Fields))
+	for _, f := range view.Fields {
+		if !isValidViewField(f) {
+			return fmt.Errorf("%w: unknown field %q", domain_error.ErrValidation, f)
+		}
+		if seen[f] {
+			return fmt.Errorf("%w: field %q is listed twice", domain_error.ErrValidation, f)
+		}
+		seen[f] = true
+	}
+
+	filter, err := viewFilter(view)
+	if err != nil {
+		return err
+	}
+	return uc.taskUseCase.ValidateFilter(ctx, filter)
+}
+
+func viewFilter(view *entity.SavedView) (*dto.TaskFilter, error) {
+	sort, err := task_usecase.ParseTaskSort(view.Sort)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.TaskFilter{Query: view.Filter, Sort: sort}, nil
+}
+
+func isValidViewField(f string) bool {
+	if idStr, ok := strings.CutPrefix(f, "field."); ok {
+		id, err := strconv.Atoi(idStr)
+		return err == nil && id > 0
+	}
+	return slices.Contains(entity.ViewFields, f)
+}
+
+func trimFields(fields []string) []string {
+	trimmed := make([]string, 0, len(fields))
+	for _, f := range fields {
+		trimmed = append(trimmed, strings.TrimSpace(f))
+	}
+	return trimmed
+}
+
+func sameViewSet(views []*entity.SavedView, viewIDs []int) error {
+	if len(views) != len(viewIDs) {
+		return fmt.Errorf("%w: every view must be listed exactly once", domain_error.ErrValidation)
+	}
+
+	known := make(map[int]bool, len(views))
+	for _, view := range views {
+		known[view.ID] = true
+	}
+	for _, id := range viewIDs {
+		if !known[id] {
+			return fmt.Errorf("%w: every view must be listed exactly once", domain_error.ErrValidation)
+		}
+		delete(known, id)
+	}
+	return nil
+}
+
+// groupByColumn returns a group per kanban of the project, empty ones included, in board order.
+func groupByColumn(tasks []*dto.Task, kanbans []*entity.Kanban) []*dto.SavedViewGroup {
+	groups := make([]*dto.SavedViewGroup, 0, len(kanbans))
+	byKanban := make(map[int]*dto.SavedViewGroup, len(kanbans))
+	for _, k := range kanbans {
+		group := &dto.SavedViewGroup{Key: strconv.Itoa(k.ID), Name: k.Name, Tasks: []*dto.Task{}}
+		groups = append(groups, group)
+		byKanban[k.ID] = group
+	}
+	for _, t := range tasks {
+		group, ok := byKanban[t.KanbanID]
+		if !ok {
+			group = &dto.SavedViewGroup{Key: strconv.Itoa(t.KanbanID), Tasks: []*dto.Task{}}
+			groups = append(groups, group)
+			byKanban[t.KanbanID] = group
+		}
+		group.Tasks = append(group.Tasks, t)
+	}
+	return groups
+}
+
+// groupByPriority returns a group per priority, empty ones included, most urgent first.
+func groupByPriority(tasks []*dto.Task) []*dto.SavedViewGroup {
+	groups := make([]*dto.SavedViewGroup, 0, len(priorityGroups))
+	byPriority := make(map[string]*dto.SavedViewGroup, len(priorityGroups))
+	for _, p := range priorityGroups {
+		group := &dto.SavedViewGroup{Key: p, Name: p, Tasks: []*dto.Task{}}
+		groups = append(groups, group)
+		byPriority[p] = group
+	}
+	for _, t := range tasks {
+		if group, ok := byPriority[t.Priority]; ok {
+			group.Tasks = append(group.Tasks, t)
+		}
+	}
+	return groups
+}
+
+// groupByAssignee returns a group per assignee by name, and Unassigned last when any task has
+// no assignee.
+func groupByAssignee(tasks []*dto.Task) []*dto.SavedViewGroup {
+	var groups []*dto.SavedViewGroup
+	byUser := make(map[int]*dto.SavedViewGroup)
+	unassigned := &dto.SavedViewGroup{Name: "Unassigned", Tasks: []*dto.Task{}}
+	for _, t := range tasks {
+		if len(t.Assignees) == 0 {
+			unassigned.Tasks = append(unassigned.Tasks, t)
+		}
+		for _, u := range t.Assignees {
+			group, ok := byUser[u.ID]
+			if !ok {
+				name := strings.TrimSpace(u.Name + " " + u.Surname)
+				if name == "" {
+					name = u.Email
+				}
+				group = &dto.SavedViewGroup{Key: strconv.Itoa(u.ID), Name: name, Tasks: []*dto.Task{}}
+				groups = append(groups, group)
+				byUser[u.ID] = group
+			}
+			group.Tasks = append(group.Tasks, t)
+		}
+	}
+	return sortGroups(groups, unassigned)
+}
+
+// groupByLabel returns a group per label by name, and No label last when any task has no label.
+func groupByLabel(tasks []*dto.Task) []*dto.SavedViewGroup {
+	var groups []*dto.SavedViewGroup
+	byLabel := make(map[int]*dto.SavedViewGroup)
+	unlabeled := &dto.SavedViewGroup{Name: "No label", Tasks: []*dto.Task{}}
+	for _, t := range tasks {
+		if len(t.Labels) == 0 {
+			unlabeled.Tasks = append(unlabeled.Tasks, t)
+		}
+		for _, l := range t.Labels {
+			group, ok := byLabel[l.ID]
+			if !ok {
+				group = &dto.SavedViewGroup{Key: strconv.Itoa(l.ID), Name: l.Name, Tasks: []*dto.Task{}}
+				groups = append(groups, group)
+				byLabel[l.ID] = group
+			}
+			group.Tasks = append(group.Tasks, t)
+		}
+	}
+	return sortGroups(groups, unlabeled)
+}
+
+// sortGroups orders the groups by name and appends the group of tasks without a value unless it
+// is empty.
+func sortGroups(groups []*dto.SavedViewGroup, none *dto.SavedViewGroup) []*dto.SavedViewGroup {
+	sort.SliceStable(groups, func(i, j int) bool {
+		return strings.ToLower(groups[i].Name) < strings.ToLower(groups[j].Name)
+	})
+	if len(none.Tasks) > 0 {
+		groups = append(groups, none)
+	}
+	return groups
+}
+
+func toViewDTO(view *entity.SavedView) *dto.SavedView {
+	fields := view.Fields
+	if fields == nil {
+		fields = []string{}
+	}
+	return &dto.SavedView{
+		ID:        view.ID,
+		ProjectID: view.ProjectID,
+		OwnerID:   view.OwnerID,
+		Name:      view.Name,
+		Filter:    view.Filter,
+		Sort:      view.Sort,
+		GroupBy:   view.GroupBy,
+		Fields:    fields,
+		Shared:    view.Shared,
+		Position:  view.Position,
+		CreatedAt: view.CreatedAt,
+		UpdatedAt: view.UpdatedAt,
+	}
+}
+
+func toViewDTOs(views []*entity.SavedView) []*dto.SavedView {
+	dtoViews := make([]*dto.SavedView, 0, len(views))
+	for _, v := range views {
+		dtoViews = append(dtoViews, toViewDTO(v))
+	}
+	return dtoViews
+}
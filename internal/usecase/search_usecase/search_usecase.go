@@ -0,0 +1,11 @@
+package search_usecase
+
+import (
+	"DataTask/internal/domain/dto"
+	"context"
+)
+
+type SearchUseCase interface {
+	// Search finds tasks, comments and projects the current user can read.
+	Search(ctx context.Context, query *dto.SearchQuery) ([]*dto.SearchResult, error)
+}
@@ -0,0 +1,114 @@
+package search_usecase
+
+import (
+	"DataTask/internal/domain/domain_error"
+	"DataTask/internal/domain/dto"
+	"DataTask/internal/domain/entity"
+	"DataTask/internal/repository/search_repository"
+	"DataTask/internal/usecase/access_usecase"
+	"context"
+	"fmt"
+	"html"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+	maxSearchLength    = 200
+)
+
+var highlighter = strings.NewReplacer(
+	entity.SearchHighlightStart, "<mark>",
+	entity.SearchHighlightStop, "</mark>",
+)
+
+type SearchUseCaseImpl struct {
+	repo   search_repository.SearchRepository
+	access access_usecase.AccessUseCase
+}
+
+func NewSearchUseCase(repo search_repository.SearchRepository, access access_usecase.AccessUseCase) *SearchUseCaseImpl {
+	return &SearchUseCaseImpl{repo: repo, access: access}
+}
+
+func (uc *SearchUseCaseImpl) Search(ctx context.Context, query *dto.SearchQuery) ([]*dto.SearchResult, error) {
+	userID, err := uc.access.CurrentUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	text := strings.TrimSpace(query.Text)
+	if text == "" {
+		return nil, fmt.Errorf("%w: search text is required", domain_error.ErrValidation)
+	}
+	if utf8.RuneCountInString(text) > maxSearchLength {
+		return nil, fmt.Errorf("%w: search text must be at most %d characters", domain_error.ErrValidation, maxSearchLength)
+	}
+	for _, resultType := range query.Types {
+		switch resultType {
+		case entity.SearchResultTask, entity.SearchResultComment, entity.SearchResultProject:
+		default:
+			return nil, fmt.Errorf("%w: unknown result type %q", domain_error.ErrValidation, resultType)
+		}
+	}
+
+	limit := query.Limit
+	if limit == 0 {
+		limit = defaultSearchLimit
+	}
+	if limit < 1 || limit > maxSearchLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", domain_error.ErrValidation, maxSearchLimit)
+	}
+	if query.Offset < 0 {
+		return nil, fmt.Errorf("%w: offset must not be negative", domain_error.ErrValidation)
+	}
+
+	// An explicit project the user cannot read is an error rather than an empty result.
+	if query.ProjectID != nil {
+		if err := uc.access.RequireProjectPermission(ctx, *query.ProjectID, entity.PermissionRead); err != nil {
+			return nil, err
+		}
+	}
+
+	assigneeID := query.AssigneeID
+	if query.AssigneeMe {
+		assigneeID = &userID
+	}
+
+	results, err := uc.repo.Search(ctx, &entity.SearchQuery{
+		Text:        text,
+		UserID:      userID,
+		ProjectID:   query.ProjectID,
+		Types:       query.Types,
+		AssigneeID:  assigneeID,
+		IsCompleted: query.IsCompleted,
+		Limit:       limit,
+		Offset:      query.Offset,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	dtoResults := make([]*dto.SearchResult, 0, len(results))
+	for _, r := range results {
+		dtoResults = append(dtoResults, &dto.SearchResult{
+			Type:        r.Type,
+			ID:          r.ID,
+			ProjectID:   r.ProjectID,
+			ProjectName: r.ProjectName,
+			TaskID:      r.TaskID,
+			Title:       r.Title,
+			Snippet:     highlightSnippet(r.Snippet),
+			IsCompleted: r.IsCompleted,
+			Rank:        r.Rank,
+		})
+	}
+	return dtoResults, nil
+}
+
+// highlightSnippet escapes the snippet and turns the highlight markers into <mark> tags.
+func highlightSnippet(snippet string) string {
+	return highlighter.Replace(html.EscapeString(snippet))
+}
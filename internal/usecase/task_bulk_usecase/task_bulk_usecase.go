@@ -0,0 +1,12 @@
+package task_bulk_usecase
+
+import (
+	"DataTask/internal/domain/dto"
+	"context"
+)
+
+type TaskBulkUseCase interface {
+	// ApplyBulk runs the operation on every task of the request. Unless the request is partial,
+	// a failing task rolls back the whole batch; the result then comes with the task's error.
+	ApplyBulk(ctx context.Context, request *dto.BulkTaskRequest) (*dto.BulkTaskResult, error)
+}
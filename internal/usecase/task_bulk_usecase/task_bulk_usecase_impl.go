@@ -0,0 +1,211 @@
+package task_bulk_usecase
+
+import (
+	"DataTask/internal/domain/domain_error"
+	"DataTask/internal/domain/dto"
+	"DataTask/internal/domain/entity"
+	"DataTask/internal/repository/database"
+	"DataTask/internal/repository/task_repository"
+	"DataTask/internal/usecase/access_usecase"
+	"DataTask/internal/usecase/assignee_usecase"
+	"DataTask/internal/usecase/label_usecase"
+	"DataTask/internal/usecase/task_usecase"
+	"context"
+	"fmt"
+)
+
+// maxBulkTasks bounds the work and the transaction of one bulk request.
+const maxBulkTasks = 500
+
+type TaskBulkUseCaseImpl struct {
+	taskRepo   task_repository.TaskRepository
+	tasks      task_usecase.TaskUseCase
+	assignees  assignee_usecase.AssigneeUseCase
+	labels     label_usecase.LabelUseCase
+	access     access_usecase.AccessUseCase
+	transactor database.Transactor
+}
+
+func NewTaskBulkUseCase(
+	taskRepo task_repository.TaskRepository,
+	tasks task_usecase.TaskUseCase,
+	assignees assignee_usecase.AssigneeUseCase,
+	labels label_usecase.LabelUseCase,
+	access access_usecase.AccessUseCase,
+	transactor database.Transactor,
+) *TaskBulkUseCaseImpl {
+	return &TaskBulkUseCaseImpl{
+		taskRepo:   taskRepo,
+		tasks:      tasks,
+		assignees:  assignees,
+		labels:     labels,
+		access:     access,
+		transactor: transactor,
+	}
+}
+
+func (uc *TaskBulkUseCaseImpl) ApplyBulk(ctx context.Context, request *dto.BulkTaskRequest) (*dto.BulkTaskResult, error) {
+	if err := validateRequest(request); err != nil {
+		return nil, err
+	}
+	if request.Operation == entity.BulkOperationMove {
+		if err := uc.access.RequireKanbanPermission(ctx, *request.KanbanID, entity.PermissionEdit); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &dto.BulkTaskResult{
+		Operation: request.Operation,
+		Partial:   request.Partial,
+		Items:     make([]*dto.BulkTaskItemResult, 0, len(request.TaskIDs)),
+	}
+	for _, taskID := range request.TaskIDs {
+		result.Items = append(result.Items, &dto.BulkTaskItemResult{TaskID: taskID})
+	}
+
+	if request.Partial {
+		for _, item := range result.Items {
+			err := uc.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+				return uc.apply(ctx, request, item.TaskID)
+			})
+			if err != nil {
+				item.Error = err.Error()
+				result.Failed++
+				continue
+			}
+			item.Success = true
+			result.Succeeded++
+		}
+		return result, nil
+	}
+
+	var failed *dto.BulkTaskItemResult
+	err := uc.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		for _, item := range result.Items {
+			if err := uc.apply(ctx, request, item.TaskID); err != nil {
+				failed = item
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if failed == nil {
+			return nil, err
+		}
+		// Nothing was applied: the failing task carries its error, the others the reason for the rollback.
+		for _, item := range result.Items {
+			if item == failed {
+				item.Error = err.Error()
+			} else {
+				item.Error = fmt.Sprintf("not applied because task %d failed", failed.TaskID)
+			}
+		}
+		result.Failed = len(result.Items)
+		return result, fmt.Errorf("task %d: %w", failed.TaskID, err)
+	}
+
+	for _, item := range result.Items {
+		item.Success = true
+	}
+	result.Succeeded = len(result.Items)
+	return result, nil
+}
+
+// apply runs the operation on one task. Operations leaving a task unchanged succeed.
+func (uc *TaskBulkUseCaseImpl) apply(ctx context.Context, request *dto.BulkTaskRequest, taskID int) error {
+	if _, err := uc.access.RequireTaskPermission(ctx, taskID, entity.PermissionEdit); err != nil {
+		return err
+	}
+
+	switch request.Operation {
+	case entity.BulkOperationMove:
+		task, err := uc.taskRepo.GetTaskByID(ctx, taskID)
+		if err != nil || task.KanbanID == *request.KanbanID {
+			return err
+		}
+		_, err = uc.tasks.UpdateTask(ctx, taskID, &dto.TaskUpdate{KanbanID: request.KanbanID})
+		return err
+	case entity.BulkOperationAssign, entity.BulkOperationUnassign:
+		assigned, err := uc.isAssigned(ctx, taskID, *request.UserID)
+		if err != nil {
+			return err
+		}
+		if request.Operation == entity.BulkOperationUnassign {
+			if !assigned {
+				return nil
+			}
+			return uc.assignees.RemoveAssignee(ctx, taskID, *request.UserID)
+		}
+		if assigned {
+			return nil
+		}
+		_, err = uc.assignees.AddAssignee(ctx, taskID, *request.UserID)
+		return err
+	case entity.BulkOperationLabel:
+		return uc.labels.AttachLabelToTask(ctx, taskID, *request.LabelID)
+	case entity.BulkOperationComplete:
+		task, err := uc.taskRepo.GetTaskByID(ctx, taskID)
+		if err != nil || task.IsCompleted {
+			return err
+		}
+		completed := true
+		_, err = uc.tasks.UpdateTask(ctx, taskID, &dto.TaskUpdate{IsCompleted: &completed})
+		return err
+	case entity.BulkOperationDelete:
+		return uc.tasks.DeleteTask(ctx, taskID)
+	}
+	return fmt.Errorf("%w: unknown operation %q", domain_error.ErrValidation, request.Operation)
+}
+
+func (uc *TaskBulkUseCaseImpl) isAssigned(ctx context.Context, taskID int, userID int) (bool, error) {
+	assignees, err := uc.assignees.GetAssignees(ctx, taskID)
+	if err != nil {
+		return false, err
+	}
+	for _, u := range assignees {
+		if u.ID == userID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func validateRequest(request *dto.BulkTaskRequest) error {
+	if len(request.TaskIDs) == 0 {
+		return fmt.Errorf("%w: task_ids must not be empty", domain_error.ErrValidation)
+	}
+	if len(request.TaskIDs) > maxBulkTasks {
+		return fmt.Errorf("%w: at most %d tasks per request", domain_error.ErrValidation, maxBulkTasks)
+	}
+	seen := make(map[int]bool, len(request.TaskIDs))
+	for _, taskID := range request.TaskIDs {
+		if seen[taskID] {
+			return fmt.Errorf("%w: task %d is listed twice", domain_error.ErrValidation, taskID)
+		}
+		seen[taskID] = true
+	}
+
+	var missing string
+	switch request.Operation {
+	case entity.BulkOperationMove:
+		if request.KanbanID == nil {
+			missing = "kanban_id"
+		}
+	case entity.BulkOperationAssign, entity.BulkOperationUnassign:
+		if request.UserID == nil {
+			missing = "user_id"
+		}
+	case entity.BulkOperationLabel:
+		if request.LabelID == nil {
+			missing = "label_id"
+		}
+	case entity.BulkOperationComplete, entity.BulkOperationDelete:
+	default:
+		return fmt.Errorf("%w: unknown operation %q", domain_error.ErrValidation, request.Operation)
+	}
+	if missing != "" {
+		return fmt.Errorf("%w: %s is required for %s", domain_error.ErrValidation, missing, request.Operation)
+	}
+	return nil
+}
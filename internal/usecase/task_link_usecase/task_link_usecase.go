@@ -0,0 +1,14 @@
+package task_link_usecase
+
+import (
+	"DataTask/internal/domain/dto"
+	"context"
+)
+
+type TaskLinkUseCase interface {
+	CreateLink(ctx context.Context, taskID int, link *dto.TaskLinkCreate) (*dto.TaskLink, error)
+	DeleteLink(ctx context.Context, taskID int, linkID int) error
+	// GetLinks lists the links of the task, skipping tasks in projects the current user cannot read.
+	GetLinks(ctx context.Context, taskID int) (*dto.TaskLinks, error)
+	CountOpenBlockers(ctx context.Context, taskID int) (int, error)
+}
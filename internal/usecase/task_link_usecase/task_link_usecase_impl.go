@@ -0,0 +1,185 @@
+package task_link_usecase
+
+import (
+	"DataTask/internal/domain/domain_error"
+	"DataTask/internal/domain/dto"
+	"DataTask/internal/domain/entity"
+	"DataTask/internal/repository/database"
+	"DataTask/internal/repository/task_link_repository"
+	"DataTask/internal/usecase/access_usecase"
+	"context"
+	"errors"
+	"fmt"
+)
+
+type TaskLinkUseCaseImpl struct {
+	repo       task_link_repository.TaskLinkRepository
+	access     access_usecase.AccessUseCase
+	transactor database.Transactor
+}
+
+func NewTaskLinkUseCase(
+	repo task_link_repository.TaskLinkRepository,
+	access access_usecase.AccessUseCase,
+	transactor database.Transactor,
+) *TaskLinkUseCaseImpl {
+	return &TaskLinkUseCaseImpl{
+		repo:       repo,
+		access:     access,
+		transactor: transactor,
+	}
+}
+
+func (uc *TaskLinkUseCaseImpl) CreateLink(ctx context.Context, taskID int, link *dto.TaskLinkCreate) (*dto.TaskLink, error) {
+	if taskID == link.TaskID {
+		return nil, fmt.Errorf("%w: a task cannot be linked to itself", domain_error.ErrValidation)
+	}
+
+	userID, err := uc.access.CurrentUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := uc.access.RequireTaskPermission(ctx, taskID, entity.PermissionEdit); err != nil {
+		return nil, err
+	}
+	// The other task may live in another project; seeing it is enough to link to it.
+	if _, err := uc.access.RequireTaskPermission(ctx, link.TaskID, entity.PermissionRead); err != nil {
+		return nil, err
+	}
+
+	entityLink := &entity.TaskLink{
+		SourceTaskID: taskID,
+		TargetTaskID: link.TaskID,
+		Type:         link.Type,
+		CreatedBy:    &userID,
+	}
+	switch link.Type {
+	case entity.TaskLinkBlocks, entity.TaskLinkDuplicates:
+	case entity.TaskLinkBlockedBy:
+		entityLink.Type = entity.TaskLinkBlocks
+		entityLink.SourceTaskID, entityLink.TargetTaskID = link.TaskID, taskID
+	case entity.TaskLinkRelatesTo:
+		// The relation is symmetric, so store it one way only to keep the unique constraint meaningful.
+		if entityLink.SourceTaskID > entityLink.TargetTaskID {
+			entityLink.SourceTaskID, entityLink.TargetTaskID = entityLink.TargetTaskID, entityLink.SourceTaskID
+		}
+	default:
+		return nil, fmt.Errorf("%w: unknown link type %q", domain_error.ErrValidation, link.Type)
+	}
+
+	var createdLink *entity.TaskLink
+	err = uc.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if entityLink.Type == entity.TaskLinkBlocks {
+			if err := uc.repo.LockBlockingGraph(ctx); err != nil {
+				return err
+			}
+			cycle, err := uc.repo.BlockingPathExists(ctx, entityLink.TargetTaskID, entityLink.SourceTaskID)
+			if err != nil {
+				return err
+			}
+			if cycle {
+				return fmt.Errorf("%w: task %d already blocks task %d, the link would form a cycle",
+					domain_error.ErrConflict, entityLink.TargetTaskID, entityLink.SourceTaskID)
+			}
+		}
+
+		createdLink, err = uc.repo.CreateLink(ctx, entityLink)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	links, err := uc.GetLinks(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	for _, l := range append(links.Outgoing, links.Incoming...) {
+		if l.ID == createdLink.ID {
+			return l, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: task link %d", domain_error.ErrNotFound, createdLink.ID)
+}
+
+func (uc *TaskLinkUseCaseImpl) DeleteLink(ctx context.Context, taskID int, linkID int) error {
+	link, err := uc.repo.GetLinkByID(ctx, linkID)
+	if err != nil {
+		return err
+	}
+	if link.SourceTaskID != taskID && link.TargetTaskID != taskID {
+		return fmt.Errorf("%w: task link %d of task %d", domain_error.ErrNotFound, linkID, taskID)
+	}
+
+	if _, err := uc.access.RequireTaskPermission(ctx, taskID, entity.PermissionEdit); err != nil {
+		return err
+	}
+	return uc.repo.DeleteLink(ctx, linkID)
+}
+
+func (uc *TaskLinkUseCaseImpl) GetLinks(ctx context.Context, taskID int) (*dto.TaskLinks, error) {
+	if _, err := uc.access.RequireTaskPermission(ctx, taskID, entity.PermissionRead); err != nil {
+		return nil, err
+	}
+
+	linkedTasks, err := uc.repo.GetLinkedTasks(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	links := &dto.TaskLinks{
+		Outgoing: []*dto.TaskLink{},
+		Incoming: []*dto.TaskLink{},
+	}
+	readable := make(map[int]bool)
+	for _, l := range linkedTasks {
+		canRead, checked := readable[l.ProjectID]
+		if !checked {
+			err := uc.access.RequireProjectPermission(ctx, l.ProjectID, entity.PermissionRead)
+			if err != nil && !errors.Is(err, domain_error.ErrForbidden) {
+				return nil, err
+			}
+			canRead = err == nil
+			readable[l.ProjectID] = canRead
+		}
+		if !canRead {
+			continue
+		}
+
+		if l.Direction == entity.TaskLinkOutgoing {
+			links.Outgoing = append(links.Outgoing, toTaskLinkDTO(l))
+		} else {
+			links.Incoming = append(links.Incoming, toTaskLinkDTO(l))
+		}
+	}
+	return links, nil
+}
+
+func (uc *TaskLinkUseCaseImpl) CountOpenBlockers(ctx context.Context, taskID int) (int, error) {
+	return uc.repo.CountOpenBlockers(ctx, taskID)
+}
+
+// viewType names the link type from the point of view of the task the link is listed on.
+func viewType(l *entity.LinkedTask) string {
+	if l.Direction == entity.TaskLinkIncoming {
+		switch l.Type {
+		case entity.TaskLinkBlocks:
+			return entity.TaskLinkBlockedBy
+		case entity.TaskLinkDuplicates:
+			return entity.TaskLinkDuplicatedBy
+		}
+	}
+	return l.Type
+}
+
+func toTaskLinkDTO(l *entity.LinkedTask) *dto.TaskLink {
+	return &dto.TaskLink{
+		ID:              l.LinkID,
+		Type:            viewType(l),
+		TaskID:          l.TaskID,
+		TaskTitle:       l.TaskTitle,
+		TaskIsCompleted: l.TaskIsCompleted,
+		ProjectID:       l.ProjectID,
+		CreatedAt:       l.CreatedAt,
+	}
+}
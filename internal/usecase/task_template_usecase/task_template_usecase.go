@@ -0,0 +1,25 @@
+package task_template_usecase
+
+import (
+	"DataTask/internal/domain/dto"
+	"DataTask/internal/domain/entity"
+	"context"
+)
+
+type TaskTemplateUseCase interface {
+	CreateTemplate(ctx context.Context, template *dto.TaskTemplate) (*dto.TaskTemplate, error)
+	GetTemplateByID(ctx context.Context, id int) (*dto.TaskTemplate, error)
+	UpdateTemplate(ctx context.Context, id int, update *dto.TaskTemplateUpdate) (*dto.TaskTemplate, error)
+	DeleteTemplate(ctx context.Context, id int) error
+	// GetTemplatesByProjectID returns the templates usable in the project, including inherited ones.
+	GetTemplatesByProjectID(ctx context.Context, projectID int) ([]*dto.TaskTemplate, error)
+
+	// PrepareTask fills a task about to be created from the template: the title is rendered from
+	// the pattern, and the description and priority are taken from the template unless the task
+	// sets them. The template must belong to the task's project or one of its ancestors.
+	PrepareTask(ctx context.Context, templateID int, task *dto.Task) (*entity.TaskTemplate, error)
+	// ApplyTemplate adds the template's labels, checklist and assignees to a task created from it.
+	// Assignees who are not members of the task's project are skipped. Call it inside the
+	// transaction that creates the task.
+	ApplyTemplate(ctx context.Context, taskID int, kanbanID int, template *entity.TaskTemplate) error
+}
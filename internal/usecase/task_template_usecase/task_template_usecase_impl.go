@@ -0,0 +1,340 @@
+package task_template_usecase
+
+import (
+	"DataTask/internal/domain/domain_error"
+	"DataTask/internal/domain/dto"
+	"DataTask/internal/domain/entity"
+	"DataTask/internal/repository/label_repository"
+	"DataTask/internal/repository/project_repository"
+	"DataTask/internal/repository/task_template_repository"
+	"DataTask/internal/usecase/access_usecase"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	maxNameLength     = 255
+	maxChecklistItems = 100
+)
+
+type TaskTemplateUseCaseImpl struct {
+	repo        task_template_repository.TaskTemplateRepository
+	labelRepo   label_repository.LabelRepository
+	projectRepo project_repository.ProjectRepository
+	access      access_usecase.AccessUseCase
+}
+
+func NewTaskTemplateUseCase(
+	repo task_template_repository.TaskTemplateRepository,
+	labelRepo label_repository.LabelRepository,
+	projectRepo project_repository.ProjectRepository,
+	access access_usecase.AccessUseCase,
+) *TaskTemplateUseCaseImpl {
+	return &TaskTemplateUseCaseImpl{
+		repo:        repo,
+		labelRepo:   labelRepo,
+		projectRepo: projectRepo,
+		access:      access,
+	}
+}
+
+func (uc *TaskTemplateUseCaseImpl) CreateTemplate(ctx context.Context, template *dto.TaskTemplate) (*dto.TaskTemplate, error) {
+	if err := uc.access.RequireProjectPermission(ctx, template.ProjectID, entity.PermissionEdit); err != nil {
+		return nil, err
+	}
+
+	entityTemplate := &entity.TaskTemplate{
+		ProjectID:    template.ProjectID,
+		Name:         strings.TrimSpace(template.Name),
+		TitlePattern: strings.TrimSpace(template.TitlePattern),
+		Description:  template.Description,
+		Priority:     template.Priority,
+		LabelIDs:     uniqueIDs(template.LabelIDs),
+		Checklist:    trimChecklist(template.Checklist),
+		AssigneeIDs:  uniqueIDs(template.AssigneeIDs),
+	}
+	if entityTemplate.Priority == "" {
+		entityTemplate.Priority = entity.PriorityNone
+	}
+	if err := uc.validateTemplate(ctx, entityTemplate); err != nil {
+		return nil, err
+	}
+
+	createdTemplate, err := uc.repo.CreateTemplate(ctx, entityTemplate)
+	if err != nil {
+		return nil, err
+	}
+	return toTemplateDTO(createdTemplate, createdTemplate.ProjectID), nil
+}
+
+func (uc *TaskTemplateUseCaseImpl) GetTemplateByID(ctx context.Context, id int) (*dto.TaskTemplate, error) {
+	template, err := uc.repo.GetTemplateByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.access.RequireProjectPermission(ctx, template.ProjectID, entity.PermissionRead); err != nil {
+		return nil, err
+	}
+	return toTemplateDTO(template, template.ProjectID), nil
+}
+
+func (uc *TaskTemplateUseCaseImpl) UpdateTemplate(ctx context.Context, id int, update *dto.TaskTemplateUpdate) (*dto.TaskTemplate, error) {
+	template, err := uc.repo.GetTemplateByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.access.RequireProjectPermission(ctx, template.ProjectID, entity.PermissionEdit); err != nil {
+		return nil, err
+	}
+
+	if update.Name != nil {
+		template.Name = strings.TrimSpace(*update.Name)
+	}
+	if update.TitlePattern != nil {
+		template.TitlePattern = strings.TrimSpace(*update.TitlePattern)
+	}
+	if update.Description != nil {
+		template.Description = *update.Description
+	}
+	if update.Priority != nil {
+		template.Priority = *update.Priority
+	}
+	if update.LabelIDs != nil {
+		template.LabelIDs = uniqueIDs(update.LabelIDs)
+	}
+	if update.Checklist != nil {
+		template.Checklist = trimChecklist(update.Checklist)
+	}
+	if update.AssigneeIDs != nil {
+		template.AssigneeIDs = uniqueIDs(update.AssigneeIDs)
+	}
+	if err := uc.validateTemplate(ctx, template); err != nil {
+		return nil, err
+	}
+
+	updatedTemplate, err := uc.repo.UpdateTemplate(ctx, template)
+	if err != nil {
+		return nil, err
+	}
+	return toTemplateDTO(updatedTemplate, updatedTemplate.ProjectID), nil
+}
+
+func (uc *TaskTemplateUseCaseImpl) DeleteTemplate(ctx context.Context, id int) error {
+	template, err := uc.repo.GetTemplateByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := uc.access.RequireProjectPermission(ctx, template.ProjectID, entity.PermissionEdit); err != nil {
+		return err
+	}
+
+	return uc.repo.DeleteTemplate(ctx, id)
+}
+
+func (uc *TaskTemplateUseCaseImpl) GetTemplatesByProjectID(ctx context.Context, projectID int) ([]*dto.TaskTemplate, error) {
+	if err := uc.access.RequireProjectPermission(ctx, projectID, entity.PermissionRead); err != nil {
+		return nil, err
+	}
+
+	templates, err := uc.repo.GetAvailableTemplates(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	dtoTemplates := make([]*dto.TaskTemplate, 0, len(templates))
+	for _, t := range templates {
+		dtoTemplates = append(dtoTemplates, toTemplateDTO(t, projectID))
+	}
+	return dtoTemplates, nil
+}
+
+func (uc *TaskTemplateUseCaseImpl) PrepareTask(ctx context.Context, templateID int, task *dto.Task) (*entity.TaskTemplate, error) {
+	if err := uc.access.RequireKanbanPermission(ctx, task.KanbanID, entity.PermissionEdit); err != nil {
+		return nil, err
+	}
+	projectID, err := uc.projectRepo.GetProjectIDByKanbanID(ctx, task.KanbanID)
+	if err != nil {
+		return nil, err
+	}
+	template, err := uc.availableTemplate(ctx, projectID, templateID)
+	if err != nil {
+		return nil, err
+	}
+	project, err := uc.projectRepo.GetProjectByID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	task.Title = renderTitle(template.TitlePattern, task.Title, project.Name, task.TimeZone, time.Now())
+	if task.Title == "" || utf8.RuneCountInString(task.Title) > maxNameLength {
+		return nil, fmt.Errorf("%w: the title rendered from template %d must have 1 to %d characters",
+			domain_error.ErrValidation, templateID, maxNameLength)
+	}
+	if task.Description == "" {
+		task.Description = template.Description
+	}
+	if task.Priority == "" {
+		task.Priority = template.Priority
+	}
+	return template, nil
+}
+
+func (uc *TaskTemplateUseCaseImpl) ApplyTemplate(ctx context.Context, taskID int, kanbanID int, template *entity.TaskTemplate) error {
+	projectID, err := uc.projectRepo.GetProjectIDByKanbanID(ctx, kanbanID)
+	if err != nil {
+		return err
+	}
+
+	// Members of the template's project are not necessarily members of a subproject using it.
+	assigneeIDs := make([]int, 0, len(template.AssigneeIDs))
+	for _, userID := range template.AssigneeIDs {
+		isMember, err := uc.access.IsProjectMember(ctx, projectID, userID)
+		if err != nil {
+			return err
+		}
+		if isMember {
+			assigneeIDs = append(assigneeIDs, userID)
+		}
+	}
+
+	return uc.repo.ApplyToTask(ctx, taskID, template, assigneeIDs)
+}
+
+// availableTemplate returns the template when it is defined in the project or one of its ancestors.
+func (uc *TaskTemplateUseCaseImpl) availableTemplate(ctx context.Context, projectID int, templateID int) (*entity.TaskTemplate, error) {
+	templates, err := uc.repo.GetAvailableTemplates(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range templates {
+		if t.ID == templateID {
+			return t, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: task template %d is not available in project %d", domain_error.ErrValidation, templateID, projectID)
+}
+
+func (uc *TaskTemplateUseCaseImpl) validateTemplate(ctx context.Context, template *entity.TaskTemplate) error {
+	if template.Name == "" || utf8.RuneCountInString(template.Name) > maxNameLength {
+		return fmt.Errorf("%w: name must have 1 to %d characters", domain_error.ErrValidation, maxNameLength)
+	}
+	if template.TitlePattern == "" || utf8.RuneCountInString(template.TitlePattern) > maxNameLength {
+		return fmt.Errorf("%w: title_pattern must have 1 to %d characters", domain_error.ErrValidation, maxNameLength)
+	}
+	if !entity.IsValidPriority(template.Priority) {
+		return fmt.Errorf("%w: unknown priority %q", domain_error.ErrValidation, template.Priority)
+	}
+	if len(template.Checklist) > maxChecklistItems {
+		return fmt.Errorf("%w: a template holds at most %d checklist items", domain_error.ErrValidation, maxChecklistItems)
+	}
+	for _, text := range template.Checklist {
+		if text == "" {
+			return fmt.Errorf("%w: checklist items must not be empty", domain_error.ErrValidation)
+		}
+	}
+
+	if len(template.LabelIDs) > 0 {
+		// Labels of the template's ancestors are fine too, subprojects inherit them as well.
+		labels, err := uc.labelRepo.GetAvailableLabels(ctx, template.ProjectID)
+		if err != nil {
+			return err
+		}
+		available := make(map[int]bool, len(labels))
+		for _, l := range labels {
+			available[l.ID] = true
+		}
+		for _, labelID := range template.LabelIDs {
+			if !available[labelID] {
+				return fmt.Errorf("%w: label %d is not available in project %d", domain_error.ErrValidation, labelID, template.ProjectID)
+			}
+		}
+	}
+
+	for _, userID := range template.AssigneeIDs {
+		isMember, err := uc.access.IsProjectMember(ctx, template.ProjectID, userID)
+		if err != nil {
+			return err
+		}
+		if !isMember {
+			return fmt.Errorf("%w: user %d is not a member of project %d", domain_error.ErrValidation, userID, template.ProjectID)
+		}
+	}
+	return nil
+}
+
+// renderTitle fills the placeholders of a title pattern. A pattern without {title} is used as it
+// is when no title is given and replaced by the given title otherwise.
+func renderTitle(pattern string, title string, projectName string, timeZone string, now time.Time) string {
+	title = strings.TrimSpace(title)
+	if !strings.Contains(pattern, entity.TemplatePlaceholderTitle) && title != "" {
+		return title
+	}
+
+	loc, err := time.LoadLocation(timeZone)
+	if err != nil {
+		loc = time.UTC
+	}
+	rendered := strings.NewReplacer(
+		entity.TemplatePlaceholderTitle, title,
+		entity.TemplatePlaceholderDate, now.In(loc).Format(time.DateOnly),
+		entity.TemplatePlaceholderProject, projectName,
+	).Replace(pattern)
+	return strings.TrimSpace(rendered)
+}
+
+// uniqueIDs drops repeated IDs, keeping the first occurrence. It never returns nil, the
+// array columns are NOT NULL.
+func uniqueIDs(ids []int) []int {
+	unique := make([]int, 0, len(ids))
+	seen := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
+
+func trimChecklist(items []string) []string {
+	trimmed := make([]string, 0, len(items))
+	for _, item := range items {
+		trimmed = append(trimmed, strings.TrimSpace(item))
+	}
+	return trimmed
+}
+
+// toTemplateDTO maps a template as seen from projectID; templates of other projects are inherited ones.
+func toTemplateDTO(template *entity.TaskTemplate, projectID int) *dto.TaskTemplate {
+	return &dto.TaskTemplate{
+		ID:           template.ID,
+		ProjectID:    template.ProjectID,
+		Name:         template.Name,
+		TitlePattern: template.TitlePattern,
+		Description:  template.Description,
+		Priority:     template.Priority,
+		LabelIDs:     nonNilIDs(template.LabelIDs),
+		Checklist:    nonNilStrings(template.Checklist),
+		AssigneeIDs:  nonNilIDs(template.AssigneeIDs),
+		Inherited:    template.ProjectID != projectID,
+		CreatedAt:    template.CreatedAt,
+		UpdatedAt:    template.UpdatedAt,
+	}
+}
+
+func nonNilIDs(ids []int) []int {
+	if ids == nil {
+		return []int{}
+	}
+	return ids
+}
+
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
@@ -0,0 +1,66 @@
+package task_usecase
+
+import (
+	"DataTask/internal/domain/dto"
+	"DataTask/internal/domain/entity"
+	"DataTask/internal/usecase/custom_field_usecase"
+	"DataTask/pkg/filterql"
+	"context"
+	"reflect"
+	"testing"
+)
+
+// noCustomFields stands in for the custom field use case of filters without field[...] values.
+type noCustomFields struct {
+	custom_field_usecase.CustomFieldUseCase
+}
+
+func (noCustomFields) ParseFilter(context.Context, map[int]string) ([]*entity.CustomFieldValue, error) {
+	return nil, nil
+}
+
+var assigneeSchema = filterql.Schema{
+	"assignee": {
+		Type:   filterql.Int,
+		Member: "EXISTS (SELECT 1 FROM task_users tu WHERE tu.task_id = t.id AND tu.user_id %s)",
+		Me:     true,
+	},
+}
+
+func TestToTaskFilterResolvesMe(t *testing.T) {
+	uc := &TaskUseCaseImpl{customFields: noCustomFields{}}
+	ctx := context.WithValue(context.Background(), "user_id", 7)
+
+	filter, err := uc.toTaskFilter(ctx, &dto.TaskFilter{Query: "assignee:me"})
+	if err != nil {
+		t.Fatalf("toTaskFilter failed: %v", err)
+	}
+	if filter.CurrentUserID != 7 {
+		t.Fatalf("CurrentUserID = %d, want 7", filter.CurrentUserID)
+	}
+
+	sql, args, err := filterql.Compile(filter.Query, assigneeSchema, filterql.Options{UserID: filter.CurrentUserID}, nil)
+	if err != nil {
+		t.Fatalf("Compile failed: %v", err)
+	}
+	wantSQL := "EXISTS (SELECT 1 FROM task_users tu WHERE tu.task_id = t.id AND tu.user_id IN ($1))"
+	if sql != wantSQL {
+		t.Errorf("sql = %q, want %q", sql, wantSQL)
+	}
+	if !reflect.DeepEqual(args, []any{7}) {
+		t.Errorf("args = %v, want [7]", args)
+	}
+}
+
+func TestToTaskFilterWithoutUser(t *testing.T) {
+	uc := &TaskUseCaseImpl{customFields: noCustomFields{}}
+
+	filter, err := uc.toTaskFilter(context.Background(), &dto.TaskFilter{Query: "assignee:me"})
+	if err != nil {
+		t.Fatalf("toTaskFilter failed: %v", err)
+	}
+	_, _, err = filterql.Compile(filter.Query, assigneeSchema, filterql.Options{UserID: filter.CurrentUserID}, nil)
+	if err != filterql.ErrNoCurrentUser {
+		t.Errorf("Compile error = %v, want ErrNoCurrentUser", err)
+	}
+}
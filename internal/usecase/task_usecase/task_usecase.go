@@ -8,10 +8,17 @@ import (
 type TaskUseCase interface {
 	CreateTask(ctx context.Context, task *dto.Task) (*dto.Task, error)
 	GetTaskByID(ctx context.Context, id int) (*dto.Task, error)
-	UpdateTask(ctx context.Context, task *dto.Task) (*dto.Task, error)
+	// ResolveTaskKey returns the ID of the task with a key such as DT-123.
+	ResolveTaskKey(ctx context.Context, key string) (int, error)
+	UpdateTask(ctx context.Context, id int, update *dto.TaskUpdate) (*dto.Task, error)
 	DeleteTask(ctx context.Context, id int) error
-	GetTasksByKanbanID(ctx context.Context, kanbanID int) ([]*dto.Task, error)
-	GetTasksByUserID(ctx context.Context, userID int) ([]*dto.Task, error)
+	GetTasksByKanbanID(ctx context.Context, kanbanID int, filter *dto.TaskFilter) ([]*dto.Task, error)
+	GetTasksByUserID(ctx context.Context, userID int, filter *dto.TaskFilter) ([]*dto.Task, error)
 	AssignUserToTask(ctx context.Context, taskID int, userID int) error
-	GetTasksByProjectID(ctx context.Context, projectID int) ([]*dto.Task, error)
+	GetTasksByProjectID(ctx context.Context, projectID int, filter *dto.TaskFilter) ([]*dto.Task, error)
+	GetSubtasks(ctx context.Context, taskID int) ([]*dto.Task, error)
+	// ValidateFilter checks a task list filter without running it.
+	ValidateFilter(ctx context.Context, filter *dto.TaskFilter) error
+	// RenderDescriptions adds the sanitized HTML of their descriptions to tasks.
+	RenderDescriptions(ctx context.Context, tasks []*dto.Task) error
 }
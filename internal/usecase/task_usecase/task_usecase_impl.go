@@ -1,43 +1,186 @@
 package task_usecase
 
 import (
+	"DataTask/internal/domain/domain_error"
 	"DataTask/internal/domain/dto"
 	"DataTask/internal/domain/entity"
+	"DataTask/internal/repository/database"
+	"DataTask/internal/repository/kanban_repository"
+	"DataTask/internal/repository/label_repository"
+	"DataTask/internal/repository/project_repository"
 	"DataTask/internal/repository/task_repository"
+	"DataTask/internal/repository/workflow_repository"
+	"DataTask/internal/usecase/assignee_usecase"
+	"DataTask/internal/usecase/custom_field_usecase"
+	"DataTask/internal/usecase/history_usecase"
+	"DataTask/internal/usecase/markdown_usecase"
+	"DataTask/internal/usecase/mention_usecase"
+	"DataTask/internal/usecase/recurrence_usecase"
+	"DataTask/internal/usecase/task_link_usecase"
+	"DataTask/internal/usecase/task_template_usecase"
+	"DataTask/internal/usecase/timetracking_usecase"
+	"DataTask/pkg/filterql"
+	"DataTask/pkg/taskkey"
 	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
 )
 
+const defaultTimeZone = "UTC"
+
+// maxSortKeys bounds the ORDER BY of task lists.
+const maxSortKeys = 5
+
+// maxEstimate keeps estimates within the NUMERIC(10, 2) column.
+const maxEstimate = 99999999
+
 type TaskUseCaseImpl struct {
-	repo task_repository.TaskRepository
+	repo         task_repository.TaskRepository
+	labelRepo    label_repository.LabelRepository
+	projectRepo  project_repository.ProjectRepository
+	workflowRepo workflow_repository.WorkflowRepository
+	kanbanRepo   kanban_repository.KanbanRepository
+	linkUseCase  task_link_usecase.TaskLinkUseCase
+	recurrence   recurrence_usecase.RecurrenceUseCase
+	timeTracker  timetracking_usecase.TimeTrackingUseCase
+	customFields custom_field_usecase.CustomFieldUseCase
+	assignees    assignee_usecase.AssigneeUseCase
+	history      history_usecase.HistoryUseCase
+	templates    task_template_usecase.TaskTemplateUseCase
+	mentions     mention_usecase.MentionUseCase
+	markdown     markdown_usecase.MarkdownUseCase
+	transactor   database.Transactor
+
+	// parentCompletionPolicy is one of the entity.ParentCompletion* values.
+	parentCompletionPolicy string
 }
 
-func NewTaskUseCase(repo task_repository.TaskRepository) *TaskUseCaseImpl {
-	return &TaskUseCaseImpl{repo: repo}
+func NewTaskUseCase(
+	repo task_repository.TaskRepository,
+	labelRepo label_repository.LabelRepository,
+	projectRepo project_repository.ProjectRepository,
+	workflowRepo workflow_repository.WorkflowRepository,
+	kanbanRepo kanban_repository.KanbanRepository,
+	linkUseCase task_link_usecase.TaskLinkUseCase,
+	recurrence recurrence_usecase.RecurrenceUseCase,
+	timeTracker timetracking_usecase.TimeTrackingUseCase,
+	customFields custom_field_usecase.CustomFieldUseCase,
+	assignees assignee_usecase.AssigneeUseCase,
+	history history_usecase.HistoryUseCase,
+	templates task_template_usecase.TaskTemplateUseCase,
+	mentions mention_usecase.MentionUseCase,
+	markdown markdown_usecase.MarkdownUseCase,
+	transactor database.Transactor,
+	parentCompletionPolicy string,
+) *TaskUseCaseImpl {
+	if parentCompletionPolicy == "" {
+		parentCompletionPolicy = entity.ParentCompletionBlock
+	}
+	return &TaskUseCaseImpl{
+		repo:                   repo,
+		labelRepo:              labelRepo,
+		projectRepo:            projectRepo,
+		workflowRepo:           workflowRepo,
+		kanbanRepo:             kanbanRepo,
+		linkUseCase:            linkUseCase,
+		recurrence:             recurrence,
+		timeTracker:            timeTracker,
+		customFields:           customFields,
+		assignees:              assignees,
+		history:                history,
+		templates:              templates,
+		mentions:               mentions,
+		markdown:               markdown,
+		transactor:             transactor,
+		parentCompletionPolicy: parentCompletionPolicy,
+	}
 }
 
 func (uc *TaskUseCaseImpl) CreateTask(ctx context.Context, task *dto.Task) (*dto.Task, error) {
+	var template *entity.TaskTemplate
+	if task.TemplateID != nil {
+		var err error
+		template, err = uc.templates.PrepareTask(ctx, *task.TemplateID, task)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	entityTask := &entity.Task{
 		Title:       task.Title,
 		KanbanID:    task.KanbanID,
 		Description: task.Description,
 		IsCompleted: task.IsCompleted,
+		StartAt:     task.StartAt,
+		DueAt:       task.DueAt,
+		TimeZone:    task.TimeZone,
+		Priority:    task.Priority,
+		Estimate:    task.Estimate,
+		StatusID:    task.StatusID,
+
+		ParentTaskID: task.ParentTaskID,
+	}
+	if entityTask.TimeZone == "" {
+		entityTask.TimeZone = defaultTimeZone
+	}
+	if entityTask.Priority == "" {
+		entityTask.Priority = entity.PriorityNone
 	}
 
-	createdTask, err := uc.repo.CreateTask(ctx, entityTask)
-	if err != nil {
+	if err := validateTask(entityTask); err != nil {
 		return nil, err
 	}
+	if err := uc.validateParent(ctx, entityTask); err != nil {
+		return nil, err
+	}
+	if err := uc.validateStatus(ctx, entityTask); err != nil {
+		return nil, err
+	}
+
+	var createdTask *entity.Task
+	var mentions []*dto.Mention
+	var warnings []string
+	err := uc.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		warning, err := uc.checkWIPLimit(ctx, entityTask.KanbanID)
+		if err != nil {
+			return err
+		}
+		if warning != "" {
+			warnings = append(warnings, warning)
+		}
 
-	dtoTask := &dto.Task{
-		ID:          createdTask.ID,
-		KanbanID:    createdTask.KanbanID,
-		Title:       createdTask.Title,
-		Description: createdTask.Description,
-		IsCompleted: createdTask.IsCompleted,
-		CreatedAt:   createdTask.CreatedAt,
-		UpdatedAt:   createdTask.UpdatedAt,
+		createdTask, err = uc.repo.CreateTask(ctx, entityTask)
+		if err != nil {
+			return err
+		}
+		mentions, err = uc.mentions.SyncTaskMentions(ctx, createdTask.ID, createdTask.Description)
+		if err != nil {
+			return err
+		}
+		if template != nil {
+			return uc.templates.ApplyTemplate(ctx, createdTask.ID, createdTask.KanbanID, template)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
+	dtoTask := toTaskDTO(createdTask)
+	dtoTask.Warnings = warnings
+	dtoTask.Mentions = mentions
+	if template != nil {
+		if err := uc.attachRelations(ctx, []*dto.Task{dtoTask}); err != nil {
+			return nil, err
+		}
+		uc.assignees.NotifyTaskChanged(ctx, createdTask.ID, "created")
+		return dtoTask, nil
+	}
+	dtoTask.Labels = []*dto.Label{}
+	dtoTask.CustomFields = []*dto.CustomFieldValue{}
+	dtoTask.Assignees = []*dto.User{}
 	return dtoTask, nil
 }
 
@@ -47,43 +190,250 @@ func (uc *TaskUseCaseImpl) GetTaskByID(ctx context.Context, id int) (*dto.Task,
 		return nil, err
 	}
 
-	dtoTask := &dto.Task{
-		ID:          task.ID,
-		Title:       task.Title,
-		Description: task.Description,
-		IsCompleted: task.IsCompleted,
-		CreatedAt:   task.CreatedAt,
-		UpdatedAt:   task.UpdatedAt,
+	dtoTask := toTaskDTO(task)
+	if err := uc.attachRelations(ctx, []*dto.Task{dtoTask}); err != nil {
+		return nil, err
+	}
+
+	progress, err := uc.repo.GetTaskProgress(ctx, id)
+	if err != nil {
+		return nil, err
 	}
+	dtoTask.Progress = toTaskProgressDTO(progress)
 
+	dtoTask.Links, err = uc.linkUseCase.GetLinks(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	dtoTask.Mentions, err = uc.mentions.GetTaskMentions(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	timeSpent, err := uc.timeTracker.GetTaskTimeSpent(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	dtoTask.TimeSpentSeconds = &timeSpent
 	return dtoTask, nil
 }
 
-func (uc *TaskUseCaseImpl) UpdateTask(ctx context.Context, task *dto.Task) (*dto.Task, error) {
-	entityTask := &entity.Task{
-		ID:          task.ID,
-		Title:       task.Title,
-		Description: task.Description,
-		IsCompleted: task.IsCompleted,
+func (uc *TaskUseCaseImpl) ResolveTaskKey(ctx context.Context, key string) (int, error) {
+	projectKey, number, ok := taskkey.Parse(key)
+	if !ok {
+		return 0, fmt.Errorf("%w: invalid task key %q", domain_error.ErrValidation, key)
 	}
+	return uc.repo.GetTaskIDByKey(ctx, projectKey, number)
+}
 
-	updatedTask, err := uc.repo.UpdateTask(ctx, entityTask)
+func (uc *TaskUseCaseImpl) UpdateTask(ctx context.Context, id int, update *dto.TaskUpdate) (*dto.Task, error) {
+	var result *taskUpdateResult
+	err := uc.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		result, err = uc.updateTask(ctx, id, update)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	dtoTask := &dto.Task{
-		ID:          updatedTask.ID,
-		Title:       updatedTask.Title,
-		Description: updatedTask.Description,
-		IsCompleted: updatedTask.IsCompleted,
-		CreatedAt:   updatedTask.CreatedAt,
-		UpdatedAt:   updatedTask.UpdatedAt,
+	for _, subtaskID := range result.completedSubtasks {
+		uc.assignees.NotifyTaskChanged(ctx, subtaskID, "completed")
+	}
+	if result.completed {
+		uc.assignees.NotifyTaskChanged(ctx, id, "completed")
+	} else {
+		uc.assignees.NotifyTaskChanged(ctx, id, "updated")
 	}
 
+	dtoTask := toTaskDTO(result.task)
+	dtoTask.Warnings = result.warnings
+	dtoTask.Mentions = result.mentions
+	if err := uc.attachRelations(ctx, []*dto.Task{dtoTask}); err != nil {
+		return nil, err
+	}
 	return dtoTask, nil
 }
 
+// taskUpdateResult is what updateTask did: the updated task, the warnings and mentions for the
+// response and the subtasks the cascade policy completed along with it.
+type taskUpdateResult struct {
+	task              *entity.Task
+	completed         bool
+	warnings          []string
+	mentions          []*dto.Mention
+	completedSubtasks []int
+}
+
+// updateTask applies an update to a task within the transaction of ctx. Subtasks completed by the
+// cascade policy go through here as well, so they are checked and recorded like any other update.
+func (uc *TaskUseCaseImpl) updateTask(ctx context.Context, id int, update *dto.TaskUpdate) (*taskUpdateResult, error) {
+	entityTask, err := uc.repo.GetTaskByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	before := *entityTask
+	wasCompleted := entityTask.IsCompleted
+	oldParentID := entityTask.ParentTaskID
+
+	applyTaskUpdate(entityTask, update)
+
+	if err := validateTask(entityTask); err != nil {
+		return nil, err
+	}
+	if entityTask.KanbanID != before.KanbanID {
+		if err := uc.validateMove(ctx, before.KanbanID, entityTask.KanbanID); err != nil {
+			return nil, err
+		}
+	}
+	if !sameParent(oldParentID, entityTask.ParentTaskID) {
+		if err := uc.validateParent(ctx, entityTask); err != nil {
+			return nil, err
+		}
+	}
+	if err := uc.applyStatus(ctx, &before, entityTask, update); err != nil {
+		return nil, err
+	}
+
+	result := &taskUpdateResult{completed: !wasCompleted && entityTask.IsCompleted}
+	if result.completed {
+		warning, err := uc.checkOpenBlockers(ctx, entityTask)
+		if err != nil {
+			return nil, err
+		}
+		if warning != "" {
+			result.warnings = append(result.warnings, warning)
+		}
+
+		openSubtasks, err := uc.repo.CountOpenDescendants(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if openSubtasks > 0 {
+			switch uc.parentCompletionPolicy {
+			case entity.ParentCompletionBlock:
+				return nil, fmt.Errorf("%w: task has %d open subtasks", domain_error.ErrConflict, openSubtasks)
+			case entity.ParentCompletionCascade:
+				if err := uc.completeSubtasks(ctx, id, result); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	if entityTask.KanbanID != before.KanbanID {
+		warning, err := uc.checkWIPLimit(ctx, entityTask.KanbanID)
+		if err != nil {
+			return nil, err
+		}
+		if warning != "" {
+			result.warnings = append(result.warnings, warning)
+		}
+	}
+
+	result.task, err = uc.repo.UpdateTask(ctx, entityTask)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.history.RecordChanges(ctx, id, taskChanges(&before, entityTask)); err != nil {
+		return nil, err
+	}
+	if result.task.Description != before.Description {
+		if result.mentions, err = uc.mentions.SyncTaskMentions(ctx, id, result.task.Description); err != nil {
+			return nil, err
+		}
+	}
+
+	if result.completed {
+		if err := uc.timeTracker.StopTimersOnTask(ctx, id); err != nil {
+			return nil, err
+		}
+		if err := uc.recurrence.OnTaskCompleted(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// completeSubtasks completes the open subtasks of a task one by one through updateTask, which
+// completes their own subtasks in turn. Their warnings and IDs are added to the result of the parent.
+func (uc *TaskUseCaseImpl) completeSubtasks(ctx context.Context, taskID int, result *taskUpdateResult) error {
+	subtasks, err := uc.repo.GetSubtasks(ctx, taskID)
+	if err != nil {
+		return err
+	}
+
+	completed := true
+	for _, subtask := range subtasks {
+		if subtask.IsCompleted {
+			// A completed subtask can still have open subtasks of its own.
+			if err := uc.completeSubtasks(ctx, subtask.ID, result); err != nil {
+				return err
+			}
+			continue
+		}
+
+		subtaskResult, err := uc.updateTask(ctx, subtask.ID, &dto.TaskUpdate{IsCompleted: &completed})
+		if err != nil {
+			return fmt.Errorf("complete subtask %d: %w", subtask.ID, err)
+		}
+		for _, warning := range subtaskResult.warnings {
+			result.warnings = append(result.warnings, fmt.Sprintf("subtask %d: %s", subtask.ID, warning))
+		}
+		result.completedSubtasks = append(result.completedSubtasks, subtask.ID)
+		result.completedSubtasks = append(result.completedSubtasks, subtaskResult.completedSubtasks...)
+	}
+	return nil
+}
+
+// checkOpenBlockers applies the project's blocked completion policy to a task being completed.
+// It returns a warning for the response when the project only warns about open blockers.
+func (uc *TaskUseCaseImpl) checkOpenBlockers(ctx context.Context, task *entity.Task) (string, error) {
+	openBlockers, err := uc.linkUseCase.CountOpenBlockers(ctx, task.ID)
+	if err != nil || openBlockers == 0 {
+		return "", err
+	}
+
+	projectID, err := uc.projectRepo.GetProjectIDByKanbanID(ctx, task.KanbanID)
+	if err != nil {
+		return "", err
+	}
+	settings, err := uc.projectRepo.GetProjectSettings(ctx, projectID)
+	if err != nil {
+		return "", err
+	}
+
+	if settings.BlockedCompletionPolicy == entity.BlockedCompletionFail {
+		return "", fmt.Errorf("%w: task is blocked by %d open tasks", domain_error.ErrConflict, openBlockers)
+	}
+	return fmt.Sprintf("task was completed while blocked by %d open tasks", openBlockers), nil
+}
+
+// checkWIPLimit applies the project's WIP limit policy to a task being added to a kanban. The kanban
+// stays locked until the transaction ends, so concurrent additions cannot overfill it. It returns a
+// warning for the response when the project only warns about full kanbans.
+func (uc *TaskUseCaseImpl) checkWIPLimit(ctx context.Context, kanbanID int) (string, error) {
+	limit, taskCount, err := uc.kanbanRepo.LockWIPLimit(ctx, kanbanID)
+	if err != nil || limit == nil || taskCount < *limit {
+		return "", err
+	}
+
+	projectID, err := uc.projectRepo.GetProjectIDByKanbanID(ctx, kanbanID)
+	if err != nil {
+		return "", err
+	}
+	settings, err := uc.projectRepo.GetProjectSettings(ctx, projectID)
+	if err != nil {
+		return "", err
+	}
+
+	if settings.WIPLimitPolicy == entity.WIPLimitFail {
+		return "", fmt.Errorf("%w: kanban is at its WIP limit of %d tasks", domain_error.ErrConflict, *limit)
+	}
+	return fmt.Sprintf("kanban is over its WIP limit of %d tasks", *limit), nil
+}
+
 func (uc *TaskUseCaseImpl) DeleteTask(ctx context.Context, id int) error {
 	err := uc.repo.DeleteTask(ctx, id)
 	if err != nil {
@@ -92,73 +442,512 @@ func (uc *TaskUseCaseImpl) DeleteTask(ctx context.Context, id int) error {
 	return nil
 }
 
-func (uc *TaskUseCaseImpl) GetTasksByKanbanID(ctx context.Context, kanbanID int) ([]*dto.Task, error) {
-	tasks, err := uc.repo.GetTasksByKanbanID(ctx, kanbanID)
+func (uc *TaskUseCaseImpl) GetTasksByKanbanID(ctx context.Context, kanbanID int, filter *dto.TaskFilter) ([]*dto.Task, error) {
+	entityFilter, err := uc.toTaskFilter(ctx, filter)
 	if err != nil {
 		return nil, err
 	}
 
-	var dtoTasks []*dto.Task
-	for _, t := range tasks {
-		dtoTasks = append(dtoTasks, &dto.Task{
-			ID:          t.ID,
-			Title:       t.Title,
-			Description: t.Description,
-			IsCompleted: t.IsCompleted,
-			CreatedAt:   t.CreatedAt,
-			UpdatedAt:   t.UpdatedAt,
-		})
+	tasks, err := uc.repo.GetTasksByKanbanID(ctx, kanbanID, entityFilter)
+	if err != nil {
+		return nil, err
 	}
 
+	dtoTasks := toTaskDTOs(tasks)
+	if err := uc.attachRelations(ctx, dtoTasks); err != nil {
+		return nil, err
+	}
 	return dtoTasks, nil
 }
 
-func (uc *TaskUseCaseImpl) GetTasksByUserID(ctx context.Context, userID int) ([]*dto.Task, error) {
-	tasks, err := uc.repo.GetTasksByUserID(ctx, userID)
+func (uc *TaskUseCaseImpl) GetTasksByUserID(ctx context.Context, userID int, filter *dto.TaskFilter) ([]*dto.Task, error) {
+	entityFilter, err := uc.toTaskFilter(ctx, filter)
 	if err != nil {
 		return nil, err
 	}
 
-	var dtoTasks []*dto.Task
-	for _, t := range tasks {
-		dtoTasks = append(dtoTasks, &dto.Task{
-			ID:          t.ID,
-			Title:       t.Title,
-			Description: t.Description,
-			IsCompleted: t.IsCompleted,
-			CreatedAt:   t.CreatedAt,
-			UpdatedAt:   t.UpdatedAt,
-		})
+	tasks, err := uc.repo.GetTasksByUserID(ctx, userID, entityFilter)
+	if err != nil {
+		return nil, err
 	}
 
+	dtoTasks := toTaskDTOs(tasks)
+	if err := uc.attachRelations(ctx, dtoTasks); err != nil {
+		return nil, err
+	}
 	return dtoTasks, nil
 }
 
 func (uc *TaskUseCaseImpl) AssignUserToTask(ctx context.Context, taskID int, userID int) error {
-	err := uc.repo.AssignUserToTask(ctx, taskID, userID)
+	_, err := uc.assignees.AddAssignee(ctx, taskID, userID)
+	return err
+}
+
+func (uc *TaskUseCaseImpl) GetSubtasks(ctx context.Context, taskID int) ([]*dto.Task, error) {
+	if _, err := uc.repo.GetTaskByID(ctx, taskID); err != nil {
+		return nil, err
+	}
+
+	tasks, err := uc.repo.GetSubtasks(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	dtoTasks := toTaskDTOs(tasks)
+	if err := uc.attachRelations(ctx, dtoTasks); err != nil {
+		return nil, err
+	}
+	return dtoTasks, nil
+}
+
+func (uc *TaskUseCaseImpl) GetTasksByProjectID(ctx context.Context, projectID int, filter *dto.TaskFilter) ([]*dto.Task, error) {
+	entityFilter, err := uc.toTaskFilter(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, err := uc.repo.GetTasksByProjectID(ctx, projectID, entityFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	dtoTasks := toTaskDTOs(tasks)
+	if err := uc.attachRelations(ctx, dtoTasks); err != nil {
+		return nil, err
+	}
+	return dtoTasks, nil
+}
+
+func (uc *TaskUseCaseImpl) ValidateFilter(ctx context.Context, filter *dto.TaskFilter) error {
+	entityFilter, err := uc.toTaskFilter(ctx, filter)
 	if err != nil {
 		return err
 	}
+	return uc.repo.ValidateFilter(entityFilter)
+}
+
+func applyTaskUpdate(task *entity.Task, update *dto.TaskUpdate) {
+	if update.Title != nil {
+		task.Title = *update.Title
+	}
+	if update.Description != nil {
+		task.Description = *update.Description
+	}
+	if update.IsCompleted != nil {
+		task.IsCompleted = *update.IsCompleted
+	}
+	if update.StartAt != nil {
+		task.StartAt = update.StartAt
+	}
+	if update.ClearStartAt {
+		task.StartAt = nil
+	}
+	if update.DueAt != nil {
+		task.DueAt = update.DueAt
+	}
+	if update.ClearDueAt {
+		task.DueAt = nil
+	}
+	if update.TimeZone != nil {
+		task.TimeZone = *update.TimeZone
+	}
+	if update.Priority != nil {
+		task.Priority = *update.Priority
+	}
+	if update.Estimate != nil {
+		task.Estimate = update.Estimate
+	}
+	if update.ClearEstimate {
+		task.Estimate = nil
+	}
+	if update.ParentTaskID != nil {
+		task.ParentTaskID = update.ParentTaskID
+	}
+	if update.ClearParentTaskID {
+		task.ParentTaskID = nil
+	}
+	if update.KanbanID != nil {
+		task.KanbanID = *update.KanbanID
+	}
+}
+
+// taskChanges lists the task columns for the history. The history skips unchanged values.
+func taskChanges(before *entity.Task, after *entity.Task) []*entity.TaskChange {
+	return []*entity.TaskChange{
+		{Field: "title", OldValue: before.Title, NewValue: after.Title},
+		{Field: "description", OldValue: before.Description, NewValue: after.Description},
+		{Field: "is_completed", OldValue: before.IsCompleted, NewValue: after.IsCompleted},
+		{Field: "kanban_id", OldValue: before.KanbanID, NewValue: after.KanbanID},
+		{Field: "status_id", OldValue: before.StatusID, NewValue: after.StatusID},
+		{Field: "priority", OldValue: before.Priority, NewValue: after.Priority},
+		{Field: "start_at", OldValue: inTimeZone(before.StartAt, time.UTC), NewValue: inTimeZone(after.StartAt, time.UTC)},
+		{Field: "due_at", OldValue: inTimeZone(before.DueAt, time.UTC), NewValue: inTimeZone(after.DueAt, time.UTC)},
+		{Field: "time_zone", OldValue: before.TimeZone, NewValue: after.TimeZone},
+		{Field: "estimate", OldValue: before.Estimate, NewValue: after.Estimate},
+		{Field: "parent_task_id", OldValue: before.ParentTaskID, NewValue: after.ParentTaskID},
+	}
+}
+
+func sameParent(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// validateMove keeps moved tasks inside their project, where their labels, custom field values
+// and parent task stay valid.
+func (uc *TaskUseCaseImpl) validateMove(ctx context.Context, fromKanbanID int, toKanbanID int) error {
+	toProjectID, err := uc.projectRepo.GetProjectIDByKanbanID(ctx, toKanbanID)
+	if err != nil {
+		return err
+	}
+	fromProjectID, err := uc.projectRepo.GetProjectIDByKanbanID(ctx, fromKanbanID)
+	if err != nil {
+		return err
+	}
+	if fromProjectID != toProjectID {
+		return fmt.Errorf("%w: tasks can only be moved between boards of the same project", domain_error.ErrValidation)
+	}
 	return nil
 }
 
-func (uc *TaskUseCaseImpl) GetTasksByProjectID(ctx context.Context, projectID int) ([]*dto.Task, error) {
-	tasks, err := uc.repo.GetTasksByProjectID(ctx, projectID)
+// RenderDescriptions renders the descriptions of tasks through the markdown use case.
+func (uc *TaskUseCaseImpl) RenderDescriptions(ctx context.Context, tasks []*dto.Task) error {
+	return uc.markdown.RenderTasks(ctx, tasks)
+}
+
+// validateStatus makes sure the status of a new task belongs to the project of its kanban. Tasks
+// created without a status get the first one agreeing with is_completed.
+func (uc *TaskUseCaseImpl) validateStatus(ctx context.Context, task *entity.Task) error {
+	if task.StatusID == nil {
+		return nil
+	}
+	projectID, err := uc.projectRepo.GetProjectIDByKanbanID(ctx, task.KanbanID)
 	if err != nil {
-		return nil, err
+		return err
+	}
+	workflow, err := uc.workflowRepo.GetWorkflow(ctx, projectID)
+	if err != nil {
+		return err
+	}
+	status := workflow.Status(*task.StatusID)
+	if status == nil {
+		return fmt.Errorf("%w: status %d is not a status of the project", domain_error.ErrValidation, *task.StatusID)
+	}
+	task.IsCompleted = status.Category == entity.StatusCategoryDone
+	return nil
+}
+
+// applyStatus works out the status of an updated task: the status given, else the one of the
+// kanban the task moved onto, else the first reachable status of the right category when only
+// is_completed changed. The change has to be allowed by the project's workflow; is_completed
+// follows the category of the status.
+func (uc *TaskUseCaseImpl) applyStatus(ctx context.Context, before *entity.Task, task *entity.Task, update *dto.TaskUpdate) error {
+	projectID, err := uc.projectRepo.GetProjectIDByKanbanID(ctx, task.KanbanID)
+	if err != nil {
+		return err
+	}
+	workflow, err := uc.workflowRepo.GetWorkflow(ctx, projectID)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case update.StatusID != nil:
+		if workflow.Status(*update.StatusID) == nil {
+			return fmt.Errorf("%w: status %d is not a status of the project", domain_error.ErrValidation, *update.StatusID)
+		}
+		task.StatusID = update.StatusID
+	case task.KanbanID != before.KanbanID:
+		kanbanStatusID, err := uc.workflowRepo.GetKanbanStatusID(ctx, task.KanbanID)
+		if err != nil {
+			return err
+		}
+		if kanbanStatusID != nil {
+			task.StatusID = kanbanStatusID
+		}
+	}
+
+	if update.StatusID == nil && update.IsCompleted != nil {
+		var current *entity.WorkflowStatus
+		if task.StatusID != nil {
+			current = workflow.Status(*task.StatusID)
+		}
+		if current == nil || (current.Category == entity.StatusCategoryDone) != *update.IsCompleted {
+			categories := []string{entity.StatusCategoryTodo, entity.StatusCategoryInProgress}
+			if *update.IsCompleted {
+				categories = []string{entity.StatusCategoryDone}
+			}
+			var next *entity.WorkflowStatus
+			for _, category := range categories {
+				if next = workflow.FirstReachable(before.StatusID, category); next != nil {
+					break
+				}
+			}
+			if next == nil {
+				return fmt.Errorf("%w: the workflow allows no status with is_completed %t from the task's status",
+					domain_error.ErrConflict, *update.IsCompleted)
+			}
+			task.StatusID = &next.ID
+		}
+	}
+
+	if task.StatusID == nil {
+		return nil
+	}
+	status := workflow.Status(*task.StatusID)
+	if status == nil {
+		return nil
+	}
+	if !workflow.Allows(before.StatusID, status.ID) {
+		return fmt.Errorf("%w: the workflow does not allow moving the task to status %q",
+			domain_error.ErrConflict, status.Name)
+	}
+	task.IsCompleted = status.Category == entity.StatusCategoryDone
+	return nil
+}
+
+// validateParent makes sure the parent task lives in the same project and that
+// attaching the task to it does not create a loop.
+func (uc *TaskUseCaseImpl) validateParent(ctx context.Context, task *entity.Task) error {
+	if task.ParentTaskID == nil {
+		return nil
+	}
+	parentID := *task.ParentTaskID
+	if parentID == task.ID {
+		return fmt.Errorf("%w: a task cannot be its own parent", domain_error.ErrValidation)
+	}
+
+	if _, err := uc.repo.GetTaskByID(ctx, parentID); err != nil {
+		return fmt.Errorf("%w: parent task %d does not exist", domain_error.ErrValidation, parentID)
+	}
+
+	parentProjectID, err := uc.projectRepo.GetProjectIDByTaskID(ctx, parentID)
+	if err != nil {
+		return err
+	}
+	projectID, err := uc.projectRepo.GetProjectIDByKanbanID(ctx, task.KanbanID)
+	if err != nil {
+		return err
+	}
+	if parentProjectID != projectID {
+		return fmt.Errorf("%w: parent task belongs to another project", domain_error.ErrValidation)
+	}
+
+	// A new task has no subtasks yet, so only existing tasks can form a loop.
+	if task.ID == 0 {
+		return nil
+	}
+	ancestorIDs, err := uc.repo.GetTaskAncestorIDs(ctx, parentID)
+	if err != nil {
+		return err
+	}
+	for _, ancestorID := range ancestorIDs {
+		if ancestorID == task.ID {
+			return fmt.Errorf("%w: task %d is a subtask of task %d", domain_error.ErrValidation, parentID, task.ID)
+		}
+	}
+	return nil
+}
+
+// attachRelations loads the labels, assignees and custom field values of all tasks, one query each.
+func (uc *TaskUseCaseImpl) attachRelations(ctx context.Context, tasks []*dto.Task) error {
+	if err := uc.attachLabels(ctx, tasks); err != nil {
+		return err
 	}
 
+	taskIDs := make([]int, 0, len(tasks))
+	for _, t := range tasks {
+		taskIDs = append(taskIDs, t.ID)
+	}
+	values, err := uc.customFields.GetValuesByTaskIDs(ctx, taskIDs)
+	if err != nil {
+		return err
+	}
+	assignees, err := uc.assignees.GetAssigneesByTaskIDs(ctx, taskIDs)
+	if err != nil {
+		return err
+	}
+	for _, t := range tasks {
+		t.CustomFields = values[t.ID]
+		t.Assignees = assignees[t.ID]
+	}
+	return nil
+}
+
+// attachLabels loads the labels of all tasks with a single query.
+func (uc *TaskUseCaseImpl) attachLabels(ctx context.Context, tasks []*dto.Task) error {
+	taskIDs := make([]int, 0, len(tasks))
+	for _, t := range tasks {
+		taskIDs = append(taskIDs, t.ID)
+	}
+
+	labelsByTask, err := uc.labelRepo.GetLabelsByTaskIDs(ctx, taskIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, t := range tasks {
+		t.Labels = make([]*dto.Label, 0, len(labelsByTask[t.ID]))
+		for _, l := range labelsByTask[t.ID] {
+			t.Labels = append(t.Labels, &dto.Label{
+				ID:        l.ID,
+				ProjectID: l.ProjectID,
+				Name:      l.Name,
+				Color:     l.Color,
+				CreatedAt: l.CreatedAt,
+				UpdatedAt: l.UpdatedAt,
+			})
+		}
+	}
+	return nil
+}
+
+// validateTask checks the priority, the time zone name and that the task does not end before it starts.
+func validateTask(task *entity.Task) error {
+	if !entity.IsValidPriority(task.Priority) {
+		return fmt.Errorf("%w: unknown priority %q", domain_error.ErrValidation, task.Priority)
+	}
+	if _, err := time.LoadLocation(task.TimeZone); err != nil {
+		return fmt.Errorf("%w: invalid time zone %q", domain_error.ErrValidation, task.TimeZone)
+	}
+	if task.StartAt != nil && task.DueAt != nil && task.DueAt.Before(*task.StartAt) {
+		return fmt.Errorf("%w: due_at must not be before start_at", domain_error.ErrValidation)
+	}
+	if task.Estimate != nil && (*task.Estimate < 0 || *task.Estimate > maxEstimate) {
+		return fmt.Errorf("%w: estimate must be between 0 and %d", domain_error.ErrValidation, maxEstimate)
+	}
+	return nil
+}
+
+// inTimeZone renders t in the task's time zone so clients get the offset the task was planned in.
+func inTimeZone(t *time.Time, loc *time.Location) *time.Time {
+	if t == nil {
+		return nil
+	}
+	local := t.In(loc)
+	return &local
+}
+
+func toTaskDTO(t *entity.Task) *dto.Task {
+	loc, err := time.LoadLocation(t.TimeZone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	return &dto.Task{
+		ID:           t.ID,
+		Number:       t.Number,
+		Key:          t.Key,
+		KanbanID:     t.KanbanID,
+		Title:        t.Title,
+		Description:  t.Description,
+		IsCompleted:  t.IsCompleted,
+		StartAt:      inTimeZone(t.StartAt, loc),
+		DueAt:        inTimeZone(t.DueAt, loc),
+		TimeZone:     t.TimeZone,
+		Priority:     t.Priority,
+		Estimate:     t.Estimate,
+		StatusID:     t.StatusID,
+		ParentTaskID: t.ParentTaskID,
+		RecurrenceID: t.RecurrenceID,
+		OccurrenceAt: inTimeZone(t.OccurrenceAt, loc),
+		IsOverdue:    !t.IsCompleted && t.DueAt != nil && t.DueAt.Before(time.Now()),
+		CreatedAt:    t.CreatedAt,
+		UpdatedAt:    t.UpdatedAt,
+	}
+}
+
+func toTaskDTOs(tasks []*entity.Task) []*dto.Task {
 	var dtoTasks []*dto.Task
 	for _, t := range tasks {
-		dtoTasks = append(dtoTasks, &dto.Task{
-			ID:          t.ID,
-			Title:       t.Title,
-			Description: t.Description,
-			IsCompleted: t.IsCompleted,
-			CreatedAt:   t.CreatedAt,
-			UpdatedAt:   t.UpdatedAt,
-		})
+		dtoTasks = append(dtoTasks, toTaskDTO(t))
 	}
+	return dtoTasks
+}
 
-	return dtoTasks, nil
+func toTaskProgressDTO(p *entity.TaskProgress) *dto.TaskProgress {
+	progress := &dto.TaskProgress{
+		ChecklistTotal:    p.ChecklistTotal,
+		ChecklistDone:     p.ChecklistDone,
+		SubtasksTotal:     p.SubtasksTotal,
+		SubtasksCompleted: p.SubtasksCompleted,
+	}
+	if total := p.ChecklistTotal + p.SubtasksTotal; total > 0 {
+		progress.Percent = (p.ChecklistDone + p.SubtasksCompleted) * 100 / total
+	}
+	return progress
+}
+
+// ParseTaskSort reads comma-separated sort keys such as "-priority,due_at,field.3": a leading
+// minus sorts descending and field.<field id> sorts by a custom field. Blank input yields no keys.
+func ParseTaskSort(s string) ([]*dto.TaskSort, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+
+	var keys []*dto.TaskSort
+	for _, key := range strings.Split(s, ",") {
+		key = strings.TrimSpace(key)
+		sort := &dto.TaskSort{}
+		if strings.HasPrefix(key, "-") {
+			sort.Desc = true
+			key = key[1:]
+		}
+
+		if idStr, ok := strings.CutPrefix(key, "field."); ok {
+			fieldID, err := strconv.Atoi(idStr)
+			if err != nil {
+				return nil, fmt.Errorf("%w: invalid custom field id %q", domain_error.ErrValidation, idStr)
+			}
+			sort.CustomFieldID = fieldID
+		} else {
+			sort.Field = key
+		}
+		keys = append(keys, sort)
+	}
+	return keys, nil
+}
+
+// toTaskFilter resolves the custom fields the filter refers to.
+func (uc *TaskUseCaseImpl) toTaskFilter(ctx context.Context, filter *dto.TaskFilter) (*entity.TaskFilter, error) {
+	if filter == nil {
+		return nil, nil
+	}
+
+	customFields, err := uc.customFields.ParseFilter(ctx, filter.CustomFields)
+	if err != nil {
+		return nil, err
+	}
+	entityFilter := &entity.TaskFilter{
+		Overdue:      filter.Overdue,
+		Priorities:   filter.Priorities,
+		LabelIDs:     filter.LabelIDs,
+		CustomFields: customFields,
+	}
+
+	entityFilter.Query, err = filterql.Parse(filter.Query)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain_error.ErrValidation, err)
+	}
+	// The list endpoints are public; me resolves when a signed in user sent their token.
+	entityFilter.CurrentUserID, _ = ctx.Value("user_id").(int)
+
+	if len(filter.Sort) > maxSortKeys {
+		return nil, fmt.Errorf("%w: tasks can be sorted by at most %d keys", domain_error.ErrValidation, maxSortKeys)
+	}
+	for _, s := range filter.Sort {
+		sort := &entity.TaskSort{Field: s.Field, Desc: s.Desc}
+		if s.CustomFieldID != 0 {
+			sort.CustomField, err = uc.customFields.GetSortField(ctx, s.CustomFieldID)
+			if err != nil {
+				return nil, err
+			}
+		} else if !entity.IsValidTaskSortField(s.Field) {
+			return nil, fmt.Errorf("%w: cannot sort tasks by %q", domain_error.ErrValidation, s.Field)
+		}
+		entityFilter.Sort = append(entityFilter.Sort, sort)
+	}
+	return entityFilter, nil
 }
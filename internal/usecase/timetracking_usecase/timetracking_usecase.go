@@ -0,0 +1,27 @@
+package timetracking_usecase
+
+import (
+	"DataTask/internal/domain/dto"
+	"context"
+	"time"
+)
+
+type TimeTrackingUseCase interface {
+	// StartTimer starts the current user's timer on the task. Users can run one timer at a time.
+	StartTimer(ctx context.Context, taskID int) (*dto.Worklog, error)
+	// StopTimer stops the current user's timer on the task and keeps it as a worklog.
+	StopTimer(ctx context.Context, taskID int, note string) (*dto.Worklog, error)
+	GetRunningTimer(ctx context.Context) (*dto.Worklog, error)
+
+	CreateWorklog(ctx context.Context, worklog *dto.WorklogCreate) (*dto.Worklog, error)
+	GetWorklogsByTaskID(ctx context.Context, taskID int, from *time.Time, to *time.Time) ([]*dto.Worklog, error)
+	UpdateWorklog(ctx context.Context, taskID int, worklogID int, update *dto.WorklogUpdate) (*dto.Worklog, error)
+	DeleteWorklog(ctx context.Context, taskID int, worklogID int) error
+
+	GetTimeTotals(ctx context.Context, filter *dto.TimeTotalsFilter) (*dto.TimeTotals, error)
+
+	// GetTaskTimeSpent returns the time logged on the task so far, for the task details.
+	GetTaskTimeSpent(ctx context.Context, taskID int) (int64, error)
+	// StopTimersOnTask stops every running timer on a task that is being completed.
+	StopTimersOnTask(ctx context.Context, taskID int) error
+}
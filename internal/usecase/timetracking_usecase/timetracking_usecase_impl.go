@@ -0,0 +1,308 @@
+package timetracking_usecase
+
+import (
+	"DataTask/internal/domain/domain_error"
+	"DataTask/internal/domain/dto"
+	"DataTask/internal/domain/entity"
+	"DataTask/internal/repository/worklog_repository"
+	"DataTask/internal/usecase/access_usecase"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxWorklogDuration rejects obviously mistyped manual entries.
+const maxWorklogDuration = 24 * time.Hour
+
+type TimeTrackingUseCaseImpl struct {
+	repo   worklog_repository.WorklogRepository
+	access access_usecase.AccessUseCase
+}
+
+func NewTimeTrackingUseCase(repo worklog_repository.WorklogRepository, access access_usecase.AccessUseCase) *TimeTrackingUseCaseImpl {
+	return &TimeTrackingUseCaseImpl{
+		repo:   repo,
+		access: access,
+	}
+}
+
+func (uc *TimeTrackingUseCaseImpl) StartTimer(ctx context.Context, taskID int) (*dto.Worklog, error) {
+	userID, err := uc.access.CurrentUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := uc.access.RequireTaskPermission(ctx, taskID, entity.PermissionEdit); err != nil {
+		return nil, err
+	}
+
+	timer, err := uc.repo.StartTimer(ctx, taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return toWorklogDTO(timer), nil
+}
+
+func (uc *TimeTrackingUseCaseImpl) StopTimer(ctx context.Context, taskID int, note string) (*dto.Worklog, error) {
+	userID, err := uc.access.CurrentUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	timer, err := uc.repo.GetRunningTimer(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if timer.TaskID != taskID {
+		return nil, fmt.Errorf("%w: the running timer belongs to task %d", domain_error.ErrConflict, timer.TaskID)
+	}
+
+	stopped, err := uc.repo.StopTimer(ctx, timer.ID, strings.TrimSpace(note))
+	if err != nil {
+		return nil, err
+	}
+	return toWorklogDTO(stopped), nil
+}
+
+func (uc *TimeTrackingUseCaseImpl) GetRunningTimer(ctx context.Context) (*dto.Worklog, error) {
+	userID, err := uc.access.CurrentUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	timer, err := uc.repo.GetRunningTimer(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return toWorklogDTO(timer), nil
+}
+
+func (uc *TimeTrackingUseCaseImpl) CreateWorklog(ctx context.Context, worklog *dto.WorklogCreate) (*dto.Worklog, error) {
+	userID, err := uc.access.CurrentUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := uc.access.RequireTaskPermission(ctx, worklog.TaskID, entity.PermissionEdit); err != nil {
+		return nil, err
+	}
+	if err := validateDuration(worklog.Duration); err != nil {
+		return nil, err
+	}
+
+	endedAt := time.Now()
+	startedAt := endedAt.Add(-worklog.Duration)
+	if worklog.StartedAt != nil {
+		startedAt = *worklog.StartedAt
+		endedAt = startedAt.Add(worklog.Duration)
+	}
+
+	createdWorklog, err := uc.repo.CreateWorklog(ctx, &entity.Worklog{
+		TaskID:    worklog.TaskID,
+		UserID:    userID,
+		StartedAt: startedAt,
+		EndedAt:   &endedAt,
+		Note:      strings.TrimSpace(worklog.Note),
+		Source:    entity.WorklogSourceManual,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toWorklogDTO(createdWorklog), nil
+}
+
+func (uc *TimeTrackingUseCaseImpl) GetWorklogsByTaskID(ctx context.Context, taskID int, from *time.Time, to *time.Time) ([]*dto.Worklog, error) {
+	if _, err := uc.access.RequireTaskPermission(ctx, taskID, entity.PermissionRead); err != nil {
+		return nil, err
+	}
+
+	worklogs, err := uc.repo.GetWorklogsByTaskID(ctx, taskID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	dtoWorklogs := make([]*dto.Worklog, 0, len(worklogs))
+	for _, w := range worklogs {
+		dtoWorklogs = append(dtoWorklogs, toWorklogDTO(w))
+	}
+	return dtoWorklogs, nil
+}
+
+func (uc *TimeTrackingUseCaseImpl) UpdateWorklog(ctx context.Context, taskID int, worklogID int, update *dto.WorklogUpdate) (*dto.Worklog, error) {
+	worklog, err := uc.getOwnWorklog(ctx, taskID, worklogID)
+	if err != nil {
+		return nil, err
+	}
+	if worklog.EndedAt == nil {
+		return nil, fmt.Errorf("%w: stop the timer before editing it", domain_error.ErrConflict)
+	}
+
+	duration := worklog.EndedAt.Sub(worklog.StartedAt)
+	if update.Duration != nil {
+		duration = *update.Duration
+	}
+	if update.StartedAt != nil {
+		worklog.StartedAt = *update.StartedAt
+	}
+	if update.Note != nil {
+		worklog.Note = strings.TrimSpace(*update.Note)
+	}
+	if err := validateDuration(duration); err != nil {
+		return nil, err
+	}
+	endedAt := worklog.StartedAt.Add(duration)
+	worklog.EndedAt = &endedAt
+
+	updatedWorklog, err := uc.repo.UpdateWorklog(ctx, worklog)
+	if err != nil {
+		return nil, err
+	}
+	return toWorklogDTO(updatedWorklog), nil
+}
+
+func (uc *TimeTrackingUseCaseImpl) DeleteWorklog(ctx context.Context, taskID int, worklogID int) error {
+	if _, err := uc.getOwnWorklog(ctx, taskID, worklogID); err != nil {
+		return err
+	}
+	return uc.repo.DeleteWorklog(ctx, worklogID)
+}
+
+func (uc *TimeTrackingUseCaseImpl) GetTimeTotals(ctx context.Context, filter *dto.TimeTotalsFilter) (*dto.TimeTotals, error) {
+	if err := uc.requireTotalsAccess(ctx, filter); err != nil {
+		return nil, err
+	}
+
+	rows, err := uc.repo.GetTimeTotals(ctx, &entity.TimeTotalsFilter{
+		ProjectID: filter.ProjectID,
+		TaskID:    filter.TaskID,
+		UserID:    filter.UserID,
+		From:      filter.From,
+		To:        filter.To,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toTimeTotalsDTO(filter, rows), nil
+}
+
+func (uc *TimeTrackingUseCaseImpl) GetTaskTimeSpent(ctx context.Context, taskID int) (int64, error) {
+	rows, err := uc.repo.GetTimeTotals(ctx, &entity.TimeTotalsFilter{TaskID: &taskID})
+	if err != nil {
+		return 0, err
+	}
+
+	var total int64
+	for _, row := range rows {
+		total += row.Seconds
+	}
+	return total, nil
+}
+
+func (uc *TimeTrackingUseCaseImpl) StopTimersOnTask(ctx context.Context, taskID int) error {
+	return uc.repo.StopTimersOnTask(ctx, taskID)
+}
+
+// requireTotalsAccess lets users see totals of tasks and projects they can read,
+// and the totals of their own time across all projects.
+func (uc *TimeTrackingUseCaseImpl) requireTotalsAccess(ctx context.Context, filter *dto.TimeTotalsFilter) error {
+	switch {
+	case filter.TaskID != nil:
+		_, err := uc.access.RequireTaskPermission(ctx, *filter.TaskID, entity.PermissionRead)
+		return err
+	case filter.ProjectID != nil:
+		return uc.access.RequireProjectPermission(ctx, *filter.ProjectID, entity.PermissionRead)
+	case filter.UserID != nil:
+		userID, err := uc.access.CurrentUserID(ctx)
+		if err != nil {
+			return err
+		}
+		if userID != *filter.UserID {
+			return fmt.Errorf("%w: totals of other users need a project or task", domain_error.ErrForbidden)
+		}
+		return nil
+	}
+	return fmt.Errorf("%w: one of project_id, task_id or user_id is required", domain_error.ErrValidation)
+}
+
+// getOwnWorklog loads a worklog of the task for editing. Users edit their own entries,
+// project owners can fix everybody's.
+func (uc *TimeTrackingUseCaseImpl) getOwnWorklog(ctx context.Context, taskID int, worklogID int) (*entity.Worklog, error) {
+	userID, err := uc.access.CurrentUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	worklog, err := uc.repo.GetWorklogByID(ctx, worklogID)
+	if err != nil {
+		return nil, err
+	}
+	if worklog.TaskID != taskID {
+		return nil, fmt.Errorf("%w: worklog %d of task %d", domain_error.ErrNotFound, worklogID, taskID)
+	}
+
+	required := entity.PermissionEdit
+	if worklog.UserID != userID {
+		required = entity.PermissionOwner
+	}
+	if _, err := uc.access.RequireTaskPermission(ctx, taskID, required); err != nil {
+		return nil, err
+	}
+	return worklog, nil
+}
+
+func validateDuration(d time.Duration) error {
+	if d < time.Minute || d > maxWorklogDuration {
+		return fmt.Errorf("%w: duration must be between 1 minute and %s", domain_error.ErrValidation, maxWorklogDuration)
+	}
+	return nil
+}
+
+func toWorklogDTO(w *entity.Worklog) *dto.Worklog {
+	endedAt := time.Now()
+	if w.EndedAt != nil {
+		endedAt = *w.EndedAt
+	}
+
+	return &dto.Worklog{
+		ID:              w.ID,
+		TaskID:          w.TaskID,
+		UserID:          w.UserID,
+		StartedAt:       w.StartedAt,
+		EndedAt:         w.EndedAt,
+		DurationSeconds: int64(endedAt.Sub(w.StartedAt).Seconds()),
+		Note:            w.Note,
+		Source:          w.Source,
+		CreatedAt:       w.CreatedAt,
+		UpdatedAt:       w.UpdatedAt,
+	}
+}
+
+func toTimeTotalsDTO(filter *dto.TimeTotalsFilter, rows []*entity.TimeTotalRow) *dto.TimeTotals {
+	totals := &dto.TimeTotals{
+		From:   filter.From,
+		To:     filter.To,
+		ByTask: []*dto.TaskTimeTotal{},
+		ByUser: []*dto.UserTimeTotal{},
+	}
+
+	byTask := make(map[int]*dto.TaskTimeTotal)
+	byUser := make(map[int]*dto.UserTimeTotal)
+	for _, row := range rows {
+		totals.TotalSeconds += row.Seconds
+
+		if byTask[row.TaskID] == nil {
+			byTask[row.TaskID] = &dto.TaskTimeTotal{TaskID: row.TaskID, TaskTitle: row.TaskTitle}
+			totals.ByTask = append(totals.ByTask, byTask[row.TaskID])
+		}
+		byTask[row.TaskID].Seconds += row.Seconds
+
+		if byUser[row.UserID] == nil {
+			byUser[row.UserID] = &dto.UserTimeTotal{UserID: row.UserID}
+			totals.ByUser = append(totals.ByUser, byUser[row.UserID])
+		}
+		byUser[row.UserID].Seconds += row.Seconds
+	}
+
+	sort.Slice(totals.ByUser, func(i, j int) bool { return totals.ByUser[i].UserID < totals.ByUser[j].UserID })
+	return totals
+}
@@ -0,0 +1,20 @@
+package trash_usecase
+
+import (
+	"DataTask/internal/domain/dto"
+	"context"
+)
+
+type TrashUseCase interface {
+	// GetProjectTrash lists the trashed subprojects, boards and tasks of a project.
+	GetProjectTrash(ctx context.Context, projectID int) ([]*dto.TrashItem, error)
+	// GetTrashedProjects lists the trashed projects owned by the current user.
+	GetTrashedProjects(ctx context.Context) ([]*dto.TrashItem, error)
+
+	RestoreProject(ctx context.Context, id int) error
+	RestoreKanban(ctx context.Context, id int) error
+	RestoreTask(ctx context.Context, id int) error
+
+	// PurgeExpired deletes items that have been in the trash longer than the retention period.
+	PurgeExpired(ctx context.Context) error
+}
@@ -0,0 +1,153 @@
+package trash_usecase
+
+import (
+	"DataTask/internal/domain/domain_error"
+	"DataTask/internal/domain/dto"
+	"DataTask/internal/domain/entity"
+	"DataTask/internal/repository/database"
+	"DataTask/internal/repository/trash_repository"
+	"DataTask/internal/usecase/access_usecase"
+	"DataTask/pkg/logger"
+	"context"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"time"
+)
+
+type TrashUseCaseImpl struct {
+	repo       trash_repository.TrashRepository
+	access     access_usecase.AccessUseCase
+	transactor database.Transactor
+	retention  time.Duration // Zero keeps trashed items forever
+}
+
+func NewTrashUseCase(
+	repo trash_repository.TrashRepository,
+	access access_usecase.AccessUseCase,
+	transactor database.Transactor,
+	retention time.Duration,
+) *TrashUseCaseImpl {
+	return &TrashUseCaseImpl{
+		repo:       repo,
+		access:     access,
+		transactor: transactor,
+		retention:  retention,
+	}
+}
+
+func (uc *TrashUseCaseImpl) GetProjectTrash(ctx context.Context, projectID int) ([]*dto.TrashItem, error) {
+	if err := uc.access.RequireProjectPermission(ctx, projectID, entity.PermissionRead); err != nil {
+		return nil, err
+	}
+
+	items, err := uc.repo.GetProjectTrash(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	return uc.toTrashItemDTOs(items), nil
+}
+
+func (uc *TrashUseCaseImpl) GetTrashedProjects(ctx context.Context) ([]*dto.TrashItem, error) {
+	userID, err := uc.access.CurrentUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := uc.repo.GetTrashedProjectsByOwnerID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return uc.toTrashItemDTOs(items), nil
+}
+
+func (uc *TrashUseCaseImpl) RestoreProject(ctx context.Context, id int) error {
+	return uc.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		item, err := uc.repo.GetTrashedProject(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := uc.access.RequireProjectPermission(ctx, item.ProjectID, entity.PermissionOwner); err != nil {
+			return err
+		}
+		if item.ContainerTrashed {
+			return fmt.Errorf("%w: parent project of project %d is in the trash, restore it first",
+				domain_error.ErrConflict, id)
+		}
+		return uc.repo.RestoreProject(ctx, id)
+	})
+}
+
+func (uc *TrashUseCaseImpl) RestoreKanban(ctx context.Context, id int) error {
+	return uc.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		item, err := uc.repo.GetTrashedKanban(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := uc.access.RequireProjectPermission(ctx, item.ProjectID, entity.PermissionEdit); err != nil {
+			return err
+		}
+		if item.ContainerTrashed {
+			return fmt.Errorf("%w: project of kanban %d is in the trash, restore it first", domain_error.ErrConflict, id)
+		}
+		return uc.repo.RestoreKanban(ctx, id)
+	})
+}
+
+func (uc *TrashUseCaseImpl) RestoreTask(ctx context.Context, id int) error {
+	return uc.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		item, err := uc.repo.GetTrashedTask(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := uc.access.RequireProjectPermission(ctx, item.ProjectID, entity.PermissionEdit); err != nil {
+			return err
+		}
+		if item.ContainerTrashed {
+			return fmt.Errorf("%w: kanban or parent task of task %d is in the trash, restore it first",
+				domain_error.ErrConflict, id)
+		}
+		return uc.repo.RestoreTask(ctx, id)
+	})
+}
+
+func (uc *TrashUseCaseImpl) PurgeExpired(ctx context.Context) error {
+	if uc.retention <= 0 {
+		return nil
+	}
+
+	var purged int64
+	err := uc.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		purged, err = uc.repo.PurgeOlderThan(ctx, uc.retention)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if purged > 0 {
+		logger.Log.WithFields(log.Fields{"rows": purged}).Info("purged expired trash")
+	}
+	return nil
+}
+
+func (uc *TrashUseCaseImpl) toTrashItemDTOs(items []*entity.TrashItem) []*dto.TrashItem {
+	result := make([]*dto.TrashItem, 0, len(items))
+	for _, item := range items {
+		dtoItem := &dto.TrashItem{
+			Type:       item.Type,
+			ID:         item.ID,
+			Name:       item.Name,
+			ProjectID:  item.ProjectID,
+			ParentID:   item.ParentID,
+			DeletedAt:  item.DeletedAt,
+			Restorable: !item.ContainerTrashed,
+		}
+		if uc.retention > 0 {
+			purgeAt := item.DeletedAt.Add(uc.retention)
+			dtoItem.PurgeAt = &purgeAt
+		}
+		result = append(result, dtoItem)
+	}
+	return result
+}
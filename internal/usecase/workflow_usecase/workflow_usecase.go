@@ -0,0 +1,25 @@
+package workflow_usecase
+
+import (
+	"DataTask/internal/domain/dto"
+	"context"
+)
+
+// WorkflowUseCase manages the statuses of a project's tasks, the transitions allowed between
+// them and the statuses kanbans are mapped to. A project always keeps at least one done status
+// and one status that is not done, so tasks can still be completed and reopened.
+type WorkflowUseCase interface {
+	GetWorkflow(ctx context.Context, projectID int) (*dto.Workflow, error)
+
+	CreateStatus(ctx context.Context, status *dto.WorkflowStatus) (*dto.WorkflowStatus, error)
+	UpdateStatus(ctx context.Context, projectID int, statusID int, update *dto.WorkflowStatusUpdate) (*dto.WorkflowStatus, error)
+	// DeleteStatus deletes a status, moving its tasks and kanbans to the replacement status.
+	DeleteStatus(ctx context.Context, projectID int, statusID int, replacementID *int) error
+	// ReorderStatuses takes the IDs of all statuses of the project in the new order.
+	ReorderStatuses(ctx context.Context, projectID int, statusIDs []int) (*dto.Workflow, error)
+	// SetTransitions replaces the allowed transitions; none allows every change.
+	SetTransitions(ctx context.Context, projectID int, transitions []*dto.WorkflowTransition) (*dto.Workflow, error)
+
+	// SetColumnStatus maps a kanban to the status its tasks take when moved onto it; nil unmaps it.
+	SetColumnStatus(ctx context.Context, kanbanID int, statusID *int) (*dto.WorkflowColumn, error)
+}
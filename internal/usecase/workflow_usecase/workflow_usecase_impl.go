@@ -0,0 +1,335 @@
+package workflow_usecase
+
+import (
+	"DataTask/internal/domain/domain_error"
+	"DataTask/internal/domain/dto"
+	"DataTask/internal/domain/entity"
+	"DataTask/internal/repository/database"
+	"DataTask/internal/repository/project_repository"
+	"DataTask/internal/repository/workflow_repository"
+	"DataTask/internal/usecase/access_usecase"
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+const maxStatusNameLength = 255
+
+type WorkflowUseCaseImpl struct {
+	repo        workflow_repository.WorkflowRepository
+	projectRepo project_repository.ProjectRepository
+	access      access_usecase.AccessUseCase
+	transactor  database.Transactor
+}
+
+func NewWorkflowUseCase(
+	repo workflow_repository.WorkflowRepository,
+	projectRepo project_repository.ProjectRepository,
+	access access_usecase.AccessUseCase,
+	transactor database.Transactor,
+) *WorkflowUseCaseImpl {
+	return &WorkflowUseCaseImpl{
+		repo:        repo,
+		projectRepo: projectRepo,
+		access:      access,
+		transactor:  transactor,
+	}
+}
+
+func (uc *WorkflowUseCaseImpl) GetWorkflow(ctx context.Context, projectID int) (*dto.Workflow, error) {
+	if err := uc.access.RequireProjectPermission(ctx, projectID, entity.PermissionRead); err != nil {
+		return nil, err
+	}
+	return uc.getWorkflow(ctx, projectID)
+}
+
+func (uc *WorkflowUseCaseImpl) CreateStatus(ctx context.Context, status *dto.WorkflowStatus) (*dto.WorkflowStatus, error) {
+	if err := uc.access.RequireProjectPermission(ctx, status.ProjectID, entity.PermissionEdit); err != nil {
+		return nil, err
+	}
+
+	entityStatus := &entity.WorkflowStatus{
+		ProjectID: status.ProjectID,
+		Name:      strings.TrimSpace(status.Name),
+		Category:  status.Category,
+	}
+	if err := validateStatus(entityStatus); err != nil {
+		return nil, err
+	}
+
+	created, err := uc.repo.CreateStatus(ctx, entityStatus)
+	if err != nil {
+		return nil, err
+	}
+	return toStatusDTO(created), nil
+}
+
+func (uc *WorkflowUseCaseImpl) UpdateStatus(
+	ctx context.Context,
+	projectID int,
+	statusID int,
+	update *dto.WorkflowStatusUpdate,
+) (*dto.WorkflowStatus, error) {
+	if err := uc.access.RequireProjectPermission(ctx, projectID, entity.PermissionEdit); err != nil {
+		return nil, err
+	}
+
+	var updated *entity.WorkflowStatus
+	err := uc.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		workflow, err := uc.repo.GetWorkflow(ctx, projectID)
+		if err != nil {
+			return err
+		}
+		status := workflow.Status(statusID)
+		if status == nil {
+			return fmt.Errorf("%w: status %d in project %d", domain_error.ErrNotFound, statusID, projectID)
+		}
+
+		if update.Name != nil {
+			status.Name = strings.TrimSpace(*update.Name)
+		}
+		if update.Category != nil {
+			status.Category = *update.Category
+		}
+		if err := validateStatus(status); err != nil {
+			return err
+		}
+		if err := validateCategories(workflow.Statuses); err != nil {
+			return err
+		}
+
+		updated, err = uc.repo.UpdateStatus(ctx, status)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toStatusDTO(updated), nil
+}
+
+func (uc *WorkflowUseCaseImpl) DeleteStatus(ctx context.Context, projectID int, statusID int, replacementID *int) error {
+	if err := uc.access.RequireProjectPermission(ctx, projectID, entity.PermissionEdit); err != nil {
+		return err
+	}
+
+	return uc.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		workflow, err := uc.repo.GetWorkflow(ctx, projectID)
+		if err != nil {
+			return err
+		}
+		if workflow.Status(statusID) == nil {
+			return fmt.Errorf("%w: status %d in project %d", domain_error.ErrNotFound, statusID, projectID)
+		}
+		if replacementID != nil {
+			if *replacementID == statusID || workflow.Status(*replacementID) == nil {
+				return fmt.Errorf("%w: replacement status %d is not another status of the project",
+					domain_error.ErrValidation, *replacementID)
+			}
+		}
+
+		remaining := make([]*entity.WorkflowStatus, 0, len(workflow.Statuses))
+		for _, s := range workflow.Statuses {
+			if s.ID != statusID {
+				remaining = append(remaining, s)
+			}
+		}
+		if err := validateCategories(remaining); err != nil {
+			return err
+		}
+
+		return uc.repo.DeleteStatus(ctx, statusID, replacementID)
+	})
+}
+
+func (uc *WorkflowUseCaseImpl) ReorderStatuses(ctx context.Context, projectID int, statusIDs []int) (*dto.Workflow, error) {
+	if err := uc.access.RequireProjectPermission(ctx, projectID, entity.PermissionEdit); err != nil {
+		return nil, err
+	}
+
+	err := uc.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		workflow, err := uc.repo.GetWorkflow(ctx, projectID)
+		if err != nil {
+			return err
+		}
+		if err := sameStatusSet(workflow.Statuses, statusIDs); err != nil {
+			return err
+		}
+		return uc.repo.SetStatusPositions(ctx, projectID, statusIDs)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return uc.getWorkflow(ctx, projectID)
+}
+
+func (uc *WorkflowUseCaseImpl) SetTransitions(
+	ctx context.Context,
+	projectID int,
+	transitions []*dto.WorkflowTransition,
+) (*dto.Workflow, error) {
+	if err := uc.access.RequireProjectPermission(ctx, projectID, entity.PermissionEdit); err != nil {
+		return nil, err
+	}
+
+	err := uc.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		workflow, err := uc.repo.GetWorkflow(ctx, projectID)
+		if err != nil {
+			return err
+		}
+
+		entityTransitions := make([]*entity.WorkflowTransition, 0, len(transitions))
+		for _, t := range transitions {
+			if workflow.Status(t.FromStatusID) == nil || workflow.Status(t.ToStatusID) == nil {
+				return fmt.Errorf("%w: transition from %d to %d uses a status of another project",
+					domain_error.ErrValidation, t.FromStatusID, t.ToStatusID)
+			}
+			if t.FromStatusID == t.ToStatusID {
+				return fmt.Errorf("%w: transition from status %d to itself", domain_error.ErrValidation, t.FromStatusID)
+			}
+			entityTransitions = append(entityTransitions, &entity.WorkflowTransition{
+				FromStatusID: t.FromStatusID,
+				ToStatusID:   t.ToStatusID,
+			})
+		}
+		return uc.repo.SetTransitions(ctx, projectID, entityTransitions)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return uc.getWorkflow(ctx, projectID)
+}
+
+func (uc *WorkflowUseCaseImpl) SetColumnStatus(ctx context.Context, kanbanID int, statusID *int) (*dto.WorkflowColumn, error) {
+	if err := uc.access.RequireKanbanPermission(ctx, kanbanID, entity.PermissionEdit); err != nil {
+		return nil, err
+	}
+	projectID, err := uc.projectRepo.GetProjectIDByKanbanID(ctx, kanbanID)
+	if err != nil {
+		return nil, err
+	}
+
+	if statusID != nil {
+		status, err := uc.repo.GetStatusByID(ctx, *statusID)
+		if err != nil {
+			return nil, err
+		}
+		if status.ProjectID != projectID {
+			return nil, fmt.Errorf("%w: status %d belongs to another project", domain_error.ErrValidation, *statusID)
+		}
+	}
+
+	if err := uc.repo.SetKanbanStatus(ctx, kanbanID, statusID); err != nil {
+		return nil, err
+	}
+
+	columns, err := uc.repo.GetColumns(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range columns {
+		if c.KanbanID == kanbanID {
+			return toColumnDTO(c), nil
+		}
+	}
+	return nil, fmt.Errorf("%w: kanban %d", domain_error.ErrNotFound, kanbanID)
+}
+
+func (uc *WorkflowUseCaseImpl) getWorkflow(ctx context.Context, projectID int) (*dto.Workflow, error) {
+	workflow, err := uc.repo.GetWorkflow(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	columns, err := uc.repo.GetColumns(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dto.Workflow{
+		ProjectID:   projectID,
+		Statuses:    make([]*dto.WorkflowStatus, 0, len(workflow.Statuses)),
+		Transitions: make([]*dto.WorkflowTransition, 0, len(workflow.Transitions)),
+		Columns:     make([]*dto.WorkflowColumn, 0, len(columns)),
+	}
+	for _, s := range workflow.Statuses {
+		result.Statuses = append(result.Statuses, toStatusDTO(s))
+	}
+	for _, t := range workflow.Transitions {
+		result.Transitions = append(result.Transitions, &dto.WorkflowTransition{
+			FromStatusID: t.FromStatusID,
+			ToStatusID:   t.ToStatusID,
+		})
+	}
+	for _, c := range columns {
+		result.Columns = append(result.Columns, toColumnDTO(c))
+	}
+	return result, nil
+}
+
+func validateStatus(status *entity.WorkflowStatus) error {
+	if status.Name == "" {
+		return fmt.Errorf("%w: name is required", domain_error.ErrValidation)
+	}
+	if utf8.RuneCountInString(status.Name) > maxStatusNameLength {
+		return fmt.Errorf("%w: name must be at most %d characters", domain_error.ErrValidation, maxStatusNameLength)
+	}
+	if !entity.IsValidStatusCategory(status.Category) {
+		return fmt.Errorf("%w: unknown status category %q", domain_error.ErrValidation, status.Category)
+	}
+	return nil
+}
+
+// validateCategories makes sure tasks can still be completed and reopened with the statuses.
+func validateCategories(statuses []*entity.WorkflowStatus) error {
+	done, open := false, false
+	for _, s := range statuses {
+		if s.Category == entity.StatusCategoryDone {
+			done = true
+		} else {
+			open = true
+		}
+	}
+	if !done || !open {
+		return fmt.Errorf("%w: a project needs at least one done status and one status that is not done",
+			domain_error.ErrConflict)
+	}
+	return nil
+}
+
+// sameStatusSet checks that statusIDs lists every status exactly once.
+func sameStatusSet(statuses []*entity.WorkflowStatus, statusIDs []int) error {
+	if len(statusIDs) != len(statuses) {
+		return fmt.Errorf("%w: status_ids must list all %d statuses of the project", domain_error.ErrValidation, len(statuses))
+	}
+	remaining := make(map[int]bool, len(statuses))
+	for _, s := range statuses {
+		remaining[s.ID] = true
+	}
+	for _, id := range statusIDs {
+		if !remaining[id] {
+			return fmt.Errorf("%w: status %d is unknown or listed twice", domain_error.ErrValidation, id)
+		}
+		delete(remaining, id)
+	}
+	return nil
+}
+
+func toStatusDTO(s *entity.WorkflowStatus) *dto.WorkflowStatus {
+	return &dto.WorkflowStatus{
+		ID:        s.ID,
+		ProjectID: s.ProjectID,
+		Name:      s.Name,
+		Category:  s.Category,
+		Position:  s.Position,
+		CreatedAt: s.CreatedAt,
+		UpdatedAt: s.UpdatedAt,
+	}
+}
+
+func toColumnDTO(c *entity.WorkflowColumn) *dto.WorkflowColumn {
+	return &dto.WorkflowColumn{
+		KanbanID: c.KanbanID,
+		Name:     c.Name,
+		StatusID: c.StatusID,
+	}
+}
@@ -0,0 +1,56 @@
+package worker
+
+import (
+	"DataTask/pkg/logger"
+	"context"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// PeriodicWorker runs a job at a fixed interval until its context is cancelled.
+type PeriodicWorker struct {
+	name     string
+	interval time.Duration
+	job      func(ctx context.Context) error
+}
+
+func NewPeriodicWorker(name string, interval time.Duration, job func(ctx context.Context) error) *PeriodicWorker {
+	return &PeriodicWorker{
+		name:     name,
+		interval: interval,
+		job:      job,
+	}
+}
+
+func (w *PeriodicWorker) Name() string {
+	return w.name
+}
+
+// Start blocks, running the job once immediately and then on every tick.
+// Job errors are logged and do not stop the worker.
+func (w *PeriodicWorker) Start(ctx context.Context) {
+	logger.Log.WithFields(log.Fields{
+		"worker":   w.name,
+		"interval": w.interval.String(),
+	}).Info("starting worker")
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		w.runOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *PeriodicWorker) runOnce(ctx context.Context) {
+	if err := w.job(ctx); err != nil {
+		logger.Log.WithField("worker", w.name).Error(err)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	log "github.com/sirupsen/logrus"
 	"os"
+	_ "time/tzdata" // task time zones must resolve in minimal containers
 )
 
 // @title           DataTask
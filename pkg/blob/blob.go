@@ -0,0 +1,32 @@
+// Package blob stores opaque binary objects under string keys. The local backend keeps them
+// on the filesystem, the S3 backend talks to any S3-compatible object storage.
+package blob
+
+import (
+	"context"
+	"io"
+	"strings"
+)
+
+type Storage interface {
+	// Put stores size bytes read from r under key, replacing an existing object.
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Get opens the object stored under key. It returns ErrNotFound for unknown keys.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the object. Deleting a missing object is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// validateKey accepts slash separated keys without empty, "." or ".." segments,
+// so keys can never point outside of the storage root.
+func validateKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return ErrInvalidKey
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return ErrInvalidKey
+		}
+	}
+	return nil
+}
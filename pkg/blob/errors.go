@@ -0,0 +1,8 @@
+package blob
+
+import "errors"
+
+var (
+	ErrNotFound   = errors.New("blob not found")
+	ErrInvalidKey = errors.New("invalid blob key")
+)
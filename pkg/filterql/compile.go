@@ -0,0 +1,272 @@
+package filterql
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Type int
+
+const (
+	String Type = iota
+	Int
+	Number
+	Bool
+	Time
+	Enum
+)
+
+// Field describes how a filterable field maps to SQL. Only fields of the schema can be used,
+// so filter input never ends up in the SQL text itself.
+type Field struct {
+	Type Type
+	// Column is the SQL expression compared with the value.
+	Column string
+	// Member turns the field into a set, e.g. the assignees of a task. It is an SQL condition
+	// with %s standing for the comparison of the member column:
+	// "EXISTS (SELECT 1 FROM task_users tu WHERE tu.task_id = t.id AND tu.user_id %s)".
+	// Sets support ":" and "!:", and none matches empty sets.
+	Member   string
+	Nullable bool     // null matches missing values
+	Values   []string // Allowed values of Enum fields
+	Me       bool     // me stands for the current user in Int fields
+}
+
+// Schema maps field names, including aliases, to fields.
+type Schema map[string]*Field
+
+type Options struct {
+	UserID int // Current user, 0 when nobody is signed in
+}
+
+// Compile renders the expression as an SQL condition. Values become arguments appended to
+// args, so placeholders continue the caller's numbering.
+func Compile(expr Expr, schema Schema, opts Options, args []any) (string, []any, error) {
+	c := &compiler{schema: schema, opts: opts, args: args}
+	sql, err := c.compile(expr)
+	if err != nil {
+		return "", nil, err
+	}
+	return sql, c.args, nil
+}
+
+type compiler struct {
+	schema Schema
+	opts   Options
+	args   []any
+}
+
+func (c *compiler) placeholder(v any) string {
+	c.args = append(c.args, v)
+	return fmt.Sprintf("$%d", len(c.args))
+}
+
+func (c *compiler) compile(expr Expr) (string, error) {
+	switch e := expr.(type) {
+	case *And:
+		return c.binary(e.Left, e.Right, "AND")
+	case *Or:
+		return c.binary(e.Left, e.Right, "OR")
+	case *Not:
+		inner, err := c.compile(e.Expr)
+		if err != nil {
+			return "", err
+		}
+		return negate(inner), nil
+	case *Term:
+		return c.term(e)
+	}
+	return "", fmt.Errorf("%w: unexpected expression %T", ErrSyntax, expr)
+}
+
+func (c *compiler) binary(left, right Expr, op string) (string, error) {
+	l, err := c.compile(left)
+	if err != nil {
+		return "", err
+	}
+	r, err := c.compile(right)
+	if err != nil {
+		return "", err
+	}
+	return "(" + l + " " + op + " " + r + ")", nil
+}
+
+// negate inverts a condition, treating NULL as false so rows without a value match the negation.
+func negate(cond string) string {
+	return "NOT COALESCE(" + cond + ", FALSE)"
+}
+
+func (c *compiler) term(t *Term) (string, error) {
+	f, ok := c.schema[t.Field]
+	if !ok {
+		return "", fmt.Errorf("%w: %q", ErrUnknownField, t.Field)
+	}
+	if !operatorAllowed(f, t.Op) {
+		return "", fmt.Errorf("%w: %s cannot be used with %s", ErrInvalidOperator, t.Op, t.Field)
+	}
+	if len(t.Values) > 1 && t.Op != OpEqual && t.Op != OpNotEqual {
+		return "", fmt.Errorf("%w: %s takes a single value", ErrInvalidOperator, t.Op)
+	}
+
+	cond, err := c.positive(t, f)
+	if err != nil {
+		return "", err
+	}
+	if t.Op == OpNotEqual {
+		return negate(cond), nil
+	}
+	return cond, nil
+}
+
+func operatorAllowed(f *Field, op Op) bool {
+	if f.Member != "" {
+		return op == OpEqual || op == OpNotEqual
+	}
+	switch f.Type {
+	case String:
+		return op == OpEqual || op == OpNotEqual || op == OpContains
+	case Bool, Enum:
+		return op == OpEqual || op == OpNotEqual
+	}
+	return op != OpContains
+}
+
+// positive renders the term with "!:" read as ":"; the caller negates it.
+func (c *compiler) positive(t *Term, f *Field) (string, error) {
+	if isKeyword(t.Values, "none") && f.Member != "" {
+		return "NOT " + fmt.Sprintf(f.Member, "IS NOT NULL"), nil
+	}
+	if isKeyword(t.Values, "null") {
+		if !f.Nullable || (t.Op != OpEqual && t.Op != OpNotEqual) {
+			return "", fmt.Errorf("%w: %s cannot be null", ErrInvalidValue, t.Field)
+		}
+		return f.Column + " IS NULL", nil
+	}
+
+	if f.Type == Time {
+		return c.timeTerm(t, f)
+	}
+
+	values := make([]any, 0, len(t.Values))
+	for _, v := range t.Values {
+		value, err := c.convert(t.Field, f, v)
+		if err != nil {
+			return "", err
+		}
+		values = append(values, value)
+	}
+
+	if t.Op == OpContains {
+		return fmt.Sprintf("%s ILIKE %s", f.Column, c.placeholder("%"+escapeLike(values[0].(string))+"%")), nil
+	}
+	if t.Op != OpEqual && t.Op != OpNotEqual {
+		return fmt.Sprintf("%s %s %s", f.Column, t.Op, c.placeholder(values[0])), nil
+	}
+
+	placeholders := make([]string, 0, len(values))
+	for _, v := range values {
+		if f.Type == String {
+			placeholders = append(placeholders, "lower("+c.placeholder(v)+")")
+		} else {
+			placeholders = append(placeholders, c.placeholder(v))
+		}
+	}
+	in := "IN (" + strings.Join(placeholders, ", ") + ")"
+
+	switch {
+	case f.Member != "":
+		return fmt.Sprintf(f.Member, in), nil
+	case f.Type == String:
+		return "lower(" + f.Column + ") " + in, nil
+	}
+	return f.Column + " " + in, nil
+}
+
+// timeTerm compares with a date or an RFC 3339 timestamp. A date covers the whole day, so
+// updated>2025-05-01 starts on May 2nd and due:2025-05-01 matches any time that day.
+func (c *compiler) timeTerm(t *Term, f *Field) (string, error) {
+	var conds []string
+	for _, v := range t.Values {
+		if ts, err := time.Parse(time.RFC3339, v.Text); err == nil {
+			op := t.Op
+			if op == OpEqual || op == OpNotEqual {
+				op = "="
+			}
+			conds = append(conds, fmt.Sprintf("%s %s %s", f.Column, op, c.placeholder(ts)))
+			continue
+		}
+
+		day, err := time.Parse(time.DateOnly, v.Text)
+		if err != nil {
+			return "", fmt.Errorf("%w: %s takes a date like 2025-05-01 or an RFC 3339 time, got %q",
+				ErrInvalidValue, t.Field, v.Text)
+		}
+		nextDay := day.AddDate(0, 0, 1)
+		switch t.Op {
+		case OpGreater:
+			conds = append(conds, fmt.Sprintf("%s >= %s", f.Column, c.placeholder(nextDay)))
+		case OpGreaterEqual:
+			conds = append(conds, fmt.Sprintf("%s >= %s", f.Column, c.placeholder(day)))
+		case OpLess:
+			conds = append(conds, fmt.Sprintf("%s < %s", f.Column, c.placeholder(day)))
+		case OpLessEqual:
+			conds = append(conds, fmt.Sprintf("%s < %s", f.Column, c.placeholder(nextDay)))
+		default:
+			conds = append(conds, fmt.Sprintf("(%s >= %s AND %s < %s)",
+				f.Column, c.placeholder(day), f.Column, c.placeholder(nextDay)))
+		}
+	}
+	if len(conds) == 1 {
+		return conds[0], nil
+	}
+	return "(" + strings.Join(conds, " OR ") + ")", nil
+}
+
+func (c *compiler) convert(field string, f *Field, v Value) (any, error) {
+	switch f.Type {
+	case Int:
+		if f.Me && !v.Quoted && strings.EqualFold(v.Text, "me") {
+			if c.opts.UserID == 0 {
+				return nil, ErrNoCurrentUser
+			}
+			return c.opts.UserID, nil
+		}
+		n, err := strconv.Atoi(v.Text)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s takes whole numbers, got %q", ErrInvalidValue, field, v.Text)
+		}
+		return n, nil
+	case Number:
+		n, err := strconv.ParseFloat(v.Text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s takes numbers, got %q", ErrInvalidValue, field, v.Text)
+		}
+		return n, nil
+	case Bool:
+		b, err := strconv.ParseBool(v.Text)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s takes true or false, got %q", ErrInvalidValue, field, v.Text)
+		}
+		return b, nil
+	case Enum:
+		value := strings.ToLower(v.Text)
+		if !slices.Contains(f.Values, value) {
+			return nil, fmt.Errorf("%w: %s takes one of %s, got %q",
+				ErrInvalidValue, field, strings.Join(f.Values, ", "), v.Text)
+		}
+		return value, nil
+	}
+	return v.Text, nil
+}
+
+// isKeyword reports whether the values are the single unquoted keyword.
+func isKeyword(values []Value, keyword string) bool {
+	return len(values) == 1 && !values[0].Quoted && strings.EqualFold(values[0].Text, keyword)
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,11 @@
+package filterql
+
+import "errors"
+
+var (
+	ErrSyntax          = errors.New("invalid filter")
+	ErrUnknownField    = errors.New("unknown filter field")
+	ErrInvalidValue    = errors.New("invalid filter value")
+	ErrNoCurrentUser   = errors.New("filter value me needs a signed in user")
+	ErrInvalidOperator = errors.New("invalid filter operator")
+)
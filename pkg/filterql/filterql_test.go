@@ -0,0 +1,155 @@
+package filterql
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var testSchema = Schema{
+	"title":        {Type: String, Column: "t.title"},
+	"is_completed": {Type: Bool, Column: "t.is_completed"},
+	"priority":     {Type: Enum, Column: "t.priority", Values: []string{"low", "high"}},
+	"estimate":     {Type: Number, Column: "t.estimate", Nullable: true},
+	"updated":      {Type: Time, Column: "t.updated_at"},
+	"assignee": {
+		Type:   Int,
+		Member: "EXISTS (SELECT 1 FROM task_users tu WHERE tu.task_id = t.id AND tu.user_id %s)",
+		Me:     true,
+	},
+}
+
+func compile(t *testing.T, filter string, opts Options) (string, []any) {
+	t.Helper()
+
+	expr, err := Parse(filter)
+	if err != nil {
+		t.Fatalf("Parse(%q) failed: %v", filter, err)
+	}
+	sql, args, err := Compile(expr, testSchema, opts, []any{"kanban"})
+	if err != nil {
+		t.Fatalf("Compile(%q) failed: %v", filter, err)
+	}
+	return sql, args
+}
+
+func TestParsePrecedence(t *testing.T) {
+	expr, err := Parse(`title:a b:c OR NOT (d>1 or e:"x y")`)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	want := `((title:a AND b:c) OR NOT (d>1 OR e:"x y"))`
+	if got := expr.String(); got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
+
+func TestParseValueLists(t *testing.T) {
+	expr, err := Parse(`priority!:low,"a \"b\""`)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	term := expr.(*Term)
+	want := []Value{{Text: "low"}, {Text: `a "b"`, Quoted: true}}
+	if term.Op != OpNotEqual || !reflect.DeepEqual(term.Values, want) {
+		t.Fatalf("got %s %+v", term.Op, term.Values)
+	}
+}
+
+func TestParseEmpty(t *testing.T) {
+	expr, err := Parse("   ")
+	if err != nil || expr != nil {
+		t.Fatalf("got %v, %v; want nil, nil", expr, err)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, filter := range []string{
+		"title",
+		"title:",
+		":a",
+		"(title:a",
+		"title:a)",
+		`title:"open`,
+		"title:a AND",
+		"title=a",
+	} {
+		if _, err := Parse(filter); !errors.Is(err, ErrSyntax) {
+			t.Errorf("Parse(%q) returned %v, want ErrSyntax", filter, err)
+		}
+	}
+}
+
+func TestCompileExample(t *testing.T) {
+	sql, args := compile(t, "assignee:me AND is_completed:false AND updated>2025-05-01", Options{UserID: 7})
+
+	wantSQL := "((EXISTS (SELECT 1 FROM task_users tu WHERE tu.task_id = t.id AND tu.user_id IN ($2)) AND " +
+		"t.is_completed IN ($3)) AND t.updated_at >= $4)"
+	if sql != wantSQL {
+		t.Fatalf("got SQL\n%s\nwant\n%s", sql, wantSQL)
+	}
+	wantArgs := []any{"kanban", 7, false, time.Date(2025, 5, 2, 0, 0, 0, 0, time.UTC)}
+	if !reflect.DeepEqual(args, wantArgs) {
+		t.Fatalf("got args %v, want %v", args, wantArgs)
+	}
+}
+
+func TestCompileOperators(t *testing.T) {
+	tests := []struct {
+		filter string
+		sql    string
+		args   []any
+	}{
+		{"title:Bug", "lower(t.title) IN (lower($2))", []any{"Bug"}},
+		{"title~50%", "t.title ILIKE $2", []any{`%50\%%`}},
+		{"priority!:LOW,high", "NOT COALESCE(t.priority IN ($2, $3), FALSE)", []any{"low", "high"}},
+		{"estimate>=2.5", "t.estimate >= $2", []any{2.5}},
+		{"estimate:null", "t.estimate IS NULL", []any{}},
+		{"assignee:none", "NOT EXISTS (SELECT 1 FROM task_users tu WHERE tu.task_id = t.id AND tu.user_id IS NOT NULL)", []any{}},
+		{
+			"updated:2025-05-01",
+			"(t.updated_at >= $2 AND t.updated_at < $3)",
+			[]any{time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 5, 2, 0, 0, 0, 0, time.UTC)},
+		},
+		{"updated<=2025-05-01", "t.updated_at < $2", []any{time.Date(2025, 5, 2, 0, 0, 0, 0, time.UTC)}},
+	}
+
+	for _, tt := range tests {
+		sql, args := compile(t, tt.filter, Options{})
+		if sql != tt.sql {
+			t.Errorf("%s: got SQL %s, want %s", tt.filter, sql, tt.sql)
+		}
+		if !reflect.DeepEqual(args[1:], tt.args) {
+			t.Errorf("%s: got args %v, want %v", tt.filter, args[1:], tt.args)
+		}
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := []struct {
+		filter string
+		err    error
+	}{
+		{"owner:1", ErrUnknownField},
+		{"is_completed>true", ErrInvalidOperator},
+		{"estimate>1,2", ErrInvalidOperator},
+		{"priority:urgent", ErrInvalidValue},
+		{"updated>yesterday", ErrInvalidValue},
+		{"title:null", ErrInvalidValue},
+		{"assignee:me", ErrNoCurrentUser},
+		{`assignee:"me"`, ErrInvalidValue},
+	}
+
+	for _, tt := range tests {
+		expr, err := Parse(tt.filter)
+		if err != nil {
+			t.Fatalf("Parse(%q) failed: %v", tt.filter, err)
+		}
+		if _, _, err := Compile(expr, testSchema, Options{}, nil); !errors.Is(err, tt.err) {
+			t.Errorf("Compile(%q) returned %v, want %v", tt.filter, err, tt.err)
+		}
+	}
+}
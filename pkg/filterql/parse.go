@@ -0,0 +1,312 @@
+// Package filterql parses the filter expressions of list endpoints, such as
+// `assignee:me AND is_completed:false AND updated>2025-05-01`, and compiles them into
+// parameterized SQL conditions over a whitelist of fields.
+//
+// The grammar, with case-insensitive keywords:
+//
+//	expr  = and { "OR" and }
+//	and   = unary { ["AND"] unary }
+//	unary = "NOT" unary | "(" expr ")" | term
+//	term  = field op value { "," value }
+//	op    = ":" | "!:" | "~" | ">" | ">=" | "<" | "<="
+//
+// Terms next to each other are ANDed. Values are bare words or "quoted strings" with \" and
+// \\ escapes; only ":" and "!:" take comma-separated lists, which match any of the values.
+package filterql
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Limits keeping parsing and the generated SQL small.
+const (
+	maxLength = 2000
+	maxTerms  = 50
+	maxDepth  = 20
+)
+
+type Op string
+
+const (
+	OpEqual        Op = ":"
+	OpNotEqual     Op = "!:"
+	OpContains     Op = "~"
+	OpGreater      Op = ">"
+	OpGreaterEqual Op = ">="
+	OpLess         Op = "<"
+	OpLessEqual    Op = "<="
+)
+
+// Expr is a parsed filter: And, Or, Not or Term.
+type Expr interface {
+	String() string
+}
+
+type And struct {
+	Left, Right Expr
+}
+
+type Or struct {
+	Left, Right Expr
+}
+
+type Not struct {
+	Expr Expr
+}
+
+type Term struct {
+	Field  string // Lowercased
+	Op     Op
+	Values []Value
+}
+
+type Value struct {
+	Text   string
+	Quoted bool // Quoted values never stand for keywords such as me or null
+}
+
+func (e *And) String() string { return "(" + e.Left.String() + " AND " + e.Right.String() + ")" }
+func (e *Or) String() string  { return "(" + e.Left.String() + " OR " + e.Right.String() + ")" }
+func (e *Not) String() string { return "NOT " + e.Expr.String() }
+
+func (t *Term) String() string {
+	values := make([]string, 0, len(t.Values))
+	for _, v := range t.Values {
+		if v.Quoted {
+			values = append(values, fmt.Sprintf("%q", v.Text))
+		} else {
+			values = append(values, v.Text)
+		}
+	}
+	return t.Field + string(t.Op) + strings.Join(values, ",")
+}
+
+// Parse reads a filter expression. An empty or blank string yields a nil Expr.
+func Parse(s string) (Expr, error) {
+	if len(s) > maxLength {
+		return nil, fmt.Errorf("%w: longer than %d characters", ErrSyntax, maxLength)
+	}
+	p := &parser{s: []rune(s)}
+	p.skipSpace()
+	if p.eof() {
+		return nil, nil
+	}
+
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if !p.eof() {
+		return nil, p.errorf("unexpected %q", string(p.s[p.pos]))
+	}
+	return expr, nil
+}
+
+type parser struct {
+	s     []rune
+	pos   int
+	terms int
+	depth int
+}
+
+func (p *parser) eof() bool {
+	return p.pos >= len(p.s)
+}
+
+func (p *parser) peek() rune {
+	if p.eof() {
+		return 0
+	}
+	return p.s[p.pos]
+}
+
+func (p *parser) skipSpace() {
+	for !p.eof() && unicode.IsSpace(p.s[p.pos]) {
+		p.pos++
+	}
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	return fmt.Errorf("%w: %s at position %d", ErrSyntax, fmt.Sprintf(format, args...), p.pos+1)
+}
+
+// keyword consumes the keyword when it is at the current position as a word of its own.
+func (p *parser) keyword(k string) bool {
+	end := p.pos + len(k)
+	if end > len(p.s) || !strings.EqualFold(string(p.s[p.pos:end]), k) {
+		return false
+	}
+	if end < len(p.s) && !unicode.IsSpace(p.s[end]) && p.s[end] != '(' {
+		return false
+	}
+	p.pos = end
+	return true
+}
+
+func (p *parser) parseOr() (Expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		p.skipSpace()
+		if !p.keyword("OR") {
+			return left, nil
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &Or{Left: left, Right: right}
+	}
+}
+
+func (p *parser) parseAnd() (Expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		p.skipSpace()
+		if p.eof() || p.peek() == ')' {
+			return left, nil
+		}
+		start := p.pos
+		if p.keyword("OR") {
+			p.pos = start
+			return left, nil
+		}
+		p.keyword("AND")
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &And{Left: left, Right: right}
+	}
+}
+
+func (p *parser) parseUnary() (Expr, error) {
+	p.skipSpace()
+	if p.keyword("NOT") {
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &Not{Expr: expr}, nil
+	}
+
+	if p.peek() != '(' {
+		return p.parseTerm()
+	}
+	p.depth++
+	if p.depth > maxDepth {
+		return nil, p.errorf("nested deeper than %d levels", maxDepth)
+	}
+	p.pos++
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if p.peek() != ')' {
+		return nil, p.errorf("missing )")
+	}
+	p.pos++
+	p.depth--
+	return expr, nil
+}
+
+func (p *parser) parseTerm() (*Term, error) {
+	p.terms++
+	if p.terms > maxTerms {
+		return nil, p.errorf("more than %d terms", maxTerms)
+	}
+
+	start := p.pos
+	for !p.eof() && isFieldRune(p.peek()) {
+		p.pos++
+	}
+	if p.pos == start {
+		if p.eof() {
+			return nil, p.errorf("expected a field")
+		}
+		return nil, p.errorf("expected a field, got %q", string(p.peek()))
+	}
+	term := &Term{Field: strings.ToLower(string(p.s[start:p.pos]))}
+
+	op, ok := p.parseOp()
+	if !ok {
+		return nil, p.errorf("expected an operator after %q", term.Field)
+	}
+	term.Op = op
+
+	for {
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		term.Values = append(term.Values, value)
+		if p.peek() != ',' {
+			break
+		}
+		p.pos++
+	}
+
+	if !p.eof() && !unicode.IsSpace(p.peek()) && p.peek() != ')' {
+		return nil, p.errorf("unexpected %q", string(p.peek()))
+	}
+	return term, nil
+}
+
+func (p *parser) parseOp() (Op, bool) {
+	for _, op := range []Op{OpGreaterEqual, OpLessEqual, OpNotEqual, OpEqual, OpContains, OpGreater, OpLess} {
+		end := p.pos + len(op)
+		if end <= len(p.s) && string(p.s[p.pos:end]) == string(op) {
+			p.pos = end
+			return op, true
+		}
+	}
+	return "", false
+}
+
+func (p *parser) parseValue() (Value, error) {
+	if p.peek() != '"' {
+		start := p.pos
+		for !p.eof() && !unicode.IsSpace(p.peek()) && !strings.ContainsRune(`,()"`, p.peek()) {
+			p.pos++
+		}
+		if p.pos == start {
+			return Value{}, p.errorf("expected a value")
+		}
+		return Value{Text: string(p.s[start:p.pos])}, nil
+	}
+
+	p.pos++
+	var b strings.Builder
+	for {
+		if p.eof() {
+			return Value{}, p.errorf("unterminated quoted value")
+		}
+		r := p.s[p.pos]
+		p.pos++
+		switch r {
+		case '"':
+			return Value{Text: b.String(), Quoted: true}, nil
+		case '\\':
+			if p.eof() {
+				return Value{}, p.errorf("unterminated quoted value")
+			}
+			b.WriteRune(p.s[p.pos])
+			p.pos++
+		default:
+			b.WriteRune(r)
+		}
+	}
+}
+
+func isFieldRune(r rune) bool {
+	return r == '_' || r == '.' || r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r))
+}
@@ -0,0 +1,62 @@
+package markdown
+
+import (
+	"container/list"
+	"sync"
+)
+
+// Cache keeps the HTML of recently rendered content, keyed by a version of the content such as a
+// hash of the source. It is safe for concurrent use.
+type Cache struct {
+	mu      sync.Mutex
+	size    int
+	entries map[string]*list.Element
+	order   *list.List // Most recently used first
+}
+
+type cacheEntry struct {
+	version string
+	html    string
+}
+
+// NewCache returns a cache holding up to size entries. A size of zero or less disables caching.
+func NewCache(size int) *Cache {
+	return &Cache{
+		size:    size,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+func (c *Cache) Get(version string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[version]
+	if !ok {
+		return "", false
+	}
+	c.order.MoveToFront(e)
+	return e.Value.(*cacheEntry).html, true
+}
+
+// Add stores the HTML of a version, evicting the least recently used entry when the cache is full.
+func (c *Cache) Add(version string, html string) {
+	if c.size <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.entries[version]; ok {
+		e.Value.(*cacheEntry).html = html
+		c.order.MoveToFront(e)
+		return
+	}
+	c.entries[version] = c.order.PushFront(&cacheEntry{version: version, html: html})
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).version)
+	}
+}
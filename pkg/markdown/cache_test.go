@@ -0,0 +1,31 @@
+package markdown
+
+import "testing"
+
+func TestCache(t *testing.T) {
+	c := NewCache(2)
+	c.Add("a", "<p>a</p>")
+	c.Add("b", "<p>b</p>")
+	if html, ok := c.Get("a"); !ok || html != "<p>a</p>" {
+		t.Fatalf("Get(a) = %q, %t", html, ok)
+	}
+
+	// b is now the least recently used entry.
+	c.Add("c", "<p>c</p>")
+	if _, ok := c.Get("b"); ok {
+		t.Error("b was not evicted")
+	}
+	for _, version := range []string{"a", "c"} {
+		if _, ok := c.Get(version); !ok {
+			t.Errorf("%s was evicted", version)
+		}
+	}
+}
+
+func TestCacheDisabled(t *testing.T) {
+	c := NewCache(0)
+	c.Add("a", "<p>a</p>")
+	if _, ok := c.Get("a"); ok {
+		t.Error("disabled cache returned an entry")
+	}
+}
@@ -0,0 +1,496 @@
+package markdown
+
+import (
+	"DataTask/pkg/mention"
+	"html"
+	"regexp"
+	"strings"
+)
+
+// maxLinkText bounds the search for the ] closing a link text.
+const maxLinkText = 1000
+
+var (
+	autolinkPattern = regexp.MustCompile(`^<((?:https?|mailto):[^\s<>]*)>`)
+	tagPattern      = regexp.MustCompile(`^<(/?)([a-zA-Z][a-zA-Z0-9]*)(?:\s[^<>]*)?/?>`)
+)
+
+// allowedTags are the raw HTML tags kept in the output. Their attributes are always dropped.
+var allowedTags = map[string]bool{
+	"b": true, "strong": true, "i": true, "em": true, "u": true, "s": true, "del": true, "ins": true,
+	"sub": true, "sup": true, "kbd": true, "mark": true, "small": true, "br": true,
+}
+
+// linkRel keeps rendered links from passing on referrers or getting hold of the page.
+const linkRel = "nofollow noopener noreferrer"
+
+type inlineRenderer struct {
+	r      *renderer
+	src    string
+	inLink bool // Link text cannot have links or mentions of its own
+	depth  int
+	text   strings.Builder // Plain text not written yet
+	open   []string        // Allowed raw HTML tags left open
+	// noCloser remembers the delimiter runs that were found to have no closer, so that a text
+	// full of unmatched delimiters is scanned once rather than once per delimiter.
+	noCloser map[string]bool
+}
+
+func (r *renderer) inline(src string, inLink bool, depth int) {
+	in := &inlineRenderer{r: r, src: src, inLink: inLink, depth: depth, noCloser: make(map[string]bool)}
+	for i := 0; i < len(src); {
+		if n := in.token(i); n > 0 {
+			i += n
+			continue
+		}
+		in.text.WriteByte(src[i])
+		i++
+	}
+	in.flush()
+	for k := len(in.open) - 1; k >= 0; k-- {
+		in.write("</" + in.open[k] + ">")
+	}
+}
+
+// token renders the construct starting at i and returns its length, or 0 if i is plain text.
+func (in *inlineRenderer) token(i int) int {
+	s := in.src
+	switch s[i] {
+	case '\\':
+		return in.escape(i)
+	case '\n':
+		return in.lineBreak()
+	case '`':
+		return in.codeSpan(i)
+	case '*', '_', '~':
+		return in.emphasis(i)
+	case '[':
+		if !in.inLink {
+			return in.link(i, false)
+		}
+	case '!':
+		if !in.inLink && i+1 < len(s) && s[i+1] == '[' {
+			return in.link(i, true)
+		}
+	case '<':
+		return in.angle(i)
+	case 'h':
+		if !in.inLink {
+			return in.bareURL(i)
+		}
+	}
+	return 0
+}
+
+func (in *inlineRenderer) escape(i int) int {
+	s := in.src
+	if i+1 >= len(s) {
+		return 0
+	}
+	if s[i+1] == '\n' {
+		in.flush()
+		in.write("<br>\n")
+		return 2
+	}
+	if !isPunct(s[i+1]) {
+		return 0
+	}
+	in.flush()
+	in.write(html.EscapeString(s[i+1 : i+2]))
+	return 2
+}
+
+// lineBreak turns a newline into a hard break when the line ends with two or more spaces.
+func (in *inlineRenderer) lineBreak() int {
+	text := in.text.String()
+	trimmed := strings.TrimRight(text, " ")
+	in.text.Reset()
+	in.text.WriteString(trimmed)
+	in.flush()
+	if len(text)-len(trimmed) >= 2 {
+		in.write("<br>\n")
+	} else {
+		in.write("\n")
+	}
+	return 1
+}
+
+func (in *inlineRenderer) codeSpan(i int) int {
+	s := in.src
+	n := runLength(s, i)
+	key := s[i : i+n]
+	if in.noCloser[key] {
+		return in.literal(i, n)
+	}
+
+	end := -1
+	for j := i + n; j < len(s); {
+		if s[j] != '`' {
+			j++
+			continue
+		}
+		m := runLength(s, j)
+		if m == n {
+			end = j
+			break
+		}
+		j += m
+	}
+	if end < 0 {
+		in.noCloser[key] = true
+		return in.literal(i, n)
+	}
+
+	code := strings.ReplaceAll(s[i+n:end], "\n", " ")
+	if len(code) >= 2 && code[0] == ' ' && code[len(code)-1] == ' ' && strings.Trim(code, " ") != "" {
+		code = code[1 : len(code)-1]
+	}
+	in.flush()
+	in.write("<code>" + html.EscapeString(code) + "</code>")
+	return end + n - i
+}
+
+func (in *inlineRenderer) emphasis(i int) int {
+	s := in.src
+	c := s[i]
+	n := runLength(s, i)
+	if in.depth >= maxDepth || (c == '~' && n != 2) {
+		return in.literal(i, n)
+	}
+
+	// Only the last three delimiters of a longer run open emphasis.
+	k := min(n, 3)
+	if n > k {
+		in.text.WriteString(s[i : i+n-k])
+		return n - k
+	}
+	if i+k >= len(s) || isSpace(s[i+k]) || (c == '_' && i > 0 && isWordByte(s[i-1])) {
+		return in.literal(i, k)
+	}
+
+	key := s[i : i+k]
+	if in.noCloser[key] {
+		return in.literal(i, k)
+	}
+	end := -1
+	for j := i + k; j < len(s); {
+		switch {
+		case s[j] == '\\':
+			j += 2
+		case s[j] == c:
+			m := runLength(s, j)
+			if m == k && !isSpace(s[j-1]) && (c != '_' || j+m == len(s) || !isWordByte(s[j+m])) {
+				end = j
+			}
+			j += m
+		default:
+			j++
+		}
+		if end >= 0 {
+			break
+		}
+	}
+	if end < 0 {
+		in.noCloser[key] = true
+		return in.literal(i, k)
+	}
+
+	var tags []string
+	switch {
+	case c == '~':
+		tags = []string{"del"}
+	case k == 1:
+		tags = []string{"em"}
+	case k == 2:
+		tags = []string{"strong"}
+	default:
+		tags = []string{"strong", "em"}
+	}
+
+	in.flush()
+	for _, tag := range tags {
+		in.write("<" + tag + ">")
+	}
+	in.r.inline(s[i+k:end], in.inLink, in.depth+1)
+	for t := len(tags) - 1; t >= 0; t-- {
+		in.write("</" + tags[t] + ">")
+	}
+	return end + k - i
+}
+
+// link renders an inline link [text](url "title") or image ![alt](url "title").
+func (in *inlineRenderer) link(i int, image bool) int {
+	s := in.src
+	if in.depth >= maxDepth {
+		return 0
+	}
+	open := i
+	if image {
+		open++
+	}
+
+	closing, nesting := -1, 0
+	for j := open + 1; j < len(s) && j <= open+maxLinkText && closing < 0; j++ {
+		switch s[j] {
+		case '\\':
+			j++
+		case '[':
+			nesting++
+		case ']':
+			if nesting == 0 {
+				closing = j
+			}
+			nesting--
+		}
+	}
+	if closing < 0 || closing+1 >= len(s) || s[closing+1] != '(' {
+		return 0
+	}
+	dest, title, end, ok := parseDestination(s, closing+2)
+	if !ok {
+		return 0
+	}
+	text := s[open+1 : closing]
+
+	in.flush()
+	if image {
+		alt := html.EscapeString(unescapePunct(text))
+		if !safeImageURL(dest) {
+			in.write(alt)
+			return end - i
+		}
+		in.write(`<img src="` + html.EscapeString(dest) + `" alt="` + alt + `"`)
+		if title != "" {
+			in.write(` title="` + html.EscapeString(title) + `"`)
+		}
+		in.write(">")
+		return end - i
+	}
+
+	if !safeURL(dest) {
+		in.r.inline(text, true, in.depth+1)
+		return end - i
+	}
+	in.write(`<a href="` + html.EscapeString(dest) + `"`)
+	if title != "" {
+		in.write(` title="` + html.EscapeString(title) + `"`)
+	}
+	in.write(` rel="` + linkRel + `">`)
+	in.r.inline(text, true, in.depth+1)
+	in.write("</a>")
+	return end - i
+}
+
+// parseDestination parses `url "title")` starting at i, returning the index after the ).
+func parseDestination(s string, i int) (dest string, title string, end int, ok bool) {
+	i = skipSpace(s, i)
+	if i < len(s) && s[i] == '<' {
+		closing := strings.IndexAny(s[i+1:], "<>\n")
+		if closing < 0 || s[i+1+closing] != '>' {
+			return "", "", 0, false
+		}
+		dest = s[i+1 : i+1+closing]
+		i += closing + 2
+	} else {
+		start, parens := i, 0
+		for ; i < len(s) && !isSpace(s[i]); i++ {
+			if s[i] == '\\' && i+1 < len(s) {
+				i++
+			} else if s[i] == '(' {
+				parens++
+			} else if s[i] == ')' {
+				if parens == 0 {
+					break
+				}
+				parens--
+			}
+		}
+		dest = s[start:i]
+	}
+
+	i = skipSpace(s, i)
+	if i < len(s) && (s[i] == '"' || s[i] == '\'') {
+		closing := strings.IndexByte(s[i+1:], s[i])
+		if closing < 0 {
+			return "", "", 0, false
+		}
+		title = s[i+1 : i+1+closing]
+		i = skipSpace(s, i+closing+2)
+	}
+	if i >= len(s) || s[i] != ')' {
+		return "", "", 0, false
+	}
+	return unescapePunct(dest), unescapePunct(title), i + 1, true
+}
+
+// angle renders an <autolink> or an allowed raw HTML tag. Anything else starting with < is text.
+func (in *inlineRenderer) angle(i int) int {
+	s := in.src[i:]
+	if m := autolinkPattern.FindStringSubmatch(s); m != nil && !in.inLink {
+		in.flush()
+		in.writeLink(m[1], html.EscapeString(m[1]))
+		return len(m[0])
+	}
+
+	m := tagPattern.FindStringSubmatch(s)
+	if m == nil {
+		return 0
+	}
+	name := strings.ToLower(m[2])
+	if !allowedTags[name] {
+		return 0
+	}
+
+	in.flush()
+	switch {
+	case name == "br":
+		in.write("<br>")
+	case m[1] == "":
+		in.open = append(in.open, name)
+		in.write("<" + name + ">")
+	default:
+		// Close the tag and any tags opened after it; closing tags that were never opened are dropped.
+		for k := len(in.open) - 1; k >= 0; k-- {
+			if in.open[k] != name {
+				continue
+			}
+			for len(in.open) > k {
+				in.write("</" + in.open[len(in.open)-1] + ">")
+				in.open = in.open[:len(in.open)-1]
+			}
+			break
+		}
+	}
+	return len(m[0])
+}
+
+// bareURL links an http or https URL written without brackets.
+func (in *inlineRenderer) bareURL(i int) int {
+	s := in.src
+	if i > 0 && isWordByte(s[i-1]) {
+		return 0
+	}
+	rest := s[i:]
+	scheme := "http://"
+	if strings.HasPrefix(rest, "https://") {
+		scheme = "https://"
+	} else if !strings.HasPrefix(rest, scheme) {
+		return 0
+	}
+
+	end := strings.IndexAny(rest, "< \t\n")
+	if end < 0 {
+		end = len(rest)
+	}
+	url := trimURL(rest[:end])
+	if len(url) <= len(scheme) {
+		return 0
+	}
+
+	in.flush()
+	in.writeLink(url, html.EscapeString(url))
+	return len(url)
+}
+
+// trimURL drops the punctuation that ends a sentence rather than the URL, and closing parentheses
+// that have no opening one in the URL.
+func trimURL(url string) string {
+	for {
+		trimmed := strings.TrimRight(url, ".,:;!?\"'*_~")
+		if strings.HasSuffix(trimmed, ")") && strings.Count(trimmed, "(") < strings.Count(trimmed, ")") {
+			trimmed = trimmed[:len(trimmed)-1]
+		}
+		if trimmed == url {
+			return url
+		}
+		url = trimmed
+	}
+}
+
+func (in *inlineRenderer) writeLink(href string, content string) {
+	if !safeURL(href) {
+		in.write(content)
+		return
+	}
+	in.write(`<a href="` + html.EscapeString(href) + `" rel="` + linkRel + `">` + content + "</a>")
+}
+
+// flush writes the pending text, linking the mentions Options.Mention knows.
+func (in *inlineRenderer) flush() {
+	text := in.text.String()
+	in.text.Reset()
+	if text == "" {
+		return
+	}
+	if in.inLink || in.r.opts.Mention == nil {
+		in.write(html.EscapeString(text))
+		return
+	}
+
+	last := 0
+	for _, m := range mention.Find(text) {
+		href, ok := in.r.opts.Mention(m.Handle)
+		if !ok || !safeURL(href) {
+			continue
+		}
+		in.write(html.EscapeString(text[last:m.Start]))
+		in.write(`<a href="` + html.EscapeString(href) + `" class="mention">`)
+		in.write(html.EscapeString(text[m.Start:m.End]))
+		in.write("</a>")
+		last = m.End
+	}
+	in.write(html.EscapeString(text[last:]))
+}
+
+func (in *inlineRenderer) write(s string) {
+	in.r.out.WriteString(s)
+}
+
+// literal adds n bytes starting at i as plain text.
+func (in *inlineRenderer) literal(i int, n int) int {
+	in.text.WriteString(in.src[i : i+n])
+	return n
+}
+
+func runLength(s string, i int) int {
+	n := 1
+	for i+n < len(s) && s[i+n] == s[i] {
+		n++
+	}
+	return n
+}
+
+func skipSpace(s string, i int) int {
+	for i < len(s) && isSpace(s[i]) {
+		i++
+	}
+	return i
+}
+
+// unescapePunct removes the backslashes escaping punctuation.
+func unescapePunct(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) && isPunct(s[i+1]) {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n'
+}
+
+// isWordByte treats bytes of multi-byte characters as letters.
+func isWordByte(c byte) bool {
+	return c >= 0x80 || c == '_' || ('0' <= c && c <= '9') || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
+
+func isPunct(c byte) bool {
+	return c < 0x80 && strings.IndexByte("!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", c) >= 0
+}
@@ -0,0 +1,316 @@
+// Package markdown renders the Markdown of task descriptions and comments to HTML that can be
+// embedded in a page as is.
+//
+// The output is built from an allow-list rather than cleaned up afterwards: raw HTML is escaped
+// unless it is one of a few formatting tags, which lose their attributes, and link and image URLs
+// must be relative or use an allowed scheme. Whatever the input, the HTML has no scripts, event
+// handlers or javascript: URLs.
+//
+// The supported syntax is a CommonMark subset: paragraphs and hard line breaks, ATX headings,
+// thematic breaks, fenced code blocks, blockquotes, bullet and ordered lists, emphasis, strong
+// emphasis, ~~strikethrough~~, code spans, inline links and images, <autolinks> and bare http(s)
+// URLs.
+package markdown
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// maxDepth bounds the nesting of blockquotes, lists, emphasis and links.
+const maxDepth = 16
+
+// Options customise rendering.
+type Options struct {
+	// Mention returns the link of a mentioned handle, lower-cased as by mention.Find. Mentions
+	// it returns false for, and mentions inside code or link text, stay plain text.
+	Mention func(handle string) (href string, ok bool)
+}
+
+var (
+	headingPattern  = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ \t]+(.*?))?(?:[ \t]+#+)?[ \t]*$`)
+	rulePattern     = regexp.MustCompile(`^ {0,3}(?:(?:\*[ \t]*){3,}|(?:-[ \t]*){3,}|(?:_[ \t]*){3,})$`)
+	fencePattern    = regexp.MustCompile("^( {0,3})(`{3,}|~{3,})[ \t]*([^`\\s]*)")
+	quotePattern    = regexp.MustCompile(`^ {0,3}> ?`)
+	listPattern     = regexp.MustCompile(`^( {0,3})([-*+]|(\d{1,9})([.)]))( +|$)`)
+	languagePattern = regexp.MustCompile(`^[A-Za-z0-9_+-]{1,32}$`)
+)
+
+type renderer struct {
+	opts Options
+	out  bytes.Buffer
+}
+
+// Render converts Markdown source to sanitized HTML.
+func Render(source string, opts Options) string {
+	source = strings.ReplaceAll(source, "\r\n", "\n")
+	source = strings.ReplaceAll(source, "\r", "\n")
+	source = strings.ReplaceAll(source, "\x00", "\uFFFD")
+
+	lines := strings.Split(source, "\n")
+	for i, line := range lines {
+		lines[i] = expandIndent(line)
+	}
+
+	r := &renderer{opts: opts}
+	r.blocks(lines, 0, false)
+	return strings.TrimRight(r.out.String(), "\n")
+}
+
+// blocks renders lines as a sequence of blocks. Paragraphs of tight list items are not wrapped in
+// <p> elements.
+func (r *renderer) blocks(lines []string, depth int, tight bool) {
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		switch {
+		case isBlank(line):
+			i++
+		case fencePattern.MatchString(line):
+			i = r.codeBlock(lines, i)
+		case headingPattern.MatchString(line):
+			r.heading(line)
+			i++
+		case rulePattern.MatchString(line):
+			r.out.WriteString("<hr>\n")
+			i++
+		case depth < maxDepth && quotePattern.MatchString(line):
+			i = r.blockquote(lines, i, depth)
+		case depth < maxDepth && listPattern.MatchString(line):
+			i = r.list(lines, i, depth)
+		default:
+			i = r.paragraph(lines, i, tight)
+		}
+	}
+}
+
+func (r *renderer) heading(line string) {
+	m := headingPattern.FindStringSubmatch(line)
+	level := len(m[1])
+	fmt.Fprintf(&r.out, "<h%d>", level)
+	r.inline(m[2], false, 0)
+	fmt.Fprintf(&r.out, "</h%d>\n", level)
+}
+
+func (r *renderer) codeBlock(lines []string, i int) int {
+	m := fencePattern.FindStringSubmatch(lines[i])
+	indent, fence, info := len(m[1]), m[2], m[3]
+
+	var code []string
+	for i++; i < len(lines); i++ {
+		if isClosingFence(lines[i], fence) {
+			i++
+			break
+		}
+		code = append(code, cutIndent(lines[i], indent))
+	}
+
+	r.out.WriteString("<pre><code")
+	if languagePattern.MatchString(info) {
+		fmt.Fprintf(&r.out, ` class="language-%s"`, info)
+	}
+	r.out.WriteString(">")
+	for _, line := range code {
+		r.out.WriteString(html.EscapeString(line))
+		r.out.WriteString("\n")
+	}
+	r.out.WriteString("</code></pre>\n")
+	return i
+}
+
+func (r *renderer) blockquote(lines []string, i int, depth int) int {
+	var inner []string
+	for i < len(lines) {
+		line := lines[i]
+		if loc := quotePattern.FindStringIndex(line); loc != nil {
+			inner = append(inner, line[loc[1]:])
+			i++
+			continue
+		}
+		// A paragraph inside the quote may continue on lines without the >.
+		if isBlank(line) || startsBlock(line) || isBlank(inner[len(inner)-1]) {
+			break
+		}
+		inner = append(inner, line)
+		i++
+	}
+
+	r.out.WriteString("<blockquote>\n")
+	r.blocks(inner, depth+1, false)
+	r.out.WriteString("</blockquote>\n")
+	return i
+}
+
+type listMarker struct {
+	ordered bool
+	delim   byte // The bullet, or the . or ) after the number
+	start   int
+	width   int // Columns before the content of the item
+}
+
+func parseListMarker(line string) (listMarker, bool) {
+	m := listPattern.FindStringSubmatch(line)
+	if m == nil {
+		return listMarker{}, false
+	}
+
+	marker := listMarker{delim: m[2][0], width: len(m[0])}
+	if m[3] != "" {
+		marker.ordered = true
+		marker.delim = m[4][0]
+		marker.start, _ = strconv.Atoi(m[3])
+	}
+	switch spaces := len(m[5]); {
+	case spaces == 0:
+		marker.width++
+	case spaces > 4:
+		marker.width = len(m[1]) + len(m[2]) + 1
+	}
+	return marker, true
+}
+
+func (m listMarker) continues(line string) bool {
+	next, ok := parseListMarker(line)
+	return ok && next.ordered == m.ordered && next.delim == m.delim
+}
+
+func (r *renderer) list(lines []string, i int, depth int) int {
+	first, _ := parseListMarker(lines[i])
+
+	var items [][]string
+	loose := false
+	for i < len(lines) && first.continues(lines[i]) {
+		marker, _ := parseListMarker(lines[i])
+		item := []string{cutColumns(lines[i], marker.width)}
+		for i++; i < len(lines); {
+			line := lines[i]
+			if isBlank(line) {
+				next := i
+				for next < len(lines) && isBlank(lines[next]) {
+					next++
+				}
+				if next < len(lines) && indentOf(lines[next]) >= marker.width {
+					item = append(item, lines[i:next]...)
+					loose = true
+					i = next
+					continue
+				}
+				if next < len(lines) && first.continues(lines[next]) {
+					loose = true
+				}
+				i = next
+				break
+			}
+			if indentOf(line) >= marker.width {
+				item = append(item, line[marker.width:])
+				i++
+				continue
+			}
+			if startsBlock(line) {
+				break
+			}
+			item = append(item, strings.TrimLeft(line, " "))
+			i++
+		}
+		items = append(items, item)
+	}
+
+	switch {
+	case !first.ordered:
+		r.out.WriteString("<ul>\n")
+	case first.start != 1:
+		fmt.Fprintf(&r.out, "<ol start=\"%d\">\n", first.start)
+	default:
+		r.out.WriteString("<ol>\n")
+	}
+	for _, item := range items {
+		r.out.WriteString("<li>")
+		r.blocks(item, depth+1, !loose)
+		r.trimNewlines()
+		r.out.WriteString("</li>\n")
+	}
+	if first.ordered {
+		r.out.WriteString("</ol>\n")
+	} else {
+		r.out.WriteString("</ul>\n")
+	}
+	return i
+}
+
+func (r *renderer) paragraph(lines []string, i int, tight bool) int {
+	var text []string
+	for start := i; i < len(lines) && !isBlank(lines[i]) && (i == start || !startsBlock(lines[i])); i++ {
+		text = append(text, strings.TrimLeft(lines[i], " "))
+	}
+
+	if !tight {
+		r.out.WriteString("<p>")
+	}
+	r.inline(strings.TrimRight(strings.Join(text, "\n"), " "), false, 0)
+	if !tight {
+		r.out.WriteString("</p>")
+	}
+	r.out.WriteString("\n")
+	return i
+}
+
+func (r *renderer) trimNewlines() {
+	for r.out.Len() > 0 && r.out.Bytes()[r.out.Len()-1] == '\n' {
+		r.out.Truncate(r.out.Len() - 1)
+	}
+}
+
+// startsBlock reports whether line interrupts a paragraph.
+func startsBlock(line string) bool {
+	if fencePattern.MatchString(line) || headingPattern.MatchString(line) || rulePattern.MatchString(line) ||
+		quotePattern.MatchString(line) {
+		return true
+	}
+	m := listPattern.FindStringSubmatch(line)
+	return m != nil && m[5] != ""
+}
+
+func isClosingFence(line string, fence string) bool {
+	if indentOf(line) > 3 {
+		return false
+	}
+	closing := strings.TrimSpace(line)
+	return len(closing) >= len(fence) && strings.Trim(closing, fence[:1]) == ""
+}
+
+func isBlank(line string) bool {
+	return strings.TrimSpace(line) == ""
+}
+
+func indentOf(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+// cutIndent removes up to n leading spaces.
+func cutIndent(line string, n int) string {
+	return line[min(n, indentOf(line)):]
+}
+
+// cutColumns removes the first n bytes of a list item line, which may be shorter for empty items.
+func cutColumns(line string, n int) string {
+	return line[min(n, len(line)):]
+}
+
+// expandIndent turns the tabs of the leading whitespace into spaces, with tab stops of four.
+func expandIndent(line string) string {
+	cols, i := 0, 0
+	for ; i < len(line) && (line[i] == ' ' || line[i] == '\t'); i++ {
+		if line[i] == '\t' {
+			cols += 4 - cols%4
+		} else {
+			cols++
+		}
+	}
+	if cols == i {
+		return line
+	}
+	return strings.Repeat(" ", cols) + line[i:]
+}
@@ -0,0 +1,135 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{name: "empty", src: "", want: ""},
+		{name: "paragraphs", src: "one\ntwo\n\nthree", want: "<p>one\ntwo</p>\n<p>three</p>"},
+		{name: "hard break", src: "one  \ntwo\\\nthree", want: "<p>one<br>\ntwo<br>\nthree</p>"},
+		{name: "heading", src: "## Plan ##\n#hashtag", want: "<h2>Plan</h2>\n<p>#hashtag</p>"},
+		{
+			name: "emphasis",
+			src:  "*a* **b** ***c*** ~~d~~ snake_case_name 2 * 3 * 4",
+			want: "<p><em>a</em> <strong>b</strong> <strong><em>c</em></strong> <del>d</del> snake_case_name 2 * 3 * 4</p>",
+		},
+		{name: "nested emphasis", src: "*a **b** c*", want: "<p><em>a <strong>b</strong> c</em></p>"},
+		{name: "escapes", src: `\*not em\* \<b\>`, want: "<p>*not em* &lt;b&gt;</p>"},
+		{name: "code span", src: "use `a < b` or `` x ` y ``", want: "<p>use <code>a &lt; b</code> or <code>x ` y</code></p>"},
+		{
+			name: "code block",
+			src:  "```go\nif a < b {\n\t*x*\n}\n```",
+			want: "<pre><code class=\"language-go\">if a &lt; b {\n    *x*\n}\n</code></pre>",
+		},
+		{name: "unsafe code language", src: "```a\"onclick=x\ncode\n```", want: "<pre><code>code\n</code></pre>"},
+		{name: "rule", src: "a\n\n---\n* * *", want: "<p>a</p>\n<hr>\n<hr>"},
+		{
+			name: "blockquote",
+			src:  "> quoted *text*\nlazy\n\nafter",
+			want: "<blockquote>\n<p>quoted <em>text</em>\nlazy</p>\n</blockquote>\n<p>after</p>",
+		},
+		{
+			name: "tight list",
+			src:  "- one\n- two\n  - nested\n- three",
+			want: "<ul>\n<li>one</li>\n<li>two\n<ul>\n<li>nested</li>\n</ul></li>\n<li>three</li>\n</ul>",
+		},
+		{
+			name: "loose ordered list",
+			src:  "3. one\n\n4. two",
+			want: "<ol start=\"3\">\n<li><p>one</p></li>\n<li><p>two</p></li>\n</ol>",
+		},
+		{
+			name: "link",
+			src:  `[the *docs*](https://example.com/a_(b) "Docs")`,
+			want: `<p><a href="https://example.com/a_(b)" title="Docs" rel="nofollow noopener noreferrer">the <em>docs</em></a></p>`,
+		},
+		{name: "relative link", src: "[task](/task/1)", want: `<p><a href="/task/1" rel="nofollow noopener noreferrer">task</a></p>`},
+		{
+			name: "autolinks",
+			src:  "<mailto:a@example.com> and https://example.com/x.",
+			want: `<p><a href="mailto:a@example.com" rel="nofollow noopener noreferrer">mailto:a@example.com</a> and ` +
+				`<a href="https://example.com/x" rel="nofollow noopener noreferrer">https://example.com/x</a>.</p>`,
+		},
+		{name: "image", src: `![a "b"](https://example.com/a.png)`, want: `<p><img src="https://example.com/a.png" alt="a &#34;b&#34;"></p>`},
+		{name: "allowed tags", src: "<b class=\"x\">bold <i>both</b> <br/> </u>", want: "<p><b>bold <i>both</i></b> <br> </p>"},
+		{name: "unclosed tag", src: "<sup>up", want: "<p><sup>up</sup></p>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Render(tt.src, Options{}); got != tt.want {
+				t.Errorf("Render(%q) =\n%s\nwant\n%s", tt.src, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderSanitizes(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{src: "<script>alert(1)</script>", want: "<p>&lt;script&gt;alert(1)&lt;/script&gt;</p>"},
+		{src: `<img src=x onerror="alert(1)">`, want: "<p>&lt;img src=x onerror=&#34;alert(1)&#34;&gt;</p>"},
+		{src: `<b onmouseover="alert(1)">x</b>`, want: "<p><b>x</b></p>"},
+		{src: "[x](javascript:alert(1))", want: "<p>x</p>"},
+		{src: "[x](JavaScript:alert(1))", want: "<p>x</p>"},
+		{src: "[x](java\tscript:alert(1))", want: "<p>[x](java\tscript:alert(1))</p>"},
+		{src: "[x](<java\tscript:alert(1)>)", want: "<p>x</p>"},
+		{src: "[x](data:text/html;base64,PHNjcmlwdD4=)", want: "<p>x</p>"},
+		{src: "![x](mailto:a@example.com)", want: "<p>x</p>"},
+		{src: `[x](https://example.com/"onclick="alert(1))`, want: `<p><a href="https://example.com/&#34;onclick=&#34;alert(1)" rel="nofollow noopener noreferrer">x</a></p>`},
+		{src: "<javascript:alert(1)>", want: "<p>&lt;javascript:alert(1)&gt;</p>"},
+		{src: "```\n</code><script>x</script>\n```", want: "<pre><code>&lt;/code&gt;&lt;script&gt;x&lt;/script&gt;\n</code></pre>"},
+	}
+
+	for _, tt := range tests {
+		if got := Render(tt.src, Options{}); got != tt.want {
+			t.Errorf("Render(%q) =\n%s\nwant\n%s", tt.src, got, tt.want)
+		}
+	}
+}
+
+func TestRenderMentions(t *testing.T) {
+	opts := Options{Mention: func(handle string) (string, bool) {
+		switch handle {
+		case "alice":
+			return "/users/1", true
+		case "mallory":
+			return "javascript:alert(1)", true
+		}
+		return "", false
+	}}
+
+	got := Render("@Alice, @bob, @mallory, `@alice` and [@alice](/x)", opts)
+	want := `<p><a href="/users/1" class="mention">@Alice</a>, @bob, @mallory, <code>@alice</code> and ` +
+		`<a href="/x" rel="nofollow noopener noreferrer">@alice</a></p>`
+	if got != want {
+		t.Errorf("Render() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestRenderPathological(t *testing.T) {
+	inputs := []string{
+		strings.Repeat("*a ", 20000),
+		strings.Repeat("[", 20000),
+		strings.Repeat("`", 20000) + "x",
+		strings.Repeat("> ", 5000) + "deep",
+		strings.Repeat("- ", 5000) + "deep",
+		strings.Repeat("*", 20000) + "x",
+	}
+
+	for _, src := range inputs {
+		got := Render(src, Options{})
+		if strings.Contains(got, "<script") || got == "" {
+			t.Errorf("Render(%.20q...) = %.50q...", src, got)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package markdown
+
+import "strings"
+
+// allowedSchemes are the URL schemes links may use. Images are limited to http and https.
+var allowedSchemes = map[string]bool{"http": true, "https": true, "mailto": true}
+
+// safeURL reports whether u is relative or uses an allowed scheme. Browsers ignore control
+// characters and whitespace when reading a scheme, so URLs with any are rejected outright.
+func safeURL(u string) bool {
+	if u == "" {
+		return false
+	}
+	s := scheme(u)
+	return s == "" || allowedSchemes[s]
+}
+
+func safeImageURL(u string) bool {
+	return safeURL(u) && scheme(u) != "mailto"
+}
+
+// scheme returns the lower-cased scheme of u, empty for relative URLs, or "invalid".
+func scheme(u string) string {
+	if hasControl(u) {
+		return "invalid"
+	}
+	i := strings.IndexAny(u, ":/?#")
+	if i < 0 || u[i] != ':' {
+		return ""
+	}
+	return strings.ToLower(u[:i])
+}
+
+func hasControl(u string) bool {
+	for _, r := range u {
+		if r <= ' ' || r == 0x7f {
+			return true
+		}
+	}
+	return false
+}
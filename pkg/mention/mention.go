@@ -0,0 +1,72 @@
+// Package mention finds @mentions in free text such as comments and task descriptions.
+//
+// A mention is an @ followed by a handle: the local part of a user's email address (@alice) or,
+// where that is ambiguous, the whole address (@alice@example.com). An @ inside a word, as in an
+// email address written without a leading @, is not a mention.
+package mention
+
+import (
+	"regexp"
+	"strings"
+)
+
+// MaxHandleLength is the longest handle recognised, the maximum length of an email address.
+const MaxHandleLength = 320
+
+var pattern = regexp.MustCompile(`(?:^|[^\w.@/])@(\w[\w.+-]*(?:@[\w-]+(?:\.[\w-]+)+)?)`)
+
+// Match is a mention found in text. Start and End delimit the @ and the handle as written.
+type Match struct {
+	Start  int
+	End    int
+	Handle string // Lower-cased
+}
+
+// Find returns the mentions in text in order of appearance, repeated handles included.
+func Find(text string) []Match {
+	var matches []Match
+	for _, loc := range pattern.FindAllStringSubmatchIndex(text, -1) {
+		written := strings.TrimRight(text[loc[2]:loc[3]], ".+-")
+		if written == "" || len(written) > MaxHandleLength {
+			continue
+		}
+		matches = append(matches, Match{
+			Start:  loc[2] - 1,
+			End:    loc[2] + len(written),
+			Handle: strings.ToLower(written),
+		})
+	}
+	return matches
+}
+
+// Parse returns the handles mentioned in text, lower-cased and in order of first appearance.
+func Parse(text string) []string {
+	var handles []string
+	seen := make(map[string]bool)
+	for _, m := range Find(text) {
+		if seen[m.Handle] {
+			continue
+		}
+		seen[m.Handle] = true
+		handles = append(handles, m.Handle)
+	}
+	return handles
+}
+
+// Handle returns the short handle of the user with the given email address.
+func Handle(email string) string {
+	email = strings.ToLower(email)
+	if i := strings.LastIndexByte(email, '@'); i >= 0 {
+		return email[:i]
+	}
+	return email
+}
+
+// Matches reports whether handle refers to the user with the given email address, either by the
+// short handle or by the whole address.
+func Matches(handle string, email string) bool {
+	if strings.Contains(handle, "@") {
+		return strings.EqualFold(handle, email)
+	}
+	return handle == Handle(email)
+}
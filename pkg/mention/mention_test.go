@@ -0,0 +1,69 @@
+package mention
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{text: "", want: nil},
+		{text: "no mentions here", want: nil},
+		{text: "@alice can you look?", want: []string{"alice"}},
+		{text: "thanks @Bob.", want: []string{"bob"}},
+		{text: "@alice, @bob and @alice again", want: []string{"alice", "bob"}},
+		{text: "cc @j.doe-smith+dev", want: []string{"j.doe-smith+dev"}},
+		{text: "ask @alice@example.com or @alice@other.org", want: []string{"alice@example.com", "alice@other.org"}},
+		{text: "mail bob@example.com", want: nil},
+		{text: "see https://example.com/@alice", want: nil},
+		{text: "(@carol) and\n@dave", want: []string{"carol", "dave"}},
+		{text: "@ alone and @@double", want: nil},
+	}
+
+	for _, tt := range tests {
+		if got := Parse(tt.text); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Parse(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestFind(t *testing.T) {
+	text := "Hi @Alice. (@bob@example.com) and @alice"
+	want := []Match{
+		{Start: 3, End: 9, Handle: "alice"},
+		{Start: 12, End: 28, Handle: "bob@example.com"},
+		{Start: 34, End: 40, Handle: "alice"},
+	}
+
+	got := Find(text)
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Find(%q) = %+v, want %+v", text, got, want)
+	}
+	for _, m := range got {
+		if text[m.Start] != '@' {
+			t.Errorf("match %+v does not start at the @", m)
+		}
+	}
+}
+
+func TestMatches(t *testing.T) {
+	tests := []struct {
+		handle string
+		email  string
+		want   bool
+	}{
+		{handle: "alice", email: "Alice@Example.com", want: true},
+		{handle: "alice@example.com", email: "Alice@Example.com", want: true},
+		{handle: "alice@other.org", email: "alice@example.com", want: false},
+		{handle: "ali", email: "alice@example.com", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := Matches(tt.handle, tt.email); got != tt.want {
+			t.Errorf("Matches(%q, %q) = %t, want %t", tt.handle, tt.email, got, tt.want)
+		}
+	}
+}
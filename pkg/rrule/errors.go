@@ -0,0 +1,8 @@
+package rrule
+
+import "errors"
+
+var (
+	ErrInvalidRule     = errors.New("invalid recurrence rule")
+	ErrUnsupportedPart = errors.New("unsupported recurrence rule part")
+)
@@ -0,0 +1,387 @@
+// Package rrule evaluates RFC 5545 recurrence rules.
+//
+// The supported parts are FREQ (DAILY, WEEKLY, MONTHLY, YEARLY), INTERVAL, COUNT, UNTIL,
+// BYDAY (with ordinals for MONTHLY and YEARLY), BYMONTHDAY, BYMONTH and WKST. Occurrences
+// keep the wall clock time of DTSTART in its location, so a rule keeps firing at 09:00
+// local time across daylight saving changes.
+package rrule
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+	Yearly  Frequency = "YEARLY"
+)
+
+// maxPeriods bounds the search for the next occurrence of rules that rarely or never match.
+const maxPeriods = 10000
+
+// WeekdayNum is a BYDAY entry. N is the ordinal, e.g. -1 for "the last"; 0 means every such weekday.
+type WeekdayNum struct {
+	N       int
+	Weekday time.Weekday
+}
+
+type Rule struct {
+	Freq       Frequency
+	Interval   int
+	Count      int        // 0 when the rule is not limited by COUNT
+	Until      *time.Time // Inclusive
+	ByDay      []WeekdayNum
+	ByMonthDay []int
+	ByMonth    []time.Month
+	WeekStart  time.Weekday
+}
+
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// Parse reads a rule such as "FREQ=WEEKLY;BYDAY=MO,WE". A leading "RRULE:" is accepted.
+func Parse(s string) (*Rule, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "RRULE:")
+	if s == "" {
+		return nil, fmt.Errorf("%w: empty rule", ErrInvalidRule)
+	}
+
+	rule := &Rule{Interval: 1, WeekStart: time.Monday}
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(s, ";") {
+		name, value, ok := strings.Cut(part, "=")
+		name = strings.ToUpper(strings.TrimSpace(name))
+		value = strings.ToUpper(strings.TrimSpace(value))
+		if !ok || name == "" || value == "" {
+			return nil, fmt.Errorf("%w: malformed part %q", ErrInvalidRule, part)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("%w: %s is given twice", ErrInvalidRule, name)
+		}
+		seen[name] = true
+
+		var err error
+		switch name {
+		case "FREQ":
+			err = rule.parseFreq(value)
+		case "INTERVAL":
+			rule.Interval, err = parsePositive(name, value)
+		case "COUNT":
+			rule.Count, err = parsePositive(name, value)
+		case "UNTIL":
+			err = rule.parseUntil(value)
+		case "BYDAY":
+			err = rule.parseByDay(value)
+		case "BYMONTHDAY":
+			err = rule.parseByMonthDay(value)
+		case "BYMONTH":
+			err = rule.parseByMonth(value)
+		case "WKST":
+			wd, ok := weekdays[value]
+			if !ok {
+				err = fmt.Errorf("%w: unknown WKST %q", ErrInvalidRule, value)
+			}
+			rule.WeekStart = wd
+		default:
+			err = fmt.Errorf("%w: %s", ErrUnsupportedPart, name)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if rule.Freq == "" {
+		return nil, fmt.Errorf("%w: FREQ is required", ErrInvalidRule)
+	}
+	if rule.Count > 0 && rule.Until != nil {
+		return nil, fmt.Errorf("%w: COUNT and UNTIL must not be combined", ErrInvalidRule)
+	}
+	for _, wd := range rule.ByDay {
+		if wd.N != 0 && rule.Freq != Monthly && rule.Freq != Yearly {
+			return nil, fmt.Errorf("%w: BYDAY ordinals need FREQ=MONTHLY or FREQ=YEARLY", ErrInvalidRule)
+		}
+	}
+	if len(rule.ByMonthDay) > 0 && rule.Freq == Weekly {
+		return nil, fmt.Errorf("%w: BYMONTHDAY cannot be used with FREQ=WEEKLY", ErrInvalidRule)
+	}
+	return rule, nil
+}
+
+func (r *Rule) parseFreq(value string) error {
+	switch f := Frequency(value); f {
+	case Daily, Weekly, Monthly, Yearly:
+		r.Freq = f
+		return nil
+	}
+	return fmt.Errorf("%w: FREQ=%s", ErrUnsupportedPart, value)
+}
+
+func (r *Rule) parseUntil(value string) error {
+	for _, layout := range []string{"20060102T150405Z", "20060102T150405", "20060102"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			if layout == "20060102" {
+				// A date-only UNTIL includes the whole day.
+				t = t.Add(24*time.Hour - time.Second)
+			}
+			r.Until = &t
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: UNTIL=%s", ErrInvalidRule, value)
+}
+
+func (r *Rule) parseByDay(value string) error {
+	for _, item := range strings.Split(value, ",") {
+		if len(item) < 2 {
+			return fmt.Errorf("%w: BYDAY=%s", ErrInvalidRule, value)
+		}
+		wd, ok := weekdays[item[len(item)-2:]]
+		if !ok {
+			return fmt.Errorf("%w: BYDAY=%s", ErrInvalidRule, value)
+		}
+
+		n := 0
+		if ordinal := item[:len(item)-2]; ordinal != "" {
+			var err error
+			n, err = strconv.Atoi(ordinal)
+			if err != nil || n == 0 || n < -53 || n > 53 {
+				return fmt.Errorf("%w: BYDAY=%s", ErrInvalidRule, value)
+			}
+		}
+		r.ByDay = append(r.ByDay, WeekdayNum{N: n, Weekday: wd})
+	}
+	return nil
+}
+
+func (r *Rule) parseByMonthDay(value string) error {
+	for _, item := range strings.Split(value, ",") {
+		day, err := strconv.Atoi(item)
+		if err != nil || day == 0 || day < -31 || day > 31 {
+			return fmt.Errorf("%w: BYMONTHDAY=%s", ErrInvalidRule, value)
+		}
+		r.ByMonthDay = append(r.ByMonthDay, day)
+	}
+	return nil
+}
+
+func (r *Rule) parseByMonth(value string) error {
+	for _, item := range strings.Split(value, ",") {
+		month, err := strconv.Atoi(item)
+		if err != nil || month < 1 || month > 12 {
+			return fmt.Errorf("%w: BYMONTH=%s", ErrInvalidRule, value)
+		}
+		r.ByMonth = append(r.ByMonth, time.Month(month))
+	}
+	return nil
+}
+
+func parsePositive(name, value string) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("%w: %s must be a positive number", ErrInvalidRule, name)
+	}
+	return n, nil
+}
+
+// After returns the first occurrence strictly after t of the series starting at dtstart.
+// DTSTART itself is always the first occurrence. ok is false when the series has ended.
+func (r *Rule) After(dtstart, t time.Time) (next time.Time, ok bool) {
+	if dtstart.After(t) {
+		return dtstart, true
+	}
+
+	emitted := 1 // DTSTART
+	for period := 0; period < maxPeriods; period++ {
+		for _, occurrence := range r.candidates(dtstart, period) {
+			if !occurrence.After(dtstart) {
+				continue
+			}
+			if r.Until != nil && occurrence.After(*r.Until) {
+				return time.Time{}, false
+			}
+			emitted++
+			if r.Count > 0 && emitted > r.Count {
+				return time.Time{}, false
+			}
+			if occurrence.After(t) {
+				return occurrence, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// candidates returns the sorted occurrences of the period-th interval after the one holding dtstart.
+func (r *Rule) candidates(dtstart time.Time, period int) []time.Time {
+	loc := dtstart.Location()
+	hour, minute, second := dtstart.Clock()
+	at := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, hour, minute, second, 0, loc)
+	}
+	step := period * r.Interval
+
+	var days []time.Time
+	switch r.Freq {
+	case Daily:
+		day := at(dtstart.Year(), dtstart.Month(), dtstart.Day()+step)
+		if r.matchesWeekday(day) && r.matchesMonthDay(day) {
+			days = append(days, day)
+		}
+	case Weekly:
+		offset := (int(dtstart.Weekday()) - int(r.WeekStart) + 7) % 7
+		weekStart := at(dtstart.Year(), dtstart.Month(), dtstart.Day()-offset+7*step)
+		for i := 0; i < 7; i++ {
+			day := at(weekStart.Year(), weekStart.Month(), weekStart.Day()+i)
+			if len(r.ByDay) == 0 && day.Weekday() != dtstart.Weekday() {
+				continue
+			}
+			if r.matchesWeekday(day) {
+				days = append(days, day)
+			}
+		}
+	case Monthly:
+		first := at(dtstart.Year(), dtstart.Month()+time.Month(step), 1)
+		days = r.monthDays(first, dtstart, at)
+	case Yearly:
+		year := dtstart.Year() + step
+		if len(r.ByMonth) == 0 && len(r.ByMonthDay) == 0 && len(r.ByDay) > 0 {
+			days = r.yearWeekdays(year, at)
+			break
+		}
+		months := r.ByMonth
+		if len(months) == 0 {
+			months = []time.Month{dtstart.Month()}
+		}
+		for _, m := range months {
+			days = append(days, r.monthDays(at(year, m, 1), dtstart, at)...)
+		}
+	}
+
+	var matched []time.Time
+	for _, day := range days {
+		if r.matchesMonth(day) {
+			matched = append(matched, day)
+		}
+	}
+	sort.Slice(matched, func(i, j int) bool { return matched[i].Before(matched[j]) })
+	return matched
+}
+
+// monthDays expands BYMONTHDAY and BYDAY within the month starting at first.
+func (r *Rule) monthDays(first, dtstart time.Time, at func(int, time.Month, int) time.Time) []time.Time {
+	year, month := first.Year(), first.Month()
+	daysInMonth := at(year, month+1, 0).Day()
+
+	var days []time.Time
+	switch {
+	case len(r.ByMonthDay) == 0 && len(r.ByDay) == 0:
+		// Months without the day of DTSTART, e.g. the 31st, are skipped.
+		if dtstart.Day() <= daysInMonth {
+			days = append(days, at(year, month, dtstart.Day()))
+		}
+	case len(r.ByMonthDay) > 0:
+		for _, d := range r.ByMonthDay {
+			if d < 0 {
+				d = daysInMonth + d + 1
+			}
+			if d < 1 || d > daysInMonth {
+				continue
+			}
+			day := at(year, month, d)
+			if r.matchesWeekday(day) {
+				days = append(days, day)
+			}
+		}
+	default:
+		for d := 1; d <= daysInMonth; d++ {
+			day := at(year, month, d)
+			if r.matchesOrdinalWeekday(day, d, daysInMonth) {
+				days = append(days, day)
+			}
+		}
+	}
+	return days
+}
+
+// yearWeekdays expands BYDAY over a whole year, with ordinals counted within the year.
+func (r *Rule) yearWeekdays(year int, at func(int, time.Month, int) time.Time) []time.Time {
+	daysInYear := at(year, time.December, 31).YearDay()
+
+	var days []time.Time
+	for d := 1; d <= daysInYear; d++ {
+		day := at(year, time.January, d)
+		if r.matchesOrdinalWeekday(day, d, daysInYear) {
+			days = append(days, day)
+		}
+	}
+	return days
+}
+
+// matchesOrdinalWeekday checks day, the index-th of total days in its month or year, against BYDAY.
+func (r *Rule) matchesOrdinalWeekday(day time.Time, index, total int) bool {
+	for _, wd := range r.ByDay {
+		if wd.Weekday != day.Weekday() {
+			continue
+		}
+		switch {
+		case wd.N == 0:
+			return true
+		case wd.N > 0 && (index-1)/7+1 == wd.N:
+			return true
+		case wd.N < 0 && (total-index)/7+1 == -wd.N:
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Rule) matchesWeekday(day time.Time) bool {
+	if len(r.ByDay) == 0 {
+		return true
+	}
+	for _, wd := range r.ByDay {
+		if wd.Weekday == day.Weekday() {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Rule) matchesMonthDay(day time.Time) bool {
+	if len(r.ByMonthDay) == 0 {
+		return true
+	}
+	daysInMonth := time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, day.Location()).Day()
+	for _, d := range r.ByMonthDay {
+		if d == day.Day() || daysInMonth+d+1 == day.Day() {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Rule) matchesMonth(day time.Time) bool {
+	if len(r.ByMonth) == 0 {
+		return true
+	}
+	for _, m := range r.ByMonth {
+		if m == day.Month() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,142 @@
+package rrule
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// occurrences collects up to n occurrences of the rule starting at dtstart.
+func occurrences(t *testing.T, rule string, dtstart time.Time, n int) []time.Time {
+	t.Helper()
+
+	r, err := Parse(rule)
+	if err != nil {
+		t.Fatalf("Parse(%q) failed: %v", rule, err)
+	}
+
+	result := []time.Time{dtstart}
+	last := dtstart
+	for len(result) < n {
+		next, ok := r.After(dtstart, last)
+		if !ok {
+			break
+		}
+		result = append(result, next)
+		last = next
+	}
+	return result
+}
+
+func assertDates(t *testing.T, got []time.Time, want ...string) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Fatalf("got %d occurrences %v, want %d", len(got), got, len(want))
+	}
+	for i := range want {
+		if got[i].Format("2006-01-02 15:04") != want[i] {
+			t.Fatalf("occurrence %d is %s, want %s", i, got[i].Format("2006-01-02 15:04"), want[i])
+		}
+	}
+}
+
+func TestWeeklyByDay(t *testing.T) {
+	dtstart := time.Date(2025, 5, 5, 9, 0, 0, 0, time.UTC) // Monday
+	got := occurrences(t, "FREQ=WEEKLY;BYDAY=MO,WE", dtstart, 5)
+
+	assertDates(t, got, "2025-05-05 09:00", "2025-05-07 09:00", "2025-05-12 09:00", "2025-05-14 09:00", "2025-05-19 09:00")
+}
+
+func TestBiweeklyWithCount(t *testing.T) {
+	dtstart := time.Date(2025, 5, 2, 18, 30, 0, 0, time.UTC) // Friday
+	got := occurrences(t, "RRULE:FREQ=WEEKLY;INTERVAL=2;COUNT=3", dtstart, 10)
+
+	assertDates(t, got, "2025-05-02 18:30", "2025-05-16 18:30", "2025-05-30 18:30")
+}
+
+func TestMonthlySkipsShortMonths(t *testing.T) {
+	dtstart := time.Date(2025, 1, 31, 10, 0, 0, 0, time.UTC)
+	got := occurrences(t, "FREQ=MONTHLY", dtstart, 4)
+
+	assertDates(t, got, "2025-01-31 10:00", "2025-03-31 10:00", "2025-05-31 10:00", "2025-07-31 10:00")
+}
+
+func TestMonthlyLastFriday(t *testing.T) {
+	dtstart := time.Date(2025, 5, 30, 16, 0, 0, 0, time.UTC)
+	got := occurrences(t, "FREQ=MONTHLY;BYDAY=-1FR", dtstart, 3)
+
+	assertDates(t, got, "2025-05-30 16:00", "2025-06-27 16:00", "2025-07-25 16:00")
+}
+
+func TestMonthlyLastDayOfMonth(t *testing.T) {
+	dtstart := time.Date(2025, 1, 31, 12, 0, 0, 0, time.UTC)
+	got := occurrences(t, "FREQ=MONTHLY;BYMONTHDAY=-1", dtstart, 3)
+
+	assertDates(t, got, "2025-01-31 12:00", "2025-02-28 12:00", "2025-03-31 12:00")
+}
+
+func TestYearlyByMonth(t *testing.T) {
+	dtstart := time.Date(2025, 1, 15, 8, 0, 0, 0, time.UTC)
+	got := occurrences(t, "FREQ=YEARLY;BYMONTH=1,7", dtstart, 4)
+
+	assertDates(t, got, "2025-01-15 08:00", "2025-07-15 08:00", "2026-01-15 08:00", "2026-07-15 08:00")
+}
+
+func TestDailyUntil(t *testing.T) {
+	dtstart := time.Date(2025, 5, 1, 7, 0, 0, 0, time.UTC)
+	got := occurrences(t, "FREQ=DAILY;UNTIL=20250503", dtstart, 10)
+
+	assertDates(t, got, "2025-05-01 07:00", "2025-05-02 07:00", "2025-05-03 07:00")
+}
+
+func TestKeepsWallClockAcrossDST(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+
+	dtstart := time.Date(2025, 3, 28, 9, 0, 0, 0, loc) // Clocks change on March 30
+	got := occurrences(t, "FREQ=DAILY", dtstart, 4)
+
+	for _, occurrence := range got {
+		if occurrence.Hour() != 9 {
+			t.Fatalf("occurrence %s is not at 09:00 local time", occurrence)
+		}
+	}
+}
+
+func TestAfterReturnsDTStartFirst(t *testing.T) {
+	r, err := Parse("FREQ=DAILY")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	dtstart := time.Date(2025, 5, 10, 9, 0, 0, 0, time.UTC)
+	next, ok := r.After(dtstart, dtstart.Add(-time.Hour))
+	if !ok || !next.Equal(dtstart) {
+		t.Fatalf("After before DTSTART = %s, %v; want DTSTART", next, ok)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		rule string
+		want error
+	}{
+		{"", ErrInvalidRule},
+		{"INTERVAL=2", ErrInvalidRule},
+		{"FREQ=WEEKLY;INTERVAL=0", ErrInvalidRule},
+		{"FREQ=WEEKLY;BYDAY=XX", ErrInvalidRule},
+		{"FREQ=WEEKLY;BYDAY=1MO", ErrInvalidRule},
+		{"FREQ=DAILY;COUNT=2;UNTIL=20250101", ErrInvalidRule},
+		{"FREQ=HOURLY", ErrUnsupportedPart},
+		{"FREQ=MONTHLY;BYSETPOS=1", ErrUnsupportedPart},
+	}
+
+	for _, tt := range tests {
+		if _, err := Parse(tt.rule); !errors.Is(err, tt.want) {
+			t.Errorf("Parse(%q) error = %v, want %v", tt.rule, err, tt.want)
+		}
+	}
+}
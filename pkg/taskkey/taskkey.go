@@ -0,0 +1,48 @@
+// Package taskkey formats and parses human-readable task keys such as DT-123: the key of the
+// task's project and the task's sequential number within the project.
+package taskkey
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var projectKeyPattern = regexp.MustCompile(`^[A-Z][A-Z0-9]{1,9}$`)
+
+// ValidProjectKey reports whether key is an upper-case letter followed by 1 to 9 upper-case
+// letters or digits.
+func ValidProjectKey(key string) bool {
+	return projectKeyPattern.MatchString(key)
+}
+
+// Format returns the task key, or "" for a task without a number.
+func Format(projectKey string, number int) string {
+	if projectKey == "" || number <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s-%d", projectKey, number)
+}
+
+// Parse splits a task key into the project key and the number. The project key is matched
+// case-insensitively and returned in upper case.
+func Parse(key string) (string, int, bool) {
+	i := strings.LastIndexByte(key, '-')
+	if i < 0 {
+		return "", 0, false
+	}
+	projectKey := strings.ToUpper(key[:i])
+	if !ValidProjectKey(projectKey) {
+		return "", 0, false
+	}
+	digits := key[i+1:]
+	if digits == "" || digits[0] < '1' || digits[0] > '9' {
+		return "", 0, false
+	}
+	number, err := strconv.Atoi(digits)
+	if err != nil || number <= 0 {
+		return "", 0, false
+	}
+	return projectKey, number, true
+}
@@ -0,0 +1,64 @@
+package taskkey
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		key        string
+		projectKey string
+		number     int
+		ok         bool
+	}{
+		{key: "DT-123", projectKey: "DT", number: 123, ok: true},
+		{key: "dt-7", projectKey: "DT", number: 7, ok: true},
+		{key: "WEB2-1", projectKey: "WEB2", number: 1, ok: true},
+		{key: "123", ok: false},
+		{key: "DT-", ok: false},
+		{key: "DT-0", ok: false},
+		{key: "DT-012", ok: false},
+		{key: "DT-1a", ok: false},
+		{key: "DT--1", ok: false},
+		{key: "D-1", ok: false},
+		{key: "2D-1", ok: false},
+		{key: "ABCDEFGHIJK-1", ok: false},
+		{key: "DT-99999999999999999999", ok: false},
+	}
+
+	for _, tt := range tests {
+		projectKey, number, ok := Parse(tt.key)
+		if ok != tt.ok || projectKey != tt.projectKey || number != tt.number {
+			t.Errorf("Parse(%q) = %q, %d, %t; want %q, %d, %t",
+				tt.key, projectKey, number, ok, tt.projectKey, tt.number, tt.ok)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	if got := Format("DT", 123); got != "DT-123" {
+		t.Errorf("Format(DT, 123) = %q, want DT-123", got)
+	}
+	if got := Format("DT", 0); got != "" {
+		t.Errorf("Format(DT, 0) = %q, want empty", got)
+	}
+	if got := Format("", 5); got != "" {
+		t.Errorf("Format(\"\", 5) = %q, want empty", got)
+	}
+
+	projectKey, number, ok := Parse(Format("OPS", 42))
+	if !ok || projectKey != "OPS" || number != 42 {
+		t.Errorf("Parse(Format(OPS, 42)) = %q, %d, %t", projectKey, number, ok)
+	}
+}
+
+func TestValidProjectKey(t *testing.T) {
+	for _, key := range []string{"DT", "OPS", "A1", "ABCDEFGHIJ"} {
+		if !ValidProjectKey(key) {
+			t.Errorf("ValidProjectKey(%q) = false, want true", key)
+		}
+	}
+	for _, key := range []string{"", "D", "dt", "1A", "D-T", "ABCDEFGHIJK"} {
+		if ValidProjectKey(key) {
+			t.Errorf("ValidProjectKey(%q) = true, want false", key)
+		}
+	}
+}